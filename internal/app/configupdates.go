@@ -89,6 +89,36 @@ func (processor *ConfigUpdateProcessor) WaitForConfigUpdates(configUpdated confi
 					processor.processConfigChangedStoreForwardEnabled()
 					lc.Infof("StoreAndForward Enabled changed to %v", currentWritable.StoreAndForward.Enabled)
 
+				case previousWriteable.Pipeline.MaxConcurrency != currentWritable.Pipeline.MaxConcurrency ||
+					previousWriteable.Pipeline.MinConcurrency != currentWritable.Pipeline.MinConcurrency:
+					// Applied when MakeItRun creates the runtime if not running yet
+					if svc.runtime != nil {
+						svc.runtime.SetConcurrency(currentWritable.Pipeline.MinConcurrency, currentWritable.Pipeline.MaxConcurrency)
+					}
+					lc.Infof("Pipeline concurrency changed to MinConcurrency=%d and MaxConcurrency=%d",
+						currentWritable.Pipeline.MinConcurrency, currentWritable.Pipeline.MaxConcurrency)
+
+				case previousWriteable.Pipeline.StrictValidation != currentWritable.Pipeline.StrictValidation:
+					// Applied when MakeItRun creates the runtime if not running yet
+					if svc.runtime != nil {
+						svc.runtime.SetStrictValidation(svc.strictValidation(currentWritable.Pipeline.StrictValidation))
+					}
+					lc.Infof("Pipeline StrictValidation changed to Enabled=%v and ValidateProfiles=%v",
+						currentWritable.Pipeline.StrictValidation.Enabled, currentWritable.Pipeline.StrictValidation.ValidateProfiles)
+
+				case !reflect.DeepEqual(previousWriteable.Telemetry, currentWritable.Telemetry):
+					// The Telemetry Reporter checks the current settings at least every 10 seconds
+					lc.Info("Telemetry configuration changed")
+
+				case previousWriteable.LogSampling != currentWritable.LogSampling:
+					// The sampling logger uses the current settings for every error and warning logged
+					if _, err := handlers.ParseLogSamplingInterval(currentWritable.LogSampling.Interval); err != nil {
+						lc.Errorf("LogSampling Interval is invalid, repeated messages are not limited: %s", err.Error())
+					} else {
+						lc.Infof("LogSampling changed to Interval=%s and MaxRepeats=%d",
+							currentWritable.LogSampling.Interval, currentWritable.LogSampling.MaxRepeats)
+					}
+
 				default:
 					// Assume change is in the pipeline since all others have been checked appropriately
 					processor.processConfigChangedPipeline()
@@ -146,12 +176,16 @@ func (processor *ConfigUpdateProcessor) processConfigChangedPipeline() {
 	sdk := processor.svc
 
 	if sdk.usingConfigurablePipeline {
+		sdk.reloadMutex.Lock()
+		defer sdk.reloadMutex.Unlock()
+
 		transforms, err := sdk.LoadConfigurablePipeline()
 		if err != nil {
 			sdk.LoggingClient().Error("unable to reload Configurable Pipeline from new configuration: " + err.Error())
 			// Reset the transforms so error occurs when attempting to execute the pipeline.
 			sdk.transforms = nil
-			sdk.runtime.SetTransforms(nil)
+			sdk.topicPipelines = nil
+			sdk.runtime.SetPipelines(nil, nil, sdk.targetType)
 			return
 		}
 
@@ -168,7 +202,7 @@ func (processor *ConfigUpdateProcessor) processConfigChangedPipeline() {
 func (svc *Service) startStoreForward() {
 	var storeForwardEnabledCtx context.Context
 	svc.ctx.storeForwardWg = &sync.WaitGroup{}
-	storeForwardEnabledCtx, svc.ctx.storeForwardCancelCtx = context.WithCancel(context.Background())
+	storeForwardEnabledCtx, svc.ctx.storeForwardCancelCtx = context.WithCancel(svc.parentContext())
 	svc.runtime.StartStoreAndForward(svc.ctx.appWg, svc.ctx.appCtx, svc.ctx.storeForwardWg, storeForwardEnabledCtx, svc.serviceKey)
 }
 
@@ -180,17 +214,23 @@ func (svc *Service) stopStoreForward() {
 
 func (svc *Service) findMatchingFunction(configurable reflect.Value, functionName string) (reflect.Value, reflect.Type, error) {
 	var functionValue reflect.Value
+	var matchedName string
 	count := configurable.Type().NumMethod()
 
 	for index := 0; index < count; index++ {
 		method := configurable.Type().Method(index)
-		// If the target configuration function name starts with actual method name then it is a match
-		if strings.Index(functionName, method.Name) == 0 {
-			functionValue = configurable.MethodByName(method.Name)
-			break
+		// If the target configuration function name starts with actual method name then it is a match.
+		// The longest match is used so aliases of a function whose name starts with another function's name,
+		// i.e. HTTPExportJSON2 vs HTTPExport, resolve to the correct function.
+		if strings.Index(functionName, method.Name) == 0 && len(method.Name) > len(matchedName) {
+			matchedName = method.Name
 		}
 	}
 
+	if len(matchedName) > 0 {
+		functionValue = configurable.MethodByName(matchedName)
+	}
+
 	if functionValue.Kind() == reflect.Invalid {
 		return functionValue, nil, fmt.Errorf("function %s is not a built in SDK function", functionName)
 	} else if functionValue.IsNil() {
@@ -18,9 +18,11 @@ package app
 
 import (
 	"fmt"
-	"strconv"
+	"os"
 	"strings"
+	"time"
 
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/runtime"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/transforms"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/util"
@@ -68,6 +70,8 @@ const (
 	TransformType       = "type"
 	TransformXml        = "xml"
 	TransformJson       = "json"
+	TransformNDJson     = "ndjson"
+	TransformV1Event    = "v1event"
 	AuthMode            = "authmode"
 	Tags                = "tags"
 	ResponseContentType = "responsecontenttype"
@@ -79,12 +83,21 @@ const (
 	BatchByCount        = "bycount"
 	BatchByTime         = "bytime"
 	BatchByTimeAndCount = "bytimecount"
+	WindowTumbling      = "tumbling"
+	WindowSliding       = "sliding"
+	Schema              = "schema"
+	SchemaFile          = "schemafile"
+	DeadLetterDir       = "deadletterdir"
+	RegistryURL         = "registryurl"
 )
 
 // Configurable contains the helper functions that return the function pointers for building the configurable function pipeline.
 // They transform the parameters map from the Pipeline configuration in to the actual actual parameters required by the function.
 type Configurable struct {
 	lc logger.LoggingClient
+	// closers close the functions created which hold resources, i.e. background workers, and are taken by the
+	// service loading the pipeline to close the functions once they are no longer used
+	closers []func()
 }
 
 // NewConfigurable returns a new instance of Configurable
@@ -94,6 +107,13 @@ func NewConfigurable(lc logger.LoggingClient) *Configurable {
 	}
 }
 
+// takeClosers returns the closers of the functions created since it was last called
+func (app *Configurable) takeClosers() []func() {
+	closers := app.closers
+	app.closers = nil
+	return closers
+}
+
 // FilterByProfileName - Specify the profile names of interest to filter for data coming from certain sensors.
 // The Filter by Profile Name transform looks at the Event in the message and looks at the profile names of interest list,
 // provided by this function, and filters out those messages whose Event is for profile names not in the
@@ -162,8 +182,9 @@ func (app *Configurable) FilterByResourceName(parameters map[string]string) inte
 	return transform.FilterByResourceName
 }
 
-// Transform transforms an EdgeX event to XML or JSON based on specified transform type.
-// It will return an error and stop the pipeline if a non-edgex event is received or if no data is received.
+// Transform transforms an EdgeX event to XML, JSON or V1 Event JSON, or a batch of data to NDJSON, based on specified
+// transform type.
+// It will return an error and stop the pipeline if unexpected data is received or if no data is received.
 // This function is a configuration function and returns a function pointer.
 func (app *Configurable) Transform(parameters map[string]string) interfaces.AppFunction {
 	transformType, ok := parameters[TransformType]
@@ -179,12 +200,18 @@ func (app *Configurable) Transform(parameters map[string]string) interfaces.AppF
 		return transform.TransformToXML
 	case TransformJson:
 		return transform.TransformToJSON
+	case TransformNDJson:
+		return transform.TransformToNDJSON
+	case TransformV1Event:
+		return transform.TransformToV1Event
 	default:
 		app.lc.Errorf(
-			"Invalid transform type '%s'. Must be '%s' or '%s'",
+			"Invalid transform type '%s'. Must be '%s', '%s', '%s' or '%s'",
 			transformType,
 			TransformXml,
-			TransformJson)
+			TransformJson,
+			TransformNDJson,
+			TransformV1Event)
 		return nil
 	}
 }
@@ -331,6 +358,84 @@ func (app *Configurable) Encrypt(parameters map[string]string) interfaces.AppFun
 	}
 }
 
+// SignWithHMAC signs data from the previous function with HMAC-SHA256 using the key from the Secret Store at the
+// specified secret path and name. The optional signature mode specifies whether the signature is stored in the
+// context to be sent as a header by a following HTTPExport ('header') or the data is wrapped in a signed JSON
+// envelope ('envelope'). The mode is 'header' by default.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) SignWithHMAC(parameters map[string]string) interfaces.AppFunction {
+	var config struct {
+		SecretPath string `param:"secretpath,required"`
+		SecretName string `param:"secretname,required"`
+		Mode       string `param:"signaturemode" default:"header"`
+	}
+	if err := util.BindParameters(parameters, &config); err != nil {
+		app.lc.Errorf("Invalid parameters for SignWithHMAC: %s", err.Error())
+		return nil
+	}
+
+	transform, err := transforms.NewHMACSigner(config.SecretPath, config.SecretName, strings.ToLower(config.Mode))
+	if err != nil {
+		app.lc.Errorf("Unable to create HMACSigner: %s", err.Error())
+		return nil
+	}
+
+	return transform.SignWithHMAC
+}
+
+// VerifySignature verifies the HMAC-SHA256 signature of the data from the previous function using the key from the
+// Secret Store at the specified secret path and name and passes on the verified data. The optional signature mode
+// specifies whether the signature is expected in the context ('header'), in a signed JSON envelope ('envelope') or
+// the data is a JWS signed with HS256 ('jws'). The mode is 'envelope' by default. With the 'header' mode the
+// signature may also be in the request header specified by the optional signature header name parameter, which is
+// 'X-Signature' by default. The number of signature mismatches is reported as the SignatureMismatches metric.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) VerifySignature(parameters map[string]string) interfaces.AppFunction {
+	var config struct {
+		SecretPath string `param:"secretpath,required"`
+		SecretName string `param:"secretname,required"`
+		Mode       string `param:"signaturemode" default:"envelope"`
+		HeaderName string `param:"signatureheadername"`
+	}
+	if err := util.BindParameters(parameters, &config); err != nil {
+		app.lc.Errorf("Invalid parameters for VerifySignature: %s", err.Error())
+		return nil
+	}
+
+	transform, err := transforms.NewSignatureVerifierWithHeader(
+		config.SecretPath,
+		config.SecretName,
+		strings.ToLower(config.Mode),
+		config.HeaderName)
+	if err != nil {
+		app.lc.Errorf("Unable to create SignatureVerifier: %s", err.Error())
+		return nil
+	}
+
+	return transform.VerifySignature
+}
+
+// FileExport appends data from the previous function to the specified file in the specified output directory.
+// The optional max size (in bytes) and rotation interval parameters control when the file is rotated, the optional
+// compress rotated parameter enables gzip compression of rotated files and the optional max files parameter limits
+// the number of rotated files retained.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) FileExport(parameters map[string]string) interfaces.AppFunction {
+	var options transforms.FileExporterOptions
+	if err := util.BindParameters(parameters, &options); err != nil {
+		app.lc.Errorf("Invalid parameters for FileExport: %s", err.Error())
+		return nil
+	}
+
+	transform, err := transforms.NewFileExporter(options)
+	if err != nil {
+		app.lc.Errorf("Unable to create FileExporter: %s", err.Error())
+		return nil
+	}
+
+	return transform.ExportToFile
+}
+
 // HTTPExport will send data from the previous function to the specified Endpoint via http POST or PUT. If no previous function exists,
 // then the event that triggered the pipeline will be used. Passing an empty string to the mimetype
 // method will default to application/json.
@@ -359,197 +464,436 @@ func (app *Configurable) HTTPExport(parameters map[string]string) interfaces.App
 	}
 }
 
+// HTTPBatchExport coalesces the JSON data from the previous function of many pipeline executions into a single JSON
+// array which is sent to the specified Endpoint via http POST by a background worker. The optional max batch size and
+// max batch interval parameters bound how many outputs are sent in a request and how long an output waits to be sent.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) HTTPBatchExport(parameters map[string]string) interfaces.AppFunction {
+	var options transforms.HTTPBatchSenderOptions
+	if err := util.BindParameters(parameters, &options); err != nil {
+		app.lc.Errorf("Invalid parameters for HTTPBatchExport: %s", err.Error())
+		return nil
+	}
+
+	transform, err := transforms.NewHTTPBatchSender(options)
+	if err != nil {
+		app.lc.Errorf("Unable to create HTTPBatchSender: %s", err.Error())
+		return nil
+	}
+
+	app.closers = append(app.closers, transform.Close)
+
+	return transform.HTTPBatchPost
+}
+
 //
 // MQTTExport will send data from the previous function to the specified Endpoint via MQTT publish. If no previous function exists,
 // then the event that triggered the pipeline will be used.
 // This function is a configuration function and returns a function pointer.
 func (app *Configurable) MQTTExport(parameters map[string]string) interfaces.AppFunction {
-	var err error
-	qos := 0
-	retain := false
-	autoReconnect := false
-	skipCertVerify := false
-
-	brokerAddress, ok := parameters[BrokerAddress]
-	if !ok {
-		app.lc.Error("Could not find " + BrokerAddress)
-		return nil
+	// KeepAlive and ConnectTimeout are optional and blank values result in MQTT defaults being used.
+	var mqttConfig transforms.MQTTSecretConfig
+	// PersistOnError is optional and is false by default.
+	var options struct {
+		PersistOnError bool `param:"persistonerror"`
 	}
-	topic, ok := parameters[Topic]
-	if !ok {
-		app.lc.Error("Could not find " + Topic)
+	if err := bindAll(parameters, &mqttConfig, &options); err != nil {
+		app.lc.Errorf("Invalid parameters for MQTTExport: %s", err.Error())
 		return nil
 	}
 
-	secretPath, ok := parameters[SecretPath]
-	if !ok {
-		app.lc.Error("Could not find " + SecretPath)
+	transform := transforms.NewMQTTSecretSender(mqttConfig, options.PersistOnError)
+	return transform.MQTTSend
+}
+
+// SetResponseData sets the response data to that passed in from the previous function and the response content type
+// to that set in the ResponseContentType configuration parameter. If the optional PreserveCBOR parameter is true,
+// Events with binary readings received as CBOR are set in CBOR with the CBOR content type. It will return an error
+// and stop the pipeline if data passed in is not of type []byte, string or json.Marshaller
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) SetResponseData(parameters map[string]string) interfaces.AppFunction {
+	var transform transforms.ResponseData
+	if err := util.BindParameters(parameters, &transform); err != nil {
+		app.lc.Errorf("Invalid parameters for SetResponseData: %s", err.Error())
 		return nil
 	}
-	authMode, ok := parameters[AuthMode]
-	if !ok {
-		app.lc.Error("Could not find " + AuthMode)
-		return nil
+
+	return transform.SetResponseData
+}
+
+// Batch sets up Batching of events based on the specified mode parameter (BatchByCount, BatchByTime or BatchByTimeAndCount)
+// and mode specific parameters.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) Batch(parameters map[string]string) interfaces.AppFunction {
+	var config struct {
+		Mode string `param:"mode,required"`
 	}
-	clientID, ok := parameters[ClientID]
-	if !ok {
-		app.lc.Error("Could not find " + ClientID)
+	if err := util.BindParameters(parameters, &config); err != nil {
+		app.lc.Errorf("Invalid parameters for Batch: %s", err.Error())
 		return nil
 	}
-	qosVal, ok := parameters[Qos]
-	if ok {
-		qos, err = strconv.Atoi(qosVal)
-		if err != nil {
-			app.lc.Error("Unable to parse " + Qos + " value")
-			return nil
+
+	var transform *transforms.BatchConfig
+	var err error
+
+	switch strings.ToLower(config.Mode) {
+	case BatchByCount:
+		var countConfig struct {
+			BatchThreshold int `param:"batchthreshold,required"`
 		}
-	}
-	retainVal, ok := parameters[Retain]
-	if ok {
-		retain, err = strconv.ParseBool(retainVal)
-		if err != nil {
-			app.lc.Error("Unable to parse " + Retain + " value")
+		if err := util.BindParameters(parameters, &countConfig); err != nil {
+			app.lc.Errorf("Invalid parameters for BatchByCount: %s", err.Error())
 			return nil
 		}
-	}
-	autoreconnectVal, ok := parameters[AutoReconnect]
-	if ok {
-		autoReconnect, err = strconv.ParseBool(autoreconnectVal)
-		if err != nil {
-			app.lc.Error("Unable to parse " + AutoReconnect + " value")
+
+		transform, err = transforms.NewBatchByCount(countConfig.BatchThreshold)
+
+	case BatchByTime:
+		var timeConfig struct {
+			TimeInterval string `param:"timeinterval,required"`
+		}
+		if err := util.BindParameters(parameters, &timeConfig); err != nil {
+			app.lc.Errorf("Invalid parameters for BatchByTime: %s", err.Error())
 			return nil
 		}
-	}
-	skipVerifyVal, ok := parameters[SkipVerify]
-	if ok {
-		skipCertVerify, err = strconv.ParseBool(skipVerifyVal)
-		if err != nil {
-			app.lc.Errorf("Could not parse '%s' to a bool for '%s' parameter: %s", skipVerifyVal, SkipVerify, err.Error())
+
+		transform, err = transforms.NewBatchByTime(timeConfig.TimeInterval)
+
+	case BatchByTimeAndCount:
+		var timeCountConfig struct {
+			TimeInterval   string `param:"timeinterval,required"`
+			BatchThreshold int    `param:"batchthreshold,required"`
+		}
+		if err := util.BindParameters(parameters, &timeCountConfig); err != nil {
+			app.lc.Errorf("Invalid parameters for BatchByTimeAndCount: %s", err.Error())
 			return nil
 		}
+
+		transform, err = transforms.NewBatchByTimeAndCount(timeCountConfig.TimeInterval, timeCountConfig.BatchThreshold)
+
+	default:
+		app.lc.Errorf(
+			"Invalid batch mode '%s'. Must be '%s', '%s' or '%s'",
+			config.Mode,
+			BatchByCount,
+			BatchByTime,
+			BatchByTimeAndCount)
+		return nil
+	}
+
+	if err != nil {
+		app.lc.Errorf("Unable to create Batch: %s", err.Error())
+		return nil
 	}
 
-	// These are optional and blank values result in MQTT defaults being used.
-	keepAlive := parameters[KeepAlive]
-	connectTimeout := parameters[ConnectTimeout]
+	return transform.Batch
+}
 
-	mqttConfig := transforms.MQTTSecretConfig{
-		Retain:         retain,
-		SkipCertVerify: skipCertVerify,
-		AutoReconnect:  autoReconnect,
-		ConnectTimeout: connectTimeout,
-		KeepAlive:      keepAlive,
-		QoS:            byte(qos),
-		BrokerAddress:  brokerAddress,
-		ClientId:       clientID,
-		SecretPath:     secretPath,
-		Topic:          topic,
-		AuthMode:       authMode,
+// Aggregate sets up aggregation of Event readings per device over a window based on the specified mode parameter
+// (Tumbling or Sliding) and mode specific parameters. The functions parameter is a comma separated list of the
+// aggregate functions (avg, min, max, count) to compute for each resource.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) Aggregate(parameters map[string]string) interfaces.AppFunction {
+	var config struct {
+		Mode       string   `param:"mode,required"`
+		WindowSize string   `param:"windowsize,required"`
+		Functions  []string `param:"functions,required"`
+	}
+	if err := util.BindParameters(parameters, &config); err != nil {
+		app.lc.Errorf("Invalid parameters for Aggregate: %s", err.Error())
+		return nil
 	}
-	// PersistOnError is optional and is false by default.
-	persistOnError := false
-	value, ok := parameters[PersistOnError]
-	if ok {
-		persistOnError, err = strconv.ParseBool(value)
-		if err != nil {
-			app.lc.Errorf("Could not parse '%s' to a bool for '%s' parameter: %s", value, PersistOnError, err.Error())
+
+	var transform *transforms.Aggregation
+	var err error
+
+	switch strings.ToLower(config.Mode) {
+	case WindowTumbling:
+		transform, err = transforms.NewTumblingWindowAggregation(config.WindowSize, config.Functions)
+
+	case WindowSliding:
+		var slidingConfig struct {
+			SlideInterval string `param:"slideinterval,required"`
+		}
+		if err := util.BindParameters(parameters, &slidingConfig); err != nil {
+			app.lc.Errorf("Invalid parameters for sliding window Aggregate: %s", err.Error())
 			return nil
 		}
+
+		transform, err = transforms.NewSlidingWindowAggregation(config.WindowSize, slidingConfig.SlideInterval, config.Functions)
+
+	default:
+		app.lc.Errorf(
+			"Invalid aggregate window mode '%s'. Must be '%s' or '%s'",
+			config.Mode,
+			WindowTumbling,
+			WindowSliding)
+		return nil
 	}
-	transform := transforms.NewMQTTSecretSender(mqttConfig, persistOnError)
-	return transform.MQTTSend
+
+	if err != nil {
+		app.lc.Errorf("Unable to create Aggregate: %s", err.Error())
+		return nil
+	}
+
+	return transform.Aggregate
 }
 
-// SetResponseData sets the response data to that passed in from the previous function and the response content type
-// to that set in the ResponseContentType configuration parameter. It will return an error and stop the pipeline if
-// data passed in is not of type []byte, string or json.Marshaller
+// SplitEventByReading splits Events with multiple readings into one Event per reading and executes the
+// remainder of the pipeline for each of the new Events.
 // This function is a configuration function and returns a function pointer.
-func (app *Configurable) SetResponseData(parameters map[string]string) interfaces.AppFunction {
-	transform := transforms.ResponseData{}
+func (app *Configurable) SplitEventByReading(parameters map[string]string) interfaces.AppFunction {
+	transform := transforms.NewSplitter()
+	return transform.SplitEventByReading
+}
 
-	value, ok := parameters[ResponseContentType]
-	if ok && len(value) > 0 {
-		transform.ResponseContentType = value
+// Correlate sets up correlating the readings for the specified resource names from Events sharing the same
+// grouping key into a single combined Event. Incomplete groups are discarded after the specified timeout.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) Correlate(parameters map[string]string) interfaces.AppFunction {
+	var config struct {
+		GroupingKey   string        `param:"groupingkey,required"`
+		ResourceNames []string      `param:"resourcenames,required"`
+		Timeout       time.Duration `param:"timeout,required"`
+	}
+	if err := util.BindParameters(parameters, &config); err != nil {
+		app.lc.Errorf("Invalid parameters for Correlate: %s", err.Error())
+		return nil
 	}
 
-	return transform.SetResponseData
+	transform, err := transforms.NewCorrelator(config.GroupingKey, config.ResourceNames, config.Timeout)
+	if err != nil {
+		app.lc.Errorf("Unable to create Correlator: %s", err.Error())
+		return nil
+	}
+
+	return transform.Correlate
 }
 
-// Batch sets up Batching of events based on the specified mode parameter (BatchByCount, BatchByTime or BatchByTimeAndCount)
-// and mode specific parameters.
+// ConvertToParquet converts a batch of Events from the previous function, i.e. Batch, into an Apache Parquet file
+// with a schema derived from the Events. The optional output directory parameter specifies a directory the Parquet
+// files are also written to.
 // This function is a configuration function and returns a function pointer.
-func (app *Configurable) Batch(parameters map[string]string) interfaces.AppFunction {
-	mode, ok := parameters[Mode]
-	if !ok {
-		app.lc.Errorf("Could not find '%s' parameter for Batch", Mode)
+func (app *Configurable) ConvertToParquet(parameters map[string]string) interfaces.AppFunction {
+	var config struct {
+		OutputDir string `param:"outputdir"`
+	}
+	if err := util.BindParameters(parameters, &config); err != nil {
+		app.lc.Errorf("Invalid parameters for ConvertToParquet: %s", err.Error())
 		return nil
 	}
 
-	switch strings.ToLower(mode) {
-	case BatchByCount:
-		batchThreshold, ok := parameters[BatchThreshold]
-		if !ok {
-			app.lc.Errorf("Could not find '%s' parameter for BatchByCount", BatchThreshold)
-			return nil
-		}
+	transform := transforms.NewParquetWriter(config.OutputDir)
+	return transform.ConvertToParquet
+}
+
+// ThresholdAlert sets up checking of Event readings against the specified alarm and clear thresholds, with optional
+// debounce count, so that alert Events are only emitted when a reading's alert state transitions.
+// The optional resource names parameter limits the readings that are checked.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) ThresholdAlert(parameters map[string]string) interfaces.AppFunction {
+	var config struct {
+		AlarmThreshold float64  `param:"alarmthreshold,required"`
+		ClearThreshold float64  `param:"clearthreshold,required"`
+		DebounceCount  int      `param:"debouncecount" default:"1"`
+		ResourceNames  []string `param:"resourcenames"`
+	}
+	if err := util.BindParameters(parameters, &config); err != nil {
+		app.lc.Errorf("Invalid parameters for ThresholdAlert: %s", err.Error())
+		return nil
+	}
+
+	transform, err := transforms.NewThresholdAlert(
+		config.ResourceNames,
+		config.AlarmThreshold,
+		config.ClearThreshold,
+		config.DebounceCount)
+	if err != nil {
+		app.lc.Errorf("Unable to create ThresholdAlert: %s", err.Error())
+		return nil
+	}
+
+	return transform.CheckThresholds
+}
+
+// EnrichWithMetadata sets up adding the specified Device and Device Profile metadata fields to Events as tags.
+// The optional cache TTL parameter controls how long the metadata for a device is cached and is 1 minute by
+// default. A TTL of 0s disables caching.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) EnrichWithMetadata(parameters map[string]string) interfaces.AppFunction {
+	var config struct {
+		Fields   []string      `param:"metadatafields,required"`
+		CacheTTL time.Duration `param:"cachettl" default:"1m"`
+	}
+	if err := util.BindParameters(parameters, &config); err != nil {
+		app.lc.Errorf("Invalid parameters for EnrichWithMetadata: %s", err.Error())
+		return nil
+	}
+
+	transform, err := transforms.NewMetadataEnricher(config.Fields, config.CacheTTL)
+	if err != nil {
+		app.lc.Errorf("Unable to create MetadataEnricher: %s", err.Error())
+		return nil
+	}
+
+	return transform.EnrichWithMetadata
+}
+
+// HTTPLookup sets up enriching Events with the JSON object returned from the specified URL, which is called with
+// the value of the specified lookup key field. The optional tag prefix is prefixed to the names of the tags added.
+// The optional request timeout is 10 seconds by default and the optional cache TTL is 1 minute by default.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) HTTPLookup(parameters map[string]string) interfaces.AppFunction {
+	var options transforms.HTTPLookupOptions
+	if err := util.BindParameters(parameters, &options); err != nil {
+		app.lc.Errorf("Invalid parameters for HTTPLookup: %s", err.Error())
+		return nil
+	}
+
+	transform, err := transforms.NewHTTPLookup(options)
+	if err != nil {
+		app.lc.Errorf("Unable to create HTTPLookup: %s", err.Error())
+		return nil
+	}
+
+	return transform.LookupAndEnrich
+}
+
+// MaskFields sets up redacting or hashing the specified Event fields before export. Fields are 'devicename',
+// 'profilename', 'sourcename', 'tag:<tag name>' or 'reading:<resource name>'. The optional mask mode is 'redact'
+// or 'hash' and is 'redact' by default. The optional secret path and name specify the key used to hash with
+// HMAC-SHA256 rather than plain SHA-256.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) MaskFields(parameters map[string]string) interfaces.AppFunction {
+	var config struct {
+		Fields     []string `param:"maskfields,required"`
+		Mode       string   `param:"maskmode" default:"redact"`
+		SecretPath string   `param:"secretpath"`
+		SecretName string   `param:"secretname"`
+	}
+	if err := util.BindParameters(parameters, &config); err != nil {
+		app.lc.Errorf("Invalid parameters for MaskFields: %s", err.Error())
+		return nil
+	}
+
+	transform, err := transforms.NewMasker(config.Fields, strings.ToLower(config.Mode), config.SecretPath, config.SecretName)
+	if err != nil {
+		app.lc.Errorf("Unable to create Masker: %s", err.Error())
+		return nil
+	}
+
+	return transform.MaskFields
+}
 
-		thresholdValue, err := strconv.Atoi(batchThreshold)
+// ValidateSchema sets up validating data from the previous function against the JSON Schema specified inline by
+// the schema parameter, loaded from the file specified by the schema file parameter or retrieved from the schema
+// registry specified by the registry URL parameter. The registry type is 'confluent' (default) or 'http', in which
+// case '{subject}' and '{version}' are replaced in the registry URL. The subject parameter is required with a
+// registry and the optional version is 'latest' by default. The latest schema is retrieved again once the optional
+// cache TTL, '5m' by default, expires. The optional header name, secret path and secret name specify a secret sent
+// in a header of the registry requests. Data which fails validation stops the pipeline with an error, or if the
+// optional dead-letter directory parameter is specified it is appended to the 'dead-letter.log' file in that
+// directory and the pipeline is stopped. The dead-letter file is rotated once the optional max size (in bytes),
+// 10 MiB by default, is reached and the optional max files parameter, 5 by default, limits the number of rotated
+// files retained.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) ValidateSchema(parameters map[string]string) interfaces.AppFunction {
+	var schema []byte
+
+	inline, inlineFound := parameters[Schema]
+	schemaFile, fileFound := parameters[SchemaFile]
+	_, registryFound := parameters[RegistryURL]
+	switch {
+	case (inlineFound && fileFound) || (registryFound && (inlineFound || fileFound)):
+		app.lc.Errorf("Only one of '%s', '%s' or '%s' parameters can be specified for ValidateSchema",
+			Schema, SchemaFile, RegistryURL)
+		return nil
+	case registryFound:
+		// Retrieved from the registry when the first data is validated
+	case inlineFound:
+		schema = []byte(inline)
+	case fileFound:
+		var err error
+		schema, err = os.ReadFile(strings.TrimSpace(schemaFile))
 		if err != nil {
-			app.lc.Errorf(
-				"Could not parse '%s' to an int for '%s' parameter for BatchByCount: %s",
-				batchThreshold, BatchThreshold, err.Error())
+			app.lc.Errorf("Unable to read schema file for ValidateSchema: %s", err.Error())
 			return nil
 		}
+	default:
+		app.lc.Errorf("Could not find '%s', '%s' or '%s' parameter for ValidateSchema", Schema, SchemaFile, RegistryURL)
+		return nil
+	}
 
-		transform, err := transforms.NewBatchByCount(thresholdValue)
-		if err != nil {
-			app.lc.Error(err.Error())
+	var deadLetter interfaces.AppFunction
+	if deadLetterDir, ok := parameters[DeadLetterDir]; ok {
+		var rotation struct {
+			MaxSize  int64 `param:"maxsize"`
+			MaxFiles int   `param:"maxfiles"`
 		}
-		return transform.Batch
-
-	case BatchByTime:
-		timeInterval, ok := parameters[TimeInterval]
-		if !ok {
-			app.lc.Errorf("Could not find '%s' parameter for BatchByTime", TimeInterval)
+		if err := util.BindParameters(parameters, &rotation); err != nil {
+			app.lc.Errorf("Invalid parameters for ValidateSchema: %s", err.Error())
 			return nil
 		}
-
-		transform, err := transforms.NewBatchByTime(timeInterval)
-		if err != nil {
-			app.lc.Error(err.Error())
+		if rotation.MaxSize <= 0 {
+			rotation.MaxSize = runtime.DefaultDeadLetterMaxSize
+		}
+		if rotation.MaxFiles <= 0 {
+			rotation.MaxFiles = runtime.DefaultDeadLetterMaxFiles
 		}
-		return transform.Batch
 
-	case BatchByTimeAndCount:
-		timeInterval, ok := parameters[TimeInterval]
-		if !ok {
-			app.lc.Error("Could not find " + TimeInterval)
+		exporter, err := transforms.NewFileExporter(transforms.FileExporterOptions{
+			Directory: strings.TrimSpace(deadLetterDir),
+			FileName:  "dead-letter.log",
+			MaxSize:   rotation.MaxSize,
+			MaxFiles:  rotation.MaxFiles,
+		})
+		if err != nil {
+			app.lc.Errorf("Unable to create dead-letter FileExporter: %s", err.Error())
 			return nil
 		}
-		batchThreshold, ok := parameters[BatchThreshold]
-		if !ok {
-			app.lc.Error("Could not find " + BatchThreshold)
+		deadLetter = exporter.ExportToFile
+	}
+
+	var transform *transforms.SchemaValidator
+	var err error
+	if registryFound {
+		var options transforms.SchemaRegistryOptions
+		if err := util.BindParameters(parameters, &options); err != nil {
+			app.lc.Errorf("Invalid parameters for ValidateSchema: %s", err.Error())
 			return nil
 		}
-		thresholdValue, err := strconv.Atoi(batchThreshold)
-		if err != nil {
-			app.lc.Errorf("Could not parse '%s' to an int for '%s' parameter: %s", batchThreshold, BatchThreshold, err.Error())
-		}
-		transform, err := transforms.NewBatchByTimeAndCount(timeInterval, thresholdValue)
-		if err != nil {
-			app.lc.Error(err.Error())
-		}
-		return transform.Batch
+		transform, err = transforms.NewSchemaValidatorWithRegistry(options, deadLetter)
+	} else {
+		transform, err = transforms.NewSchemaValidator(schema, deadLetter)
+	}
+	if err != nil {
+		app.lc.Errorf("Unable to create SchemaValidator: %s", err.Error())
+		return nil
+	}
 
-	default:
-		app.lc.Errorf(
-			"Invalid batch mode '%s'. Must be '%s', '%s' or '%s'",
-			mode,
-			BatchByCount,
-			BatchByTime,
-			BatchByTimeAndCount)
+	return transform.ValidateSchema
+}
+
+// NormalizeTimestamps sets up normalizing the origins of Events and their Readings. The optional source unit is
+// the epoch unit origins are received in ('s', 'ms', 'us', 'ns' or 'auto') and is 'auto' by default. The optional
+// target unit is the epoch unit origins are converted to and is 'ns' by default. The optional precision is the
+// duration origins are truncated to, i.e. '1ms'. The optional timestamp tag is the name of a tag the origin is added
+// to in RFC3339 format using the optional time zone, which is 'UTC' by default.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) NormalizeTimestamps(parameters map[string]string) interfaces.AppFunction {
+	var options transforms.TimestampNormalizerOptions
+	if err := util.BindParameters(parameters, &options); err != nil {
+		app.lc.Errorf("Invalid parameters for NormalizeTimestamps: %s", err.Error())
+		return nil
+	}
+
+	transform, err := transforms.NewTimestampNormalizer(options)
+	if err != nil {
+		app.lc.Errorf("Unable to create TimestampNormalizer: %s", err.Error())
 		return nil
 	}
+
+	return transform.NormalizeTimestamps
 }
 
 // JSONLogic ...
@@ -599,6 +943,16 @@ func (app *Configurable) AddTags(parameters map[string]string) interfaces.AppFun
 	return transform.AddTags
 }
 
+// bindAll binds the parameters to each of the targets, see util.BindParameters
+func bindAll(parameters map[string]string, targets ...interface{}) error {
+	for _, target := range targets {
+		if err := util.BindParameters(parameters, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (app *Configurable) processFilterParameters(
 	funcName string,
 	parameters map[string]string,
@@ -609,21 +963,18 @@ func (app *Configurable) processFilterParameters(
 		return nil, false
 	}
 
-	filterOutBool := false
-	filterOut, ok := parameters[FilterOut]
-	if ok {
-		var err error
-		filterOutBool, err = strconv.ParseBool(filterOut)
-		if err != nil {
-			app.lc.Errorf("Could not convert filterOut value `%s` to bool for %s", filterOut, funcName)
-			return nil, false
-		}
+	var config struct {
+		FilterOut bool `param:"filterout"`
+	}
+	if err := util.BindParameters(parameters, &config); err != nil {
+		app.lc.Errorf("Invalid parameters for %s: %s", funcName, err.Error())
+		return nil, false
 	}
 
 	namesCleaned := util.DeleteEmptyAndTrim(strings.FieldsFunc(names, util.SplitComma))
 	transform := transforms.Filter{
 		FilterValues: namesCleaned,
-		FilterOut:    filterOutBool,
+		FilterOut:    config.FilterOut,
 	}
 
 	return &transform, true
@@ -632,74 +983,14 @@ func (app *Configurable) processFilterParameters(
 func (app *Configurable) processHttpExportParameters(
 	parameters map[string]string) (transforms.HTTPSenderOptions, string, error) {
 
-	result := transforms.HTTPSenderOptions{}
-
-	method, ok := parameters[ExportMethod]
-	if !ok {
-		return result, "", fmt.Errorf("HTTPExport Could not find %s", ExportMethod)
-	}
-
-	result.URL, ok = parameters[Url]
-	if !ok {
-		return result, "", fmt.Errorf("HTTPExport Could not find %s", Url)
-	}
-	result.MimeType, ok = parameters[MimeType]
-	if !ok {
-		return result, "", fmt.Errorf("HTTPExport Could not find %s", MimeType)
-	}
-
-	// PersistOnError is optional and is false by default.
-	var value string
-	result.PersistOnError = false
-	value, ok = parameters[PersistOnError]
-	if ok {
-		var err error
-		result.PersistOnError, err = strconv.ParseBool(value)
-		if err != nil {
-			return result, "",
-				fmt.Errorf("HTTPExport Could not parse '%s' to a bool for '%s' parameter: %s",
-					value,
-					PersistOnError,
-					err.Error())
-		}
-	}
-
-	// ContinueOnSendError is optional and is false by default.
-	result.ContinueOnSendError = false
-	value, ok = parameters[ContinueOnSendError]
-	if ok {
-		var err error
-		result.ContinueOnSendError, err = strconv.ParseBool(value)
-		if err != nil {
-			return result, "",
-				fmt.Errorf("HTTPExport Could not parse '%s' to a bool for '%s' parameter: %s",
-					value,
-					ContinueOnSendError,
-					err.Error())
-		}
+	var result transforms.HTTPSenderOptions
+	var config struct {
+		Method string `param:"method,required"`
 	}
-
-	// ReturnInputData is optional and is false by default.
-	result.ReturnInputData = false
-	value, ok = parameters[ReturnInputData]
-	if ok {
-		var err error
-		result.ReturnInputData, err = strconv.ParseBool(value)
-		if err != nil {
-			return result, "",
-				fmt.Errorf("HTTPExport Could not parse '%s' to a bool for '%s' parameter: %s",
-					value,
-					ReturnInputData,
-					err.Error())
-		}
+	if err := bindAll(parameters, &config, &result); err != nil {
+		return result, "", fmt.Errorf("invalid parameters for HTTPExport: %s", err.Error())
 	}
 
-	result.URL = strings.TrimSpace(result.URL)
-	result.MimeType = strings.TrimSpace(result.MimeType)
-	result.HTTPHeaderName = strings.TrimSpace(parameters[HeaderName])
-	result.SecretPath = strings.TrimSpace(parameters[SecretPath])
-	result.SecretName = strings.TrimSpace(parameters[SecretName])
-
 	if len(result.HTTPHeaderName) == 0 && len(result.SecretPath) != 0 && len(result.SecretName) != 0 {
 		return result, "",
 			fmt.Errorf("HTTPExport missing %s since %s & %s are specified", HeaderName, SecretPath, SecretName)
@@ -713,5 +1004,5 @@ func (app *Configurable) processHttpExportParameters(
 			fmt.Errorf("HTTPExport missing %s since %s & %s are specified", SecretName, SecretPath, HeaderName)
 	}
 
-	return result, method, nil
+	return result, config.Method, nil
 }
@@ -17,11 +17,16 @@ package app
 
 import (
 	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 
+	sdkCommon "github.com/edgexfoundry/app-functions-sdk-go/v2/internal/common"
+
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFilterByProfileName(t *testing.T) {
@@ -138,6 +143,8 @@ func TestTransform(t *testing.T) {
 	}{
 		{"Good - XML", "xMl", true},
 		{"Good - JSON", "JsOn", true},
+		{"Good - NDJSON", "NDJson", true},
+		{"Good - V1 Event", "V1Event", true},
 		{"Bad Type", "baDType", false},
 	}
 
@@ -298,10 +305,351 @@ func TestBatchByTimeAndCount(t *testing.T) {
 	params := make(map[string]string)
 	params[Mode] = BatchByTimeAndCount
 	params[BatchThreshold] = "30"
-	params[TimeInterval] = "10"
+	params[TimeInterval] = "10s"
 
 	trx := configurable.Batch(params)
 	assert.NotNil(t, trx, "return result for BatchByTimeAndCount should not be nil")
+
+	params[TimeInterval] = "10"
+	trx = configurable.Batch(params)
+	assert.Nil(t, trx, "return result for BatchByTimeAndCount with invalid time interval should be nil")
+
+	params[TimeInterval] = "10s"
+	params[BatchThreshold] = "bogus"
+	trx = configurable.Batch(params)
+	assert.Nil(t, trx, "return result for BatchByTimeAndCount with invalid threshold should be nil")
+}
+
+func TestAggregate(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name      string
+		Params    map[string]string
+		ExpectNil bool
+	}{
+		{"Valid tumbling", map[string]string{Mode: "Tumbling", "windowsize": "1m", "functions": "avg, min,max,count"}, false},
+		{"Valid sliding", map[string]string{Mode: WindowSliding, "windowsize": "1m", "slideinterval": "10s", "functions": "avg"}, false},
+		{"Missing mode", map[string]string{"windowsize": "1m", "functions": "avg"}, true},
+		{"Bad mode", map[string]string{Mode: "bogus", "windowsize": "1m", "functions": "avg"}, true},
+		{"Missing window size", map[string]string{Mode: WindowTumbling, "functions": "avg"}, true},
+		{"Bad window size", map[string]string{Mode: WindowTumbling, "windowsize": "bogus", "functions": "avg"}, true},
+		{"Missing functions", map[string]string{Mode: WindowTumbling, "windowsize": "1m"}, true},
+		{"Bad function", map[string]string{Mode: WindowTumbling, "windowsize": "1m", "functions": "median"}, true},
+		{"Missing slide interval", map[string]string{Mode: WindowSliding, "windowsize": "1m", "functions": "avg"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			transform := configurable.Aggregate(test.Params)
+			assert.Equal(t, test.ExpectNil, transform == nil)
+		})
+	}
+}
+
+func TestSplitEventByReading(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	trx := configurable.SplitEventByReading(nil)
+	assert.NotNil(t, trx, "return result from SplitEventByReading should not be nil")
+}
+
+func TestCorrelate(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name      string
+		Params    map[string]string
+		ExpectNil bool
+	}{
+		{"Valid", map[string]string{"groupingkey": "tag:pairId", ResourceNames: "temperature, humidity", "timeout": "10s"}, false},
+		{"Missing grouping key", map[string]string{ResourceNames: "temperature, humidity", "timeout": "10s"}, true},
+		{"Bad grouping key", map[string]string{"groupingkey": "bogus", ResourceNames: "temperature, humidity", "timeout": "10s"}, true},
+		{"Missing resource names", map[string]string{"groupingkey": "profilename", "timeout": "10s"}, true},
+		{"Single resource name", map[string]string{"groupingkey": "profilename", ResourceNames: "temperature", "timeout": "10s"}, true},
+		{"Missing timeout", map[string]string{"groupingkey": "profilename", ResourceNames: "temperature, humidity"}, true},
+		{"Bad timeout", map[string]string{"groupingkey": "profilename", ResourceNames: "temperature, humidity", "timeout": "bogus"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			transform := configurable.Correlate(test.Params)
+			assert.Equal(t, test.ExpectNil, transform == nil)
+		})
+	}
+}
+
+func TestConvertToParquet(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	trx := configurable.ConvertToParquet(map[string]string{})
+	assert.NotNil(t, trx, "return result from ConvertToParquet should not be nil")
+
+	trx = configurable.ConvertToParquet(map[string]string{"outputdir": "/tmp"})
+	assert.NotNil(t, trx, "return result from ConvertToParquet should not be nil")
+}
+
+func TestThresholdAlert(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name      string
+		Params    map[string]string
+		ExpectNil bool
+	}{
+		{"Valid - only required params", map[string]string{"alarmthreshold": "30", "clearthreshold": "25.5"}, false},
+		{"Valid - all params", map[string]string{"alarmthreshold": "30", "clearthreshold": "25", "debouncecount": "3", ResourceNames: "temperature, humidity"}, false},
+		{"Missing alarm threshold", map[string]string{"clearthreshold": "25"}, true},
+		{"Bad alarm threshold", map[string]string{"alarmthreshold": "bogus", "clearthreshold": "25"}, true},
+		{"Missing clear threshold", map[string]string{"alarmthreshold": "30"}, true},
+		{"Bad clear threshold", map[string]string{"alarmthreshold": "30", "clearthreshold": "bogus"}, true},
+		{"Bad debounce count", map[string]string{"alarmthreshold": "30", "clearthreshold": "25", "debouncecount": "bogus"}, true},
+		{"Zero debounce count", map[string]string{"alarmthreshold": "30", "clearthreshold": "25", "debouncecount": "0"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			transform := configurable.ThresholdAlert(test.Params)
+			assert.Equal(t, test.ExpectNil, transform == nil)
+		})
+	}
+}
+
+func TestEnrichWithMetadata(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name      string
+		Params    map[string]string
+		ExpectNil bool
+	}{
+		{"Valid - default cache TTL", map[string]string{"metadatafields": "devicelabels, profilemodel"}, false},
+		{"Valid - cache disabled", map[string]string{"metadatafields": "devicelocation", "cachettl": "0s"}, false},
+		{"Missing fields", map[string]string{"cachettl": "5m"}, true},
+		{"Empty fields", map[string]string{"metadatafields": " , "}, true},
+		{"Bad field", map[string]string{"metadatafields": "bogus"}, true},
+		{"Bad cache TTL", map[string]string{"metadatafields": "devicelabels", "cachettl": "bogus"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			transform := configurable.EnrichWithMetadata(test.Params)
+			assert.Equal(t, test.ExpectNil, transform == nil)
+		})
+	}
+}
+
+func TestHTTPLookup(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name      string
+		Params    map[string]string
+		ExpectNil bool
+	}{
+		{"Valid - only required params", map[string]string{Url: "http://host/assets/{key}", "lookupkey": "devicename"}, false},
+		{"Valid - all params", map[string]string{Url: "http://host/assets/{key}", "lookupkey": "tag:assetId", "tagprefix": "asset.", "requesttimeout": "5s", "cachettl": "0s"}, false},
+		{"Missing url", map[string]string{"lookupkey": "devicename"}, true},
+		{"Missing lookup key", map[string]string{Url: "http://host/assets/{key}"}, true},
+		{"Bad lookup key", map[string]string{Url: "http://host/assets/{key}", "lookupkey": "bogus"}, true},
+		{"Bad request timeout", map[string]string{Url: "http://host/assets/{key}", "lookupkey": "devicename", "requesttimeout": "bogus"}, true},
+		{"Bad cache TTL", map[string]string{Url: "http://host/assets/{key}", "lookupkey": "devicename", "cachettl": "bogus"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			transform := configurable.HTTPLookup(test.Params)
+			assert.Equal(t, test.ExpectNil, transform == nil)
+		})
+	}
+}
+
+func TestSignWithHMAC(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name      string
+		Params    map[string]string
+		ExpectNil bool
+	}{
+		{"Valid - default mode", map[string]string{SecretPath: "hmac", SecretName: "key"}, false},
+		{"Valid - header mode", map[string]string{SecretPath: "hmac", SecretName: "key", "signaturemode": "header"}, false},
+		{"Valid - header name", map[string]string{SecretPath: "hmac", SecretName: "key", "signaturemode": "header", "signatureheadername": "X-Sig"}, false},
+		{"Valid - envelope mode", map[string]string{SecretPath: "hmac", SecretName: "key", "signaturemode": "Envelope"}, false},
+		{"Missing secret path", map[string]string{SecretName: "key"}, true},
+		{"Missing secret name", map[string]string{SecretPath: "hmac"}, true},
+		{"Empty secret name", map[string]string{SecretPath: "hmac", SecretName: " "}, true},
+		{"Bad mode", map[string]string{SecretPath: "hmac", SecretName: "key", "signaturemode": "bogus"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			transform := configurable.SignWithHMAC(test.Params)
+			assert.Equal(t, test.ExpectNil, transform == nil)
+		})
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name      string
+		Params    map[string]string
+		ExpectNil bool
+	}{
+		{"Valid - default mode", map[string]string{SecretPath: "hmac", SecretName: "key"}, false},
+		{"Valid - header mode", map[string]string{SecretPath: "hmac", SecretName: "key", "signaturemode": "header"}, false},
+		{"Valid - jws mode", map[string]string{SecretPath: "hmac", SecretName: "key", "signaturemode": "JWS"}, false},
+		{"Missing secret path", map[string]string{SecretName: "key"}, true},
+		{"Missing secret name", map[string]string{SecretPath: "hmac"}, true},
+		{"Bad mode", map[string]string{SecretPath: "hmac", SecretName: "key", "signaturemode": "bogus"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			transform := configurable.VerifySignature(test.Params)
+			assert.Equal(t, test.ExpectNil, transform == nil)
+		})
+	}
+}
+
+func TestFileExport(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name      string
+		Params    map[string]string
+		ExpectNil bool
+	}{
+		{"Valid - only required params", map[string]string{"outputdir": "/tmp/export", "filename": "export.log"}, false},
+		{"Valid - all params", map[string]string{"outputdir": "/tmp/export", "filename": "export.log", "maxsize": "1048576", "rotationinterval": "1h", "compressrotated": "true", "maxfiles": "10"}, false},
+		{"Missing output dir", map[string]string{"filename": "export.log"}, true},
+		{"Missing file name", map[string]string{"outputdir": "/tmp/export"}, true},
+		{"Bad max size", map[string]string{"outputdir": "/tmp/export", "filename": "export.log", "maxsize": "bogus"}, true},
+		{"Bad rotation interval", map[string]string{"outputdir": "/tmp/export", "filename": "export.log", "rotationinterval": "bogus"}, true},
+		{"Bad compress rotated", map[string]string{"outputdir": "/tmp/export", "filename": "export.log", "compressrotated": "bogus"}, true},
+		{"Bad max files", map[string]string{"outputdir": "/tmp/export", "filename": "export.log", "maxfiles": "bogus"}, true},
+		{"Negative max files", map[string]string{"outputdir": "/tmp/export", "filename": "export.log", "maxfiles": "-1"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			transform := configurable.FileExport(test.Params)
+			assert.Equal(t, test.ExpectNil, transform == nil)
+		})
+	}
+}
+
+func TestHTTPBatchExport(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name      string
+		Params    map[string]string
+		ExpectNil bool
+	}{
+		{"Valid - only required params", map[string]string{Url: "http://localhost/batch"}, false},
+		{"Valid - all params", map[string]string{Url: "http://localhost/batch", HeaderName: "Authorization", SecretPath: "cloud", SecretName: "token", "maxbatchsize": "50", "maxbatchinterval": "10s"}, false},
+		{"Missing url", map[string]string{"maxbatchsize": "50"}, true},
+		{"Bad max batch size", map[string]string{Url: "http://localhost/batch", "maxbatchsize": "bogus"}, true},
+		{"Zero max batch size", map[string]string{Url: "http://localhost/batch", "maxbatchsize": "0"}, true},
+		{"Bad max batch interval", map[string]string{Url: "http://localhost/batch", "maxbatchinterval": "bogus"}, true},
+		{"Missing secret name", map[string]string{Url: "http://localhost/batch", HeaderName: "Authorization", SecretPath: "cloud"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			transform := configurable.HTTPBatchExport(test.Params)
+			assert.Equal(t, test.ExpectNil, transform == nil)
+		})
+	}
+}
+
+func TestMaskFields(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name      string
+		Params    map[string]string
+		ExpectNil bool
+	}{
+		{"Valid - default mode", map[string]string{"maskfields": "devicename, tag:gps, reading:latitude"}, false},
+		{"Valid - hash mode", map[string]string{"maskfields": "devicename", "maskmode": "Hash"}, false},
+		{"Valid - hash mode with key", map[string]string{"maskfields": "devicename", "maskmode": "hash", SecretPath: "mask", SecretName: "key"}, false},
+		{"Missing mask fields", map[string]string{"maskmode": "hash"}, true},
+		{"Empty mask fields", map[string]string{"maskfields": " , "}, true},
+		{"Bad mask field", map[string]string{"maskfields": "bogus"}, true},
+		{"Bad mode", map[string]string{"maskfields": "devicename", "maskmode": "bogus"}, true},
+		{"Missing secret name", map[string]string{"maskfields": "devicename", "maskmode": "hash", SecretPath: "mask"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			transform := configurable.MaskFields(test.Params)
+			assert.Equal(t, test.ExpectNil, transform == nil)
+		})
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	schemaFile := filepath.Join(t.TempDir(), "schema.json")
+	require.NoError(t, os.WriteFile(schemaFile, []byte(`{"type": "object"}`), 0600))
+
+	tests := []struct {
+		Name      string
+		Params    map[string]string
+		ExpectNil bool
+	}{
+		{"Valid - inline schema", map[string]string{Schema: `{"type": "object", "required": ["deviceName"]}`}, false},
+		{"Valid - schema file", map[string]string{SchemaFile: schemaFile}, false},
+		{"Valid - dead-letter", map[string]string{Schema: `{"type": "object"}`, DeadLetterDir: "/tmp/dead-letter"}, false},
+		{"Missing schema", map[string]string{}, true},
+		{"Both schema and schema file", map[string]string{Schema: `{}`, SchemaFile: schemaFile}, true},
+		{"Missing schema file", map[string]string{SchemaFile: filepath.Join(t.TempDir(), "missing.json")}, true},
+		{"Bad schema", map[string]string{Schema: `{"type": 1}`}, true},
+		{"Empty dead-letter dir", map[string]string{Schema: `{}`, DeadLetterDir: " "}, true},
+		{"Valid - dead-letter rotation", map[string]string{Schema: `{}`, DeadLetterDir: "/tmp/dead-letter", "maxsize": "1024", "maxfiles": "2"}, false},
+		{"Bad dead-letter max size", map[string]string{Schema: `{}`, DeadLetterDir: "/tmp/dead-letter", "maxsize": "big"}, true},
+		{"Valid - registry", map[string]string{RegistryURL: "http://localhost:8081", "subject": "events-value", "version": "3"}, false},
+		{"Valid - http store", map[string]string{RegistryURL: "http://localhost/schemas/{subject}/{version}.json", "registrytype": "HTTP", "subject": "events"}, false},
+		{"Registry without subject", map[string]string{RegistryURL: "http://localhost:8081"}, true},
+		{"Registry and schema", map[string]string{RegistryURL: "http://localhost:8081", "subject": "events-value", Schema: `{}`}, true},
+		{"Bad registry type", map[string]string{RegistryURL: "http://localhost:8081", "registrytype": "bogus", "subject": "events-value"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			transform := configurable.ValidateSchema(test.Params)
+			assert.Equal(t, test.ExpectNil, transform == nil)
+		})
+	}
+}
+
+func TestNormalizeTimestamps(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name      string
+		Params    map[string]string
+		ExpectNil bool
+	}{
+		{"Valid - no params", map[string]string{}, false},
+		{"Valid - all params", map[string]string{"sourceunit": "MS", "targetunit": "ns", "precision": "1ms", "timezone": "UTC", "timestamptag": "originTime"}, false},
+		{"Bad source unit", map[string]string{"sourceunit": "bogus"}, true},
+		{"Bad target unit", map[string]string{"targetunit": "bogus"}, true},
+		{"Bad precision", map[string]string{"precision": "bogus"}, true},
+		{"Bad time zone", map[string]string{"timezone": "Nowhere/Bogus"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			transform := configurable.NormalizeTimestamps(test.Params)
+			assert.Equal(t, test.ExpectNil, transform == nil)
+		})
+	}
 }
 
 func TestJSONLogic(t *testing.T) {
@@ -334,6 +682,15 @@ func TestMQTTExport(t *testing.T) {
 
 	trx := configurable.MQTTExport(params)
 	assert.NotNil(t, trx, "return result from MQTTSecretSend should not be nil")
+
+	params[Qos] = "bogus"
+	trx = configurable.MQTTExport(params)
+	assert.Nil(t, trx, "return result from MQTTSecretSend with invalid QoS should be nil")
+
+	params[Qos] = "1"
+	delete(params, Topic)
+	trx = configurable.MQTTExport(params)
+	assert.Nil(t, trx, "return result from MQTTSecretSend without topic should be nil")
 }
 
 func TestAddTags(t *testing.T) {
@@ -416,6 +773,11 @@ func TestEncrypt(t *testing.T) {
 	}
 }
 
+func TestEncryptParametersRedacted(t *testing.T) {
+	assert.True(t, sdkCommon.SecretFunctionParameters[EncryptionKey])
+	assert.True(t, sdkCommon.SecretFunctionParameters[InitVector])
+}
+
 func TestConfigurable_PushToCore(t *testing.T) {
 	configurable := Configurable{lc: lc}
 
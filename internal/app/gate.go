@@ -0,0 +1,137 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+)
+
+// pipelineGate conditionally executes a group of functions configured with ExecuteOnlyIfMatched depending on
+// whether the function preceding the group, typically a filter, matched the data. The data the condition received
+// is passed to the function following the group, so alternate exports can be configured, i.e.
+// 'FilterByDeviceName, HTTPExport, FilterByProfileName, MQTTExport' with both exports only executed if matched.
+type pipelineGate struct {
+	// key of the gate's state in the context of the pipeline execution
+	key string
+}
+
+type gateState struct {
+	matched bool
+	input   interface{}
+}
+
+// gateContext is implemented by the context of the pipeline execution, which keeps the state of the gates so it is
+// copied along with the context, i.e. by PipelineFanOut, and dropped with it when the execution ends
+type gateContext interface {
+	SetExecutionValue(key string, value interface{})
+	ExecutionValue(key string) (interface{}, bool)
+	RemoveExecutionValue(key string)
+	SetWrappedFunction(function interfaces.AppFunction)
+}
+
+// gatePipelineFunctions returns the pipeline with each group of consecutive gated functions, and the function
+// preceding the group, wrapped so the group is only executed if the preceding function matched the data
+func gatePipelineFunctions(pipeline []interfaces.AppFunction, gated []bool) []interfaces.AppFunction {
+	result := make([]interfaces.AppFunction, len(pipeline))
+	copy(result, pipeline)
+
+	for index := 1; index < len(result); index++ {
+		if !gated[index] {
+			continue
+		}
+
+		end := index
+		for end+1 < len(result) && gated[end+1] {
+			end++
+		}
+
+		gate := &pipelineGate{key: fmt.Sprintf("pipeline-gate-%d", index-1)}
+		result[index-1] = gate.condition(pipeline[index-1])
+		for position := index; position <= end; position++ {
+			result[position] = gate.gated(pipeline[position], position == end)
+		}
+
+		index = end
+	}
+
+	return result
+}
+
+// condition wraps the function preceding the gated group so that not matching, i.e. returning false without
+// an error, continues the pipeline with the data received rather than stopping it
+func (gate *pipelineGate) condition(function interfaces.AppFunction) interfaces.AppFunction {
+	return func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		gateCtx, ok := ctx.(gateContext)
+		if !ok {
+			return function(ctx, data)
+		}
+
+		gateCtx.SetWrappedFunction(function)
+		continuePipeline, result := function(ctx, data)
+		if !continuePipeline {
+			if _, ok := result.(error); ok {
+				return false, result
+			}
+
+			ctx.LoggingClient().Debugf("Data not matched, skipping functions which execute only if matched")
+			gateCtx.SetExecutionValue(gate.key, gateState{matched: false, input: data})
+			return true, data
+		}
+
+		gateCtx.SetExecutionValue(gate.key, gateState{matched: true, input: data})
+		return true, result
+	}
+}
+
+// gated wraps a function in the gated group so it is only executed if the condition matched. The last function
+// in the group passes on the data the condition received.
+func (gate *pipelineGate) gated(function interfaces.AppFunction, last bool) interfaces.AppFunction {
+	return func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		gateCtx, ok := ctx.(gateContext)
+		if !ok {
+			return function(ctx, data)
+		}
+
+		gateCtx.SetWrappedFunction(function)
+		value, found := gateCtx.ExecutionValue(gate.key)
+		if last {
+			defer gateCtx.RemoveExecutionValue(gate.key)
+		}
+
+		// State isn't found when retrying from store and forward, which only occurs if the condition matched
+		if found && !value.(gateState).matched {
+			if last {
+				return true, value.(gateState).input
+			}
+			return true, data
+		}
+
+		continuePipeline, result := function(ctx, data)
+		if !continuePipeline {
+			gateCtx.RemoveExecutionValue(gate.key)
+			return false, result
+		}
+
+		if last && found {
+			return true, value.(gateState).input
+		}
+
+		return true, result
+	}
+}
@@ -0,0 +1,141 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/appfunction"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGatePipelineFunctions(t *testing.T) {
+	var executed []string
+
+	matchIf := func(name string, expected string) interfaces.AppFunction {
+		return func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+			executed = append(executed, name)
+			if data != expected {
+				return false, nil
+			}
+			return true, data
+		}
+	}
+
+	export := func(name string) interfaces.AppFunction {
+		return func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+			executed = append(executed, name)
+			return true, name + " response"
+		}
+	}
+
+	pipeline := gatePipelineFunctions(
+		[]interfaces.AppFunction{
+			matchIf("filterA", "A"),
+			export("exportA1"),
+			export("exportA2"),
+			matchIf("filterB", "B"),
+			export("exportB"),
+			export("last"),
+		},
+		[]bool{false, true, true, false, true, false})
+
+	tests := []struct {
+		Name     string
+		Data     string
+		Expected []string
+	}{
+		{"Matched first", "A", []string{"filterA", "exportA1", "exportA2", "filterB", "last"}},
+		{"Matched second", "B", []string{"filterA", "filterB", "exportB", "last"}},
+		{"Matched none", "C", []string{"filterA", "filterB", "last"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			executed = nil
+			ctx := appfunction.NewContext("123", dic, "")
+
+			var data interface{} = test.Data
+			for _, function := range pipeline {
+				var continuePipeline bool
+				continuePipeline, data = function(ctx, data)
+				require.True(t, continuePipeline)
+			}
+
+			assert.Equal(t, test.Expected, executed)
+			assert.Equal(t, "last response", data)
+		})
+	}
+}
+
+func TestGatePipelineFunctionsErrors(t *testing.T) {
+	failingFilter := func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		return false, errors.New("filter failed")
+	}
+	failingExport := func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		return false, errors.New("export failed")
+	}
+	passThrough := func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		return true, data
+	}
+
+	ctx := appfunction.NewContext("123", dic, "")
+
+	// Errors from the condition stop the pipeline
+	pipeline := gatePipelineFunctions([]interfaces.AppFunction{failingFilter, passThrough}, []bool{false, true})
+	continuePipeline, result := pipeline[0](ctx, "data")
+	assert.False(t, continuePipeline)
+	assert.EqualError(t, result.(error), "filter failed")
+
+	// Errors from gated functions stop the pipeline
+	gate := &pipelineGate{key: "gate"}
+	pipeline = []interfaces.AppFunction{gate.condition(passThrough), gate.gated(failingExport, true)}
+	_, _ = pipeline[0](ctx, "data")
+	continuePipeline, result = pipeline[1](ctx, "data")
+	assert.False(t, continuePipeline)
+	assert.EqualError(t, result.(error), "export failed")
+	_, found := ctx.ExecutionValue(gate.key)
+	assert.False(t, found)
+}
+
+func TestGatePipelineFunctionsClonedContext(t *testing.T) {
+	executed := false
+	notMatched := func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		return false, nil
+	}
+	export := func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		executed = true
+		return true, "response"
+	}
+
+	pipeline := gatePipelineFunctions([]interfaces.AppFunction{notMatched, export}, []bool{false, true})
+	ctx := appfunction.NewContext("123", dic, "")
+
+	// Functions following a fan out are executed with a clone of the context, which must keep the gate's state
+	continuePipeline, data := pipeline[0](ctx, "data")
+	require.True(t, continuePipeline)
+	clone := ctx.Clone()
+	continuePipeline, data = pipeline[1](clone, data)
+	require.True(t, continuePipeline)
+	assert.False(t, executed)
+	assert.Equal(t, "data", data)
+	assert.Equal(t, "app.TestGatePipelineFunctionsClonedContext.func2", clone.FunctionName())
+}
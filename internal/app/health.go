@@ -0,0 +1,182 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/bootstrap/container"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/util"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+)
+
+// clientPingTimeout is how long to wait for a dependent Edgex client to respond when checking readiness
+const clientPingTimeout = 5 * time.Second
+
+// readyCacheTTL is how long the result of pinging the dependent Edgex clients is reused, so frequent readiness
+// probes don't ping the clients every time
+const readyCacheTTL = 5 * time.Second
+
+// builtInFunctionClients are the dependent Edgex clients used by the built-in pipeline functions which use any,
+// keyed by the name of the function
+var builtInFunctionClients = map[string]string{
+	"PushToCore":         common.CoreDataServiceKey,
+	"EnrichWithMetadata": common.CoreMetaDataServiceKey,
+}
+
+// Ready implements container.HealthChecker. The service is ready once its configuration is loaded, its trigger is
+// initialized and the dependent Edgex clients its pipelines need respond to ping. The clients are pinged at most
+// once every readyCacheTTL.
+func (svc *Service) Ready() error {
+	if svc.config == nil {
+		return errors.New("configuration not loaded")
+	}
+
+	if atomic.LoadInt32(&svc.triggerReady) == 0 {
+		return errors.New("trigger not initialized")
+	}
+
+	svc.readyMutex.Lock()
+	defer svc.readyMutex.Unlock()
+
+	if !svc.readyCheckedAt.IsZero() && time.Since(svc.readyCheckedAt) < readyCacheTTL {
+		return svc.readyErr
+	}
+
+	svc.readyErr = svc.pingClients(svc.requiredClients())
+	svc.readyCheckedAt = time.Now()
+	return svc.readyErr
+}
+
+// requiredClients returns the names of the configured clients the pipelines need, in order. Which clients are needed
+// is only known for the configurable pipelines using built-in functions, so all the clients are required otherwise.
+func (svc *Service) requiredClients() []string {
+	names := make([]string, 0, len(svc.config.Clients))
+	for name := range svc.config.Clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if !svc.usingConfigurablePipeline {
+		return names
+	}
+
+	pipelineConfig := svc.config.Writable.Pipeline
+	executionOrders := []string{pipelineConfig.ExecutionOrder}
+	for _, topicPipeline := range pipelineConfig.PerTopicPipelines {
+		executionOrders = append(executionOrders, topicPipeline.ExecutionOrder)
+	}
+
+	needed := make(map[string]bool)
+	if pipelineConfig.StrictValidation.Enabled && pipelineConfig.StrictValidation.ValidateProfiles {
+		needed[common.CoreMetaDataServiceKey] = true
+	}
+
+	for _, executionOrder := range executionOrders {
+		for _, functionName := range util.DeleteEmptyAndTrim(strings.FieldsFunc(executionOrder, util.SplitComma)) {
+			// The configuration may be an alias for the function it specifies the name of
+			if name := strings.TrimSpace(pipelineConfig.Functions[functionName].Name); len(name) > 0 {
+				functionName = name
+			}
+
+			if _, custom := svc.findCustomFunctionFactory(functionName); custom {
+				return names
+			}
+
+			for builtIn, client := range builtInFunctionClients {
+				if strings.HasPrefix(functionName, builtIn) {
+					needed[client] = true
+				}
+			}
+		}
+	}
+
+	required := make([]string, 0, len(needed))
+	for _, name := range names {
+		if needed[name] {
+			required = append(required, name)
+		}
+	}
+	return required
+}
+
+// pingClients pings the clients with the names at once, returning the error of the first, in order, not reachable
+func (svc *Service) pingClients(names []string) error {
+	errs := make([]error, len(names))
+
+	var wg sync.WaitGroup
+	for index, name := range names {
+		wg.Add(1)
+		go func(index int, url string) {
+			defer wg.Done()
+			errs[index] = svc.pingClient(url)
+		}(index, svc.config.Clients[name].Url())
+	}
+	wg.Wait()
+
+	for index, err := range errs {
+		if err != nil {
+			return fmt.Errorf("client %s not reachable: %w", names[index], err)
+		}
+	}
+
+	return nil
+}
+
+func (svc *Service) setTriggerReady(ready bool) {
+	var value int32
+	if ready {
+		value = 1
+	}
+	atomic.StoreInt32(&svc.triggerReady, value)
+}
+
+func (svc *Service) pingClient(baseUrl string) error {
+	ctx, cancel := context.WithTimeout(svc.parentContext(), clientPingTimeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, baseUrl+common.ApiPingRoute, nil)
+	if err != nil {
+		return err
+	}
+
+	client := container.HttpClientFrom(svc.dic.Get)
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	_ = response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("ping responded with status %d", response.StatusCode)
+	}
+
+	return nil
+}
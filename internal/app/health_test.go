@@ -0,0 +1,132 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/common"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
+	bootstrapConfig "github.com/edgexfoundry/go-mod-bootstrap/v2/config"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	contractsCommon "github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReady(t *testing.T) {
+	pingStatus := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(pingStatus)
+	}))
+	defer server.Close()
+
+	svc := Service{
+		lc:  lc,
+		dic: di.NewContainer(di.ServiceConstructorMap{}),
+	}
+
+	err := svc.Ready()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "configuration not loaded")
+
+	serverUrl, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(serverUrl.Port())
+	require.NoError(t, err)
+
+	svc.config = &common.ConfigurationStruct{
+		Clients: map[string]bootstrapConfig.ClientInfo{
+			"CoreData": {Protocol: "http", Host: serverUrl.Hostname(), Port: port},
+		},
+	}
+
+	err = svc.Ready()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "trigger not initialized")
+
+	svc.setTriggerReady(true)
+	require.NoError(t, svc.Ready())
+
+	// The result is reused until it expires
+	pingStatus = http.StatusInternalServerError
+	require.NoError(t, svc.Ready())
+
+	svc.readyCheckedAt = time.Now().Add(-readyCacheTTL)
+	err = svc.Ready()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "client CoreData not reachable")
+
+	svc.setTriggerReady(false)
+	require.Error(t, svc.Ready())
+}
+
+func TestRequiredClients(t *testing.T) {
+	svc := Service{
+		lc: lc,
+		config: &common.ConfigurationStruct{
+			Clients: map[string]bootstrapConfig.ClientInfo{
+				contractsCommon.CoreDataServiceKey:     {},
+				contractsCommon.CoreMetaDataServiceKey: {},
+				contractsCommon.CoreCommandServiceKey:  {},
+			},
+			Writable: common.WritableInfo{
+				Pipeline: common.PipelineInfo{
+					ExecutionOrder: "FilterByDeviceName, Core",
+					Functions: map[string]common.PipelineFunction{
+						"FilterByDeviceName": {},
+						"Core":               {Name: "PushToCore"},
+						"Enrich":             {Name: "EnrichWithMetadata"},
+						"Custom":             {},
+					},
+				},
+			},
+		},
+	}
+
+	// All the clients are required by pipelines set in code
+	assert.Equal(t, []string{
+		contractsCommon.CoreCommandServiceKey,
+		contractsCommon.CoreDataServiceKey,
+		contractsCommon.CoreMetaDataServiceKey,
+	}, svc.requiredClients())
+
+	svc.usingConfigurablePipeline = true
+	assert.Equal(t, []string{contractsCommon.CoreDataServiceKey}, svc.requiredClients())
+
+	svc.config.Writable.Pipeline.PerTopicPipelines = map[string]common.TopicPipeline{
+		"enrich": {Topics: "edgex/events/#", ExecutionOrder: "Enrich"},
+	}
+	assert.Equal(t, []string{
+		contractsCommon.CoreDataServiceKey,
+		contractsCommon.CoreMetaDataServiceKey,
+	}, svc.requiredClients())
+
+	// Custom functions may use any client
+	svc.customFunctionFactories = map[string]interfaces.ConfigurableFunctionFactory{
+		"Custom": func(parameters map[string]string) (interfaces.AppFunction, error) { return nil, nil },
+	}
+	svc.config.Writable.Pipeline.ExecutionOrder = "Custom"
+	assert.Len(t, svc.requiredClients(), 3)
+}
@@ -0,0 +1,96 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+)
+
+// pipelineLoader holds the state used while loading the functions of the pipelines from configuration so that
+// every problem with the configuration is found and reported together rather than just the first one.
+type pipelineLoader struct {
+	builtIns     *Configurable
+	configurable reflect.Value
+	errorLogger  *errorCapturingLogger
+	loaded       map[string][]loadedPipelineFunction
+	problems     []string
+}
+
+func newPipelineLoader(lc logger.LoggingClient) *pipelineLoader {
+	errorLogger := &errorCapturingLogger{LoggingClient: lc}
+	builtIns := NewConfigurable(errorLogger)
+	return &pipelineLoader{
+		builtIns:     builtIns,
+		configurable: reflect.ValueOf(builtIns),
+		errorLogger:  errorLogger,
+		loaded:       make(map[string][]loadedPipelineFunction),
+	}
+}
+
+func (loader *pipelineLoader) addProblem(pipelineId string, err error) {
+	if len(pipelineId) > 0 {
+		loader.problems = append(loader.problems, fmt.Sprintf("pipeline '%s': %s", pipelineId, err.Error()))
+		return
+	}
+
+	loader.problems = append(loader.problems, err.Error())
+}
+
+// err returns nil when no problems were found, the problem when only one was found, otherwise an error listing
+// all the problems found
+func (loader *pipelineLoader) err() error {
+	switch len(loader.problems) {
+	case 0:
+		return nil
+	case 1:
+		return errors.New(loader.problems[0])
+	default:
+		return fmt.Errorf(
+			"pipeline configuration has %d problems:\n - %s",
+			len(loader.problems),
+			strings.Join(loader.problems, "\n - "))
+	}
+}
+
+// errorCapturingLogger captures the errors the built in configurable functions log when they fail to be created,
+// so the reason can be included in the error returned when loading the pipeline
+type errorCapturingLogger struct {
+	logger.LoggingClient
+	errors []string
+}
+
+func (errorLogger *errorCapturingLogger) Error(msg string, args ...interface{}) {
+	errorLogger.errors = append(errorLogger.errors, msg)
+	errorLogger.LoggingClient.Error(msg, args...)
+}
+
+func (errorLogger *errorCapturingLogger) Errorf(format string, args ...interface{}) {
+	errorLogger.errors = append(errorLogger.errors, fmt.Sprintf(format, args...))
+	errorLogger.LoggingClient.Errorf(format, args...)
+}
+
+// take returns the errors captured since it was last called
+func (errorLogger *errorCapturingLogger) take() []string {
+	captured := errorLogger.errors
+	errorLogger.errors = nil
+	return captured
+}
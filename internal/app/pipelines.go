@@ -0,0 +1,113 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package app
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/bootstrap/container"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/util"
+)
+
+// Pipelines implements container.PipelineManager. The function names and parameters, with secrets redacted, are
+// reported for configurable pipelines.
+func (svc *Service) Pipelines() []container.PipelineInfo {
+	statuses := svc.runtime.Pipelines()
+
+	pipelines := make([]container.PipelineInfo, 0, len(statuses))
+	for _, status := range statuses {
+		pipelines = append(pipelines, container.PipelineInfo{
+			Id:            status.Id,
+			Topics:        status.Topics,
+			FunctionCount: status.FunctionCount,
+			Paused:        status.Paused,
+		})
+	}
+
+	if !svc.usingConfigurablePipeline {
+		return pipelines
+	}
+
+	pipelineConfig := svc.config.Redacted().Writable.Pipeline
+	for index, pipeline := range pipelines {
+		executionOrder := pipelineConfig.ExecutionOrder
+		if pipeline.Id != interfaces.DefaultPipelineId {
+			executionOrder = pipelineConfig.PerTopicPipelines[pipeline.Id].ExecutionOrder
+		}
+
+		pipeline.ExecutionOrder = util.DeleteEmptyAndTrim(strings.FieldsFunc(executionOrder, util.SplitComma))
+		for _, name := range pipeline.ExecutionOrder {
+			parameters := pipelineConfig.Functions[name].Parameters
+			if len(parameters) == 0 {
+				continue
+			}
+			if pipeline.Parameters == nil {
+				pipeline.Parameters = make(map[string]map[string]string)
+			}
+			pipeline.Parameters[name] = parameters
+		}
+
+		pipelines[index] = pipeline
+	}
+
+	return pipelines
+}
+
+// PausePipeline implements container.PipelineManager
+func (svc *Service) PausePipeline(id string) error {
+	if err := svc.runtime.PausePipeline(id); err != nil {
+		return err
+	}
+
+	svc.lc.Infof("Function Pipeline '%s' paused", id)
+	return nil
+}
+
+// ResumePipeline implements container.PipelineManager
+func (svc *Service) ResumePipeline(id string) error {
+	if err := svc.runtime.ResumePipeline(id); err != nil {
+		return err
+	}
+
+	svc.lc.Infof("Function Pipeline '%s' resumed", id)
+	return nil
+}
+
+// ReloadPipelines implements container.PipelineManager. The pipelines in use are kept when the configuration
+// is not valid.
+func (svc *Service) ReloadPipelines() error {
+	if !svc.usingConfigurablePipeline {
+		return errors.New("pipelines are not configurable, only pipelines loaded from configuration can be reloaded")
+	}
+
+	svc.reloadMutex.Lock()
+	defer svc.reloadMutex.Unlock()
+
+	transforms, err := svc.LoadConfigurablePipeline()
+	if err != nil {
+		return err
+	}
+
+	if err := svc.SetFunctionsPipeline(transforms...); err != nil {
+		return err
+	}
+
+	svc.lc.Info("Configurable Pipeline successfully reloaded on request")
+	return nil
+}
@@ -0,0 +1,68 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package app
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/common"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/runtime"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReloadPipelines(t *testing.T) {
+	sdk := Service{
+		lc:      lc,
+		runtime: &runtime.GolangRuntime{},
+		config: &common.ConfigurationStruct{
+			Writable: common.WritableInfo{
+				Pipeline: common.PipelineInfo{
+					ExecutionOrder: "FilterByDeviceName, SetResponseData",
+					Functions: map[string]common.PipelineFunction{
+						"FilterByDeviceName": {
+							Parameters: map[string]string{"DeviceNames": "Random-Float-Device"},
+						},
+						"SetResponseData": {},
+					},
+				},
+			},
+		},
+	}
+
+	require.Error(t, sdk.ReloadPipelines(), "only configurable pipelines can be reloaded")
+
+	sdk.usingConfigurablePipeline = true
+	require.NoError(t, sdk.ReloadPipelines())
+
+	pipelines := sdk.Pipelines()
+	require.Len(t, pipelines, 1)
+	assert.Equal(t, interfaces.DefaultPipelineId, pipelines[0].Id)
+	assert.Equal(t, 2, pipelines[0].FunctionCount)
+	assert.Equal(t, []string{"FilterByDeviceName", "SetResponseData"}, pipelines[0].ExecutionOrder)
+	assert.Equal(t, map[string]map[string]string{
+		"FilterByDeviceName": {"DeviceNames": "Random-Float-Device"},
+	}, pipelines[0].Parameters)
+
+	require.NoError(t, sdk.PausePipeline(interfaces.DefaultPipelineId))
+	assert.True(t, sdk.Pipelines()[0].Paused)
+	require.NoError(t, sdk.ResumePipeline(interfaces.DefaultPipelineId))
+	assert.False(t, sdk.Pipelines()[0].Paused)
+	assert.Error(t, sdk.PausePipeline("bogus"))
+}
@@ -0,0 +1,146 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package app
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	bootstrapInterfaces "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/interfaces"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+)
+
+// secretUpdateCheckInterval is how often the secrets are checked for updates once a callback is registered
+const secretUpdateCheckInterval = 5 * time.Second
+
+// secretUpdateWatcher invokes the callbacks registered for secret paths when the secrets at the path are updated
+type secretUpdateWatcher struct {
+	mutex       sync.Mutex
+	callbacks   map[string][]func()
+	values      map[string]map[string]string
+	lastUpdated time.Time
+	started     bool
+}
+
+// RegisterSecretUpdatedCallback registers a callback which is invoked when the secrets at the path are updated
+// in the secret store, i.e. via the /secret endpoint or the InsecureSecrets configuration, so long lived
+// connections can be rebuilt with the new credentials.
+func (svc *Service) RegisterSecretUpdatedCallback(path string, callback func()) error {
+	if len(strings.TrimSpace(path)) == 0 {
+		return errors.New("secret path can not be empty")
+	}
+
+	if callback == nil {
+		return errors.New("secret updated callback can not be nil")
+	}
+
+	secretProvider := bootstrapContainer.SecretProviderFrom(svc.dic.Get)
+	if secretProvider == nil {
+		return errors.New("secret provider is missing")
+	}
+
+	if svc.secretWatcher.register(path, callback, secretProvider) {
+		svc.ctx.appWg.Add(1)
+		go svc.watchSecretUpdates(secretProvider)
+	}
+
+	return nil
+}
+
+func (svc *Service) watchSecretUpdates(secretProvider bootstrapInterfaces.SecretProvider) {
+	defer svc.ctx.appWg.Done()
+
+	svc.lc.Infof("Checking for secret updates every %s", secretUpdateCheckInterval.String())
+
+	for {
+		select {
+		case <-svc.ctx.appCtx.Done():
+			svc.lc.Info("Exiting checking for secret updates")
+			return
+
+		case <-time.After(secretUpdateCheckInterval):
+			svc.secretWatcher.checkForUpdates(secretProvider, svc.lc)
+		}
+	}
+}
+
+// register adds the callback for the path and returns whether the watcher needs to be started
+func (watcher *secretUpdateWatcher) register(
+	path string,
+	callback func(),
+	secretProvider bootstrapInterfaces.SecretProvider) bool {
+	watcher.mutex.Lock()
+	defer watcher.mutex.Unlock()
+
+	if watcher.callbacks == nil {
+		watcher.callbacks = make(map[string][]func())
+		watcher.values = make(map[string]map[string]string)
+		watcher.lastUpdated = secretProvider.SecretsLastUpdated()
+	}
+
+	if _, found := watcher.values[path]; !found {
+		// The secrets may not exist yet, in which case the callback is invoked once they are added
+		values, _ := secretProvider.GetSecret(path)
+		watcher.values[path] = values
+	}
+
+	watcher.callbacks[path] = append(watcher.callbacks[path], callback)
+
+	startWatching := !watcher.started
+	watcher.started = true
+	return startWatching
+}
+
+// checkForUpdates invokes the callbacks for the paths whose secrets changed since the secrets were last updated
+func (watcher *secretUpdateWatcher) checkForUpdates(secretProvider bootstrapInterfaces.SecretProvider, lc logger.LoggingClient) {
+	lastUpdated := secretProvider.SecretsLastUpdated()
+
+	watcher.mutex.Lock()
+	if !lastUpdated.After(watcher.lastUpdated) {
+		watcher.mutex.Unlock()
+		return
+	}
+
+	watcher.lastUpdated = lastUpdated
+
+	var updated []func()
+	for path, callbacks := range watcher.callbacks {
+		values, err := secretProvider.GetSecret(path)
+		if err != nil {
+			lc.Debugf("Unable to get secrets at '%s' to check for updates: %s", path, err.Error())
+			continue
+		}
+
+		if reflect.DeepEqual(values, watcher.values[path]) {
+			continue
+		}
+
+		lc.Infof("Secrets at '%s' have been updated", path)
+		watcher.values[path] = values
+		updated = append(updated, callbacks...)
+	}
+	watcher.mutex.Unlock()
+
+	// Callbacks are invoked without the lock held so they may register further callbacks
+	for _, callback := range updated {
+		callback()
+	}
+}
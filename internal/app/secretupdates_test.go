@@ -0,0 +1,74 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package app
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/interfaces/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterSecretUpdatedCallbackErrors(t *testing.T) {
+	sdk := Service{lc: lc}
+
+	assert.Error(t, sdk.RegisterSecretUpdatedCallback(" ", func() {}))
+	assert.Error(t, sdk.RegisterSecretUpdatedCallback("mqtt", nil))
+}
+
+func TestSecretUpdateWatcher(t *testing.T) {
+	lastUpdated := time.Now()
+	mqttSecrets := map[string]string{"username": "user1", "password": "password1"}
+
+	secretProvider := &mocks.SecretProvider{}
+	secretProvider.On("SecretsLastUpdated").Return(func() time.Time { return lastUpdated })
+	secretProvider.On("GetSecret", "mqtt").Return(
+		func(path string, keys ...string) map[string]string { return mqttSecrets },
+		func(path string, keys ...string) error { return nil })
+	secretProvider.On("GetSecret", "db").Return(nil, errors.New("not found"))
+
+	watcher := secretUpdateWatcher{}
+
+	mqttCalls := 0
+	dbCalls := 0
+	require.True(t, watcher.register("mqtt", func() { mqttCalls++ }, secretProvider))
+	require.False(t, watcher.register("db", func() { dbCalls++ }, secretProvider))
+
+	// Not updated since registered
+	watcher.checkForUpdates(secretProvider, lc)
+	assert.Equal(t, 0, mqttCalls)
+
+	// Updated, but not the secrets at the registered paths
+	lastUpdated = lastUpdated.Add(time.Second)
+	watcher.checkForUpdates(secretProvider, lc)
+	assert.Equal(t, 0, mqttCalls)
+
+	// Secrets at the mqtt path rotated
+	mqttSecrets = map[string]string{"username": "user1", "password": "password2"}
+	lastUpdated = lastUpdated.Add(time.Second)
+	watcher.checkForUpdates(secretProvider, lc)
+	assert.Equal(t, 1, mqttCalls)
+	assert.Equal(t, 0, dbCalls)
+
+	// Already reported
+	watcher.checkForUpdates(secretProvider, lc)
+	assert.Equal(t, 1, mqttCalls)
+}
@@ -18,15 +18,21 @@ package app
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"flag"
 	"fmt"
 	nethttp "net/http"
 	"os"
 	"os/signal"
 	"reflect"
+	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/bootstrap/container"
@@ -56,8 +62,15 @@ import (
 const (
 	envProfile    = "EDGEX_PROFILE"
 	envServiceKey = "EDGEX_SERVICE_KEY"
+	envInstance   = "EDGEX_INSTANCE"
+
+	defaultShutdownTimeout = 30 * time.Second
 )
 
+// parameterReference matches the '${secret:PATH/KEY}' references to secrets and the '${NAME}' references to
+// environment variables in pipeline parameters
+var parameterReference = regexp.MustCompile(`\$\{(?:secret:([^}]+)/([^}/]+)|([A-Za-z_][A-Za-z0-9_]*))\}`)
+
 // NewService create, initializes and returns new instance of app.Service which implements the
 // interfaces.ApplicationService interface
 func NewService(serviceKey string, targetType interface{}, profileSuffixPlaceholder string) *Service {
@@ -77,6 +90,9 @@ type Service struct {
 	config                    *common.ConfigurationStruct
 	lc                        logger.LoggingClient
 	transforms                []interfaces.AppFunction
+	topicPipelines            []runtime.TopicPipeline
+	loadedFunctions           map[string][]loadedPipelineFunction
+	retiredFunctions          []loadedPipelineFunction
 	usingConfigurablePipeline bool
 	runtime                   *runtime.GolangRuntime
 	webserver                 *webserver.WebServer
@@ -84,18 +100,53 @@ type Service struct {
 	deferredFunctions         []bootstrap.Deferred
 	backgroundPublishChannel  <-chan interfaces.BackgroundMessage
 	customTriggerFactories    map[string]func(sdk *Service) (interfaces.Trigger, error)
+	customFunctionFactories   map[string]interfaces.ConfigurableFunctionFactory
+	payloadDecoders           map[string]interfaces.PayloadDecoder
 	profileSuffixPlaceholder  string
 	commandLine               commandLineFlags
+	commandLineArgs           []string
+	customFlags               func(flagSet *flag.FlagSet)
 	flags                     *flags.Default
 	configProcessor           *config.Processor
+	secretWatcher             secretUpdateWatcher
+	triggerReady              int32
+	shutdownHooks             []func()
+	shutdownHooksMutex        sync.Mutex
+	// reloadMutex serializes reloading the configurable pipelines, on request and on configuration changes, so
+	// the pipelines loaded by one reload aren't mixed with those of another
+	reloadMutex sync.Mutex
+	// The result of the last readiness check of the dependent Edgex clients, reused for readyCacheTTL
+	readyMutex     sync.Mutex
+	readyCheckedAt time.Time
+	readyErr       error
+	// version of the application set by SetVersion, which overrides the version set when building
+	version string
+}
+
+// loadedPipelineFunction is a function loaded from the pipeline configuration. Loaded functions are keyed by
+// the function name and the fingerprint of the configuration they were created with, see loadedFunctionKey.
+type loadedPipelineFunction struct {
+	function interfaces.AppFunction
+	// closers release the resources held by the function, i.e. the background worker of a batched export, once
+	// the function is no longer used
+	closers []func()
+}
+
+// close closes the function, which must no longer be used
+func (loaded loadedPipelineFunction) close() {
+	for _, closer := range loaded.closers {
+		closer()
+	}
 }
 
 type commandLineFlags struct {
 	skipVersionCheck   bool
 	serviceKeyOverride string
+	instance           string
 }
 
 type contextGroup struct {
+	parent                context.Context
 	storeForwardWg        *sync.WaitGroup
 	storeForwardCancelCtx context.CancelFunc
 	appWg                 *sync.WaitGroup
@@ -106,14 +157,53 @@ type contextGroup struct {
 
 // AddRoute allows you to leverage the existing webserver to add routes.
 func (svc *Service) AddRoute(route string, handler func(nethttp.ResponseWriter, *nethttp.Request), methods ...string) error {
+	return svc.AddRouteWithMiddleware(route, handler, nil, methods...)
+}
+
+// AddRouteWithMiddleware adds a route to the existing webserver with the handler wrapped by the middleware, the first
+// of which is the outermost.
+func (svc *Service) AddRouteWithMiddleware(
+	route string,
+	handler func(nethttp.ResponseWriter, *nethttp.Request),
+	middleware []interfaces.Middleware,
+	methods ...string) error {
 	if route == commonConstants.ApiPingRoute ||
 		route == commonConstants.ApiConfigRoute ||
 		route == commonConstants.ApiMetricsRoute ||
 		route == commonConstants.ApiVersionRoute ||
-		route == internal.ApiTriggerRoute {
+		route == internal.ApiTriggerRoute ||
+		route == internal.ApiAddSecretRoute ||
+		route == internal.ApiCustomMetricsRoute ||
+		strings.HasPrefix(route, internal.ApiStoreForwardRoute) ||
+		strings.HasPrefix(route, internal.ApiHealthRoute) ||
+		strings.HasPrefix(route, internal.ApiOpenAPIRoute) ||
+		strings.HasPrefix(route, internal.ApiPipelinesRoute) {
 		return errors.New("route is reserved")
 	}
-	return svc.webserver.AddRoute(route, svc.addContext(handler), methods...)
+
+	var wrapped nethttp.Handler = nethttp.HandlerFunc(handler)
+	for index := len(middleware) - 1; index >= 0; index-- {
+		if middleware[index] != nil {
+			wrapped = middleware[index](wrapped)
+		}
+	}
+
+	// The context is added first so the middleware can also retrieve the ApplicationService from it
+	return svc.webserver.AddRoute(route, svc.addContext(wrapped.ServeHTTP), methods...)
+}
+
+// AddMiddleware adds global middleware, which wraps the handlers of all the routes
+func (svc *Service) AddMiddleware(middleware ...interfaces.Middleware) {
+	for _, m := range middleware {
+		if m != nil {
+			svc.webserver.AddMiddleware(mux.MiddlewareFunc(m))
+		}
+	}
+}
+
+// SetOpenAPIDocument serves the OpenAPI document describing the custom routes and optionally the Swagger UI for it
+func (svc *Service) SetOpenAPIDocument(document []byte, enableUI bool) error {
+	return svc.webserver.SetOpenAPIDocument(document, enableUI)
 }
 
 // AddBackgroundPublisher will create a channel of provided capacity to be
@@ -142,7 +232,9 @@ func (svc *Service) AddBackgroundPublisherWithTopic(capacity int, topic string)
 	return pub, nil
 }
 
-// MakeItStop will force the service loop to exit in the same fashion as SIGINT/SIGTERM received from the OS
+// MakeItStop will force the service loop to exit in the same fashion as SIGINT/SIGTERM received from the OS.
+// MakeItRun then gracefully stops the web server and trigger, waits for the pipeline executions in progress to
+// complete within the Trigger ShutdownTimeout and de-registers from the Registry before returning.
 func (svc *Service) MakeItStop() {
 	if svc.ctx.stop != nil {
 		svc.ctx.stop()
@@ -151,21 +243,100 @@ func (svc *Service) MakeItStop() {
 	}
 }
 
+// shutdownTimeout returns how long to wait for the requests and pipeline executions in progress to complete when
+// the service is stopped
+func (svc *Service) shutdownTimeout() time.Duration {
+	if len(strings.TrimSpace(svc.config.Trigger.ShutdownTimeout)) == 0 {
+		return defaultShutdownTimeout
+	}
+
+	timeout, err := time.ParseDuration(svc.config.Trigger.ShutdownTimeout)
+	if err != nil {
+		svc.lc.Warnf("Trigger ShutdownTimeout '%s' is invalid, defaulting to %s: %s",
+			svc.config.Trigger.ShutdownTimeout, defaultShutdownTimeout.String(), err.Error())
+		return defaultShutdownTimeout
+	}
+
+	return timeout
+}
+
+// strictValidation returns the strict validation settings of the configuration. Invalid durations are logged and
+// their defaults used.
+func (svc *Service) strictValidation(config common.StrictValidationInfo) runtime.StrictValidation {
+	settings := runtime.StrictValidation{
+		Enabled:            config.Enabled,
+		ValidateProfiles:   config.ValidateProfiles,
+		ProfileCacheTTL:    runtime.DefaultProfileCacheTTL,
+		DeadLetterDir:      strings.TrimSpace(config.DeadLetterDir),
+		DeadLetterMaxSize:  config.DeadLetterMaxSize,
+		DeadLetterMaxFiles: config.DeadLetterMaxFiles,
+	}
+
+	if len(strings.TrimSpace(config.ProfileCacheTTL)) > 0 {
+		ttl, err := time.ParseDuration(config.ProfileCacheTTL)
+		if err != nil {
+			svc.lc.Warnf("StrictValidation ProfileCacheTTL '%s' is invalid, defaulting to %s: %s",
+				config.ProfileCacheTTL, runtime.DefaultProfileCacheTTL.String(), err.Error())
+		} else if ttl <= 0 {
+			svc.lc.Warnf("StrictValidation ProfileCacheTTL '%s' must be greater than zero, defaulting to %s",
+				config.ProfileCacheTTL, runtime.DefaultProfileCacheTTL.String())
+		} else {
+			settings.ProfileCacheTTL = ttl
+		}
+	}
+
+	if len(strings.TrimSpace(config.MaxOriginSkew)) > 0 {
+		skew, err := time.ParseDuration(config.MaxOriginSkew)
+		if err != nil {
+			svc.lc.Warnf("StrictValidation MaxOriginSkew '%s' is invalid, origins are not validated: %s",
+				config.MaxOriginSkew, err.Error())
+		} else {
+			settings.MaxOriginSkew = skew
+		}
+	}
+
+	return settings
+}
+
 // MakeItRun initializes and starts the trigger as specified in the
 // configuration. It will also configure the webserver and start listening on
 // the specified port.
 func (svc *Service) MakeItRun() error {
-	runCtx, stop := context.WithCancel(context.Background())
+	// The service also stops when the application context is cancelled, i.e. by the parent context set by SetContext
+	runParent := svc.ctx.appCtx
+	if runParent == nil {
+		runParent = svc.parentContext()
+	}
+	runCtx, stop := context.WithCancel(runParent)
 
 	svc.ctx.stop = stop
 
 	svc.runtime = &runtime.GolangRuntime{
-		TargetType: svc.targetType,
-		ServiceKey: svc.serviceKey,
+		TargetType:    svc.targetType,
+		ServiceKey:    svc.serviceKey,
+		Instance:      svc.commandLine.instance,
+		V1ProfileName: svc.config.Writable.Pipeline.V1ProfileName,
 	}
 
 	svc.runtime.Initialize(svc.dic)
-	svc.runtime.SetTransforms(svc.transforms)
+	svc.runtime.SetPipelines(svc.transforms, svc.topicPipelines, svc.targetType)
+	for contentType, decoder := range svc.payloadDecoders {
+		svc.runtime.RegisterPayloadDecoder(contentType, decoder)
+	}
+	svc.runtime.SetConcurrency(svc.config.Writable.Pipeline.MinConcurrency, svc.config.Writable.Pipeline.MaxConcurrency)
+	svc.runtime.SetStrictValidation(svc.strictValidation(svc.config.Writable.Pipeline.StrictValidation))
+
+	svc.dic.Update(di.ServiceConstructorMap{
+		container.StoreForwardManagerName: func(get di.Get) interface{} {
+			return svc.runtime
+		},
+		container.HealthCheckerName: func(get di.Get) interface{} {
+			return svc
+		},
+		container.PipelineManagerName: func(get di.Get) interface{} {
+			return svc
+		},
+	})
 
 	// determine input type and create trigger for it
 	t := svc.setupTrigger(svc.config, svc.runtime)
@@ -182,6 +353,7 @@ func (svc *Service) MakeItRun() error {
 
 	// deferred is a a function that needs to be called when services exits.
 	svc.addDeferred(deferred)
+	svc.setTriggerReady(true)
 
 	if svc.config.Writable.StoreAndForward.Enabled {
 		svc.startStoreForward()
@@ -208,10 +380,28 @@ func (svc *Service) MakeItRun() error {
 		svc.lc.Info("Terminating signal received: " + signalReceived.String())
 
 	case <-runCtx.Done():
-		svc.lc.Info("Terminating: svc.MakeItStop called")
+		svc.lc.Info("Terminating: svc.MakeItStop called or service context cancelled")
 	}
 
+	stop()
+
 	svc.ctx.stop = nil
+	svc.setTriggerReady(false)
+
+	// Reject new requests with 503 while shutting down, so clients retry elsewhere rather than being reset
+	svc.webserver.StartDraining()
+
+	// Deregister first so the Registry stops directing traffic to this instance while it shuts down
+	svc.unregisterFromRegistry()
+
+	shutdownTimeout := svc.shutdownTimeout()
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelShutdown()
+
+	// Stops accepting requests, including those for the HTTP trigger, and waits for those in progress
+	if stopErr := svc.webserver.StopWebServer(shutdownCtx); stopErr != nil {
+		svc.lc.Warnf("Web Server did not stop gracefully: %s", stopErr.Error())
+	}
 
 	if svc.config.Writable.StoreAndForward.Enabled {
 		svc.ctx.storeForwardCancelCtx()
@@ -220,6 +410,19 @@ func (svc *Service) MakeItRun() error {
 
 	svc.ctx.appCancelCtx() // Cancel all long running go funcs
 	svc.ctx.appWg.Wait()
+
+	// The triggers have stopped, so wait for the pipeline executions in progress to complete, which may store
+	// data for later retry, before disconnecting from the Message Bus and the Database
+	if !svc.runtime.WaitForInFlight(shutdownTimeout) {
+		svc.lc.Warnf("Pipeline executions in progress did not complete within %s", shutdownTimeout.String())
+	}
+	svc.runtime.Stop()
+
+	// Sends the data pending in batched exports before the Message Bus and the Database are disconnected
+	svc.closePipelineFunctions()
+
+	svc.runShutdownHooks()
+
 	// Call all the deferred funcs that need to happen when exiting.
 	// These are things like un-register from the Registry, disconnect from the Message Bus, etc
 	for _, deferredFunc := range svc.deferredFunctions {
@@ -229,100 +432,421 @@ func (svc *Service) MakeItRun() error {
 	return err
 }
 
-// LoadConfigurablePipeline sets the function pipeline from configuration
-func (svc *Service) LoadConfigurablePipeline() ([]interfaces.AppFunction, error) {
-	var pipeline []interfaces.AppFunction
+// unregisterFromRegistry removes the service from the Registry, if used, so dead instances don't accumulate
+// in the Registry after the service is redeployed
+func (svc *Service) unregisterFromRegistry() {
+	registryClient := bootstrapContainer.RegistryFrom(svc.dic.Get)
+	if registryClient == nil {
+		return
+	}
 
-	svc.usingConfigurablePipeline = true
+	if err := registryClient.Unregister(); err != nil {
+		svc.lc.Warnf("Unable to unregister %s from the Registry: %s", svc.serviceKey, err.Error())
+		return
+	}
+
+	svc.lc.Infof("Unregistered %s from the Registry", svc.serviceKey)
+}
 
-	svc.targetType = nil
+// SetVersion sets the version of the application, which is reported by the /version endpoint
+func (svc *Service) SetVersion(version string) {
+	svc.version = version
+}
 
-	if svc.config.Writable.Pipeline.UseTargetTypeOfByteArray {
-		svc.targetType = &[]byte{}
+// ApplicationVersion implements container.VersionProvider, returning the version set by SetVersion or otherwise the
+// version set when building the application
+func (svc *Service) ApplicationVersion() string {
+	if len(svc.version) > 0 {
+		return svc.version
 	}
+	return internal.ApplicationVersion
+}
 
-	configurable := reflect.ValueOf(NewConfigurable(svc.lc))
+// LoadConfigurablePipeline sets the function pipeline from configuration. Any pipelines from the
+// PerTopicPipelines section are also loaded and used for the data received on their topics.
+// All the pipelines are validated before an error is returned, so the error lists every problem found.
+func (svc *Service) LoadConfigurablePipeline() ([]interfaces.AppFunction, error) {
+	svc.usingConfigurablePipeline = true
+
+	loader := newPipelineLoader(svc.lc)
 	pipelineConfig := svc.config.Writable.Pipeline
+
+	var targetType interface{}
+	switch strings.ToLower(strings.TrimSpace(pipelineConfig.TargetType)) {
+	case "":
+		if pipelineConfig.UseTargetTypeOfByteArray {
+			targetType = &[]byte{}
+		}
+	case common.PipelineTargetTypeEvent:
+		// nil TargetType defaults to an Event
+	case common.PipelineTargetTypeRaw:
+		targetType = &[]byte{}
+	default:
+		loader.addProblem("", fmt.Errorf(
+			"invalid pipeline TargetType '%s', must be '%s' or '%s'",
+			pipelineConfig.TargetType,
+			common.PipelineTargetTypeEvent,
+			common.PipelineTargetTypeRaw))
+	}
+
+	ids := make([]string, 0, len(pipelineConfig.PerTopicPipelines))
+	for id := range pipelineConfig.PerTopicPipelines {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	topicPipelines := make([]runtime.TopicPipeline, 0, len(ids))
+	for _, id := range ids {
+		if id == interfaces.DefaultPipelineId {
+			loader.addProblem(id, fmt.Errorf("'%s' is reserved for the default pipeline", id))
+		}
+
+		topicPipelineConfig := pipelineConfig.PerTopicPipelines[id]
+		topics := util.DeleteEmptyAndTrim(strings.FieldsFunc(topicPipelineConfig.Topics, util.SplitComma))
+		if len(topics) == 0 {
+			loader.addProblem(id, errors.New("no topics specified"))
+		}
+
+		executionOrder := util.DeleteEmptyAndTrim(strings.FieldsFunc(topicPipelineConfig.ExecutionOrder, util.SplitComma))
+		if len(executionOrder) == 0 {
+			loader.addProblem(id, errors.New(
+				"execution Order has 0 functions specified. You must have a least one function in the pipeline"))
+		}
+
+		svc.lc.Debugf("Function Pipeline '%s' Topics: [%s] Execution Order: [%s]",
+			id, topicPipelineConfig.Topics, topicPipelineConfig.ExecutionOrder)
+
+		topicPipelines = append(topicPipelines, runtime.TopicPipeline{
+			Id:         id,
+			Topics:     topics,
+			Transforms: svc.loadPipelineFunctions(loader, id, executionOrder),
+		})
+	}
+
 	executionOrder := util.DeleteEmptyAndTrim(strings.FieldsFunc(pipelineConfig.ExecutionOrder, util.SplitComma))
 
-	if len(executionOrder) <= 0 {
-		return nil, errors.New(
-			"execution Order has 0 functions specified. You must have a least one function in the pipeline")
+	// The default pipeline is optional when all the data is processed by per topic pipelines
+	if len(executionOrder) <= 0 && len(topicPipelines) == 0 {
+		loader.addProblem("", errors.New(
+			"execution Order has 0 functions specified. You must have a least one function in the pipeline"))
 	}
 
 	svc.lc.Debugf("Function Pipeline Execution Order: [%s]", pipelineConfig.ExecutionOrder)
 
-	for _, functionName := range executionOrder {
+	pipeline := svc.loadPipelineFunctions(loader, "", executionOrder)
+
+	if err := loader.err(); err != nil {
+		// The functions created for the pipeline which won't be used are closed
+		for key, loaded := range loader.loaded {
+			for index := len(svc.loadedFunctions[key]); index < len(loaded); index++ {
+				loaded[index].close()
+			}
+		}
+		return nil, err
+	}
+
+	// The functions of the previous pipeline which aren't reused are closed once the new pipeline is set
+	for key, previous := range svc.loadedFunctions {
+		for index := len(loader.loaded[key]); index < len(previous); index++ {
+			svc.retiredFunctions = append(svc.retiredFunctions, previous[index])
+		}
+	}
+
+	svc.targetType = targetType
+	svc.topicPipelines = topicPipelines
+	svc.loadedFunctions = loader.loaded
+
+	return pipeline, nil
+}
+
+// loadPipelineFunctions creates the functions, from the Functions section of the pipeline configuration, in the
+// execution order specified. Functions whose configuration hasn't changed since the pipeline was last loaded are
+// reused rather than created again, so that their state, i.e. in-flight batches, is preserved when only the
+// parameters of other functions are changed or the functions are moved in the execution order. When the same
+// configuration is used several times, the previous instances are reused in the order they are found. Problems
+// found are added to the loader rather than returned.
+func (svc *Service) loadPipelineFunctions(
+	loader *pipelineLoader,
+	pipelineId string,
+	executionOrder []string) []interfaces.AppFunction {
+	var pipeline []interfaces.AppFunction
+	var gated []bool
+	pipelineConfig := svc.config.Writable.Pipeline
+
+	for position, functionName := range executionOrder {
 		functionName = strings.TrimSpace(functionName)
 		configuration, ok := pipelineConfig.Functions[functionName]
 		if !ok {
-			return nil, fmt.Errorf("function '%s' configuration not found in Pipeline.Functions section", functionName)
+			loader.addProblem(pipelineId,
+				fmt.Errorf("function '%s' configuration not found in Pipeline.Functions section", functionName))
+			continue
 		}
 
-		functionValue, functionType, err := svc.findMatchingFunction(configurable, functionName)
-		if err != nil {
-			return nil, err
+		if configuration.ExecuteOnlyIfMatched && position == 0 {
+			loader.addProblem(pipelineId,
+				fmt.Errorf("function '%s' is set to execute only if matched but isn't preceded by a function to match", functionName))
+			continue
 		}
 
-		// determine number of parameters required for function call
-		inputParameters := make([]reflect.Value, functionType.NumIn())
 		// set keys to be all lowercase to avoid casing issues from configuration
 		for key := range configuration.Parameters {
 			value := configuration.Parameters[key]
 			delete(configuration.Parameters, key) // Make sure the old key has been removed so don't have multiples
 			configuration.Parameters[strings.ToLower(key)] = value
 		}
-		for index := range inputParameters {
-			parameter := functionType.In(index)
-
-			switch parameter {
-			case reflect.TypeOf(map[string]string{}):
-				inputParameters[index] = reflect.ValueOf(configuration.Parameters)
-
-			default:
-				return nil, fmt.Errorf(
-					"function %s has an unsupported parameter type: %s",
-					functionName,
-					parameter.String(),
-				)
-			}
+
+		parameters, err := svc.resolveParameterReferences(configuration.Parameters)
+		if err != nil {
+			loader.addProblem(pipelineId, fmt.Errorf("%s from configuration failed: %s", functionName, err.Error()))
+			continue
 		}
 
-		function, ok := functionValue.Call(inputParameters)[0].Interface().(interfaces.AppFunction)
-		if !ok {
-			return nil, fmt.Errorf("failed to cast function %s as AppFunction type", functionName)
+		key := loadedFunctionKey(
+			functionName,
+			pipelineFunctionFingerprint(configuration.Name, configuration.Parameters, parameters))
+		if previous, reuse := svc.loadedFunctions[key], len(loader.loaded[key]); reuse < len(previous) {
+			pipeline = append(pipeline, previous[reuse].function)
+			gated = append(gated, configuration.ExecuteOnlyIfMatched)
+			loader.loaded[key] = append(loader.loaded[key], previous[reuse])
+			svc.lc.Debugf("%s function configuration unchanged, reusing existing function", functionName)
+			continue
 		}
 
-		if function == nil {
-			return nil, fmt.Errorf("%s from configuration failed", functionName)
+		// The configuration may be an alias for the function it specifies the name of
+		if name := strings.TrimSpace(configuration.Name); len(name) > 0 {
+			svc.lc.Debugf("%s is an alias for the %s function", functionName, name)
+			functionName = name
+		}
+
+		function, err := svc.createPipelineFunction(loader, functionName, parameters)
+		closers := loader.builtIns.takeClosers()
+		if err != nil {
+			loader.addProblem(pipelineId, err)
+			continue
 		}
 
 		pipeline = append(pipeline, function)
+		gated = append(gated, configuration.ExecuteOnlyIfMatched)
+		loader.loaded[key] = append(loader.loaded[key], loadedPipelineFunction{function: function, closers: closers})
 		svc.lc.Debugf(
 			"%s function added to configurable pipeline with parameters: [%s]",
 			functionName,
 			listParameters(configuration.Parameters))
 	}
 
-	return pipeline, nil
+	return gatePipelineFunctions(pipeline, gated)
+}
+
+// createPipelineFunction creates the custom or built in function with the parameters specified
+func (svc *Service) createPipelineFunction(
+	loader *pipelineLoader,
+	functionName string,
+	parameters map[string]string) (interfaces.AppFunction, error) {
+	if factory, found := svc.findCustomFunctionFactory(functionName); found {
+		function, err := factory(parameters)
+		if err != nil {
+			return nil, fmt.Errorf("%s from configuration failed: %s", functionName, err.Error())
+		}
+
+		if function == nil {
+			return nil, fmt.Errorf("%s from configuration failed", functionName)
+		}
+
+		return function, nil
+	}
+
+	functionValue, functionType, err := svc.findMatchingFunction(loader.configurable, functionName)
+	if err != nil {
+		return nil, err
+	}
+
+	// determine number of parameters required for function call
+	inputParameters := make([]reflect.Value, functionType.NumIn())
+	for index := range inputParameters {
+		parameter := functionType.In(index)
+
+		switch parameter {
+		case reflect.TypeOf(map[string]string{}):
+			inputParameters[index] = reflect.ValueOf(parameters)
+
+		default:
+			return nil, fmt.Errorf(
+				"function %s has an unsupported parameter type: %s",
+				functionName,
+				parameter.String(),
+			)
+		}
+	}
+
+	// Built in functions log the reason they failed to be created, which is captured for the error returned
+	loader.errorLogger.take()
+	function, ok := functionValue.Call(inputParameters)[0].Interface().(interfaces.AppFunction)
+	if !ok {
+		return nil, fmt.Errorf("failed to cast function %s as AppFunction type", functionName)
+	}
+
+	if function == nil {
+		if reasons := loader.errorLogger.take(); len(reasons) > 0 {
+			return nil, fmt.Errorf("%s from configuration failed: %s", functionName, strings.Join(reasons, "; "))
+		}
+		return nil, fmt.Errorf("%s from configuration failed", functionName)
+	}
+
+	return function, nil
+}
+
+// loadedFunctionKey returns the key of a loaded function, which is the same wherever the function is used in
+// the pipelines as long as its configuration doesn't change
+func loadedFunctionKey(functionName string, fingerprint string) string {
+	return functionName + "\n" + fingerprint
+}
+
+// pipelineFunctionFingerprint returns a value which changes when the function name or any of the parameters
+// a function is configured with change, including the values of the environment variables and secrets the
+// parameters reference once resolved. The resolved parameters are only included as a hash, so the fingerprint
+// kept for the function doesn't contain the secrets.
+func pipelineFunctionFingerprint(name string, configured map[string]string, resolved map[string]string) string {
+	fingerprint := strings.TrimSpace(name)
+	for _, key := range sortedParameterNames(configured) {
+		fingerprint += fmt.Sprintf("\n%s=%s", key, configured[key])
+	}
+
+	hash := sha256.New()
+	for _, key := range sortedParameterNames(resolved) {
+		_, _ = fmt.Fprintf(hash, "%q=%q\n", key, resolved[key])
+	}
+
+	return fingerprint + "\nresolved=" + hex.EncodeToString(hash.Sum(nil))
+}
+
+func sortedParameterNames(parameters map[string]string) []string {
+	names := make([]string, 0, len(parameters))
+	for name := range parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RegisterCustomConfigurableFunction registers a factory for a custom function to be used in the configurable
+// pipeline. As with the built in functions, a function name in the pipeline configuration which starts with the
+// registered name uses the factory, so the same function can be configured multiple times, i.e. MyTransform1.
+func (svc *Service) RegisterCustomConfigurableFunction(name string, factory interfaces.ConfigurableFunctionFactory) error {
+	name = strings.TrimSpace(name)
+	if len(name) == 0 {
+		return errors.New("custom configurable function name can not be empty")
+	}
+
+	if factory == nil {
+		return fmt.Errorf("factory for custom configurable function %s can not be nil", name)
+	}
+
+	if _, found := reflect.TypeOf(&Configurable{}).MethodByName(name); found {
+		return fmt.Errorf("cannot register custom configurable function for built in function (%s)", name)
+	}
+
+	if _, found := svc.customFunctionFactories[name]; found {
+		return fmt.Errorf("custom configurable function %s is already registered", name)
+	}
+
+	if svc.customFunctionFactories == nil {
+		svc.customFunctionFactories = make(map[string]interfaces.ConfigurableFunctionFactory, 1)
+	}
+
+	svc.customFunctionFactories[name] = factory
+
+	return nil
+}
+
+// RegisterPayloadDecoder registers the decoder of the payloads received with the content type, so custom payload
+// formats can be processed by the pipeline.
+// An error is returned if the content type is empty, built in or already registered.
+func (svc *Service) RegisterPayloadDecoder(contentType string, decoder interfaces.PayloadDecoder) error {
+	if len(strings.TrimSpace(contentType)) == 0 {
+		return errors.New("payload decoder content type can not be empty")
+	}
+
+	if decoder == nil {
+		return fmt.Errorf("payload decoder for content type %s can not be nil", contentType)
+	}
+
+	if runtime.IsBuiltInPayloadContentType(contentType) {
+		return fmt.Errorf("cannot register payload decoder for built in content type (%s)", contentType)
+	}
+
+	mediaType := runtime.PayloadMediaType(contentType)
+	if _, found := svc.payloadDecoders[mediaType]; found {
+		return fmt.Errorf("payload decoder for content type %s is already registered", mediaType)
+	}
+
+	if svc.payloadDecoders == nil {
+		svc.payloadDecoders = make(map[string]interfaces.PayloadDecoder, 1)
+	}
+
+	svc.payloadDecoders[mediaType] = decoder
+
+	// Applied when MakeItRun creates the runtime if not running yet
+	if svc.runtime != nil {
+		svc.runtime.RegisterPayloadDecoder(mediaType, decoder)
+	}
+
+	return nil
+}
+
+// findCustomFunctionFactory returns the factory for the longest registered custom function name the function
+// name starts with, so that custom functions take precedence over built in functions with a shorter name.
+func (svc *Service) findCustomFunctionFactory(functionName string) (interfaces.ConfigurableFunctionFactory, bool) {
+	var matchedName string
+	for name := range svc.customFunctionFactories {
+		if strings.HasPrefix(functionName, name) && len(name) > len(matchedName) {
+			matchedName = name
+		}
+	}
+
+	if len(matchedName) == 0 {
+		return nil, false
+	}
+
+	return svc.customFunctionFactories[matchedName], true
 }
 
 // SetFunctionsPipeline sets the function pipeline to the list of specified functions in the order provided.
 func (svc *Service) SetFunctionsPipeline(transforms ...interfaces.AppFunction) error {
-	if len(transforms) == 0 {
+	if len(transforms) == 0 && len(svc.topicPipelines) == 0 {
 		return errors.New("no transforms provided to pipeline")
 	}
 
 	svc.transforms = transforms
 
 	if svc.runtime != nil {
-		svc.runtime.SetTransforms(transforms)
-		svc.runtime.TargetType = svc.targetType
+		svc.runtime.V1ProfileName = svc.config.Writable.Pipeline.V1ProfileName
+		svc.runtime.SetPipelines(transforms, svc.topicPipelines, svc.targetType)
 	}
 
+	svc.closeRetiredFunctions()
+
 	return nil
 }
 
+// closeRetiredFunctions closes the functions of the previous configurable pipeline which aren't used by the
+// pipeline loaded since
+func (svc *Service) closeRetiredFunctions() {
+	for _, retired := range svc.retiredFunctions {
+		retired.close()
+	}
+	svc.retiredFunctions = nil
+}
+
+// closePipelineFunctions closes all the functions loaded from the pipeline configuration when the service stops
+func (svc *Service) closePipelineFunctions() {
+	svc.closeRetiredFunctions()
+	for _, loaded := range svc.loadedFunctions {
+		for _, function := range loaded {
+			function.close()
+		}
+	}
+}
+
 // ApplicationSettings returns the values specified in the custom configuration section.
 func (svc *Service) ApplicationSettings() map[string]string {
 	return svc.config.ApplicationSettings
@@ -358,39 +882,96 @@ func (svc *Service) GetAppSettingStrings(setting string) ([]string, error) {
 	return valueStrings, nil
 }
 
-// Initialize bootstraps the service making it ready to accept functions for the pipeline and to run the configured trigger.
-func (svc *Service) Initialize() error {
-	startupTimer := startup.NewStartUpTimer(svc.serviceKey)
+// SetContext sets the context the service runs under, so the service stops, as if MakeItStop was called, when
+// the context is cancelled. Must be called before Initialize.
+func (svc *Service) SetContext(ctx context.Context) {
+	svc.ctx.parent = ctx
+}
 
+// parentContext returns the context set by SetContext, if any, which all the contexts used by the service
+// are derived from
+func (svc *Service) parentContext() context.Context {
+	if svc.ctx.parent == nil {
+		return context.Background()
+	}
+	return svc.ctx.parent
+}
+
+// SetCommandLineArgs sets the command line arguments parsed by Initialize rather than those the process was
+// started with
+func (svc *Service) SetCommandLineArgs(args []string) {
+	svc.commandLineArgs = args
+}
+
+// SetCustomFlags sets the function which registers the application's own command line flags, so they are
+// parsed by Initialize along with the service's flags rather than rejected as unknown
+func (svc *Service) SetCustomFlags(register func(flagSet *flag.FlagSet)) {
+	svc.customFlags = register
+}
+
+// RemainingArgs returns the command line arguments remaining after the flags have been parsed by Initialize
+func (svc *Service) RemainingArgs() []string {
+	if svc.flags == nil {
+		return nil
+	}
+	return svc.flags.FlagSet.Args()
+}
+
+// parseCommandLine parses the service's command line flags, along with the application's custom flags, from the
+// arguments set by SetCommandLineArgs or those the process was started with
+func (svc *Service) parseCommandLine() {
 	additionalUsage :=
 		"    -s/--skipVersionCheck           Indicates the service should skip the Core Service's version compatibility check.\n" +
 			"    -sk/--serviceKey                Overrides the service service key used with Registry and/or Configuration Providers.\n" +
 			"                                    If the name provided contains the text `<profile>`, this text will be replaced with\n" +
-			"                                    the name of the profile used."
+			"                                    the name of the profile used.\n" +
+			"    -i/--instance                   Indicates the instance of the service, which is appended to the service key so\n" +
+			"                                    multiple instances of the same service can run with their own configuration.\n" +
+			"                                    Can also be set with the EDGEX_INSTANCE environment variable."
 
 	svc.flags = flags.NewWithUsage(additionalUsage)
 	svc.flags.FlagSet.BoolVar(&svc.commandLine.skipVersionCheck, "skipVersionCheck", false, "")
 	svc.flags.FlagSet.BoolVar(&svc.commandLine.skipVersionCheck, "s", false, "")
 	svc.flags.FlagSet.StringVar(&svc.commandLine.serviceKeyOverride, "serviceKey", "", "")
 	svc.flags.FlagSet.StringVar(&svc.commandLine.serviceKeyOverride, "sk", "", "")
+	svc.flags.FlagSet.StringVar(&svc.commandLine.instance, "instance", "", "")
+	svc.flags.FlagSet.StringVar(&svc.commandLine.instance, "i", "", "")
 
-	svc.flags.Parse(os.Args[1:])
+	if svc.customFlags != nil {
+		svc.customFlags(svc.flags.FlagSet)
+	}
+
+	args := svc.commandLineArgs
+	if args == nil {
+		args = os.Args[1:]
+	}
+	svc.flags.Parse(args)
+}
+
+// Initialize bootstraps the service making it ready to accept functions for the pipeline and to run the configured trigger.
+func (svc *Service) Initialize() error {
+	startupTimer := startup.NewStartUpTimer(svc.serviceKey)
+
+	svc.parseCommandLine()
 
 	// Temporarily setup logging to STDOUT so the client can be used before bootstrapping is completed
 	svc.lc = logger.NewClient(svc.serviceKey, models.InfoLog)
 
 	svc.setServiceKey(svc.flags.Profile())
 
-	svc.lc.Info(fmt.Sprintf("Starting %s %s ", svc.serviceKey, internal.ApplicationVersion))
+	svc.lc.Info(fmt.Sprintf("Starting %s %s ", svc.serviceKey, svc.ApplicationVersion()))
 
 	svc.config = &common.ConfigurationStruct{}
 	svc.dic = di.NewContainer(di.ServiceConstructorMap{
 		container.ConfigurationName: func(get di.Get) interface{} {
 			return svc.config
 		},
+		container.VersionProviderName: func(get di.Get) interface{} {
+			return svc
+		},
 	})
 
-	svc.ctx.appCtx, svc.ctx.appCancelCtx = context.WithCancel(context.Background())
+	svc.ctx.appCtx, svc.ctx.appCancelCtx = context.WithCancel(svc.parentContext())
 	svc.ctx.appWg = &sync.WaitGroup{}
 
 	var deferred bootstrap.Deferred
@@ -409,9 +990,11 @@ func (svc *Service) Initialize() error {
 		svc.dic,
 		true,
 		[]bootstrapInterfaces.BootstrapHandler{
+			handlers.NewLogSampling().BootstrapHandler,
 			handlers.NewDatabase().BootstrapHandler,
 			handlers.NewClients().BootstrapHandler,
-			handlers.NewTelemetry().BootstrapHandler,
+			handlers.NewHttpClient().BootstrapHandler,
+			handlers.NewTelemetry(svc.serviceKey, svc.commandLine.instance).BootstrapHandler,
 			handlers.NewVersionValidator(svc.commandLine.skipVersionCheck, internal.SDKVersion).BootstrapHandler,
 		},
 	)
@@ -445,7 +1028,25 @@ func (svc *Service) LoadCustomConfig(customConfig interfaces.UpdatableConfig, se
 	if svc.configProcessor == nil {
 		svc.configProcessor = config.NewProcessorForCustomConfig(svc.flags, svc.ctx.appCtx, svc.ctx.appWg, svc.dic)
 	}
-	return svc.configProcessor.LoadCustomConfigSection(customConfig, sectionName)
+	if err := svc.configProcessor.LoadCustomConfigSection(customConfig, sectionName); err != nil {
+		return err
+	}
+
+	return validateCustomConfig(customConfig, sectionName)
+}
+
+// validateCustomConfig validates the custom configuration if it implements interfaces.ValidatableConfig
+func validateCustomConfig(customConfig interfaces.UpdatableConfig, sectionName string) error {
+	validatable, ok := customConfig.(interfaces.ValidatableConfig)
+	if !ok {
+		return nil
+	}
+
+	if err := validatable.Validate(); err != nil {
+		return fmt.Errorf("custom configuration section '%s' failed validation: %w", sectionName, err)
+	}
+
+	return nil
 }
 
 // ListenForCustomConfigChanges uses the Config Processor from go-mod-bootstrap to attempt to listen for
@@ -479,6 +1080,11 @@ func (svc *Service) LoggingClient() logger.LoggingClient {
 	return svc.lc
 }
 
+// MetricsManager returns the manager used to register custom metrics from the dependency injection container
+func (svc *Service) MetricsManager() interfaces.MetricsManager {
+	return container.MetricsManagerFrom(svc.dic.Get)
+}
+
 // RegistryClient returns the Registry client, which may be nil, from the dependency injection container
 func (svc *Service) RegistryClient() registry.Client {
 	return bootstrapContainer.RegistryFrom(svc.dic.Get)
@@ -519,6 +1125,53 @@ func (svc *Service) SubscriptionClient() clientInterfaces.SubscriptionClient {
 	return container.SubscriptionClientFrom(svc.dic.Get)
 }
 
+// resolveParameterReferences returns a copy of the parameters with the '${NAME}' references in their values replaced
+// by the value of the NAME environment variable and the '${secret:PATH/KEY}' references replaced by the KEY secret
+// at PATH from the secret provider, so host specific values and credentials don't need to be embedded in the
+// configuration. Only the references in the configured values are resolved, so references in the values of the
+// environment variables and secrets are left as is. An error is returned if a referenced environment variable isn't
+// set or a referenced secret can't be retrieved.
+func (svc *Service) resolveParameterReferences(parameters map[string]string) (map[string]string, error) {
+	if parameters == nil {
+		return nil, nil
+	}
+
+	resolved := make(map[string]string, len(parameters))
+	for key, value := range parameters {
+		var missing []string
+		var secretErr error
+		resolved[key] = parameterReference.ReplaceAllStringFunc(value, func(reference string) string {
+			match := parameterReference.FindStringSubmatch(reference)
+			if name := match[3]; len(name) > 0 {
+				envValue, found := os.LookupEnv(name)
+				if !found {
+					missing = append(missing, name)
+				}
+				return envValue
+			}
+
+			secretPath, secretName := match[1], match[2]
+			secrets, err := svc.GetSecret(secretPath, secretName)
+			if err != nil {
+				secretErr = fmt.Errorf(
+					"unable to get secret '%s' at '%s' referenced by '%s' parameter: %s", secretName, secretPath, key, err.Error())
+				return ""
+			}
+			return secrets[secretName]
+		})
+
+		if secretErr != nil {
+			return nil, secretErr
+		}
+		if len(missing) > 0 {
+			return nil, fmt.Errorf(
+				"environment variable(s) '%s' referenced by '%s' parameter not set", strings.Join(missing, ", "), key)
+		}
+	}
+
+	return resolved, nil
+}
+
 func listParameters(parameters map[string]string) string {
 	result := ""
 	first := true
@@ -542,6 +1195,30 @@ func (svc *Service) addContext(next func(nethttp.ResponseWriter, *nethttp.Reques
 	}
 }
 
+// RegisterCustomShutdownHook registers a function which is called when the service is stopping, once the pipeline
+// executions in progress have completed and before the service disconnects from the Message Bus and the Database.
+// The hooks are called in the reverse order they were registered.
+func (svc *Service) RegisterCustomShutdownHook(hook func()) {
+	if hook == nil {
+		return
+	}
+
+	svc.shutdownHooksMutex.Lock()
+	svc.shutdownHooks = append(svc.shutdownHooks, hook)
+	svc.shutdownHooksMutex.Unlock()
+}
+
+func (svc *Service) runShutdownHooks() {
+	svc.shutdownHooksMutex.Lock()
+	hooks := svc.shutdownHooks
+	svc.shutdownHooks = nil
+	svc.shutdownHooksMutex.Unlock()
+
+	for index := len(hooks) - 1; index >= 0; index-- {
+		hooks[index]()
+	}
+}
+
 func (svc *Service) addDeferred(deferred bootstrap.Deferred) {
 	if deferred != nil {
 		svc.deferredFunctions = append(svc.deferredFunctions, deferred)
@@ -563,6 +1240,24 @@ func (svc *Service) setServiceKey(profile string) {
 		svc.serviceKey = svc.commandLine.serviceKeyOverride
 	}
 
+	svc.replaceProfilePlaceholder(profile)
+
+	// instance may have been set by the -i/--instance command-line option and not the environment variable
+	if envValue := os.Getenv(envInstance); len(envValue) > 0 {
+		svc.commandLine.instance = envValue
+		svc.lc.Info(
+			fmt.Sprintf("Environment override of '-i/--instance' by environment variable: %s=%s",
+				envInstance,
+				envValue))
+	}
+
+	// The instance, if specified, is appended last so each instance of the same service has its own service key
+	if len(svc.commandLine.instance) > 0 {
+		svc.serviceKey = svc.serviceKey + "-" + svc.commandLine.instance
+	}
+}
+
+func (svc *Service) replaceProfilePlaceholder(profile string) {
 	if !strings.Contains(svc.serviceKey, svc.profileSuffixPlaceholder) {
 		// No placeholder, so nothing to do here
 		return
@@ -17,13 +17,21 @@
 package app
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"github.com/google/uuid"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"reflect"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/appfunction"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/bootstrap/container"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/common"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/runtime"
@@ -33,9 +41,11 @@ import (
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
 
 	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/interfaces/mocks"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
 	clients "github.com/edgexfoundry/go-mod-core-contracts/v2/clients/http"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-registry/v2/registry"
 
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
@@ -90,6 +100,96 @@ func TestAddRoute(t *testing.T) {
 
 }
 
+func TestAddRouteReserved(t *testing.T) {
+	sdk := Service{
+		webserver: webserver.NewWebServer(dic, mux.NewRouter()),
+	}
+
+	handler := func(http.ResponseWriter, *http.Request) {}
+	for _, route := range []string{
+		internal.ApiTriggerRoute,
+		internal.ApiAddSecretRoute,
+		internal.ApiCustomMetricsRoute,
+		internal.ApiStoreForwardRetryRoute,
+		internal.ApiOpenAPIUIRoute,
+	} {
+		assert.Error(t, sdk.AddRoute(route, handler, http.MethodGet), route)
+	}
+}
+
+func TestAddRouteWithMiddleware(t *testing.T) {
+	router := mux.NewRouter()
+	sdk := Service{
+		webserver: webserver.NewWebServer(dic, router),
+	}
+
+	var calls []string
+	middleware := func(name string) interfaces.Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				assert.NotNil(t, request.Context().Value(interfaces.AppServiceContextKey), name)
+				calls = append(calls, name)
+				next.ServeHTTP(writer, request)
+			})
+		}
+	}
+	denied := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+			writer.WriteHeader(http.StatusForbidden)
+		})
+	}
+	handler := func(http.ResponseWriter, *http.Request) { calls = append(calls, "handler") }
+
+	sdk.AddMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			calls = append(calls, "global")
+			next.ServeHTTP(writer, request)
+		})
+	})
+	require.NoError(t, sdk.AddRouteWithMiddleware("/admin", handler,
+		[]interfaces.Middleware{middleware("first"), nil, middleware("second")}, http.MethodGet))
+	require.NoError(t, sdk.AddRouteWithMiddleware("/denied", handler, []interfaces.Middleware{denied}, http.MethodGet))
+	assert.Error(t, sdk.AddRouteWithMiddleware(internal.ApiTriggerRoute, handler, nil, http.MethodPost))
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/admin", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, []string{"global", "first", "second", "handler"}, calls)
+
+	calls = nil
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/denied", nil))
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	assert.Equal(t, []string{"global"}, calls)
+}
+
+type testCustomConfig struct {
+	SomeValue int
+}
+
+func (c *testCustomConfig) UpdateFromRaw(_ interface{}) bool {
+	return true
+}
+
+type testValidatableConfig struct {
+	testCustomConfig
+}
+
+func (c *testValidatableConfig) Validate() error {
+	if c.SomeValue <= 0 {
+		return errors.New("SomeValue must be greater than zero")
+	}
+	return nil
+}
+
+func TestValidateCustomConfig(t *testing.T) {
+	assert.NoError(t, validateCustomConfig(&testCustomConfig{}, "AppCustom"))
+	assert.NoError(t, validateCustomConfig(&testValidatableConfig{testCustomConfig{SomeValue: 1}}, "AppCustom"))
+	assert.EqualError(t,
+		validateCustomConfig(&testValidatableConfig{}, "AppCustom"),
+		"custom configuration section 'AppCustom' failed validation: SomeValue must be greater than zero")
+}
+
 func TestAddBackgroundPublisherNoTopic(t *testing.T) {
 	sdk := Service{
 		config: &common.ConfigurationStruct{},
@@ -465,6 +565,613 @@ func TestLoadConfigurablePipelineNumFunctions(t *testing.T) {
 	assert.Equal(t, 3, len(appFunctions))
 }
 
+func TestLoadConfigurablePipelineAliases(t *testing.T) {
+	functions := make(map[string]common.PipelineFunction)
+	functions["ToXml"] = common.PipelineFunction{
+		Name:       "Transform",
+		Parameters: map[string]string{TransformType: TransformXml},
+	}
+	functions["ToJson"] = common.PipelineFunction{
+		Name:       "Transform",
+		Parameters: map[string]string{TransformType: TransformJson},
+	}
+	functions["Transform2"] = common.PipelineFunction{
+		Parameters: map[string]string{TransformType: TransformJson},
+	}
+	functions["Unknown"] = common.PipelineFunction{
+		Name: "Bogus",
+	}
+
+	tests := []struct {
+		Name           string
+		ExecutionOrder string
+		ExpectError    bool
+	}{
+		{"Valid aliases", "ToXml, ToJson", false},
+		{"Valid alias and starts with", "ToXml, Transform2", false},
+		{"Invalid alias function name", "ToXml, Unknown", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			sdk := Service{
+				lc: lc,
+				config: &common.ConfigurationStruct{
+					Writable: common.WritableInfo{
+						Pipeline: common.PipelineInfo{
+							ExecutionOrder: test.ExecutionOrder,
+							Functions:      functions,
+						},
+					},
+				},
+			}
+
+			appFunctions, err := sdk.LoadConfigurablePipeline()
+			if test.ExpectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, appFunctions, 2)
+		})
+	}
+}
+
+func TestLoadConfigurablePipelinePerTopicPipelines(t *testing.T) {
+	functions := make(map[string]common.PipelineFunction)
+	functions["FilterByDeviceName"] = common.PipelineFunction{
+		Parameters: map[string]string{"DeviceNames": "Random-Float-Device"},
+	}
+	functions["Transform"] = common.PipelineFunction{
+		Parameters: map[string]string{TransformType: TransformXml},
+	}
+	functions["SetResponseData"] = common.PipelineFunction{}
+
+	tests := []struct {
+		Name                   string
+		ExecutionOrder         string
+		PerTopicPipelines      map[string]common.TopicPipeline
+		ExpectedDefault        int
+		ExpectedTopics         []string
+		ExpectedTopicFunctions int
+		ExpectError            bool
+	}{
+		{"Valid with default", "Transform, SetResponseData", map[string]common.TopicPipeline{
+			"floats": {Topics: "edgex/events/#, other/topic", ExecutionOrder: "FilterByDeviceName, Transform, SetResponseData"},
+		}, 2, []string{"edgex/events/#", "other/topic"}, 3, false},
+		{"Valid without default", "", map[string]common.TopicPipeline{
+			"floats": {Topics: "edgex/events/#", ExecutionOrder: "FilterByDeviceName, SetResponseData"},
+		}, 0, []string{"edgex/events/#"}, 2, false},
+		{"No topics", "Transform", map[string]common.TopicPipeline{
+			"floats": {Topics: " ", ExecutionOrder: "FilterByDeviceName"},
+		}, 0, nil, 0, true},
+		{"No functions", "Transform", map[string]common.TopicPipeline{
+			"floats": {Topics: "edgex/events/#", ExecutionOrder: ""},
+		}, 0, nil, 0, true},
+		{"Function not found", "Transform", map[string]common.TopicPipeline{
+			"floats": {Topics: "edgex/events/#", ExecutionOrder: "Bogus"},
+		}, 0, nil, 0, true},
+		{"Reserved Id", "Transform", map[string]common.TopicPipeline{
+			interfaces.DefaultPipelineId: {Topics: "edgex/events/#", ExecutionOrder: "SetResponseData"},
+		}, 0, nil, 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			sdk := Service{
+				lc: lc,
+				config: &common.ConfigurationStruct{
+					Writable: common.WritableInfo{
+						Pipeline: common.PipelineInfo{
+							ExecutionOrder:    test.ExecutionOrder,
+							Functions:         functions,
+							PerTopicPipelines: test.PerTopicPipelines,
+						},
+					},
+				},
+			}
+
+			appFunctions, err := sdk.LoadConfigurablePipeline()
+			if test.ExpectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, appFunctions, test.ExpectedDefault)
+			require.Len(t, sdk.topicPipelines, 1)
+			assert.Equal(t, "floats", sdk.topicPipelines[0].Id)
+			assert.Equal(t, test.ExpectedTopics, sdk.topicPipelines[0].Topics)
+			assert.Len(t, sdk.topicPipelines[0].Transforms, test.ExpectedTopicFunctions)
+		})
+	}
+}
+
+func TestResolveParameterReferencesEnvironmentVariables(t *testing.T) {
+	require.NoError(t, os.Setenv("TEST_EXPORT_HOST", "cloud.example.com"))
+	require.NoError(t, os.Setenv("TEST_EXPORT_PORT", "8443"))
+	defer func() {
+		_ = os.Unsetenv("TEST_EXPORT_HOST")
+		_ = os.Unsetenv("TEST_EXPORT_PORT")
+	}()
+
+	tests := []struct {
+		Name        string
+		Parameters  map[string]string
+		Expected    map[string]string
+		ExpectError bool
+	}{
+		{"No references", map[string]string{"url": "http://localhost"}, map[string]string{"url": "http://localhost"}, false},
+		{"References",
+			map[string]string{"url": "https://${TEST_EXPORT_HOST}:${TEST_EXPORT_PORT}/api", "method": "post"},
+			map[string]string{"url": "https://cloud.example.com:8443/api", "method": "post"}, false},
+		{"Not a reference", map[string]string{"value": "$TEST_EXPORT_HOST ${}"}, map[string]string{"value": "$TEST_EXPORT_HOST ${}"}, false},
+		{"Not set", map[string]string{"url": "https://${TEST_EXPORT_BOGUS}"}, nil, true},
+		{"Nil", nil, nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			actual, err := (&Service{lc: lc}).resolveParameterReferences(test.Parameters)
+			if test.ExpectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "TEST_EXPORT_BOGUS")
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.Expected, actual)
+		})
+	}
+}
+
+func TestLoadConfigurablePipelineEnvironmentVariables(t *testing.T) {
+	var receivedParameters map[string]string
+
+	functions := make(map[string]common.PipelineFunction)
+	functions["MyExport"] = common.PipelineFunction{
+		Parameters: map[string]string{"Topic": "sites/${TEST_SITE_ID}/events"},
+	}
+
+	sdk := Service{
+		lc: lc,
+		config: &common.ConfigurationStruct{
+			Writable: common.WritableInfo{
+				Pipeline: common.PipelineInfo{
+					ExecutionOrder: "MyExport",
+					Functions:      functions,
+				},
+			},
+		},
+	}
+
+	err := sdk.RegisterCustomConfigurableFunction("MyExport", func(parameters map[string]string) (interfaces.AppFunction, error) {
+		receivedParameters = parameters
+		return func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+			return true, data
+		}, nil
+	})
+	require.NoError(t, err)
+
+	_, err = sdk.LoadConfigurablePipeline()
+	require.Error(t, err)
+
+	require.NoError(t, os.Setenv("TEST_SITE_ID", "plant-7"))
+	defer func() {
+		_ = os.Unsetenv("TEST_SITE_ID")
+	}()
+
+	_, err = sdk.LoadConfigurablePipeline()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"topic": "sites/plant-7/events"}, receivedParameters)
+	// Configuration isn't modified so changes to the environment are picked up when the pipeline is reloaded
+	assert.Equal(t, "sites/${TEST_SITE_ID}/events", functions["MyExport"].Parameters["topic"])
+}
+
+func TestLoadConfigurablePipelineReusesUnchangedFunctions(t *testing.T) {
+	var created []string
+
+	functions := make(map[string]common.PipelineFunction)
+	functions["Counter1"] = common.PipelineFunction{
+		Parameters: map[string]string{"Threshold": "10"},
+	}
+	functions["Counter2"] = common.PipelineFunction{
+		Parameters: map[string]string{"Threshold": "20"},
+	}
+
+	sdk := Service{
+		lc: lc,
+		config: &common.ConfigurationStruct{
+			Writable: common.WritableInfo{
+				Pipeline: common.PipelineInfo{
+					ExecutionOrder: "Counter1, Counter2",
+					Functions:      functions,
+				},
+			},
+		},
+	}
+
+	err := sdk.RegisterCustomConfigurableFunction("Counter", func(parameters map[string]string) (interfaces.AppFunction, error) {
+		created = append(created, parameters["threshold"])
+		return func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+			return true, data
+		}, nil
+	})
+	require.NoError(t, err)
+
+	_, err = sdk.LoadConfigurablePipeline()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10", "20"}, created)
+
+	// Nothing changed so no functions are created
+	_, err = sdk.LoadConfigurablePipeline()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10", "20"}, created)
+
+	// Only the function whose parameters changed is created
+	functions["Counter2"].Parameters["threshold"] = "25"
+	appFunctions, err := sdk.LoadConfigurablePipeline()
+	require.NoError(t, err)
+	assert.Len(t, appFunctions, 2)
+	assert.Equal(t, []string{"10", "20", "25"}, created)
+
+	// Moving a function in the execution order reuses it
+	sdk.config.Writable.Pipeline.ExecutionOrder = "Counter2, Counter1"
+	_, err = sdk.LoadConfigurablePipeline()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10", "20", "25"}, created)
+
+	// Using a function more than once creates the additional instances only
+	sdk.config.Writable.Pipeline.ExecutionOrder = "Counter2, Counter1, Counter2"
+	appFunctions, err = sdk.LoadConfigurablePipeline()
+	require.NoError(t, err)
+	assert.Len(t, appFunctions, 3)
+	assert.Equal(t, []string{"10", "20", "25", "25"}, created)
+	assert.Len(t, sdk.loadedFunctions, 2)
+	assert.Len(t, sdk.retiredFunctions, 1, "only the function created with threshold 20 is retired")
+
+	// The additional instance is retired once it is no longer used
+	sdk.config.Writable.Pipeline.ExecutionOrder = "Counter1, Counter2"
+	_, err = sdk.LoadConfigurablePipeline()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10", "20", "25", "25"}, created)
+	assert.Len(t, sdk.retiredFunctions, 2)
+}
+
+func TestLoadConfigurablePipelineClosesRetiredFunctions(t *testing.T) {
+	var received int32
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		atomic.AddInt32(&received, 1)
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	functions := map[string]common.PipelineFunction{
+		"HTTPBatchExport": {Parameters: map[string]string{"Url": ts.URL, "MaxBatchInterval": "1h"}},
+	}
+	sdk := Service{
+		lc: lc,
+		config: &common.ConfigurationStruct{
+			Writable: common.WritableInfo{
+				Pipeline: common.PipelineInfo{ExecutionOrder: "HTTPBatchExport", Functions: functions},
+			},
+		},
+	}
+
+	appFunctions, err := sdk.LoadConfigurablePipeline()
+	require.NoError(t, err)
+	require.NoError(t, sdk.SetFunctionsPipeline(appFunctions...))
+	continuePipeline, _ := appFunctions[0](appfunction.NewContext("123", dic, ""), `{"value":1}`)
+	require.True(t, continuePipeline)
+
+	// The pending batch is sent once the pipeline is reloaded without the sender
+	functions["HTTPBatchExport"].Parameters["maxbatchsize"] = "10"
+	appFunctions, err = sdk.LoadConfigurablePipeline()
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&received))
+	require.NoError(t, sdk.SetFunctionsPipeline(appFunctions...))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&received))
+
+	// The pending batch of the current sender is sent when the service stops
+	continuePipeline, _ = appFunctions[0](appfunction.NewContext("123", dic, ""), `{"value":2}`)
+	require.True(t, continuePipeline)
+	sdk.closePipelineFunctions()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&received))
+}
+
+func TestResolveParameterReferencesSecrets(t *testing.T) {
+	require.NoError(t, os.Setenv("TEST_SECRET_REFERENCE", "${secret:mqtt/password}"))
+	require.NoError(t, os.Setenv("TEST_BROKER_HOST", "broker"))
+	defer func() {
+		_ = os.Unsetenv("TEST_SECRET_REFERENCE")
+		_ = os.Unsetenv("TEST_BROKER_HOST")
+	}()
+
+	mockSP := &mocks.SecretProvider{}
+	mockSP.On("GetSecret", "mqtt", "password").Return(map[string]string{"password": "S3cr3t"}, nil)
+	mockSP.On("GetSecret", "mqtt/cloud", "username").Return(map[string]string{"username": "edgex"}, nil)
+	mockSP.On("GetSecret", "mqtt", "token").Return(map[string]string{"token": "${TEST_BROKER_HOST}"}, nil)
+	mockSP.On("GetSecret", "mqtt", "bogus").Return(nil, errors.New("FAKE NOT FOUND ERROR"))
+
+	sdk := Service{
+		lc: lc,
+		dic: di.NewContainer(di.ServiceConstructorMap{
+			bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+				return mockSP
+			},
+		}),
+	}
+
+	tests := []struct {
+		Name        string
+		Parameters  map[string]string
+		Expected    map[string]string
+		ExpectError bool
+	}{
+		{"No references", map[string]string{"url": "tcp://localhost"}, map[string]string{"url": "tcp://localhost"}, false},
+		{"References",
+			map[string]string{"password": "${secret:mqtt/password}", "url": "tcp://${secret:mqtt/cloud/username}@broker"},
+			map[string]string{"password": "S3cr3t", "url": "tcp://edgex@broker"}, false},
+		{"Secret and environment variable references",
+			map[string]string{"url": "tcp://${secret:mqtt/cloud/username}@${TEST_BROKER_HOST}"},
+			map[string]string{"url": "tcp://edgex@broker"}, false},
+		// Only the references configured are resolved, not those in the values they are resolved to
+		{"Environment variable with secret reference",
+			map[string]string{"password": "${TEST_SECRET_REFERENCE}"},
+			map[string]string{"password": "${secret:mqtt/password}"}, false},
+		{"Secret with environment variable reference",
+			map[string]string{"token": "${secret:mqtt/token}"},
+			map[string]string{"token": "${TEST_BROKER_HOST}"}, false},
+		{"Not found", map[string]string{"password": "${secret:mqtt/bogus}"}, nil, true},
+		{"Nil", nil, nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			actual, err := sdk.resolveParameterReferences(test.Parameters)
+			if test.ExpectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.Expected, actual)
+		})
+	}
+}
+
+func TestPipelineFunctionFingerprint(t *testing.T) {
+	configured := map[string]string{"password": "${secret:mqtt/password}", "url": "tcp://broker"}
+
+	fingerprint := pipelineFunctionFingerprint("MQTTExport", configured,
+		map[string]string{"password": "S3cr3t", "url": "tcp://broker"})
+	assert.Contains(t, fingerprint, "password=${secret:mqtt/password}")
+	assert.NotContains(t, fingerprint, "S3cr3t")
+
+	// Changes when the secret referenced changes
+	rotated := pipelineFunctionFingerprint("MQTTExport", configured,
+		map[string]string{"password": "N3wS3cr3t", "url": "tcp://broker"})
+	assert.NotEqual(t, fingerprint, rotated)
+
+	unchanged := pipelineFunctionFingerprint("MQTTExport", configured,
+		map[string]string{"password": "S3cr3t", "url": "tcp://broker"})
+	assert.Equal(t, fingerprint, unchanged)
+}
+
+func TestLoadConfigurablePipelineAllProblemsReported(t *testing.T) {
+	functions := make(map[string]common.PipelineFunction)
+	functions["Transform"] = common.PipelineFunction{
+		Parameters: map[string]string{TransformType: "bogus"},
+	}
+	functions["Compress"] = common.PipelineFunction{
+		Parameters: map[string]string{Algorithm: CompressGZIP},
+	}
+
+	sdk := Service{
+		lc: lc,
+		config: &common.ConfigurationStruct{
+			Writable: common.WritableInfo{
+				Pipeline: common.PipelineInfo{
+					ExecutionOrder: "Transform, Missing, Compress",
+					TargetType:     "bogus",
+					Functions:      functions,
+					PerTopicPipelines: map[string]common.TopicPipeline{
+						"floats": {Topics: "", ExecutionOrder: "Compress, Bogus"},
+					},
+				},
+			},
+		},
+	}
+
+	expected := "pipeline configuration has 5 problems:\n" +
+		" - invalid pipeline TargetType 'bogus', must be 'event' or 'raw'\n" +
+		" - pipeline 'floats': no topics specified\n" +
+		" - pipeline 'floats': function 'Bogus' configuration not found in Pipeline.Functions section\n" +
+		" - Transform from configuration failed: Invalid transform type 'bogus'. Must be 'xml', 'json' or 'ndjson'\n" +
+		" - function 'Missing' configuration not found in Pipeline.Functions section"
+
+	appFunctions, err := sdk.LoadConfigurablePipeline()
+	require.Error(t, err)
+	assert.Nil(t, appFunctions)
+	assert.Equal(t, expected, err.Error())
+}
+
+func TestLoadConfigurablePipelineExecuteOnlyIfMatched(t *testing.T) {
+	functions := make(map[string]common.PipelineFunction)
+	functions["FilterByDeviceName"] = common.PipelineFunction{
+		Parameters: map[string]string{"DeviceNames": "Random-Float-Device"},
+	}
+	functions["Transform"] = common.PipelineFunction{
+		Parameters:           map[string]string{TransformType: TransformXml},
+		ExecuteOnlyIfMatched: true,
+	}
+	functions["SetResponseData"] = common.PipelineFunction{}
+
+	sdk := Service{
+		lc: lc,
+		config: &common.ConfigurationStruct{
+			Writable: common.WritableInfo{
+				Pipeline: common.PipelineInfo{
+					ExecutionOrder: "FilterByDeviceName, Transform, SetResponseData",
+					Functions:      functions,
+				},
+			},
+		},
+	}
+
+	appFunctions, err := sdk.LoadConfigurablePipeline()
+	require.NoError(t, err)
+	assert.Len(t, appFunctions, 3)
+
+	sdk.config.Writable.Pipeline.ExecutionOrder = "Transform, SetResponseData"
+	_, err = sdk.LoadConfigurablePipeline()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "isn't preceded by a function to match")
+}
+
+func TestRegisterCustomConfigurableFunction(t *testing.T) {
+	factory := func(parameters map[string]string) (interfaces.AppFunction, error) {
+		return nil, nil
+	}
+
+	sdk := Service{lc: lc}
+
+	require.NoError(t, sdk.RegisterCustomConfigurableFunction("MyTransform", factory))
+
+	err := sdk.RegisterCustomConfigurableFunction("MyTransform", factory)
+	assert.EqualError(t, err, "custom configurable function MyTransform is already registered")
+
+	err = sdk.RegisterCustomConfigurableFunction("Transform", factory)
+	assert.EqualError(t, err, "cannot register custom configurable function for built in function (Transform)")
+
+	err = sdk.RegisterCustomConfigurableFunction(" ", factory)
+	assert.Error(t, err)
+
+	err = sdk.RegisterCustomConfigurableFunction("Other", nil)
+	assert.Error(t, err)
+}
+
+func TestRegisterPayloadDecoder(t *testing.T) {
+	decoder := func(payload []byte, target interface{}) error {
+		return nil
+	}
+
+	sdk := Service{lc: lc}
+
+	require.NoError(t, sdk.RegisterPayloadDecoder("application/x-protobuf", decoder))
+	assert.Contains(t, sdk.payloadDecoders, "application/x-protobuf")
+
+	err := sdk.RegisterPayloadDecoder("Application/X-Protobuf; proto=Event", decoder)
+	assert.EqualError(t, err, "payload decoder for content type application/x-protobuf is already registered")
+
+	err = sdk.RegisterPayloadDecoder("application/json", decoder)
+	assert.EqualError(t, err, "cannot register payload decoder for built in content type (application/json)")
+
+	err = sdk.RegisterPayloadDecoder(" ", decoder)
+	assert.Error(t, err)
+
+	err = sdk.RegisterPayloadDecoder("application/x-other", nil)
+	assert.Error(t, err)
+}
+
+func TestStrictValidation(t *testing.T) {
+	sdk := Service{lc: lc}
+
+	settings := sdk.strictValidation(common.StrictValidationInfo{
+		Enabled:            true,
+		ValidateProfiles:   true,
+		ProfileCacheTTL:    "10m",
+		MaxOriginSkew:      "1h",
+		DeadLetterDir:      " /tmp/dead ",
+		DeadLetterMaxSize:  1024,
+		DeadLetterMaxFiles: 2,
+	})
+	assert.Equal(t, runtime.StrictValidation{
+		Enabled:            true,
+		ValidateProfiles:   true,
+		ProfileCacheTTL:    10 * time.Minute,
+		MaxOriginSkew:      time.Hour,
+		DeadLetterDir:      "/tmp/dead",
+		DeadLetterMaxSize:  1024,
+		DeadLetterMaxFiles: 2,
+	}, settings)
+
+	settings = sdk.strictValidation(common.StrictValidationInfo{Enabled: true, ProfileCacheTTL: "bogus", MaxOriginSkew: "bogus"})
+	assert.Equal(t, runtime.DefaultProfileCacheTTL, settings.ProfileCacheTTL)
+	assert.Zero(t, settings.MaxOriginSkew)
+
+	settings = sdk.strictValidation(common.StrictValidationInfo{Enabled: true, ProfileCacheTTL: "0s"})
+	assert.Equal(t, runtime.DefaultProfileCacheTTL, settings.ProfileCacheTTL)
+}
+
+func TestLoadConfigurablePipelineCustomFunction(t *testing.T) {
+	var receivedParameters []map[string]string
+	customFunction := func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		return true, data
+	}
+
+	functions := make(map[string]common.PipelineFunction)
+	functions["TransformUpper1"] = common.PipelineFunction{
+		Parameters: map[string]string{"Field": "deviceName"},
+	}
+	functions["TransformUpper2"] = common.PipelineFunction{
+		Parameters: map[string]string{"Field": "sourceName"},
+	}
+	functions["SetResponseData"] = common.PipelineFunction{}
+
+	sdk := Service{
+		lc: lc,
+		config: &common.ConfigurationStruct{
+			Writable: common.WritableInfo{
+				Pipeline: common.PipelineInfo{
+					ExecutionOrder: "TransformUpper1, TransformUpper2, SetResponseData",
+					Functions:      functions,
+				},
+			},
+		},
+	}
+
+	// Custom function name starting with a built in function name takes precedence over the built in function
+	err := sdk.RegisterCustomConfigurableFunction("TransformUpper", func(parameters map[string]string) (interfaces.AppFunction, error) {
+		receivedParameters = append(receivedParameters, parameters)
+		return customFunction, nil
+	})
+	require.NoError(t, err)
+
+	appFunctions, err := sdk.LoadConfigurablePipeline()
+	require.NoError(t, err)
+	assert.Len(t, appFunctions, 3)
+	assert.Equal(t, []map[string]string{{"field": "deviceName"}, {"field": "sourceName"}}, receivedParameters)
+}
+
+func TestLoadConfigurablePipelineCustomFunctionError(t *testing.T) {
+	functions := make(map[string]common.PipelineFunction)
+	functions["MyTransform"] = common.PipelineFunction{}
+
+	sdk := Service{
+		lc: lc,
+		config: &common.ConfigurationStruct{
+			Writable: common.WritableInfo{
+				Pipeline: common.PipelineInfo{
+					ExecutionOrder: "MyTransform",
+					Functions:      functions,
+				},
+			},
+		},
+	}
+
+	err := sdk.RegisterCustomConfigurableFunction("MyTransform", func(parameters map[string]string) (interfaces.AppFunction, error) {
+		return nil, fmt.Errorf("missing parameter")
+	})
+	require.NoError(t, err)
+
+	appFunctions, err := sdk.LoadConfigurablePipeline()
+	require.Error(t, err)
+	assert.Equal(t, "MyTransform from configuration failed: missing parameter", err.Error())
+	assert.Nil(t, appFunctions)
+}
+
 func TestUseTargetTypeOfByteArrayTrue(t *testing.T) {
 	functions := make(map[string]common.PipelineFunction)
 	functions["Compress"] = common.PipelineFunction{
@@ -517,6 +1224,88 @@ func TestUseTargetTypeOfByteArrayFalse(t *testing.T) {
 	assert.Nil(t, sdk.targetType)
 }
 
+func TestPipelineTargetType(t *testing.T) {
+	functions := make(map[string]common.PipelineFunction)
+	functions["Compress"] = common.PipelineFunction{
+		Parameters: map[string]string{Algorithm: CompressGZIP},
+	}
+
+	tests := []struct {
+		Name                     string
+		TargetType               string
+		UseTargetTypeOfByteArray bool
+		ExpectRaw                bool
+		ExpectError              bool
+	}{
+		{"Raw", "raw", false, true, false},
+		{"Raw mixed case", " Raw ", false, true, false},
+		{"Event", "event", false, false, false},
+		{"Event overrides UseTargetTypeOfByteArray", "event", true, false, false},
+		{"Not specified uses UseTargetTypeOfByteArray", "", true, true, false},
+		{"Invalid", "metric", false, false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			sdk := Service{
+				lc: lc,
+				config: &common.ConfigurationStruct{
+					Writable: common.WritableInfo{
+						Pipeline: common.PipelineInfo{
+							ExecutionOrder:           "Compress",
+							TargetType:               test.TargetType,
+							UseTargetTypeOfByteArray: test.UseTargetTypeOfByteArray,
+							Functions:                functions,
+						},
+					},
+				},
+			}
+
+			_, err := sdk.LoadConfigurablePipeline()
+			if test.ExpectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			if test.ExpectRaw {
+				assert.Equal(t, &[]byte{}, sdk.targetType)
+				return
+			}
+
+			assert.Nil(t, sdk.targetType)
+		})
+	}
+}
+
+func TestParseCommandLineCustomFlags(t *testing.T) {
+	svc := Service{lc: lc}
+	assert.Nil(t, svc.RemainingArgs())
+
+	var verbose bool
+	svc.SetCustomFlags(func(flagSet *flag.FlagSet) {
+		flagSet.BoolVar(&verbose, "verbose", false, "")
+	})
+	svc.SetCommandLineArgs([]string{"-i", "east", "--verbose", "extra", "args"})
+
+	svc.parseCommandLine()
+
+	assert.True(t, verbose)
+	assert.Equal(t, "east", svc.commandLine.instance)
+	assert.Equal(t, []string{"extra", "args"}, svc.RemainingArgs())
+}
+
+func TestSetVersion(t *testing.T) {
+	svc := Service{lc: lc}
+	assert.Equal(t, internal.ApplicationVersion, svc.ApplicationVersion())
+
+	// The version set when building isn't changed, since it's shared by all the Service instances
+	original := internal.ApplicationVersion
+	svc.SetVersion("1.2.3")
+	assert.Equal(t, "1.2.3", svc.ApplicationVersion())
+	assert.Equal(t, original, internal.ApplicationVersion)
+}
+
 func TestSetServiceKey(t *testing.T) {
 	sdk := Service{
 		lc:                       lc,
@@ -531,6 +1320,8 @@ func TestSetServiceKey(t *testing.T) {
 		profileEnvValue               string
 		serviceKeyEnvValue            string
 		serviceKeyCommandLineOverride string
+		instanceCommandLine           string
+		instanceEnvValue              string
 		originalServiceKey            string
 		expectedServiceKey            string
 	}{
@@ -599,6 +1390,33 @@ func TestSetServiceKey(t *testing.T) {
 			originalServiceKey: "AppService",
 			expectedServiceKey: "AppService-http-export-MyCloud",
 		},
+		{
+			name:                "Instance, no profile",
+			instanceCommandLine: "2",
+			originalServiceKey:  "MyAppService" + interfaces.ProfileSuffixPlaceholder,
+			expectedServiceKey:  "MyAppService-2",
+		},
+		{
+			name:                "Instance with profile",
+			instanceCommandLine: "east",
+			profile:             "mqtt-export",
+			originalServiceKey:  "MyAppService-" + interfaces.ProfileSuffixPlaceholder,
+			expectedServiceKey:  "MyAppService-mqtt-export-east",
+		},
+		{
+			name:                          "Instance with Service Key command-line override",
+			instanceCommandLine:           "east",
+			serviceKeyCommandLineOverride: "MyCustomAppService",
+			originalServiceKey:            "AppService",
+			expectedServiceKey:            "MyCustomAppService-east",
+		},
+		{
+			name:                "Instance ENV override",
+			instanceCommandLine: "east",
+			instanceEnvValue:    "west",
+			originalServiceKey:  "MyAppService",
+			expectedServiceKey:  "MyAppService-west",
+		},
 	}
 
 	// Just in case...
@@ -614,11 +1432,14 @@ func TestSetServiceKey(t *testing.T) {
 				err := os.Setenv(envServiceKey, test.serviceKeyEnvValue)
 				require.NoError(t, err)
 			}
+			if len(test.instanceEnvValue) > 0 {
+				err := os.Setenv(envInstance, test.instanceEnvValue)
+				require.NoError(t, err)
+			}
 			defer os.Clearenv()
 
-			if len(test.serviceKeyCommandLineOverride) > 0 {
-				sdk.commandLine.serviceKeyOverride = test.serviceKeyCommandLineOverride
-			}
+			sdk.commandLine.serviceKeyOverride = test.serviceKeyCommandLineOverride
+			sdk.commandLine.instance = test.instanceCommandLine
 
 			sdk.serviceKey = test.originalServiceKey
 			sdk.setServiceKey(test.profile)
@@ -628,6 +1449,65 @@ func TestSetServiceKey(t *testing.T) {
 	}
 }
 
+// unregisterRegistryClient is a registry.Client that only records whether it was unregistered
+type unregisterRegistryClient struct {
+	registry.Client
+	unregistered bool
+}
+
+func (client *unregisterRegistryClient) Unregister() error {
+	client.unregistered = true
+	return nil
+}
+
+func TestUnregisterFromRegistry(t *testing.T) {
+	registryClient := &unregisterRegistryClient{}
+
+	svc := Service{
+		lc:         lc,
+		serviceKey: "MyAppService",
+		dic: di.NewContainer(di.ServiceConstructorMap{
+			bootstrapContainer.RegistryClientInterfaceName: func(get di.Get) interface{} {
+				return registryClient
+			},
+		}),
+	}
+
+	svc.unregisterFromRegistry()
+	assert.True(t, registryClient.unregistered)
+
+	// Registry not used
+	svc.dic = di.NewContainer(di.ServiceConstructorMap{})
+	svc.unregisterFromRegistry()
+}
+
+func TestSetContext(t *testing.T) {
+	svc := Service{lc: lc}
+	assert.Equal(t, context.Background(), svc.parentContext())
+
+	parent, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	svc.SetContext(parent)
+	assert.Equal(t, parent, svc.parentContext())
+}
+
+func TestRunShutdownHooks(t *testing.T) {
+	svc := Service{lc: lc}
+
+	var calls []string
+	svc.RegisterCustomShutdownHook(func() { calls = append(calls, "first") })
+	svc.RegisterCustomShutdownHook(nil)
+	svc.RegisterCustomShutdownHook(func() { calls = append(calls, "second") })
+
+	svc.runShutdownHooks()
+	assert.Equal(t, []string{"second", "first"}, calls)
+
+	// Hooks are only called once
+	svc.runShutdownHooks()
+	assert.Len(t, calls, 2)
+}
+
 func TestMakeItStop(t *testing.T) {
 	stopCalled := false
 
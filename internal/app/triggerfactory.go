@@ -28,6 +28,7 @@ import (
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/appfunction"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/common"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/runtime"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/trigger/benchmark"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/trigger/http"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/trigger/messagebus"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/trigger/mqtt"
@@ -39,6 +40,7 @@ const (
 	TriggerTypeMessageBus = "EDGEX-MESSAGEBUS"
 	TriggerTypeMQTT       = "EXTERNAL-MQTT"
 	TriggerTypeHTTP       = "HTTP"
+	TriggerTypeBenchmark  = "BENCHMARK"
 )
 
 // RegisterCustomTriggerFactory allows users to register builders for custom trigger types
@@ -48,7 +50,8 @@ func (svc *Service) RegisterCustomTriggerFactory(name string,
 
 	if nu == TriggerTypeMessageBus ||
 		nu == TriggerTypeHTTP ||
-		nu == TriggerTypeMQTT {
+		nu == TriggerTypeMQTT ||
+		nu == TriggerTypeBenchmark {
 		return fmt.Errorf("cannot register custom trigger for builtin type (%s)", name)
 	}
 
@@ -108,6 +111,10 @@ func (svc *Service) setupTrigger(configuration *common.ConfigurationStruct, runt
 		svc.LoggingClient().Info("External MQTT trigger selected")
 		t = mqtt.NewTrigger(svc.dic, runtime)
 
+	case TriggerTypeBenchmark:
+		svc.LoggingClient().Info("Benchmark trigger selected")
+		t = benchmark.NewTrigger(svc.dic, runtime)
+
 	default:
 		if factory, found := svc.customTriggerFactories[triggerType]; found {
 			var err error
@@ -28,6 +28,7 @@ import (
 
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/bootstrap/container"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/common"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/trigger/benchmark"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/trigger/http"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/trigger/messagebus"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/trigger/mqtt"
@@ -69,6 +70,16 @@ func TestRegisterCustomTriggerFactory_MQTT(t *testing.T) {
 	require.Zero(t, len(sdk.customTriggerFactories), "nothing should be registered")
 }
 
+func TestRegisterCustomTriggerFactory_Benchmark(t *testing.T) {
+	name := strings.ToTitle(TriggerTypeBenchmark)
+
+	sdk := Service{}
+	err := sdk.RegisterCustomTriggerFactory(name, nil)
+
+	require.Error(t, err, "should throw error")
+	require.Zero(t, len(sdk.customTriggerFactories), "nothing should be registered")
+}
+
 func TestRegisterCustomTrigger(t *testing.T) {
 	name := "cUsToM tRiGgEr"
 	trig := mockCustomTrigger{}
@@ -148,6 +159,31 @@ func TestSetupTrigger_MQTT(t *testing.T) {
 	require.IsType(t, &mqtt.Trigger{}, trigger, "should be an external-MQTT trigger")
 }
 
+func TestSetupTrigger_Benchmark(t *testing.T) {
+	config := &common.ConfigurationStruct{
+		Trigger: common.TriggerInfo{
+			Type: TriggerTypeBenchmark,
+		},
+	}
+
+	dic.Update(di.ServiceConstructorMap{
+		container.ConfigurationName: func(get di.Get) interface{} {
+			return config
+		},
+	})
+
+	sdk := Service{
+		dic:    dic,
+		config: config,
+		lc:     lc,
+	}
+
+	trigger := sdk.setupTrigger(sdk.config, sdk.runtime)
+
+	require.NotNil(t, trigger, "should be defined")
+	require.IsType(t, &benchmark.Trigger{}, trigger, "should be a benchmark trigger")
+}
+
 type mockCustomTrigger struct {
 }
 
@@ -20,19 +20,26 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"reflect"
 	"regexp"
+	"runtime"
 	"strings"
 	"time"
 
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/bootstrap/container"
+	sdkInterfaces "github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/util"
 	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
 
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/interfaces"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	commonConstants "github.com/edgexfoundry/go-mod-core-contracts/v2/common"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/requests"
+	"github.com/fxamacker/cbor/v2"
 )
 
 // NewContext creates, initializes and return a new Context with implements the interfaces.AppFunctionContext interface
@@ -56,6 +63,21 @@ type Context struct {
 	responseContentType  string
 	contextData          map[string]string
 	valuePlaceholderSpec *regexp.Regexp
+	pipelineState        sdkInterfaces.StateStore
+	pipelineId           string
+	pipelinePosition     int
+	pipelineFunction     sdkInterfaces.AppFunction
+	executionValues      map[string]interface{}
+	serialized           *serializedData
+	serializedUsed       bool
+}
+
+// serializedData is the data serialized by SerializedData, which is reused by the following functions, typically
+// exports, which serialize the same data
+type serializedData struct {
+	data  interface{}
+	bytes []byte
+	cbor  bool
 }
 
 // SetCorrelationID sets the correlationID. This function is not part of the AppFunctionContext interface,
@@ -64,6 +86,146 @@ func (appContext *Context) SetCorrelationID(id string) {
 	appContext.correlationID = id
 }
 
+// SetPipelineState sets the state store of the pipeline processing the data. This function is not part of the
+// AppFunctionContext interface, so it is internal SDK use only
+func (appContext *Context) SetPipelineState(state sdkInterfaces.StateStore) {
+	appContext.pipelineState = state
+}
+
+// PipelineState returns the state store of the pipeline processing the data, which is kept across the messages the
+// pipeline processes. If the context isn't executing a pipeline, a state store for just this context is returned.
+func (appContext *Context) PipelineState() sdkInterfaces.StateStore {
+	if appContext.pipelineState == nil {
+		appContext.pipelineState = NewStateStore()
+	}
+	return appContext.pipelineState
+}
+
+// SetPipelineId sets the Id of the pipeline processing the data. This function is not part of the
+// AppFunctionContext interface, so it is internal SDK use only
+func (appContext *Context) SetPipelineId(id string) {
+	appContext.pipelineId = id
+}
+
+// PipelineId returns the Id of the pipeline processing the data
+func (appContext *Context) PipelineId() string {
+	return appContext.pipelineId
+}
+
+// SetPipelineFunction sets the position in the pipeline of the function being executed and the function. This
+// function is not part of the AppFunctionContext interface, so it is internal SDK use only
+func (appContext *Context) SetPipelineFunction(position int, function sdkInterfaces.AppFunction) {
+	appContext.pipelinePosition = position
+	appContext.pipelineFunction = function
+
+	// A function which didn't serialize the data may have modified it in place, i.e. adding tags to the Event, so the
+	// serialized data is only kept while consecutive functions serialize it
+	if !appContext.serializedUsed {
+		appContext.serialized = nil
+	}
+	appContext.serializedUsed = false
+}
+
+// SetWrappedFunction sets the function wrapped by the pipeline function being executed, so FunctionName reports
+// the wrapped function rather than the wrapper. This function is not part of the AppFunctionContext interface,
+// so it is internal SDK use only
+func (appContext *Context) SetWrappedFunction(function sdkInterfaces.AppFunction) {
+	appContext.pipelineFunction = function
+}
+
+// SetExecutionValue stores a value for the functions executed later in the pipeline execution. Unlike AddValue, the
+// value may be of any type and isn't available to the functions via the AppFunctionContext interface. This function
+// is not part of the AppFunctionContext interface, so it is internal SDK use only
+func (appContext *Context) SetExecutionValue(key string, value interface{}) {
+	if appContext.executionValues == nil {
+		appContext.executionValues = make(map[string]interface{})
+	}
+	appContext.executionValues[key] = value
+}
+
+// ExecutionValue returns the value stored by SetExecutionValue at the given key. This function is not part of the
+// AppFunctionContext interface, so it is internal SDK use only
+func (appContext *Context) ExecutionValue(key string) (interface{}, bool) {
+	value, found := appContext.executionValues[key]
+	return value, found
+}
+
+// RemoveExecutionValue deletes the value stored by SetExecutionValue at the given key. This function is not part of
+// the AppFunctionContext interface, so it is internal SDK use only
+func (appContext *Context) RemoveExecutionValue(key string) {
+	delete(appContext.executionValues, key)
+}
+
+// SerializedData returns the data as bytes, as util.CoerceType does, serializing it only once for consecutive
+// functions in the pipeline, i.e. exporting the same Event via MQTT and to a file. The bytes returned are shared, so
+// must not be modified.
+func (appContext *Context) SerializedData(data interface{}) ([]byte, error) {
+	return appContext.serialize(data, false)
+}
+
+// SerializedCBORData returns the data as bytes as SerializedData does, except Events with binary readings received
+// as CBOR are serialized as CBOR, see util.EncodesAsCBOR, in which case true is also returned. The bytes returned are
+// shared, so must not be modified.
+func (appContext *Context) SerializedCBORData(data interface{}) ([]byte, bool, error) {
+	asCBOR := util.EncodesAsCBOR(data, appContext.inputContentType)
+	serialized, err := appContext.serialize(data, asCBOR)
+	if err != nil {
+		return nil, false, err
+	}
+	return serialized, asCBOR, nil
+}
+
+func (appContext *Context) serialize(data interface{}, asCBOR bool) ([]byte, error) {
+	switch data.(type) {
+	case string, []byte:
+		// Nothing to serialize
+		return util.CoerceType(data)
+	}
+
+	appContext.serializedUsed = true
+	if appContext.serialized != nil && appContext.serialized.cbor == asCBOR &&
+		reflect.DeepEqual(appContext.serialized.data, data) {
+		return appContext.serialized.bytes, nil
+	}
+
+	var serialized []byte
+	var err error
+	if asCBOR {
+		serialized, err = cbor.Marshal(data)
+	} else {
+		serialized, err = util.CoerceType(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	appContext.serialized = &serializedData{data: data, bytes: serialized, cbor: asCBOR}
+	return serialized, nil
+}
+
+// PipelinePosition returns the zero based position in the pipeline of the function being executed
+func (appContext *Context) PipelinePosition() int {
+	return appContext.pipelinePosition
+}
+
+// FunctionName returns the name of the function being executed, i.e. 'transforms.Filter.FilterByDeviceName',
+// or an empty string if no function is being executed.
+func (appContext *Context) FunctionName() string {
+	if appContext.pipelineFunction == nil {
+		return ""
+	}
+
+	function := runtime.FuncForPC(reflect.ValueOf(appContext.pipelineFunction).Pointer())
+	if function == nil {
+		return ""
+	}
+
+	// Trim the package path and the suffix added to method values
+	name := function.Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	return strings.TrimSuffix(name, "-fm")
+}
+
 // CorrelationID returns context's the correlation ID
 func (appContext *Context) CorrelationID() string {
 	return appContext.correlationID
@@ -131,6 +293,16 @@ func (appContext *Context) LoggingClient() logger.LoggingClient {
 	return bootstrapContainer.LoggingClientFrom(appContext.dic.Get)
 }
 
+// FlowLoggingClient returns the logger from the dependency injection container wrapped to add the correlation ID,
+// pipeline ID, function name and device name of the context to the messages logged, so the runtime and trigger
+// messages of a message flow can be filtered by log aggregation tools
+func (appContext *Context) FlowLoggingClient() logger.LoggingClient {
+	return contextLogger{
+		LoggingClient: bootstrapContainer.LoggingClientFrom(appContext.dic.Get),
+		context:       appContext,
+	}
+}
+
 // EventClient returns the Event client, which may be nil, from the dependency injection container
 func (appContext *Context) EventClient() interfaces.EventClient {
 	return container.EventClientFrom(appContext.dic.Get)
@@ -166,6 +338,20 @@ func (appContext *Context) SubscriptionClient() interfaces.SubscriptionClient {
 	return container.SubscriptionClientFrom(appContext.dic.Get)
 }
 
+// MetricsManager returns the manager used to register custom metrics from the dependency injection container
+func (appContext *Context) MetricsManager() sdkInterfaces.MetricsManager {
+	return container.MetricsManagerFrom(appContext.dic.Get)
+}
+
+// HttpClient returns the shared, connection pooled HTTP client from the dependency injection container,
+// or http.DefaultClient if it hasn't been created
+func (appContext *Context) HttpClient() *http.Client {
+	if client := container.HttpClientFrom(appContext.dic.Get); client != nil {
+		return client
+	}
+	return http.DefaultClient
+}
+
 // AddValue stores a value for access within other functions in pipeline
 func (appContext *Context) AddValue(key string, value string) {
 	appContext.contextData[strings.ToLower(key)] = value
@@ -176,13 +362,18 @@ func (appContext *Context) RemoveValue(key string) {
 	delete(appContext.contextData, strings.ToLower(key))
 }
 
-// PushToCore pushes a new event to Core Data.
+// PushToCore pushes a new event to Core Data. Events without an apiVersion, i.e. those converted from V1 Events or
+// not created with dtos.NewEvent, are pushed as the API version this SDK supports.
 func (appContext *Context) PushToCore(event dtos.Event) (common.BaseWithIdResponse, error) {
 	client := appContext.EventClient()
 	if client == nil {
 		return common.BaseWithIdResponse{}, errors.New("EventClient not initialized. Core Metadata is missing from clients configuration")
 	}
 
+	if len(event.ApiVersion) == 0 {
+		event.ApiVersion = commonConstants.ApiVersion
+	}
+
 	request := requests.NewAddEventRequest(event)
 	return client.Add(context.Background(), request)
 }
@@ -251,3 +442,35 @@ func (appContext *Context) ApplyValues(format string) (string, error) {
 
 	return result, nil
 }
+
+// Clone returns an independent copy of the context, which is safe to use from goroutines started by a pipeline
+// function after the function has returned, since the original context is reused once the pipeline completes.
+func (appContext *Context) Clone() sdkInterfaces.AppFunctionContext {
+	clone := &Context{
+		dic:                  appContext.dic,
+		correlationID:        appContext.correlationID,
+		inputContentType:     appContext.inputContentType,
+		responseContentType:  appContext.responseContentType,
+		contextData:          appContext.GetAllValues(),
+		valuePlaceholderSpec: appContext.valuePlaceholderSpec,
+		pipelineState:        appContext.pipelineState,
+		pipelineId:           appContext.pipelineId,
+		pipelinePosition:     appContext.pipelinePosition,
+		pipelineFunction:     appContext.pipelineFunction,
+	}
+
+	if appContext.executionValues != nil {
+		clone.executionValues = make(map[string]interface{}, len(appContext.executionValues))
+		for key, value := range appContext.executionValues {
+			clone.executionValues[key] = value
+		}
+	}
+	if appContext.responseData != nil {
+		clone.responseData = append([]byte{}, appContext.responseData...)
+	}
+	if appContext.retryData != nil {
+		clone.retryData = append([]byte{}, appContext.retryData...)
+	}
+
+	return clone
+}
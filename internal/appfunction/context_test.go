@@ -17,6 +17,7 @@
 package appfunction
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -24,6 +25,7 @@ import (
 	"time"
 
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/bootstrap/container"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
 	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
 
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/interfaces/mocks"
@@ -34,8 +36,10 @@ import (
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
 	commonDtos "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/requests"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/responses"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -429,6 +433,25 @@ func TestContext_PushToCore(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestContext_PushToCore_NoApiVersion(t *testing.T) {
+	mockClient := clientMocks.EventClient{}
+	mockClient.On("Add", mock.Anything, mock.MatchedBy(func(request requests.AddEventRequest) bool {
+		return request.Event.ApiVersion == common.ApiVersion
+	})).Return(commonDtos.BaseWithIdResponse{}, nil)
+	dic.Update(di.ServiceConstructorMap{
+		container.EventClientName: func(get di.Get) interface{} {
+			return &mockClient
+		},
+	})
+
+	event := dtos.NewEvent("MyProfile", "MyDevice", "MyResource")
+	event.ApiVersion = ""
+
+	_, err := target.PushToCore(event)
+	require.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
+
 func TestContext_PushToCore_error(t *testing.T) {
 	dic.Update(di.ServiceConstructorMap{
 		container.EventClientName: func(get di.Get) interface{} {
@@ -463,3 +486,132 @@ func TestContext_GetDeviceResource_Error(t *testing.T) {
 	_, err := target.GetDeviceResource("MyProfile", "MyResource")
 	require.Error(t, err)
 }
+
+func TestContext_Clone(t *testing.T) {
+	original := NewContext("123", dic, "application/json")
+	original.AddValue("key1", "value1")
+	original.SetResponseData([]byte("response"))
+	original.SetResponseContentType("text/plain")
+	original.SetExecutionValue("state", 1)
+
+	clone := original.Clone()
+
+	assert.Equal(t, original.CorrelationID(), clone.CorrelationID())
+	assert.Equal(t, original.InputContentType(), clone.InputContentType())
+	assert.Equal(t, original.ResponseContentType(), clone.ResponseContentType())
+	assert.Equal(t, original.ResponseData(), clone.ResponseData())
+	assert.Equal(t, original.GetAllValues(), clone.GetAllValues())
+
+	// Changes to either context must not be seen by the other
+	clone.AddValue("key2", "value2")
+	original.RemoveValue("key1")
+	original.ResponseData()[0] = 'R'
+
+	_, found := original.GetValue("key2")
+	assert.False(t, found)
+	value, found := clone.GetValue("key1")
+	assert.True(t, found)
+	assert.Equal(t, "value1", value)
+	assert.Equal(t, []byte("response"), clone.ResponseData())
+
+	original.RemoveExecutionValue("state")
+	_, found = original.ExecutionValue("state")
+	assert.False(t, found)
+	state, found := clone.(*Context).ExecutionValue("state")
+	assert.True(t, found)
+	assert.Equal(t, 1, state)
+}
+
+func TestContext_FunctionName(t *testing.T) {
+	context := NewContext("123", dic, "")
+	assert.Equal(t, "", context.FunctionName())
+
+	context.SetPipelineFunction(2, testAppFunction)
+	assert.Equal(t, 2, context.PipelinePosition())
+	assert.Equal(t, "appfunction.testAppFunction", context.FunctionName())
+
+	holder := functionHolder{}
+	context.SetPipelineFunction(3, holder.Process)
+	assert.Equal(t, "appfunction.functionHolder.Process", context.FunctionName())
+
+	context.SetWrappedFunction(testAppFunction)
+	assert.Equal(t, 3, context.PipelinePosition())
+	assert.Equal(t, "appfunction.testAppFunction", context.FunctionName())
+}
+
+func TestContext_SerializedData(t *testing.T) {
+	context := NewContext("123", dic, "")
+	event := dtos.NewEvent("profile", "device", "source")
+
+	context.SetPipelineFunction(0, testAppFunction)
+	first, err := context.SerializedData(event)
+	require.NoError(t, err)
+	expected, err := json.Marshal(event)
+	require.NoError(t, err)
+	assert.Equal(t, expected, first)
+
+	// The next export reuses the bytes serialized by the previous one
+	context.SetPipelineFunction(1, testAppFunction)
+	second, err := context.SerializedData(event)
+	require.NoError(t, err)
+	assert.Same(t, &first[0], &second[0])
+
+	// Different data is serialized again
+	other := dtos.NewEvent("profile", "other", "source")
+	third, err := context.SerializedData(other)
+	require.NoError(t, err)
+	assert.NotSame(t, &first[0], &third[0])
+
+	// A function which didn't serialize the data may have modified it, so the bytes aren't reused after it
+	context.SetPipelineFunction(2, testAppFunction)
+	context.SetPipelineFunction(3, testAppFunction)
+	fourth, err := context.SerializedData(other)
+	require.NoError(t, err)
+	assert.NotSame(t, &third[0], &fourth[0])
+	assert.Equal(t, third, fourth)
+
+	raw, err := context.SerializedData("raw")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("raw"), raw)
+}
+
+func TestContext_SerializedData_CBOR(t *testing.T) {
+	image := []byte{0xff, 0xd8, 0xff, 0xe0}
+	event := dtos.NewEvent("camera", "device", "image")
+	event.AddBinaryReading("image", image, "image/jpeg")
+
+	expected, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	context := NewContext("123", dic, common.ContentTypeCBOR)
+	serialized, isCBOR, err := context.SerializedCBORData(event)
+	require.NoError(t, err)
+	require.True(t, isCBOR)
+
+	actual := dtos.Event{}
+	require.NoError(t, cbor.Unmarshal(serialized, &actual))
+	assert.Equal(t, image, actual.Readings[0].BinaryValue)
+	assert.Equal(t, "image/jpeg", actual.Readings[0].MediaType)
+
+	// Exports which don't opt in to CBOR always get JSON, even following a CBOR export of the same Event
+	serialized, err = context.SerializedData(event)
+	require.NoError(t, err)
+	assert.Equal(t, expected, serialized)
+
+	// Events received as JSON are serialized as JSON
+	context = NewContext("123", dic, common.ContentTypeJSON)
+	serialized, isCBOR, err = context.SerializedCBORData(event)
+	require.NoError(t, err)
+	assert.False(t, isCBOR)
+	assert.Equal(t, expected, serialized)
+}
+
+func testAppFunction(_ interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	return true, data
+}
+
+type functionHolder struct{}
+
+func (functionHolder) Process(_ interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	return true, data
+}
@@ -0,0 +1,178 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package appfunction
+
+import (
+	"strings"
+
+	sdkInterfaces "github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+)
+
+// Keys of the fields the context's LoggingClient adds to every log message, along with the correlation ID
+const (
+	PipelineLogKey = "pipeline"
+	FunctionLogKey = "function"
+	DeviceLogKey   = "device"
+)
+
+// logLevels orders the log levels so messages below the level of the LoggingClient are passed on as is, without the
+// cost of adding the fields
+var logLevels = map[string]int{
+	models.TraceLog: 0,
+	models.DebugLog: 1,
+	models.InfoLog:  2,
+	models.WarnLog:  3,
+	models.ErrorLog: 4,
+}
+
+// contextLogger adds the correlation ID, pipeline ID, function name and device name of the context, when available,
+// to every message logged. The fields are added as key/value pairs, or for formatted messages appended to the
+// message as key=value text, so that log aggregation tools can filter the messages of a message flow.
+type contextLogger struct {
+	logger.LoggingClient
+	context *Context
+}
+
+func (contextLogger contextLogger) Trace(msg string, args ...interface{}) {
+	if contextLogger.enabled(models.TraceLog) {
+		args = contextLogger.withFields(args)
+	}
+	contextLogger.LoggingClient.Trace(msg, args...)
+}
+
+func (contextLogger contextLogger) Debug(msg string, args ...interface{}) {
+	if contextLogger.enabled(models.DebugLog) {
+		args = contextLogger.withFields(args)
+	}
+	contextLogger.LoggingClient.Debug(msg, args...)
+}
+
+func (contextLogger contextLogger) Info(msg string, args ...interface{}) {
+	if contextLogger.enabled(models.InfoLog) {
+		args = contextLogger.withFields(args)
+	}
+	contextLogger.LoggingClient.Info(msg, args...)
+}
+
+func (contextLogger contextLogger) Warn(msg string, args ...interface{}) {
+	if contextLogger.enabled(models.WarnLog) {
+		args = contextLogger.withFields(args)
+	}
+	contextLogger.LoggingClient.Warn(msg, args...)
+}
+
+func (contextLogger contextLogger) Error(msg string, args ...interface{}) {
+	if contextLogger.enabled(models.ErrorLog) {
+		args = contextLogger.withFields(args)
+	}
+	contextLogger.LoggingClient.Error(msg, args...)
+}
+
+func (contextLogger contextLogger) Tracef(format string, args ...interface{}) {
+	if contextLogger.enabled(models.TraceLog) {
+		format, args = contextLogger.withFormattedFields(format, args)
+	}
+	contextLogger.LoggingClient.Tracef(format, args...)
+}
+
+func (contextLogger contextLogger) Debugf(format string, args ...interface{}) {
+	if contextLogger.enabled(models.DebugLog) {
+		format, args = contextLogger.withFormattedFields(format, args)
+	}
+	contextLogger.LoggingClient.Debugf(format, args...)
+}
+
+func (contextLogger contextLogger) Infof(format string, args ...interface{}) {
+	if contextLogger.enabled(models.InfoLog) {
+		format, args = contextLogger.withFormattedFields(format, args)
+	}
+	contextLogger.LoggingClient.Infof(format, args...)
+}
+
+func (contextLogger contextLogger) Warnf(format string, args ...interface{}) {
+	if contextLogger.enabled(models.WarnLog) {
+		format, args = contextLogger.withFormattedFields(format, args)
+	}
+	contextLogger.LoggingClient.Warnf(format, args...)
+}
+
+func (contextLogger contextLogger) Errorf(format string, args ...interface{}) {
+	if contextLogger.enabled(models.ErrorLog) {
+		format, args = contextLogger.withFormattedFields(format, args)
+	}
+	contextLogger.LoggingClient.Errorf(format, args...)
+}
+
+// enabled returns whether messages of the level are logged by the LoggingClient. Unknown levels are logged.
+func (contextLogger contextLogger) enabled(level string) bool {
+	return logLevels[level] >= logLevels[contextLogger.LoggingClient.LogLevel()]
+}
+
+// withFormattedFields appends a ' key=value' verb to the format, and the key and value to the arguments, for each
+// of the fields of the context which have a value
+func (contextLogger contextLogger) withFormattedFields(format string, args []interface{}) (string, []interface{}) {
+	fields := contextLogger.withFields(nil)
+
+	// Copied so the caller's arguments aren't modified
+	withFields := make([]interface{}, 0, len(args)+len(fields))
+	withFields = append(withFields, args...)
+	withFields = append(withFields, fields...)
+
+	return format + strings.Repeat(" %s=%s", len(fields)/2), withFields
+}
+
+// withFields appends the fields of the context which have a value and aren't already in the key/value pairs
+func (contextLogger contextLogger) withFields(args []interface{}) []interface{} {
+	appContext := contextLogger.context
+	deviceName, _ := appContext.GetValue(sdkInterfaces.DEVICENAME)
+
+	fields := []interface{}{
+		common.CorrelationHeader, appContext.CorrelationID(),
+		PipelineLogKey, appContext.PipelineId(),
+		FunctionLogKey, appContext.FunctionName(),
+		DeviceLogKey, deviceName,
+	}
+
+	// Copied so the caller's arguments aren't modified
+	withFields := make([]interface{}, 0, len(args)+len(fields))
+	withFields = append(withFields, args...)
+
+	for index := 0; index < len(fields); index += 2 {
+		key := fields[index]
+		value := fields[index+1].(string)
+		if len(value) == 0 || hasKey(args, key) {
+			continue
+		}
+		withFields = append(withFields, key, value)
+	}
+
+	return withFields
+}
+
+// hasKey returns whether the key is one of the keys of the key/value pairs
+func hasKey(args []interface{}, key interface{}) bool {
+	for index := 0; index < len(args); index += 2 {
+		if args[index] == key {
+			return true
+		}
+	}
+	return false
+}
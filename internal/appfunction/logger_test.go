@@ -0,0 +1,99 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package appfunction
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingLogger records the messages, or formats, and arguments logged at the debug and error levels
+type recordingLogger struct {
+	logger.LoggingClient
+	level    string
+	messages []string
+	args     [][]interface{}
+}
+
+func (recorder *recordingLogger) LogLevel() string {
+	return recorder.level
+}
+
+func (recorder *recordingLogger) Debug(msg string, args ...interface{}) {
+	recorder.messages = append(recorder.messages, msg)
+	recorder.args = append(recorder.args, args)
+}
+
+func (recorder *recordingLogger) Error(msg string, args ...interface{}) {
+	recorder.messages = append(recorder.messages, msg)
+	recorder.args = append(recorder.args, args)
+}
+
+func (recorder *recordingLogger) Errorf(format string, args ...interface{}) {
+	recorder.messages = append(recorder.messages, format)
+	recorder.args = append(recorder.args, args)
+}
+
+func TestContextLogger(t *testing.T) {
+	recorder := &recordingLogger{LoggingClient: logger.NewMockClient(), level: models.TraceLog}
+	appContext := NewContext("123", dic, "")
+	lc := contextLogger{LoggingClient: recorder, context: appContext}
+
+	// Only the fields with a value are added
+	lc.Debug("received")
+	assert.Equal(t, []interface{}{common.CorrelationHeader, "123"}, recorder.args[0])
+
+	appContext.SetPipelineId("default")
+	appContext.SetPipelineFunction(0, testAppFunction)
+	appContext.AddValue(interfaces.DEVICENAME, "Random-Integer-Device")
+
+	// Formatted messages are still logged as formatted messages, with the fields appended
+	lc.Errorf("failed with %d", 500)
+	assert.Equal(t,
+		"failed with 500 "+common.CorrelationHeader+"=123 pipeline=default function=appfunction.testAppFunction device=Random-Integer-Device",
+		fmt.Sprintf(recorder.messages[1], recorder.args[1]...))
+
+	// Fields already logged aren't repeated
+	lc.Debug("exported", "Transport", "HTTP", common.CorrelationHeader, "123")
+	assert.Equal(t, []interface{}{
+		"Transport", "HTTP",
+		common.CorrelationHeader, "123",
+		PipelineLogKey, "default",
+		FunctionLogKey, "appfunction.testAppFunction",
+		DeviceLogKey, "Random-Integer-Device",
+	}, recorder.args[2])
+}
+
+func TestContextLoggerLevelDisabled(t *testing.T) {
+	recorder := &recordingLogger{LoggingClient: logger.NewMockClient(), level: models.ErrorLog}
+	appContext := NewContext("123", dic, "")
+	lc := contextLogger{LoggingClient: recorder, context: appContext}
+
+	// Passed on as is, without the fields, as debug messages aren't logged
+	lc.Debug("received", "topic", "events")
+	assert.Equal(t, []interface{}{"topic", "events"}, recorder.args[0])
+
+	lc.Error("failed")
+	assert.Equal(t, []interface{}{common.CorrelationHeader, "123"}, recorder.args[1])
+}
@@ -0,0 +1,66 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package appfunction
+
+import "sync"
+
+// StateStore implements interfaces.StateStore for the state of a function pipeline
+type StateStore struct {
+	values map[string]interface{}
+	mutex  sync.Mutex
+}
+
+// NewStateStore creates a new empty StateStore
+func NewStateStore() *StateStore {
+	return &StateStore{
+		values: make(map[string]interface{}),
+	}
+}
+
+// Get returns the value stored for the key and whether it was found
+func (store *StateStore) Get(key string) (interface{}, bool) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	value, found := store.values[key]
+	return value, found
+}
+
+// Set stores the value for the key, replacing any existing value
+func (store *StateStore) Set(key string, value interface{}) {
+	store.mutex.Lock()
+	store.values[key] = value
+	store.mutex.Unlock()
+}
+
+// Delete removes the value stored for the key
+func (store *StateStore) Delete(key string) {
+	store.mutex.Lock()
+	delete(store.values, key)
+	store.mutex.Unlock()
+}
+
+// Update atomically replaces the value stored for the key with the value returned by the update function
+func (store *StateStore) Update(key string, update func(current interface{}, found bool) interface{}) interface{} {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	current, found := store.values[key]
+	value := update(current, found)
+	store.values[key] = value
+	return value
+}
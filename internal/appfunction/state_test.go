@@ -0,0 +1,66 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package appfunction
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateStore(t *testing.T) {
+	store := NewStateStore()
+
+	_, found := store.Get("key")
+	assert.False(t, found)
+
+	store.Set("key", "value")
+	value, found := store.Get("key")
+	require.True(t, found)
+	assert.Equal(t, "value", value)
+
+	store.Delete("key")
+	_, found = store.Get("key")
+	assert.False(t, found)
+}
+
+func TestStateStoreUpdate(t *testing.T) {
+	store := NewStateStore()
+	increment := func(current interface{}, found bool) interface{} {
+		if !found {
+			return 1
+		}
+		return current.(int) + 1
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store.Update("count", increment)
+		}()
+	}
+	wg.Wait()
+
+	value, found := store.Get("count")
+	require.True(t, found)
+	assert.Equal(t, 100, value)
+	assert.Equal(t, 101, store.Update("count", increment))
+}
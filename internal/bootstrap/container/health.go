@@ -0,0 +1,41 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package container
+
+import (
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+)
+
+// HealthChecker reports whether the service is ready to process data
+type HealthChecker interface {
+	// Ready returns an error describing why the service is not ready to process data, or nil if it is ready
+	Ready() error
+}
+
+// HealthCheckerName contains the name of the HealthChecker implementation in the DIC.
+var HealthCheckerName = di.TypeInstanceToName((*HealthChecker)(nil))
+
+// HealthCheckerFrom helper function queries the DIC and returns the HealthChecker implementation.
+func HealthCheckerFrom(get di.Get) HealthChecker {
+	item := get(HealthCheckerName)
+
+	if item == nil {
+		return nil
+	}
+
+	return item.(HealthChecker)
+}
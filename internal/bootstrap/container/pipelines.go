@@ -0,0 +1,60 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package container
+
+import (
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+)
+
+// PipelineInfo describes a function pipeline loaded by the service
+type PipelineInfo struct {
+	Id string `json:"id"`
+	// Topics are the topics the pipeline processes, empty for the default pipeline
+	Topics []string `json:"topics,omitempty"`
+	// ExecutionOrder is the names of the pipeline's functions, only known for configurable pipelines
+	ExecutionOrder []string `json:"executionOrder,omitempty"`
+	// Parameters are the parameters of the pipeline's functions by function name, with secrets redacted
+	Parameters    map[string]map[string]string `json:"parameters,omitempty"`
+	FunctionCount int                          `json:"functionCount"`
+	Paused        bool                         `json:"paused"`
+}
+
+// PipelineManager inspects and controls the function pipelines of the service
+type PipelineManager interface {
+	// Pipelines returns the pipelines loaded, the default pipeline first
+	Pipelines() []PipelineInfo
+	// PausePipeline stops the pipeline with the id from processing data until it is resumed
+	PausePipeline(id string) error
+	// ResumePipeline resumes processing data with the pipeline with the id
+	ResumePipeline(id string) error
+	// ReloadPipelines reloads the configurable pipelines from the current configuration
+	ReloadPipelines() error
+}
+
+// PipelineManagerName contains the name of the PipelineManager implementation in the DIC.
+var PipelineManagerName = di.TypeInstanceToName((*PipelineManager)(nil))
+
+// PipelineManagerFrom helper function queries the DIC and returns the PipelineManager implementation.
+func PipelineManagerFrom(get di.Get) PipelineManager {
+	item := get(PipelineManagerName)
+
+	if item == nil {
+		return nil
+	}
+
+	return item.(PipelineManager)
+}
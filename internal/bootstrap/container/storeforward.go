@@ -0,0 +1,48 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package container
+
+import (
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/store/contracts"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+)
+
+// StoreForwardManager manages the data items stored for later retry by Store and Forward
+type StoreForwardManager interface {
+	// StoredItems returns the data items currently stored for later retry
+	StoredItems() ([]contracts.StoredObject, error)
+	// PurgeStoredItems removes the stored data items with the ids, or all the items if no ids are specified,
+	// and returns the number of items removed
+	PurgeStoredItems(ids ...string) (int, error)
+	// RetryStoredItems retries the stored data items now rather than waiting for the next RetryInterval
+	RetryStoredItems() error
+}
+
+// StoreForwardManagerName contains the name of the StoreForwardManager implementation in the DIC.
+var StoreForwardManagerName = di.TypeInstanceToName((*StoreForwardManager)(nil))
+
+// StoreForwardManagerFrom helper function queries the DIC and returns the StoreForwardManager implementation.
+func StoreForwardManagerFrom(get di.Get) StoreForwardManager {
+	item := get(StoreForwardManagerName)
+
+	if item == nil {
+		return nil
+	}
+
+	return item.(StoreForwardManager)
+}
@@ -0,0 +1,41 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package container
+
+import (
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+)
+
+// VersionProvider reports the version of the application, which is set by the application rather than the SDK
+type VersionProvider interface {
+	// ApplicationVersion returns the version of the application
+	ApplicationVersion() string
+}
+
+// VersionProviderName contains the name of the VersionProvider implementation in the DIC.
+var VersionProviderName = di.TypeInstanceToName((*VersionProvider)(nil))
+
+// VersionProviderFrom helper function queries the DIC and returns the VersionProvider implementation.
+func VersionProviderFrom(get di.Get) VersionProvider {
+	item := get(VersionProviderName)
+
+	if item == nil {
+		return nil
+	}
+
+	return item.(VersionProvider)
+}
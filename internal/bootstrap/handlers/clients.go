@@ -17,15 +17,20 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 	"sync"
 
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/bootstrap/container"
 	clients "github.com/edgexfoundry/go-mod-core-contracts/v2/clients/http"
 
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/startup"
+	bootstrapConfig "github.com/edgexfoundry/go-mod-bootstrap/v2/config"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/interfaces"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-registry/v2/registry"
 )
 
 // Clients contains references to dependencies required by the Clients bootstrap implementation.
@@ -45,6 +50,8 @@ func (_ *Clients) BootstrapHandler(
 	dic *di.Container) bool {
 
 	config := container.ConfigurationFrom(dic.Get)
+	lc := bootstrapContainer.LoggingClientFrom(dic.Get)
+	registryClient := bootstrapContainer.RegistryFrom(dic.Get)
 
 	var eventClient interfaces.EventClient
 	var commandClient interfaces.CommandClient
@@ -57,22 +64,30 @@ func (_ *Clients) BootstrapHandler(
 	// Use of these client interfaces is optional, so they are not required to be configured. For instance if not
 	// sending commands, then don't need to have the Command client in the configuration.
 	if val, ok := config.Clients[common.CoreDataServiceKey]; ok {
-		eventClient = clients.NewEventClient(val.Url())
+		url := clientUrl(lc, registryClient, common.CoreDataServiceKey, val)
+		pooledClient, err := newEventClient(clients.NewEventClient(url), url, config.CoreDataClient)
+		if err != nil {
+			lc.Errorf("unable to create the Core Data client: %s", err.Error())
+			return false
+		}
+		eventClient = pooledClient
 	}
 
 	if val, ok := config.Clients[common.CoreCommandServiceKey]; ok {
-		commandClient = clients.NewCommandClient(val.Url())
+		commandClient = clients.NewCommandClient(clientUrl(lc, registryClient, common.CoreCommandServiceKey, val))
 	}
 
 	if val, ok := config.Clients[common.CoreMetaDataServiceKey]; ok {
-		deviceServiceClient = clients.NewDeviceServiceClient(val.Url())
-		deviceProfileClient = clients.NewDeviceProfileClient(val.Url())
-		deviceClient = clients.NewDeviceClient(val.Url())
+		url := clientUrl(lc, registryClient, common.CoreMetaDataServiceKey, val)
+		deviceServiceClient = clients.NewDeviceServiceClient(url)
+		deviceProfileClient = clients.NewDeviceProfileClient(url)
+		deviceClient = clients.NewDeviceClient(url)
 	}
 
 	if val, ok := config.Clients[common.SupportNotificationsServiceKey]; ok {
-		notificationClient = clients.NewNotificationClient(val.Url())
-		subscriptionClient = clients.NewSubscriptionClient(val.Url())
+		url := clientUrl(lc, registryClient, common.SupportNotificationsServiceKey, val)
+		notificationClient = clients.NewNotificationClient(url)
+		subscriptionClient = clients.NewSubscriptionClient(url)
 	}
 
 	// Note that all the clients are optional so some or all these clients may be nil
@@ -104,3 +119,19 @@ func (_ *Clients) BootstrapHandler(
 
 	return true
 }
+
+// clientUrl returns the URL for the client of the service. When the registry is used the endpoint the service
+// registered is used, so clients follow the service's registered location, otherwise the URL from configuration.
+func clientUrl(lc logger.LoggingClient, registryClient registry.Client, serviceKey string, clientInfo bootstrapConfig.ClientInfo) string {
+	if registryClient == nil {
+		return clientInfo.Url()
+	}
+
+	endpoint, err := registryClient.GetServiceEndpoint(serviceKey)
+	if err != nil {
+		lc.Warnf("unable to get endpoint for %s from the Registry, using configuration: %s", serviceKey, err.Error())
+		return clientInfo.Url()
+	}
+
+	return fmt.Sprintf("%s://%s:%v", clientInfo.Protocol, endpoint.Host, endpoint.Port)
+}
@@ -0,0 +1,160 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/interfaces"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/requests"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/fxamacker/cbor/v2"
+
+	sdkCommon "github.com/edgexfoundry/app-functions-sdk-go/v2/internal/common"
+)
+
+const defaultCoreDataIdleConns = 10
+
+// eventClient pushes events to Core Data using a connection pooled http.Client with keep-alives, optionally
+// limiting how many pushes are in progress at the same time. All other requests are made by the wrapped client.
+type eventClient struct {
+	interfaces.EventClient
+	baseUrl string
+	client  *http.Client
+	slots   chan struct{}
+}
+
+// newEventClient creates the Core Data event client for the base URL from the CoreDataClient configuration
+func newEventClient(wrapped interfaces.EventClient, baseUrl string, config sdkCommon.CoreDataClientConfig) (*eventClient, error) {
+	var timeout time.Duration
+	if strings.TrimSpace(config.Timeout) != "" {
+		var err error
+		if timeout, err = time.ParseDuration(config.Timeout); err != nil {
+			return nil, fmt.Errorf("invalid CoreDataClient Timeout '%s': %s", config.Timeout, err.Error())
+		}
+	}
+
+	idleConns := config.MaxIdleConns
+	if idleConns <= 0 {
+		idleConns = config.MaxConcurrentPushes
+	}
+	if idleConns <= 0 {
+		idleConns = defaultCoreDataIdleConns
+	}
+
+	// All the connections are to Core Data, so the per host limit, which defaults to 2, is what bounds reuse
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = idleConns
+	transport.MaxIdleConnsPerHost = idleConns
+
+	client := &eventClient{
+		EventClient: wrapped,
+		baseUrl:     strings.TrimSuffix(baseUrl, "/"),
+		client:      &http.Client{Transport: transport, Timeout: timeout},
+	}
+
+	if config.MaxConcurrentPushes > 0 {
+		client.slots = make(chan struct{}, config.MaxConcurrentPushes)
+	}
+
+	return client, nil
+}
+
+// Add pushes the event to Core Data, waiting for a push in progress to complete when the max concurrent pushes
+// are in progress. Events with binary readings are encoded as CBOR, all others as JSON.
+func (client *eventClient) Add(ctx context.Context, request requests.AddEventRequest) (common.BaseWithIdResponse, errors.EdgeX) {
+	var response common.BaseWithIdResponse
+
+	contentType := common.ContentTypeJSON
+	encode := json.Marshal
+	if hasBinaryReading(request.Event) {
+		contentType = common.ContentTypeCBOR
+		encode = cbor.Marshal
+	}
+
+	body, err := encode(request)
+	if err != nil {
+		return response, errors.NewCommonEdgeX(errors.KindContractInvalid, "failed to encode the AddEventRequest", err)
+	}
+
+	route := strings.Join([]string{
+		common.ApiEventRoute,
+		url.PathEscape(request.Event.ProfileName),
+		url.PathEscape(request.Event.DeviceName),
+		url.PathEscape(request.Event.SourceName),
+	}, "/")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, client.baseUrl+route, bytes.NewReader(body))
+	if err != nil {
+		return response, errors.NewCommonEdgeX(errors.KindServerError, "failed to create the Core Data request", err)
+	}
+	req.Header.Set(common.ContentType, contentType)
+	if correlationID, ok := ctx.Value(common.CorrelationHeader).(string); ok {
+		req.Header.Set(common.CorrelationHeader, correlationID)
+	}
+
+	if client.slots != nil {
+		select {
+		case client.slots <- struct{}{}:
+			defer func() { <-client.slots }()
+		case <-ctx.Done():
+			return response, errors.NewCommonEdgeX(errors.KindCommunicationError, "canceled waiting to push event to Core Data", ctx.Err())
+		}
+	}
+
+	resp, err := client.client.Do(req)
+	if err != nil {
+		return response, errors.NewCommonEdgeX(errors.KindCommunicationError, "failed to push event to Core Data", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// The body is always read in full so the connection can be reused
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return response, errors.NewCommonEdgeX(errors.KindCommunicationError, "failed to read the Core Data response", err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		message := fmt.Sprintf("pushing event to Core Data failed with %d HTTP status code: %s", resp.StatusCode, string(data))
+		return response, errors.NewCommonEdgeX(errors.KindMapping(resp.StatusCode), message, nil)
+	}
+
+	if err := json.Unmarshal(data, &response); err != nil {
+		return response, errors.NewCommonEdgeX(errors.KindContractInvalid, "failed to decode the Core Data response", err)
+	}
+
+	return response, nil
+}
+
+func hasBinaryReading(event dtos.Event) bool {
+	for _, reading := range event.Readings {
+		if reading.ValueType == common.ValueTypeBinary {
+			return true
+		}
+	}
+	return false
+}
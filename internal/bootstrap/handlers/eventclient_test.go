@@ -0,0 +1,148 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handlers
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/requests"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sdkCommon "github.com/edgexfoundry/app-functions-sdk-go/v2/internal/common"
+)
+
+func newTestAddEventRequest(valueType string) requests.AddEventRequest {
+	event := dtos.NewEvent("profile", "device", "source")
+	if valueType == common.ValueTypeBinary {
+		event.AddBinaryReading("source", []byte{1, 2, 3}, "application/octet-stream")
+	} else {
+		event.AddSimpleReading("source", valueType, int32(1))
+	}
+	return requests.NewAddEventRequest(event)
+}
+
+func TestNewEventClient(t *testing.T) {
+	client, err := newEventClient(nil, "http://localhost:59880/", sdkCommon.CoreDataClientConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:59880", client.baseUrl)
+	assert.Nil(t, client.slots)
+	assert.Equal(t, defaultCoreDataIdleConns, client.client.Transport.(*http.Transport).MaxIdleConnsPerHost)
+
+	client, err = newEventClient(nil, "http://localhost:59880", sdkCommon.CoreDataClientConfig{MaxConcurrentPushes: 4, Timeout: "5s"})
+	require.NoError(t, err)
+	assert.Equal(t, 4, cap(client.slots))
+	assert.Equal(t, 4, client.client.Transport.(*http.Transport).MaxIdleConnsPerHost)
+	assert.Equal(t, 5*time.Second, client.client.Timeout)
+
+	_, err = newEventClient(nil, "http://localhost:59880", sdkCommon.CoreDataClientConfig{Timeout: "bogus"})
+	assert.Error(t, err)
+}
+
+func TestEventClientAdd(t *testing.T) {
+	var contentType string
+	var route string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get(common.ContentType)
+		route = r.URL.EscapedPath()
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"apiVersion":"v2","statusCode":201,"id":"1234"}`))
+	}
+	ts := httptest.NewServer(http.HandlerFunc(handler))
+	defer ts.Close()
+
+	client, err := newEventClient(nil, ts.URL, sdkCommon.CoreDataClientConfig{})
+	require.NoError(t, err)
+
+	response, err := client.Add(context.Background(), newTestAddEventRequest(common.ValueTypeInt32))
+	require.NoError(t, err)
+	assert.Equal(t, "1234", response.Id)
+	assert.Equal(t, common.ContentTypeJSON, contentType)
+	assert.Equal(t, common.ApiEventRoute+"/profile/device/source", route)
+
+	_, err = client.Add(context.Background(), newTestAddEventRequest(common.ValueTypeBinary))
+	require.NoError(t, err)
+	assert.Equal(t, common.ContentTypeCBOR, contentType)
+}
+
+func TestEventClientAddFailed(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	ts := httptest.NewServer(http.HandlerFunc(handler))
+	defer ts.Close()
+
+	client, err := newEventClient(nil, ts.URL, sdkCommon.CoreDataClientConfig{})
+	require.NoError(t, err)
+
+	_, edgexErr := client.Add(context.Background(), newTestAddEventRequest(common.ValueTypeInt32))
+	require.Error(t, edgexErr)
+	assert.Equal(t, errors.KindContractInvalid, errors.Kind(edgexErr))
+}
+
+func TestEventClientAddReusesConnections(t *testing.T) {
+	var connections int32
+	var inProgress int32
+	var maxInProgress int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inProgress, 1)
+		for {
+			max := atomic.LoadInt32(&maxInProgress)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInProgress, max, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&inProgress, -1)
+		_, _ = w.Write([]byte(`{"apiVersion":"v2","statusCode":201}`))
+	}
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(handler))
+	ts.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&connections, 1)
+		}
+	}
+	ts.Start()
+	defer ts.Close()
+
+	client, err := newEventClient(nil, ts.URL, sdkCommon.CoreDataClientConfig{MaxConcurrentPushes: 2})
+	require.NoError(t, err)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.Add(context.Background(), newTestAddEventRequest(common.ValueTypeInt32))
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInProgress), int32(2))
+	assert.LessOrEqual(t, atomic.LoadInt32(&connections), int32(2))
+}
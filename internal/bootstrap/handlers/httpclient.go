@@ -0,0 +1,123 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handlers
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/startup"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+
+	sdkContainer "github.com/edgexfoundry/app-functions-sdk-go/v2/internal/bootstrap/container"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/common"
+)
+
+// HttpClient contains references to dependencies required by the HttpClient bootstrap implementation.
+type HttpClient struct {
+}
+
+// NewHttpClient create a new instance of HttpClient
+func NewHttpClient() *HttpClient {
+	return &HttpClient{}
+}
+
+// BootstrapHandler creates the shared, connection pooled http.Client used for outbound HTTP requests from the
+// HttpClient configuration and adds it to the DIC
+func (_ *HttpClient) BootstrapHandler(
+	_ context.Context,
+	_ *sync.WaitGroup,
+	_ startup.Timer,
+	dic *di.Container) bool {
+
+	lc := container.LoggingClientFrom(dic.Get)
+	config := sdkContainer.ConfigurationFrom(dic.Get)
+
+	client, err := newHttpClient(config.HttpClient)
+	if err != nil {
+		lc.Errorf("unable to create the HTTP client: %s", err.Error())
+		return false
+	}
+
+	dic.Update(di.ServiceConstructorMap{
+		sdkContainer.HttpClientName: func(get di.Get) interface{} {
+			return client
+		},
+	})
+
+	return true
+}
+
+// newHttpClient creates the http.Client from the configuration, using the defaults of http.DefaultTransport for
+// any settings not specified
+func newHttpClient(config common.HttpClientConfig) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	client := &http.Client{Transport: transport}
+
+	var err error
+	if client.Timeout, err = parseOptionalDuration("Timeout", config.Timeout); err != nil {
+		return nil, err
+	}
+
+	if config.IdleConnTimeout != "" {
+		if transport.IdleConnTimeout, err = parseOptionalDuration("IdleConnTimeout", config.IdleConnTimeout); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.MaxIdleConns > 0 {
+		transport.MaxIdleConns = config.MaxIdleConns
+	}
+
+	if config.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
+	}
+
+	if proxy := strings.TrimSpace(config.Proxy); proxy != "" {
+		proxyUrl, err := url.Parse(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid HttpClient Proxy '%s': %s", proxy, err.Error())
+		}
+		transport.Proxy = http.ProxyURL(proxyUrl)
+	}
+
+	if config.SkipCertVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	return client, nil
+}
+
+func parseOptionalDuration(name string, value string) (time.Duration, error) {
+	if strings.TrimSpace(value) == "" {
+		return 0, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid HttpClient %s '%s': %s", name, value, err.Error())
+	}
+
+	return duration, nil
+}
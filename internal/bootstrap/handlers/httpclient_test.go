@@ -0,0 +1,80 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/common"
+)
+
+func TestNewHttpClient(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Config      common.HttpClientConfig
+		ExpectError bool
+	}{
+		{"Defaults", common.HttpClientConfig{}, false},
+		{"All settings", common.HttpClientConfig{
+			Timeout:             "30s",
+			MaxIdleConns:        50,
+			MaxIdleConnsPerHost: 10,
+			IdleConnTimeout:     "1m",
+			Proxy:               "http://proxy:3128",
+			SkipCertVerify:      true,
+		}, false},
+		{"Invalid Timeout", common.HttpClientConfig{Timeout: "bogus"}, true},
+		{"Invalid IdleConnTimeout", common.HttpClientConfig{IdleConnTimeout: "bogus"}, true},
+		{"Invalid Proxy", common.HttpClientConfig{Proxy: "://proxy"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			client, err := newHttpClient(test.Config)
+			if test.ExpectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			transport := client.Transport.(*http.Transport)
+			defaultTransport := http.DefaultTransport.(*http.Transport)
+
+			if test.Config.Timeout == "" {
+				assert.Zero(t, client.Timeout)
+				assert.Equal(t, defaultTransport.MaxIdleConns, transport.MaxIdleConns)
+				assert.Equal(t, defaultTransport.IdleConnTimeout, transport.IdleConnTimeout)
+				return
+			}
+
+			assert.Equal(t, 30*time.Second, client.Timeout)
+			assert.Equal(t, 50, transport.MaxIdleConns)
+			assert.Equal(t, 10, transport.MaxIdleConnsPerHost)
+			assert.Equal(t, time.Minute, transport.IdleConnTimeout)
+			assert.True(t, transport.TLSClientConfig.InsecureSkipVerify)
+
+			proxy, err := transport.Proxy(&http.Request{})
+			require.NoError(t, err)
+			assert.Equal(t, "proxy:3128", proxy.Host)
+		})
+	}
+}
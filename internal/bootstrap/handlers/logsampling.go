@@ -0,0 +1,82 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handlers
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/startup"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+
+	sdkContainer "github.com/edgexfoundry/app-functions-sdk-go/v2/internal/bootstrap/container"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/logging"
+)
+
+// LogSampling contains references to dependencies required by the LogSampling bootstrap implementation.
+type LogSampling struct {
+}
+
+// NewLogSampling create a new instance of LogSampling
+func NewLogSampling() *LogSampling {
+	return &LogSampling{}
+}
+
+// BootstrapHandler replaces the LoggingClient in the DIC with a logging.SamplingLogger, which limits how often the
+// same error or warning is logged as configured in Writable.LogSampling
+func (_ *LogSampling) BootstrapHandler(
+	_ context.Context,
+	_ *sync.WaitGroup,
+	_ startup.Timer,
+	dic *di.Container) bool {
+
+	lc := container.LoggingClientFrom(dic.Get)
+	config := sdkContainer.ConfigurationFrom(dic.Get)
+
+	if _, err := ParseLogSamplingInterval(config.Writable.LogSampling.Interval); err != nil {
+		lc.Errorf("LogSampling Interval is invalid, repeated messages are not limited: %s", err.Error())
+	}
+
+	sampler := logging.NewSamplingLogger(lc, func() logging.SamplingSettings {
+		samplingConfig := sdkContainer.ConfigurationFrom(dic.Get).Writable.LogSampling
+		// Invalid intervals are reported when configured, so are treated as not limiting here
+		interval, _ := ParseLogSamplingInterval(samplingConfig.Interval)
+		return logging.SamplingSettings{
+			Interval:   interval,
+			MaxRepeats: samplingConfig.MaxRepeats,
+		}
+	})
+
+	dic.Update(di.ServiceConstructorMap{
+		container.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return sampler
+		},
+	})
+
+	return true
+}
+
+// ParseLogSamplingInterval parses the LogSampling Interval, which is zero when not specified
+func ParseLogSamplingInterval(interval string) (time.Duration, error) {
+	if len(strings.TrimSpace(interval)) == 0 {
+		return 0, nil
+	}
+	return time.ParseDuration(interval)
+}
@@ -23,20 +23,28 @@ import (
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/startup"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
 
+	sdkContainer "github.com/edgexfoundry/app-functions-sdk-go/v2/internal/bootstrap/container"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/telemetry"
 )
 
 // Telemetry contains references to dependencies required by the Telemetry bootstrap implementation.
 type Telemetry struct {
+	serviceKey string
+	instance   string
 }
 
-// New Telemetry create a new instance of Telemetry
-func NewTelemetry() *Telemetry {
-	return &Telemetry{}
+// New Telemetry create a new instance of Telemetry. The instance, if specified, tags all the custom metrics reported
+// so the metrics of replicas of the same service can be told apart.
+func NewTelemetry(serviceKey string, instance string) *Telemetry {
+	return &Telemetry{
+		serviceKey: serviceKey,
+		instance:   instance,
+	}
 }
 
-// BootstrapHandler starts the telemetry collection
-func (_ *Telemetry) BootstrapHandler(
+// BootstrapHandler adds the MetricsManager for custom metrics to the DIC, starts collecting the system metrics into it
+// and starts publishing the metrics to the EdgeX MessageBus as configured in Writable.Telemetry
+func (t *Telemetry) BootstrapHandler(
 	ctx context.Context,
 	wg *sync.WaitGroup,
 	_ startup.Timer,
@@ -44,8 +52,21 @@ func (_ *Telemetry) BootstrapHandler(
 
 	logger := container.LoggingClientFrom(dic.Get)
 
-	wg.Add(1)
-	go telemetry.StartCpuUsageAverage(wg, ctx, logger)
+	var commonTags map[string]string
+	if len(t.instance) > 0 {
+		commonTags = map[string]string{telemetry.InstanceTag: t.instance}
+	}
+
+	metricsManager := telemetry.NewMetricsManagerWithTags(commonTags)
+	dic.Update(di.ServiceConstructorMap{
+		sdkContainer.MetricsManagerName: func(get di.Get) interface{} {
+			return metricsManager
+		},
+	})
+
+	telemetry.NewSystemMetrics(metricsManager, logger).Start(ctx, wg)
+
+	telemetry.NewReporter(dic, t.serviceKey).Start(ctx, wg)
 
 	return true
 }
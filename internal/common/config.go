@@ -32,6 +32,8 @@ type WritableInfo struct {
 	Pipeline        PipelineInfo
 	StoreAndForward StoreAndForwardInfo
 	InsecureSecrets bootstrapConfig.InsecureSecrets
+	Telemetry       TelemetryInfo
+	LogSampling     LogSamplingInfo
 }
 
 // ConfigurationStruct
@@ -45,6 +47,10 @@ type ConfigurationStruct struct {
 	Service bootstrapConfig.ServiceInfo
 	// HttpServer contains the configuration for the HTTP Server
 	HttpServer HttpConfig
+	// HttpClient contains the configuration for the shared HTTP client used for outbound requests
+	HttpClient HttpClientConfig
+	// CoreDataClient contains the configuration for the connections used to push events to Core Data
+	CoreDataClient CoreDataClientConfig
 	// Trigger contains the configuration for the Function Pipeline Trigger
 	Trigger TriggerInfo
 	// ApplicationSettings contains the custom configuration for the Application service
@@ -60,12 +66,17 @@ type ConfigurationStruct struct {
 // TriggerInfo contains Metadata associated with each Trigger
 type TriggerInfo struct {
 	// Type of trigger to start pipeline
-	// enum: http, edgex-messagebus, or external-mqtt
+	// enum: http, edgex-messagebus, external-mqtt or benchmark
 	Type string
 	// Used when Type=edgex-messagebus
 	EdgexMessageBus MessageBusConfig
 	// Used when Type=external-mqtt
 	ExternalMqtt ExternalMqttConfig
+	// Used when Type=benchmark
+	Benchmark BenchmarkConfig
+	// ShutdownTimeout is how long to wait for the HTTP requests and pipeline executions in progress to complete
+	// when the service is stopped, i.e. '30s'. Defaults to 30 seconds if not specified.
+	ShutdownTimeout string
 }
 
 // HttpConfig contains the addition configuration for HTTP Server
@@ -79,6 +90,123 @@ type HttpConfig struct {
 	HTTPSCertName string
 	// HTTPSKeyName is name of the HTTPS key in the secret store
 	HTTPSKeyName string
+	// ClientCAName is the name of the PEM encoded CA certificate in the secret store used to verify client
+	// certificates. When specified, HTTPS clients must present a certificate signed by the CA (mutual TLS).
+	ClientCAName string
+	// CORS contains the Cross-Origin Resource Sharing configuration for all the routes, including custom routes
+	CORS CORSConfig
+	// Compression contains the configuration for compressing the responses of all the routes, including custom routes
+	Compression CompressionConfig
+	// Auth contains the configuration for authenticating requests to all the routes, including custom routes,
+	// other than the ping and health probe routes
+	Auth AuthConfig
+	// ReadTimeout is the maximum duration for reading an entire request, including the body, i.e. '30s'.
+	// No limit if not specified.
+	ReadTimeout string
+	// ReadHeaderTimeout is the maximum duration for reading the request headers, i.e. '5s', which protects
+	// against slow-loris requests. Defaults to ReadTimeout if not specified.
+	ReadHeaderTimeout string
+	// WriteTimeout is the maximum duration before timing out writing the response, i.e. '30s'.
+	// No limit if not specified.
+	WriteTimeout string
+	// IdleTimeout is the maximum duration to wait for the next request on a keep-alive connection, i.e. '2m'.
+	// Defaults to ReadTimeout if not specified.
+	IdleTimeout string
+	// MaxHeaderBytes is the maximum size, in bytes, of the request headers. Defaults to 1MB if not specified.
+	MaxHeaderBytes int
+	// StaticFiles contains the configuration for serving static files, i.e. a bundled UI
+	StaticFiles StaticFilesConfig
+	// SwaggerUIAssetsURL is the URL of vendored swagger-ui-dist assets the Swagger UI for the OpenAPI document loads,
+	// i.e. '/ui/swagger-ui' when served from the StaticFiles. Defaults to a pinned version on the unpkg CDN.
+	SwaggerUIAssetsURL string
+	// PrometheusRoute is the route, i.e. '/metrics', on which the system usage, pipeline, trigger and custom metrics
+	// are exposed in the Prometheus text exposition format. Not exposed if not specified.
+	PrometheusRoute string
+	// EnableProfiling exposes the net/http/pprof profiling routes under /debug/pprof/ for diagnosing memory and
+	// goroutine leaks. Not exposed if false.
+	EnableProfiling bool
+}
+
+// AuthConfig contains the configuration for authenticating requests to the webserver
+type AuthConfig struct {
+	// Type is the authentication required, either 'none', 'apikey' or 'jwt'. Defaults to 'none' if not specified.
+	// 'apikey' requires the API key in the APIKeyHeader request header.
+	// 'jwt' requires a JWT signed with HS256 in the 'Authorization: Bearer' request header.
+	Type string
+	// SecretName is the name in the secret store of the secret containing the 'apikey' or the 'jwtkey' used to
+	// validate the requests. The secret is retrieved for each request, so rotated secrets take effect immediately.
+	SecretName string
+	// APIKeyHeader is the request header containing the API key. Defaults to 'X-API-Key' if not specified.
+	APIKeyHeader string
+}
+
+// CORSConfig contains the Cross-Origin Resource Sharing configuration, which allows browser based applications
+// served from other origins to call the service's endpoints
+type CORSConfig struct {
+	// EnableCORS enables the CORS headers in responses and the handling of preflight requests
+	EnableCORS bool
+	// AllowedOrigins is a comma separated list of the origins allowed, i.e. 'https://localhost:3000', or '*' for any
+	AllowedOrigins string
+	// AllowedMethods is a comma separated list of the methods allowed, i.e. 'GET, POST, PUT, DELETE'
+	AllowedMethods string
+	// AllowedHeaders is a comma separated list of the request headers allowed, i.e. 'Content-Type, X-Correlation-ID'
+	AllowedHeaders string
+	// ExposeHeaders is a comma separated list of the response headers the browser exposes to the application
+	ExposeHeaders string
+	// AllowCredentials allows the requests to include credentials, i.e. cookies. Only applies to the origins listed
+	// in AllowedOrigins, not to those allowed by '*'.
+	AllowCredentials bool
+	// MaxAge is how long, in seconds, the browser may cache the results of a preflight request
+	MaxAge int
+}
+
+// StaticFilesConfig contains the configuration for serving the static files in a directory from the webserver
+type StaticFilesConfig struct {
+	// Route is the route prefix the files are served on, i.e. '/ui/'. Not served if not specified.
+	// The route can not be under the '/api' routes.
+	Route string
+	// Directory is the directory containing the files, i.e. './res/ui'. Directories are only served when they
+	// contain an 'index.html' file.
+	Directory string
+}
+
+// CompressionConfig contains the configuration for compressing the webserver responses
+type CompressionConfig struct {
+	// EnableGzip enables gzip compression of the responses to requests which accept it, per the Accept-Encoding header
+	EnableGzip bool
+	// Level is the gzip compression level, from 1 (best speed) to 9 (best compression). Defaults to 6 if not specified.
+	Level int
+}
+
+// HttpClientConfig contains the configuration for the shared, connection pooled HTTP client used by pipeline
+// functions for outbound requests. Settings not specified use the defaults of http.DefaultTransport.
+type HttpClientConfig struct {
+	// Timeout is the time limit for requests, i.e. '30s'. No limit if not specified.
+	Timeout string
+	// MaxIdleConns is the maximum number of idle connections kept across all hosts
+	MaxIdleConns int
+	// MaxIdleConnsPerHost is the maximum number of idle connections kept for each host
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept before being closed, i.e. '90s'
+	IdleConnTimeout string
+	// Proxy is the URL of the proxy to use. The HTTP_PROXY and HTTPS_PROXY environment variables are used if
+	// not specified.
+	Proxy string
+	// SkipCertVerify disables verification of the server's certificate. Only for testing.
+	SkipCertVerify bool
+}
+
+// CoreDataClientConfig contains the configuration for the connection pooled client used to push events to Core Data.
+// Connections are kept alive and reused so that pipelines which push every event don't pay for connection setup.
+type CoreDataClientConfig struct {
+	// MaxConcurrentPushes is the maximum number of events pushed to Core Data at the same time. Further pushes wait
+	// for one in progress to complete. No limit if not specified.
+	MaxConcurrentPushes int
+	// MaxIdleConns is the maximum number of idle connections to Core Data kept for reuse. Defaults to
+	// MaxConcurrentPushes, or 10 when that isn't specified.
+	MaxIdleConns int
+	// Timeout is the time limit for pushing an event, i.e. '30s'. No limit if not specified.
+	Timeout string
 }
 
 // MessageBusConfig defines the messaging information need to connect to the MessageBus
@@ -88,10 +216,13 @@ type MessageBusConfig struct {
 	SubscribeHost SubscribeHostInfo
 	// PublishHost contains the connection information for a publishing to the MessageBus
 	PublishHost PublishHostInfo
-	// Type indicates the message queue platform being used. eg. "redis", "mqtt" or "zero"
+	// Type indicates the message queue platform being used. eg. "redis" (Redis Pub/Sub), "mqtt", "nats-core",
+	// "nats-jetstream" or "zero"
 	Type string
 	// Optional contains all other properties of MessageBus that is specific to
 	// certain concrete implementation like MQTT's QoS, for example
+	// For secure connections, AuthMode ('usernamepassword', 'clientcert' or 'cacert') and SecretName name the
+	// secret in the secret store holding the credentials. A CA Cert in the secret is used for all modes.
 	Optional map[string]string
 }
 
@@ -117,6 +248,28 @@ type PublishHostInfo struct {
 	Protocol string
 	// PublishTopic is the topic in which to publish pipeline output (if any)
 	PublishTopic string
+	// Compression is the encoding, currently only 'gzip', used to compress the pipeline output published, which is
+	// flagged in the envelope's content type so subscribing app services decompress it transparently. Payloads of
+	// less than 256 bytes are published uncompressed. Not compressed if not specified.
+	Compression string
+}
+
+// BenchmarkConfig contains the configuration for the Benchmark Trigger, which generates events at a fixed rate and
+// reports the pipeline throughput and latency achieved
+type BenchmarkConfig struct {
+	// Rate is the number of events generated per second. Defaults to 100 if not specified.
+	Rate int
+	// ReadingCount is the number of readings in each event. Defaults to 1 if not specified.
+	ReadingCount int
+	// ReadingSize is the size in bytes of each reading's value. Defaults to 16 if not specified.
+	ReadingSize int
+	// Binary generates binary readings, sent as CBOR, rather than string readings, sent as JSON
+	Binary bool
+	// Duration is how long events are generated for, i.e. '5m'. Generated until the service is stopped if not specified.
+	Duration string
+	// ReportInterval is how often the throughput and latency are reported, i.e. '10s'. Defaults to 10 seconds if not
+	// specified.
+	ReportInterval string
 }
 
 // ExternalMqttConfig contains the MQTT broker configuration for MQTT Trigger
@@ -146,16 +299,90 @@ type ExternalMqttConfig struct {
 	// AuthMode indicates what to use when connecting to the broker. Options are "none", "cacert" , "usernamepassword", "clientcert".
 	// If a CA Cert exists in the SecretPath then it will be used for all modes except "none".
 	AuthMode string
+	// ContentType is the content type of the messages received, i.e. 'text/plain', which selects the decoder of
+	// the payloads. When not specified, payloads starting with '{' or '[' are JSON and all others are CBOR.
+	ContentType string
 }
 
+const (
+	// PipelineTargetTypeEvent is the PipelineInfo TargetType for pipelines which process EdgeX Events
+	PipelineTargetTypeEvent = "event"
+	// PipelineTargetTypeRaw is the PipelineInfo TargetType for pipelines which process the raw bytes received
+	PipelineTargetTypeRaw = "raw"
+)
+
 type PipelineInfo struct {
-	ExecutionOrder           string
+	ExecutionOrder string
+	// TargetType is the type of data the pipeline processes, either 'event' or 'raw'. When not specified the
+	// TargetType is 'raw' if UseTargetTypeOfByteArray is true, otherwise 'event'.
+	TargetType               string
 	UseTargetTypeOfByteArray bool
 	Functions                map[string]PipelineFunction
+	// PerTopicPipelines are additional pipelines, keyed by pipeline id, which process the data received on
+	// specific topics rather than the default pipeline specified by ExecutionOrder
+	PerTopicPipelines map[string]TopicPipeline
+	// MaxConcurrency is the maximum number of pipeline executions in progress at once. Data received once the limit
+	// is reached waits for an execution to complete. Unlimited if not specified or zero, except the data received
+	// from the MessageBus is always queued for a bounded number of workers, 64 when unlimited.
+	MaxConcurrency int
+	// MinConcurrency enables autoscaling of the executions in progress at once when greater than zero and less than
+	// MaxConcurrency. The limit then scales between MinConcurrency and MaxConcurrency based on the data waiting to be
+	// processed and the processing time, so bursts are absorbed without always allowing MaxConcurrency executions.
+	MinConcurrency int
+	// V1ProfileName enables the conversion of EdgeX V1 Events received to Event DTOs, for interoperating with V1
+	// device and core services. V1 Events have no device profile, so are given this profile name. V1 Events are
+	// rejected as invalid when not specified.
+	V1ProfileName string
+	// StrictValidation validates the Events received before the pipeline processes them
+	StrictValidation StrictValidationInfo
+}
+
+// StrictValidationInfo contains the configuration of the strict validation of the Events received. Invalid Events
+// are counted as invalid messages and, optionally, appended to a dead-letter file rather than being processed.
+type StrictValidationInfo struct {
+	// Enabled validates the readings of the Events received have the Event's device and profile names and a value
+	// of their value type, and that the origins are within MaxOriginSkew of the current time
+	Enabled bool
+	// ValidateProfiles also validates the value type of each reading is the one of the resource in the device
+	// profile, retrieved from Core Metadata which must be in the Clients configuration
+	ValidateProfiles bool
+	// ProfileCacheTTL is how long the device profiles retrieved are cached, i.e. '10m'. Defaults to '5m', which is
+	// also used when not greater than zero.
+	ProfileCacheTTL string
+	// MaxOriginSkew is how far the origins may be from the current time, i.e. '1h'. Not validated when empty.
+	MaxOriginSkew string
+	// DeadLetterDir is the directory the invalid Events are appended to, as received, in the 'dead-letter.log'
+	// file. Invalid Events are dropped when not specified.
+	DeadLetterDir string
+	// DeadLetterMaxSize is the size in bytes the dead-letter file is rotated at. Defaults to 10 MiB when zero.
+	DeadLetterMaxSize int64
+	// DeadLetterMaxFiles is the number of rotated dead-letter files retained. Defaults to 5 when zero.
+	DeadLetterMaxFiles int
+}
+
+// TopicPipeline contains the configuration of a pipeline which processes the data received on specific topics
+type TopicPipeline struct {
+	// Topics is a comma separated list of topics the pipeline processes. The '+' (single level) and
+	// '#' (multi level) wildcards are supported, i.e. 'edgex/events/device/+/Random-Integer-Device/#'.
+	// The topics must also be covered by the SubscribeTopics of the trigger.
+	Topics string
+	// ExecutionOrder is a comma separated list of the functions, from the Functions section, in the pipeline
+	ExecutionOrder string
 }
 
 type PipelineFunction struct {
-	// Name	string
+	// Name is the optional name of the built in or custom function configured, which allows the configuration to be
+	// keyed by any alias in the ExecutionOrder, i.e. 'ExportToCloud' and 'ExportToBackup' both using 'HTTPExport'.
+	// When empty the function is the one the configuration key starts with, i.e. 'HTTPExport2'.
+	Name string
+	// ExecuteOnlyIfMatched specifies the function is only executed if the preceding function, typically a filter,
+	// matched the data. The preceding function then doesn't stop the pipeline when the data isn't matched and
+	// the function following a group of these functions receives the data the preceding function received,
+	// allowing alternate exports, i.e. 'FilterByDeviceName, HTTPExport, FilterByProfileName, MQTTExport'.
+	ExecuteOnlyIfMatched bool
+	// Parameters are the parameters the function is configured with. '${NAME}' references in the values are
+	// replaced with the value of the NAME environment variable and '${secret:PATH/KEY}' references with the KEY
+	// secret at PATH from the secret provider when the pipeline is loaded.
 	Parameters map[string]string
 }
 
@@ -165,6 +392,28 @@ type StoreAndForwardInfo struct {
 	MaxRetryCount int
 }
 
+// TelemetryInfo contains the configuration for publishing the service's metrics to the EdgeX MessageBus
+type TelemetryInfo struct {
+	// Interval is how often the enabled metrics are published, i.e. '30s'. Metrics are not published if not
+	// specified or zero.
+	Interval string
+	// PublishTopicPrefix is the prefix of the topic the metrics are published to. The service key and the metric
+	// name are appended, i.e. 'edgex/telemetry/app-rules-engine/SystemUsage'
+	PublishTopicPrefix string
+	// Metrics enables the metrics to publish by name, either 'SystemUsage' or the name of a custom metric.
+	// Metrics not listed are not published.
+	Metrics map[string]bool
+}
+
+// LogSamplingInfo contains the configuration for limiting how often the same error or warning message is logged
+type LogSamplingInfo struct {
+	// Interval in which each distinct error or warning message is logged at most MaxRepeats times, i.e. '1m'. The
+	// repetitions suppressed are summarized once the interval has ended. Not limited if not specified.
+	Interval string
+	// MaxRepeats is how many times the same message is logged in each Interval. Defaults to 1 if not specified.
+	MaxRepeats int
+}
+
 // Credentials encapsulates username-password attributes.
 type Credentials struct {
 	Username string
@@ -0,0 +1,115 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package common
+
+import (
+	"strings"
+
+	bootstrapConfig "github.com/edgexfoundry/go-mod-bootstrap/v2/config"
+)
+
+// RedactedValue replaces the values of secrets in the redacted configuration
+const RedactedValue = "<redacted>"
+
+// sensitiveNames are the parts of setting names, in lower case, which indicate the value is a secret
+var sensitiveNames = []string{"password", "secret", "token", "apikey", "privatekey", "credential"}
+
+// SecretFunctionParameters are the names, in lower case, of the parameters the configurable pipeline functions
+// declare as containing secrets whatever their names, i.e. the 'Key' and 'InitVector' of EncryptWithAES
+var SecretFunctionParameters = map[string]bool{"key": true, "initvector": true}
+
+// Redacted returns a copy of the configuration with the values of secrets replaced by RedactedValue, so the
+// effective configuration can be reported without disclosing them. The InsecureSecrets, the Secret Store token
+// and the ApplicationSettings, MessageBus Optional and pipeline function Parameters settings with names
+// indicating a secret, i.e. 'Password', are redacted, as are the SecretFunctionParameters.
+func (c *ConfigurationStruct) Redacted() ConfigurationStruct {
+	redacted := *c
+
+	if c.Writable.InsecureSecrets != nil {
+		redacted.Writable.InsecureSecrets = make(bootstrapConfig.InsecureSecrets, len(c.Writable.InsecureSecrets))
+	}
+	for name, insecureSecret := range c.Writable.InsecureSecrets {
+		secrets := make(map[string]string, len(insecureSecret.Secrets))
+		for key := range insecureSecret.Secrets {
+			secrets[key] = RedactedValue
+		}
+		insecureSecret.Secrets = secrets
+		redacted.Writable.InsecureSecrets[name] = insecureSecret
+	}
+
+	if len(c.SecretStore.Authentication.AuthToken) > 0 {
+		redacted.SecretStore.Authentication.AuthToken = RedactedValue
+	}
+
+	redacted.ApplicationSettings = redactSettings(c.ApplicationSettings)
+	redacted.Trigger.EdgexMessageBus.Optional = redactSettings(c.Trigger.EdgexMessageBus.Optional)
+
+	if c.Writable.Pipeline.Functions != nil {
+		redacted.Writable.Pipeline.Functions = make(map[string]PipelineFunction, len(c.Writable.Pipeline.Functions))
+	}
+	for name, function := range c.Writable.Pipeline.Functions {
+		function.Parameters = redactParameters(function.Parameters)
+		redacted.Writable.Pipeline.Functions[name] = function
+	}
+
+	return redacted
+}
+
+// redactSettings returns a copy of the settings with the values of the settings whose names indicate a secret
+// replaced by RedactedValue
+func redactSettings(settings map[string]string) map[string]string {
+	if settings == nil {
+		return nil
+	}
+
+	redacted := make(map[string]string, len(settings))
+	for name, value := range settings {
+		if isSensitiveName(name) {
+			value = RedactedValue
+		}
+		redacted[name] = value
+	}
+	return redacted
+}
+
+// redactParameters returns a copy of the pipeline function parameters with the values of the
+// SecretFunctionParameters and of the parameters whose names indicate a secret replaced by RedactedValue
+func redactParameters(parameters map[string]string) map[string]string {
+	redacted := redactSettings(parameters)
+	for name := range redacted {
+		if SecretFunctionParameters[strings.ToLower(name)] {
+			redacted[name] = RedactedValue
+		}
+	}
+	return redacted
+}
+
+func isSensitiveName(name string) bool {
+	name = strings.ToLower(name)
+
+	// Settings such as 'SecretName' or 'SecretPath' refer to secrets in the Secret Store rather than contain them
+	if strings.HasSuffix(name, "name") || strings.HasSuffix(name, "path") {
+		return false
+	}
+
+	for _, sensitive := range sensitiveNames {
+		if strings.Contains(name, sensitive) {
+			return true
+		}
+	}
+	return false
+}
@@ -25,6 +25,32 @@ const (
 
 	ApiTriggerRoute   = common.ApiBase + "/trigger"
 	ApiAddSecretRoute = common.ApiBase + "/secret"
+	// ApiCustomMetricsRoute reports the custom metrics registered with the MetricsManager
+	ApiCustomMetricsRoute = common.ApiBase + "/metrics/custom"
+	// ApiStoreForwardRoute lists or purges the data stored for later retry by Store and Forward
+	ApiStoreForwardRoute = common.ApiBase + "/storeforward"
+	// ApiStoreForwardByIdRoute purges the data item stored for later retry with the id
+	ApiStoreForwardByIdRoute = ApiStoreForwardRoute + "/id/{" + common.Id + "}"
+	// ApiStoreForwardRetryRoute retries the data stored for later retry now
+	ApiStoreForwardRetryRoute = ApiStoreForwardRoute + "/retry"
+	// ApiHealthRoute is the base of the health probe routes
+	ApiHealthRoute = common.ApiBase + "/health"
+	// ApiHealthReadyRoute reports whether the service is ready to process data
+	ApiHealthReadyRoute = ApiHealthRoute + "/ready"
+	// ApiHealthLiveRoute reports whether the service is alive
+	ApiHealthLiveRoute = ApiHealthRoute + "/live"
+	// ApiPipelinesRoute reports the function pipelines loaded
+	ApiPipelinesRoute = common.ApiBase + "/pipelines"
+	// ApiPipelinesReloadRoute reloads the configurable pipelines from the current configuration
+	ApiPipelinesReloadRoute = ApiPipelinesRoute + "/reload"
+	// ApiPipelinePauseRoute pauses the pipeline with the id
+	ApiPipelinePauseRoute = ApiPipelinesRoute + "/id/{" + common.Id + "}/pause"
+	// ApiPipelineResumeRoute resumes the pipeline with the id
+	ApiPipelineResumeRoute = ApiPipelinesRoute + "/id/{" + common.Id + "}/resume"
+	// ApiOpenAPIRoute serves the OpenAPI document the application service registered for its custom routes
+	ApiOpenAPIRoute = common.ApiBase + "/openapi"
+	// ApiOpenAPIUIRoute serves the Swagger UI for the OpenAPI document, when enabled
+	ApiOpenAPIUIRoute = ApiOpenAPIRoute + "/ui"
 )
 
 // SDKVersion indicates the version of the SDK - will be overwritten by build
@@ -29,6 +29,7 @@ import (
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/bootstrap/container"
 	sdkCommon "github.com/edgexfoundry/app-functions-sdk-go/v2/internal/common"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/telemetry"
+	sdkInterfaces "github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
 
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/interfaces"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
@@ -44,6 +45,42 @@ type Controller struct {
 	secretProvider interfaces.SecretProvider
 	lc             logger.LoggingClient
 	config         *sdkCommon.ConfigurationStruct
+	dic            *di.Container
+}
+
+// customMetricsResponse is the response to the request for the custom metrics
+type customMetricsResponse struct {
+	commonDtos.BaseResponse `json:",inline"`
+	Metrics                 []telemetry.MetricSnapshot `json:"metrics"`
+}
+
+// pipelinesResponse is the response to the request for the function pipelines loaded
+type pipelinesResponse struct {
+	commonDtos.BaseResponse `json:",inline"`
+	Pipelines               []container.PipelineInfo `json:"pipelines"`
+}
+
+// storedItem summarizes a data item stored for later retry by Store and Forward
+type storedItem struct {
+	Id               string `json:"id"`
+	CorrelationId    string `json:"correlationId"`
+	ReceivedTopic    string `json:"receivedTopic,omitempty"`
+	PipelinePosition int    `json:"pipelinePosition"`
+	RetryCount       int    `json:"retryCount"`
+	PayloadSize      int    `json:"payloadSize"`
+}
+
+// storedItemsResponse is the response to the request for the data stored for later retry
+type storedItemsResponse struct {
+	commonDtos.BaseResponse `json:",inline"`
+	Count                   int          `json:"count"`
+	Items                   []storedItem `json:"items"`
+}
+
+// purgeStoredItemsResponse is the response to the request to purge the data stored for later retry
+type purgeStoredItemsResponse struct {
+	commonDtos.BaseResponse `json:",inline"`
+	Count                   int `json:"count"`
 }
 
 // NewController creates and initializes an Controller
@@ -53,6 +90,7 @@ func NewController(router *mux.Router, dic *di.Container) *Controller {
 		secretProvider: bootstrapContainer.SecretProviderFrom(dic.Get),
 		lc:             bootstrapContainer.LoggingClientFrom(dic.Get),
 		config:         container.ConfigurationFrom(dic.Get),
+		dic:            dic,
 	}
 }
 
@@ -66,14 +104,20 @@ func (c *Controller) Ping(writer http.ResponseWriter, request *http.Request) {
 // Version handles the request to /version endpoint. Is used to request the service's versions
 // It returns a response as specified by the V2 API swagger in openapi/v2
 func (c *Controller) Version(writer http.ResponseWriter, request *http.Request) {
-	response := commonDtos.NewVersionSdkResponse(internal.ApplicationVersion, internal.SDKVersion)
+	version := internal.ApplicationVersion
+	if provider := container.VersionProviderFrom(c.dic.Get); provider != nil {
+		version = provider.ApplicationVersion()
+	}
+
+	response := commonDtos.NewVersionSdkResponse(version, internal.SDKVersion)
 	c.sendResponse(writer, request, common.ApiVersionRoute, response, http.StatusOK)
 }
 
-// Config handles the request to /config endpoint. Is used to request the service's configuration
+// Config handles the request to /config endpoint. Is used to request the service's effective configuration,
+// with the secrets redacted.
 // It returns a response as specified by the V2 API swagger in openapi/v2
 func (c *Controller) Config(writer http.ResponseWriter, request *http.Request) {
-	response := commonDtos.NewConfigResponse(*c.config)
+	response := commonDtos.NewConfigResponse(c.config.Redacted())
 	c.sendResponse(writer, request, common.ApiVersionRoute, response, http.StatusOK)
 }
 
@@ -95,6 +139,213 @@ func (c *Controller) Metrics(writer http.ResponseWriter, request *http.Request)
 	c.sendResponse(writer, request, common.ApiMetricsRoute, response, http.StatusOK)
 }
 
+// CustomMetrics handles the request to the /metrics/custom endpoint, which reports the current values of the
+// custom metrics the application service registered with the MetricsManager
+func (c *Controller) CustomMetrics(writer http.ResponseWriter, request *http.Request) {
+	response := customMetricsResponse{
+		BaseResponse: commonDtos.NewBaseResponse("", "", http.StatusOK),
+		Metrics:      []telemetry.MetricSnapshot{},
+	}
+
+	if manager, ok := container.MetricsManagerFrom(c.dic.Get).(*telemetry.MetricsManager); ok {
+		response.Metrics = manager.Snapshot()
+	}
+
+	c.sendResponse(writer, request, internal.ApiCustomMetricsRoute, response, http.StatusOK)
+}
+
+// PrometheusMetrics handles the request to the configured HttpServer.PrometheusRoute, which reports the metrics
+// registered with the MetricsManager, including the system metrics, in the Prometheus text exposition format
+func (c *Controller) PrometheusMetrics(writer http.ResponseWriter, request *http.Request) {
+	manager, ok := container.MetricsManagerFrom(c.dic.Get).(*telemetry.MetricsManager)
+	if !ok {
+		manager = telemetry.NewMetricsManager()
+	}
+
+	writer.Header().Set(common.ContentType, telemetry.PrometheusContentType)
+	writer.WriteHeader(http.StatusOK)
+	if err := manager.WritePrometheus(writer); err != nil {
+		c.lc.Errorf("unable to write Prometheus metrics: %s", err.Error())
+	}
+}
+
+// StoredItems handles the request to the /storeforward endpoint, which lists the data items stored for
+// later retry by Store and Forward
+func (c *Controller) StoredItems(writer http.ResponseWriter, request *http.Request) {
+	manager := container.StoreForwardManagerFrom(c.dic.Get)
+	if manager == nil {
+		c.sendError(writer, request, errors.KindServiceUnavailable, "Store and Forward not available", nil, "")
+		return
+	}
+
+	items, err := manager.StoredItems()
+	if err != nil {
+		c.sendError(writer, request, errors.KindServiceUnavailable, "Retrieving stored data failed", err, "")
+		return
+	}
+
+	response := storedItemsResponse{
+		BaseResponse: commonDtos.NewBaseResponse("", "", http.StatusOK),
+		Count:        len(items),
+		Items:        make([]storedItem, 0, len(items)),
+	}
+
+	for _, item := range items {
+		response.Items = append(response.Items, storedItem{
+			Id:               item.ID,
+			CorrelationId:    item.CorrelationID,
+			ReceivedTopic:    item.ContextData[sdkInterfaces.RECEIVEDTOPIC],
+			PipelinePosition: item.PipelinePosition,
+			RetryCount:       item.RetryCount,
+			PayloadSize:      len(item.Payload),
+		})
+	}
+
+	c.sendResponse(writer, request, internal.ApiStoreForwardRoute, response, http.StatusOK)
+}
+
+// PurgeStoredItems handles the request to the /storeforward endpoint, which removes all the data items stored for
+// later retry, or to the /storeforward/id/{id} endpoint, which removes the data item with the id
+func (c *Controller) PurgeStoredItems(writer http.ResponseWriter, request *http.Request) {
+	manager := container.StoreForwardManagerFrom(c.dic.Get)
+	if manager == nil {
+		c.sendError(writer, request, errors.KindServiceUnavailable, "Store and Forward not available", nil, "")
+		return
+	}
+
+	var ids []string
+	if id, found := mux.Vars(request)[common.Id]; found {
+		ids = append(ids, id)
+	}
+
+	count, err := manager.PurgeStoredItems(ids...)
+	if err != nil {
+		kind := errors.KindServiceUnavailable
+		if len(ids) > 0 && count == 0 {
+			kind = errors.KindEntityDoesNotExist
+		}
+		c.sendError(writer, request, kind, "Purging stored data failed", err, "")
+		return
+	}
+
+	response := purgeStoredItemsResponse{
+		BaseResponse: commonDtos.NewBaseResponse("", "", http.StatusOK),
+		Count:        count,
+	}
+	c.sendResponse(writer, request, internal.ApiStoreForwardRoute, response, http.StatusOK)
+}
+
+// RetryStoredItems handles the request to the /storeforward/retry endpoint, which retries the data items stored
+// for later retry now rather than waiting for the next RetryInterval
+func (c *Controller) RetryStoredItems(writer http.ResponseWriter, request *http.Request) {
+	manager := container.StoreForwardManagerFrom(c.dic.Get)
+	if manager == nil {
+		c.sendError(writer, request, errors.KindServiceUnavailable, "Store and Forward not available", nil, "")
+		return
+	}
+
+	if err := manager.RetryStoredItems(); err != nil {
+		c.sendError(writer, request, errors.KindServiceUnavailable, "Retrying stored data failed", err, "")
+		return
+	}
+
+	response := commonDtos.NewBaseResponse("", "", http.StatusOK)
+	c.sendResponse(writer, request, internal.ApiStoreForwardRetryRoute, response, http.StatusOK)
+}
+
+// Pipelines handles the request to the /pipelines endpoint, which reports the function pipelines loaded, their
+// functions and whether they are paused
+func (c *Controller) Pipelines(writer http.ResponseWriter, request *http.Request) {
+	manager := container.PipelineManagerFrom(c.dic.Get)
+	if manager == nil {
+		c.sendError(writer, request, errors.KindServiceUnavailable, "Pipelines not available", nil, "")
+		return
+	}
+
+	response := pipelinesResponse{
+		BaseResponse: commonDtos.NewBaseResponse("", "", http.StatusOK),
+		Pipelines:    manager.Pipelines(),
+	}
+	c.sendResponse(writer, request, internal.ApiPipelinesRoute, response, http.StatusOK)
+}
+
+// PausePipeline handles the request to the /pipelines/id/{id}/pause endpoint, which stops the pipeline with the id
+// from processing data until it is resumed
+func (c *Controller) PausePipeline(writer http.ResponseWriter, request *http.Request) {
+	c.setPipelinePaused(writer, request, true)
+}
+
+// ResumePipeline handles the request to the /pipelines/id/{id}/resume endpoint, which resumes processing data with
+// the pipeline with the id
+func (c *Controller) ResumePipeline(writer http.ResponseWriter, request *http.Request) {
+	c.setPipelinePaused(writer, request, false)
+}
+
+func (c *Controller) setPipelinePaused(writer http.ResponseWriter, request *http.Request, paused bool) {
+	manager := container.PipelineManagerFrom(c.dic.Get)
+	if manager == nil {
+		c.sendError(writer, request, errors.KindServiceUnavailable, "Pipelines not available", nil, "")
+		return
+	}
+
+	id := mux.Vars(request)[common.Id]
+	setPaused := manager.ResumePipeline
+	if paused {
+		setPaused = manager.PausePipeline
+	}
+
+	if err := setPaused(id); err != nil {
+		c.sendError(writer, request, errors.KindEntityDoesNotExist, "Pipeline not found", err, "")
+		return
+	}
+
+	response := commonDtos.NewBaseResponse("", "", http.StatusOK)
+	c.sendResponse(writer, request, internal.ApiPipelinesRoute, response, http.StatusOK)
+}
+
+// ReloadPipelines handles the request to the /pipelines/reload endpoint, which reloads the configurable pipelines
+// from the current configuration
+func (c *Controller) ReloadPipelines(writer http.ResponseWriter, request *http.Request) {
+	manager := container.PipelineManagerFrom(c.dic.Get)
+	if manager == nil {
+		c.sendError(writer, request, errors.KindServiceUnavailable, "Pipelines not available", nil, "")
+		return
+	}
+
+	if err := manager.ReloadPipelines(); err != nil {
+		c.sendError(writer, request, errors.KindServerError, "Reloading pipelines failed", err, "")
+		return
+	}
+
+	response := commonDtos.NewBaseResponse("", "", http.StatusOK)
+	c.sendResponse(writer, request, internal.ApiPipelinesReloadRoute, response, http.StatusOK)
+}
+
+// Ready handles the request to the /health/ready endpoint, which responds with 200 when the service is ready to
+// process data and 503 when it is not, i.e. while it is starting or stopping
+func (c *Controller) Ready(writer http.ResponseWriter, request *http.Request) {
+	checker := container.HealthCheckerFrom(c.dic.Get)
+	if checker == nil {
+		c.sendError(writer, request, errors.KindServiceUnavailable, "Service not started", nil, "")
+		return
+	}
+
+	if err := checker.Ready(); err != nil {
+		c.sendError(writer, request, errors.KindServiceUnavailable, "Service not ready", err, "")
+		return
+	}
+
+	response := commonDtos.NewBaseResponse("", "", http.StatusOK)
+	c.sendResponse(writer, request, internal.ApiHealthReadyRoute, response, http.StatusOK)
+}
+
+// Live handles the request to the /health/live endpoint, which responds with 200 whenever the web server is able
+// to handle requests
+func (c *Controller) Live(writer http.ResponseWriter, request *http.Request) {
+	response := commonDtos.NewBaseResponse("", "", http.StatusOK)
+	c.sendResponse(writer, request, internal.ApiHealthLiveRoute, response, http.StatusOK)
+}
+
 // AddSecret handles the request to add App Service exclusive secret to the Secret Store
 // It returns a response as specified by the V2 API swagger in openapi/v2
 func (c *Controller) AddSecret(writer http.ResponseWriter, request *http.Request) {
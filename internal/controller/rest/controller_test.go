@@ -19,6 +19,7 @@ package rest
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -30,6 +31,8 @@ import (
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/bootstrap/container"
 	sdkCommon "github.com/edgexfoundry/app-functions-sdk-go/v2/internal/common"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/store/contracts"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/telemetry"
 
 	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/interfaces/mocks"
@@ -41,6 +44,7 @@ import (
 	commonDtos "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
 
 	"github.com/google/uuid"
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -89,6 +93,29 @@ func TestVersionRequest(t *testing.T) {
 	assert.Equal(t, common.ApiVersion, actual.ApiVersion)
 	assert.Equal(t, expectedAppVersion, actual.Version)
 	assert.Equal(t, expectedSdkVersion, actual.SdkVersion)
+
+	// The version set by the application overrides the version set when building
+	dic.Update(di.ServiceConstructorMap{
+		container.VersionProviderName: func(get di.Get) interface{} {
+			return fakeVersionProvider("2.0.1")
+		},
+	})
+	defer dic.Update(di.ServiceConstructorMap{
+		container.VersionProviderName: func(get di.Get) interface{} {
+			return nil
+		},
+	})
+
+	recorder = doRequest(t, http.MethodGet, common.ApiVersion, target.Version, nil)
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &actual))
+	assert.Equal(t, "2.0.1", actual.Version)
+	assert.Equal(t, expectedSdkVersion, actual.SdkVersion)
+}
+
+type fakeVersionProvider string
+
+func (version fakeVersionProvider) ApplicationVersion() string {
+	return string(version)
 }
 
 func TestMetricsRequest(t *testing.T) {
@@ -110,6 +137,260 @@ func TestMetricsRequest(t *testing.T) {
 	assert.NotNil(t, actual.Metrics.CpuBusyAvg)
 }
 
+func TestCustomMetricsRequest(t *testing.T) {
+	manager := telemetry.NewMetricsManager()
+	counter := manager.NewCounter()
+	counter.Inc(3)
+	require.NoError(t, manager.Register("EventsExported", counter, map[string]string{"destination": "http"}))
+
+	dic.Update(di.ServiceConstructorMap{
+		container.MetricsManagerName: func(get di.Get) interface{} {
+			return manager
+		},
+	})
+
+	target := NewController(nil, dic)
+
+	recorder := doRequest(t, http.MethodGet, internal.ApiCustomMetricsRoute, target.CustomMetrics, nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	actual := customMetricsResponse{}
+	err := json.Unmarshal(recorder.Body.Bytes(), &actual)
+	require.NoError(t, err)
+
+	assert.Equal(t, common.ApiVersion, actual.ApiVersion)
+	require.Len(t, actual.Metrics, 1)
+	assert.Equal(t, "EventsExported", actual.Metrics[0].Name)
+	assert.Equal(t, telemetry.MetricTypeCounter, actual.Metrics[0].Type)
+	assert.Equal(t, "http", actual.Metrics[0].Tags["destination"])
+	assert.Equal(t, float64(3), actual.Metrics[0].Values["count"])
+}
+
+func TestPrometheusMetricsRequest(t *testing.T) {
+	manager := telemetry.NewMetricsManager()
+	counter := manager.NewCounter()
+	counter.Inc(3)
+	require.NoError(t, manager.Register("EventsExported", counter, map[string]string{"destination": "http"}))
+
+	dic.Update(di.ServiceConstructorMap{
+		container.MetricsManagerName: func(get di.Get) interface{} {
+			return manager
+		},
+	})
+
+	target := NewController(nil, dic)
+
+	// Not using doRequest since the response isn't JSON
+	recorder := httptest.NewRecorder()
+	target.PrometheusMetrics(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	assert.Equal(t, telemetry.PrometheusContentType, recorder.Header().Get(common.ContentType))
+	assert.Contains(t, recorder.Body.String(), "# TYPE EventsExported counter\nEventsExported{destination=\"http\"} 3\n")
+}
+
+type fakeStoreForwardManager struct {
+	items   []contracts.StoredObject
+	retried bool
+}
+
+func (manager *fakeStoreForwardManager) StoredItems() ([]contracts.StoredObject, error) {
+	return manager.items, nil
+}
+
+func (manager *fakeStoreForwardManager) PurgeStoredItems(ids ...string) (int, error) {
+	if len(ids) > 0 && ids[0] != manager.items[0].ID {
+		return 0, errors.New("not found")
+	}
+	return len(manager.items), nil
+}
+
+func (manager *fakeStoreForwardManager) RetryStoredItems() error {
+	manager.retried = true
+	return nil
+}
+
+func TestStoreForwardRequests(t *testing.T) {
+	manager := &fakeStoreForwardManager{
+		items: []contracts.StoredObject{
+			{
+				ID:               uuid.NewString(),
+				CorrelationID:    expectedCorrelationId,
+				Payload:          []byte("payload"),
+				PipelinePosition: 2,
+				RetryCount:       3,
+				ContextData:      map[string]string{"receivedtopic": "edgex/events"},
+			},
+		},
+	}
+
+	dic.Update(di.ServiceConstructorMap{
+		container.StoreForwardManagerName: func(get di.Get) interface{} {
+			return manager
+		},
+	})
+
+	router := mux.NewRouter()
+	target := NewController(router, dic)
+	router.HandleFunc(internal.ApiStoreForwardRoute, target.StoredItems).Methods(http.MethodGet)
+	router.HandleFunc(internal.ApiStoreForwardByIdRoute, target.PurgeStoredItems).Methods(http.MethodDelete)
+	router.HandleFunc(internal.ApiStoreForwardRetryRoute, target.RetryStoredItems).Methods(http.MethodPost)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, internal.ApiStoreForwardRoute, nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	items := storedItemsResponse{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &items))
+	require.Equal(t, 1, items.Count)
+	assert.Equal(t, storedItem{
+		Id:               manager.items[0].ID,
+		CorrelationId:    expectedCorrelationId,
+		ReceivedTopic:    "edgex/events",
+		PipelinePosition: 2,
+		RetryCount:       3,
+		PayloadSize:      7,
+	}, items.Items[0])
+
+	recorder = httptest.NewRecorder()
+	path := strings.Replace(internal.ApiStoreForwardByIdRoute, "{"+common.Id+"}", manager.items[0].ID, 1)
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, path, nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	path = strings.Replace(internal.ApiStoreForwardByIdRoute, "{"+common.Id+"}", uuid.NewString(), 1)
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, path, nil))
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, internal.ApiStoreForwardRetryRoute, nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.True(t, manager.retried)
+}
+
+type fakeHealthChecker struct {
+	err error
+}
+
+func (checker *fakeHealthChecker) Ready() error {
+	return checker.err
+}
+
+func TestHealthRequests(t *testing.T) {
+	checker := &fakeHealthChecker{err: errors.New("trigger not initialized")}
+
+	dic.Update(di.ServiceConstructorMap{
+		container.HealthCheckerName: func(get di.Get) interface{} {
+			return checker
+		},
+	})
+
+	router := mux.NewRouter()
+	target := NewController(router, dic)
+	router.HandleFunc(internal.ApiHealthReadyRoute, target.Ready).Methods(http.MethodGet)
+	router.HandleFunc(internal.ApiHealthLiveRoute, target.Live).Methods(http.MethodGet)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, internal.ApiHealthLiveRoute, nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, internal.ApiHealthReadyRoute, nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	checker.err = nil
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, internal.ApiHealthReadyRoute, nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+type fakePipelineManager struct {
+	paused    map[string]bool
+	reloadErr error
+	reloaded  bool
+}
+
+func (manager *fakePipelineManager) Pipelines() []container.PipelineInfo {
+	return []container.PipelineInfo{
+		{Id: "default-pipeline", ExecutionOrder: []string{"HTTPExport"}, FunctionCount: 1, Paused: manager.paused["default-pipeline"]},
+	}
+}
+
+func (manager *fakePipelineManager) PausePipeline(id string) error {
+	if id != "default-pipeline" {
+		return fmt.Errorf("pipeline '%s' not found", id)
+	}
+	manager.paused[id] = true
+	return nil
+}
+
+func (manager *fakePipelineManager) ResumePipeline(id string) error {
+	if id != "default-pipeline" {
+		return fmt.Errorf("pipeline '%s' not found", id)
+	}
+	delete(manager.paused, id)
+	return nil
+}
+
+func (manager *fakePipelineManager) ReloadPipelines() error {
+	manager.reloaded = manager.reloadErr == nil
+	return manager.reloadErr
+}
+
+func TestPipelinesRequests(t *testing.T) {
+	manager := &fakePipelineManager{paused: map[string]bool{}}
+
+	dic.Update(di.ServiceConstructorMap{
+		container.PipelineManagerName: func(get di.Get) interface{} {
+			return manager
+		},
+	})
+
+	router := mux.NewRouter()
+	target := NewController(router, dic)
+	router.HandleFunc(internal.ApiPipelinesRoute, target.Pipelines).Methods(http.MethodGet)
+	router.HandleFunc(internal.ApiPipelinesReloadRoute, target.ReloadPipelines).Methods(http.MethodPost)
+	router.HandleFunc(internal.ApiPipelinePauseRoute, target.PausePipeline).Methods(http.MethodPost)
+	router.HandleFunc(internal.ApiPipelineResumeRoute, target.ResumePipeline).Methods(http.MethodPost)
+
+	pipelineRoute := func(route string, id string) string {
+		return strings.Replace(route, "{"+common.Id+"}", id, 1)
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, pipelineRoute(internal.ApiPipelinePauseRoute, "default-pipeline"), nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, internal.ApiPipelinesRoute, nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	actual := pipelinesResponse{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &actual))
+	require.Len(t, actual.Pipelines, 1)
+	assert.Equal(t, []string{"HTTPExport"}, actual.Pipelines[0].ExecutionOrder)
+	assert.True(t, actual.Pipelines[0].Paused)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, pipelineRoute(internal.ApiPipelineResumeRoute, "default-pipeline"), nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.False(t, manager.paused["default-pipeline"])
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, pipelineRoute(internal.ApiPipelinePauseRoute, "bogus"), nil))
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, internal.ApiPipelinesReloadRoute, nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.True(t, manager.reloaded)
+
+	manager.reloadErr = errors.New("pipelines are not configurable")
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, internal.ApiPipelinesReloadRoute, nil))
+	require.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
+
 func TestConfigRequest(t *testing.T) {
 	expectedConfig := sdkCommon.ConfigurationStruct{
 		Writable: sdkCommon.WritableInfo{
@@ -150,6 +431,68 @@ func TestConfigRequest(t *testing.T) {
 	assert.Equal(t, expectedConfig, actualConfig)
 }
 
+func TestConfigRequestRedacted(t *testing.T) {
+	config := sdkCommon.ConfigurationStruct{
+		Writable: sdkCommon.WritableInfo{
+			InsecureSecrets: bootstrapConfig.InsecureSecrets{
+				"mqtt": bootstrapConfig.InsecureSecretsInfo{
+					Path:    "mqtt",
+					Secrets: map[string]string{"username": "edgex", "password": "p@ssw0rd"},
+				},
+			},
+			Pipeline: sdkCommon.PipelineInfo{
+				Functions: map[string]sdkCommon.PipelineFunction{
+					"HTTPExport": {
+						Parameters: map[string]string{"Url": "http://localhost", "SecretPath": "http", "Token": "abc"},
+					},
+					"EncryptWithAES": {
+						Parameters: map[string]string{"Algorithm": "AES", "Key": "aquqweoruqwpeoruqwpoeruqwpoierupqoweiurpoqwiuerpqowieurqpowieurpoqiweuroipwqure", "InitVector": "123456789012345678901234567890"},
+					},
+				},
+			},
+		},
+		ApplicationSettings: map[string]string{"DeviceNames": "Random", "ApiKey": "xyz"},
+	}
+
+	dic.Update(di.ServiceConstructorMap{
+		container.ConfigurationName: func(get di.Get) interface{} {
+			return &config
+		},
+	})
+
+	target := NewController(nil, dic)
+
+	recorder := doRequest(t, http.MethodGet, common.ApiConfigRoute, target.Config, nil)
+
+	actualResponse := commonDtos.ConfigResponse{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &actualResponse))
+	configJson, err := json.Marshal(actualResponse.Config)
+	require.NoError(t, err)
+	actualConfig := sdkCommon.ConfigurationStruct{}
+	require.NoError(t, json.Unmarshal(configJson, &actualConfig))
+
+	assert.Equal(t, "mqtt", actualConfig.Writable.InsecureSecrets["mqtt"].Path)
+	assert.Equal(t, sdkCommon.RedactedValue, actualConfig.Writable.InsecureSecrets["mqtt"].Secrets["username"])
+	assert.Equal(t, sdkCommon.RedactedValue, actualConfig.Writable.InsecureSecrets["mqtt"].Secrets["password"])
+
+	parameters := actualConfig.Writable.Pipeline.Functions["HTTPExport"].Parameters
+	assert.Equal(t, "http://localhost", parameters["Url"])
+	assert.Equal(t, "http", parameters["SecretPath"])
+	assert.Equal(t, sdkCommon.RedactedValue, parameters["Token"])
+
+	parameters = actualConfig.Writable.Pipeline.Functions["EncryptWithAES"].Parameters
+	assert.Equal(t, "AES", parameters["Algorithm"])
+	assert.Equal(t, sdkCommon.RedactedValue, parameters["Key"])
+	assert.Equal(t, sdkCommon.RedactedValue, parameters["InitVector"])
+
+	assert.Equal(t, "Random", actualConfig.ApplicationSettings["DeviceNames"])
+	assert.Equal(t, sdkCommon.RedactedValue, actualConfig.ApplicationSettings["ApiKey"])
+
+	// The configuration in use isn't changed
+	assert.Equal(t, "p@ssw0rd", config.Writable.InsecureSecrets["mqtt"].Secrets["password"])
+	assert.Equal(t, "xyz", config.ApplicationSettings["ApiKey"])
+}
+
 func TestAddSecretRequest(t *testing.T) {
 	expectedRequestId := "82eb2e26-0f24-48aa-ae4c-de9dac3fb9bc"
 
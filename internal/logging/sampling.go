@@ -0,0 +1,164 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logging
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+)
+
+// maxTrackedMessages is the number of distinct messages tracked before the messages whose interval has ended are
+// forgotten, so messages which vary, i.e. include an ID, don't grow the tracked messages without bound
+const maxTrackedMessages = 1000
+
+// SamplingSettings are the settings of a SamplingLogger, which are read for every error and warning logged so they
+// can be changed at runtime
+type SamplingSettings struct {
+	// Interval in which each distinct message is logged at most MaxRepeats times. Not limited when zero.
+	Interval time.Duration
+	// MaxRepeats is how many times the same message is logged in each Interval
+	MaxRepeats int
+}
+
+// SamplingLogger is a LoggingClient which limits how often the same error or warning message is logged, so a
+// prolonged outage, i.e. an export endpoint being down, doesn't fill the disk. The repetitions suppressed during an
+// interval are summarized when the message is next logged after the interval. Messages at the other levels are
+// always logged.
+type SamplingLogger struct {
+	logger.LoggingClient
+	settings func() SamplingSettings
+	messages map[string]*sampledMessage
+	mutex    sync.Mutex
+	now      func() time.Time
+}
+
+// sampledMessage tracks how often a message was logged in the current interval, and the function it's logged with
+type sampledMessage struct {
+	msg           string
+	log           func(msg string, args ...interface{})
+	intervalStart time.Time
+	logged        int
+	suppressed    int
+}
+
+// NewSamplingLogger creates a SamplingLogger which logs to the LoggingClient using the current settings
+func NewSamplingLogger(lc logger.LoggingClient, settings func() SamplingSettings) *SamplingLogger {
+	return &SamplingLogger{
+		LoggingClient: lc,
+		settings:      settings,
+		messages:      make(map[string]*sampledMessage),
+		now:           time.Now,
+	}
+}
+
+func (sampler *SamplingLogger) Error(msg string, args ...interface{}) {
+	if sampler.allow("ERROR", msg, sampler.LoggingClient.Error) {
+		sampler.LoggingClient.Error(msg, args...)
+	}
+}
+
+func (sampler *SamplingLogger) Errorf(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	if sampler.allow("ERROR", msg, sampler.LoggingClient.Error) {
+		sampler.LoggingClient.Error(msg)
+	}
+}
+
+func (sampler *SamplingLogger) Warn(msg string, args ...interface{}) {
+	if sampler.allow("WARN", msg, sampler.LoggingClient.Warn) {
+		sampler.LoggingClient.Warn(msg, args...)
+	}
+}
+
+func (sampler *SamplingLogger) Warnf(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	if sampler.allow("WARN", msg, sampler.LoggingClient.Warn) {
+		sampler.LoggingClient.Warn(msg)
+	}
+}
+
+// allow returns whether the message logged at the level is allowed by the current settings. The repetitions
+// suppressed in the previous interval are summarized with the log function when a new interval starts.
+func (sampler *SamplingLogger) allow(level string, msg string, log func(msg string, args ...interface{})) bool {
+	settings := sampler.settings()
+	if settings.Interval <= 0 {
+		return true
+	}
+
+	maxRepeats := settings.MaxRepeats
+	if maxRepeats < 1 {
+		maxRepeats = 1
+	}
+
+	sampler.mutex.Lock()
+	defer sampler.mutex.Unlock()
+
+	now := sampler.now()
+	key := level + ":" + msg
+
+	message, found := sampler.messages[key]
+	if !found {
+		sampler.forgetEnded(now, settings.Interval)
+		message = &sampledMessage{msg: msg, log: log, intervalStart: now}
+		sampler.messages[key] = message
+	}
+
+	if now.Sub(message.intervalStart) >= settings.Interval {
+		message.summarize(settings.Interval)
+		message.intervalStart = now
+		message.logged = 0
+	}
+
+	if message.logged >= maxRepeats {
+		message.suppressed++
+		return false
+	}
+
+	message.logged++
+	return true
+}
+
+// forgetEnded forgets the messages whose interval has ended, summarizing their suppressed repetitions, once too many
+// messages are tracked. Must be called with the mutex locked.
+func (sampler *SamplingLogger) forgetEnded(now time.Time, interval time.Duration) {
+	if len(sampler.messages) < maxTrackedMessages {
+		return
+	}
+
+	for key, message := range sampler.messages {
+		if now.Sub(message.intervalStart) >= interval {
+			message.summarize(interval)
+			delete(sampler.messages, key)
+		}
+	}
+}
+
+// summarize logs how many repetitions of the message were suppressed in the interval, if any, and resets the count
+func (message *sampledMessage) summarize(interval time.Duration) {
+	if message.suppressed == 0 {
+		return
+	}
+
+	message.log("Suppressed repeated message",
+		"message", message.msg,
+		"repetitions", message.suppressed,
+		"interval", interval.String())
+	message.suppressed = 0
+}
@@ -0,0 +1,90 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logging
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingLogger records the messages and key/value pairs logged at the error level
+type recordingLogger struct {
+	logger.LoggingClient
+	messages []string
+	args     [][]interface{}
+}
+
+func (recorder *recordingLogger) Error(msg string, args ...interface{}) {
+	recorder.messages = append(recorder.messages, msg)
+	recorder.args = append(recorder.args, args)
+}
+
+func TestSamplingLogger(t *testing.T) {
+	recorder := &recordingLogger{LoggingClient: logger.NewMockClient()}
+	settings := SamplingSettings{Interval: time.Minute, MaxRepeats: 2}
+	sampler := NewSamplingLogger(recorder, func() SamplingSettings { return settings })
+
+	now := time.Now()
+	sampler.now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		sampler.Error("export failed", "attempt", i)
+	}
+	sampler.Errorf("export to %s failed", "cloud")
+	assert.Equal(t, []string{"export failed", "export failed", "export to cloud failed"}, recorder.messages)
+
+	// The suppressed repetitions are summarized when the message is logged after the interval
+	now = now.Add(time.Minute)
+	sampler.Error("export failed")
+	require.Len(t, recorder.messages, 5)
+	assert.Equal(t, "Suppressed repeated message", recorder.messages[3])
+	assert.Equal(t,
+		[]interface{}{"message", "export failed", "repetitions", 3, "interval", "1m0s"},
+		recorder.args[3])
+	assert.Equal(t, "export failed", recorder.messages[4])
+
+	// Not limited when the interval isn't set
+	settings.Interval = 0
+	for i := 0; i < 5; i++ {
+		sampler.Error("export failed")
+	}
+	assert.Len(t, recorder.messages, 10)
+}
+
+func TestSamplingLoggerForgetsEnded(t *testing.T) {
+	recorder := &recordingLogger{LoggingClient: logger.NewMockClient()}
+	sampler := NewSamplingLogger(recorder, func() SamplingSettings {
+		return SamplingSettings{Interval: time.Minute, MaxRepeats: 1}
+	})
+
+	now := time.Now()
+	sampler.now = func() time.Time { return now }
+
+	for i := 0; i < maxTrackedMessages; i++ {
+		sampler.Error(fmt.Sprintf("message %d", i))
+	}
+	assert.Len(t, sampler.messages, maxTrackedMessages)
+
+	now = now.Add(time.Minute)
+	sampler.Error("new message")
+	assert.Len(t, sampler.messages, 1)
+}
@@ -0,0 +1,129 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package runtime
+
+import (
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/requests"
+	edgexErrors "github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/fxamacker/cbor/v2"
+)
+
+// The CBOR shadows of the AddEventRequest, Event and BaseReading DTOs decode the binary reading values as slices of
+// the payload, rather than copies of them, so large binary readings, i.e. camera images, are only held in memory once.
+// The shadows' fields take precedence over the fields of the same name in the embedded DTOs.
+
+type cborAddEventRequest struct {
+	common.BaseRequest
+	Event *cborEvent `json:"event"`
+}
+
+type cborEvent struct {
+	dtos.Event
+	Readings []cborReading `json:"readings"`
+}
+
+type cborReading struct {
+	dtos.BaseReading
+	BinaryValue cborBinaryValue `json:"binaryValue,omitempty"`
+}
+
+// cborBinaryValue is a byte string which references the payload it was decoded from
+type cborBinaryValue []byte
+
+// UnmarshalCBOR receives the encoded byte string as a slice of the payload, so the value is the slice following the
+// byte string's header. Indefinite length byte strings, made up of chunks, are copied since they aren't contiguous.
+func (value *cborBinaryValue) UnmarshalCBOR(data []byte) error {
+	const majorTypeByteString = 2 << 5
+
+	if len(data) == 0 || data[0]&0xe0 != majorTypeByteString || data[0]&0x1f > 27 {
+		var copied []byte
+		if err := cbor.Unmarshal(data, &copied); err != nil {
+			return err
+		}
+		*value = copied
+		return nil
+	}
+
+	// The additional information is the length, or the number of bytes following which hold the length
+	headerSize := 1
+	switch data[0] & 0x1f {
+	case 24:
+		headerSize += 1
+	case 25:
+		headerSize += 2
+	case 26:
+		headerSize += 4
+	case 27:
+		headerSize += 8
+	}
+
+	*value = data[headerSize:len(data):len(data)]
+	return nil
+}
+
+// decodeCBOREvent decodes the CBOR payload, which is either an AddEventRequest or Event DTO, into the Event with the
+// binary reading values referencing the payload. The payload is decoded once as whichever DTO it holds, rather than
+// as an Event after first failing to decode it as an AddEventRequest.
+func decodeCBOREvent(payload []byte) (*dtos.Event, error) {
+	request := cborAddEventRequest{}
+	if err := cbor.Unmarshal(payload, &request); err != nil {
+		return nil, edgexErrors.NewCommonEdgeX(edgexErrors.KindContractInvalid, "failed to decode CBOR payload", err)
+	}
+
+	if request.Event != nil {
+		addEventRequest := requests.AddEventRequest{
+			BaseRequest: request.BaseRequest,
+			Event:       request.Event.toEvent(),
+		}
+		if err := addEventRequest.Validate(); err != nil {
+			return nil, err
+		}
+		if err := validateApiVersion(addEventRequest.ApiVersion); err != nil {
+			return nil, err
+		}
+		return &addEventRequest.Event, nil
+	}
+
+	shadow := cborEvent{}
+	if err := cbor.Unmarshal(payload, &shadow); err != nil {
+		return nil, edgexErrors.NewCommonEdgeX(edgexErrors.KindContractInvalid, "failed to decode CBOR payload", err)
+	}
+
+	event := shadow.toEvent()
+	if err := common.Validate(event); err != nil {
+		return nil, err
+	}
+	if err := validateApiVersion(event.ApiVersion); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (shadow cborEvent) toEvent() dtos.Event {
+	event := shadow.Event
+	event.Readings = nil
+	if shadow.Readings != nil {
+		event.Readings = make([]dtos.BaseReading, len(shadow.Readings))
+		for index, reading := range shadow.Readings {
+			event.Readings[index] = reading.BaseReading
+			event.Readings[index].BinaryValue = reading.BinaryValue
+		}
+	}
+	return event
+}
@@ -0,0 +1,126 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package runtime
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/appfunction"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/transforms"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/requests"
+	"github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeCBOREventBinaryReading(t *testing.T) {
+	image := bytes.Repeat([]byte{0xab}, 100000)
+	event := dtos.NewEvent("camera", "camera-1", "image")
+	event.AddBinaryReading("image", image, "image/jpeg")
+	// Fixed origins so the only 0xcd byte in the payloads is the one set below
+	event.Origin = 1
+	event.Readings[0].Origin = 1
+	request := requests.NewAddEventRequest(event)
+
+	requestPayload, err := cbor.Marshal(request)
+	require.NoError(t, err)
+	eventPayload, err := cbor.Marshal(event)
+	require.NoError(t, err)
+
+	for _, payload := range [][]byte{requestPayload, eventPayload} {
+		actual, err := decodeCBOREvent(payload)
+		require.NoError(t, err)
+		require.Len(t, actual.Readings, 1)
+		assert.Equal(t, event.DeviceName, actual.DeviceName)
+		assert.Equal(t, "image/jpeg", actual.Readings[0].MediaType)
+		assert.Equal(t, image, actual.Readings[0].BinaryValue)
+
+		// The binary value references the payload rather than a copy of it
+		require.Equal(t, -1, bytes.IndexByte(payload, 0xcd))
+		actual.Readings[0].BinaryValue[0] = 0xcd
+		assert.NotEqual(t, -1, bytes.IndexByte(payload, 0xcd))
+	}
+}
+
+func TestCBORBinaryValueUnmarshal(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Value []byte
+	}{
+		{"empty", []byte{}},
+		{"short", []byte{1, 2, 3}},
+		{"1 byte length", bytes.Repeat([]byte{1}, 200)},
+		{"2 byte length", bytes.Repeat([]byte{1}, 1000)},
+		{"4 byte length", bytes.Repeat([]byte{1}, 70000)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			encoded, err := cbor.Marshal(test.Value)
+			require.NoError(t, err)
+
+			var actual cborBinaryValue
+			require.NoError(t, actual.UnmarshalCBOR(encoded))
+			assert.Equal(t, test.Value, []byte(actual))
+		})
+	}
+
+	// Indefinite length byte strings are decoded by copying the chunks
+	var actual cborBinaryValue
+	require.NoError(t, actual.UnmarshalCBOR([]byte{0x5f, 0x42, 1, 2, 0x41, 3, 0xff}))
+	assert.Equal(t, []byte{1, 2, 3}, []byte(actual))
+}
+
+func TestProcessMessageCBORRoundTrip(t *testing.T) {
+	image := bytes.Repeat([]byte{0xab}, 1000)
+	event := dtos.NewEvent("camera", "camera-1", "image")
+	event.AddBinaryReading("image", image, "image/jpeg")
+	payload, err := cbor.Marshal(requests.NewAddEventRequest(event))
+	require.NoError(t, err)
+
+	envelope := types.MessageEnvelope{
+		CorrelationID: "123-234-345-456",
+		Payload:       payload,
+		ContentType:   common.ContentTypeCBOR,
+	}
+	context := appfunction.NewContext("testing", dic, common.ContentTypeCBOR)
+
+	runtime := GolangRuntime{}
+	runtime.Initialize(nil)
+	runtime.SetTransforms([]interfaces.AppFunction{transforms.ResponseData{PreserveCBOR: true}.SetResponseData})
+
+	require.Nil(t, runtime.ProcessMessage(context, envelope))
+
+	mediaType, found := context.GetValue(interfaces.MEDIATYPE)
+	require.True(t, found)
+	assert.Equal(t, "image/jpeg", mediaType)
+
+	// The binary value is re-encoded as a CBOR byte string rather than base64 encoded in JSON
+	assert.Equal(t, common.ContentTypeCBOR, context.ResponseContentType())
+	assert.Less(t, len(context.ResponseData()), len(image)+500)
+	actual := dtos.Event{}
+	require.NoError(t, cbor.Unmarshal(context.ResponseData(), &actual))
+	require.Len(t, actual.Readings, 1)
+	assert.Equal(t, image, actual.Readings[0].BinaryValue)
+	assert.Equal(t, "image/jpeg", actual.Readings[0].MediaType)
+}
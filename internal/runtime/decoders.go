@@ -0,0 +1,103 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/fxamacker/cbor/v2"
+)
+
+const (
+	// ContentTypeText is the content type of plain text payloads, which are decoded into string or []byte targets
+	ContentTypeText = "text/plain"
+	// ContentTypeOctetStream is the content type of binary payloads, which are decoded into []byte or string targets
+	ContentTypeOctetStream = "application/octet-stream"
+)
+
+// builtInPayloadDecoders are the decoders of the content types supported without any being registered
+var builtInPayloadDecoders = map[string]interfaces.PayloadDecoder{
+	common.ContentTypeJSON: json.Unmarshal,
+	common.ContentTypeCBOR: cbor.Unmarshal,
+	ContentTypeText:        decodeBytes,
+	ContentTypeOctetStream: decodeBytes,
+}
+
+// decodeBytes decodes the payload as is into a string or []byte target
+func decodeBytes(payload []byte, target interface{}) error {
+	switch value := target.(type) {
+	case *string:
+		*value = string(payload)
+	case *[]byte:
+		*value = append((*value)[:0], payload...)
+	default:
+		return fmt.Errorf("payload can only be decoded into a string or []byte, not %T", target)
+	}
+	return nil
+}
+
+// IsBuiltInPayloadContentType returns whether the content type is one of those decoded by the built in decoders
+func IsBuiltInPayloadContentType(contentType string) bool {
+	_, found := builtInPayloadDecoders[PayloadMediaType(contentType)]
+	return found
+}
+
+// PayloadMediaType returns the lowercase media type of the content type without its parameters, i.e. 'text/plain'
+// for 'text/plain; charset=utf-8', or the trimmed lowercase content type if it can't be parsed. Decoders are
+// registered and selected by this media type.
+func PayloadMediaType(contentType string) string {
+	parsed, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(contentType))
+	}
+	return parsed
+}
+
+// RegisterPayloadDecoder registers the decoder for the payloads received with the content type, replacing the
+// decoder of the content type if there is one, so custom payload formats can be processed
+func (gr *GolangRuntime) RegisterPayloadDecoder(contentType string, decoder interfaces.PayloadDecoder) {
+	gr.decodersMutex.Lock()
+	defer gr.decodersMutex.Unlock()
+
+	if gr.decoders == nil {
+		gr.decoders = make(map[string]interfaces.PayloadDecoder, len(builtInPayloadDecoders)+1)
+		for builtInType, builtInDecoder := range builtInPayloadDecoders {
+			gr.decoders[builtInType] = builtInDecoder
+		}
+	}
+	gr.decoders[PayloadMediaType(contentType)] = decoder
+}
+
+// payloadDecoder returns the decoder of the payloads received with the content type
+func (gr *GolangRuntime) payloadDecoder(contentType string) (interfaces.PayloadDecoder, bool) {
+	gr.decodersMutex.RLock()
+	defer gr.decodersMutex.RUnlock()
+
+	decoders := gr.decoders
+	if decoders == nil {
+		decoders = builtInPayloadDecoders
+	}
+
+	decoder, found := decoders[PayloadMediaType(contentType)]
+	return decoder, found
+}
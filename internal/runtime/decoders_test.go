@@ -0,0 +1,105 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package runtime
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/appfunction"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayloadMediaType(t *testing.T) {
+	assert.Equal(t, "text/plain", PayloadMediaType("Text/Plain; charset=utf-8"))
+	assert.Equal(t, common.ContentTypeJSON, PayloadMediaType(common.ContentTypeJSON))
+	assert.Equal(t, "", PayloadMediaType(""))
+
+	assert.True(t, IsBuiltInPayloadContentType("application/json; charset=utf-8"))
+	assert.True(t, IsBuiltInPayloadContentType(ContentTypeOctetStream))
+	assert.False(t, IsBuiltInPayloadContentType("application/x-protobuf"))
+}
+
+func TestDecodeBytes(t *testing.T) {
+	var text string
+	require.NoError(t, decodeBytes([]byte("hello"), &text))
+	assert.Equal(t, "hello", text)
+
+	var data []byte
+	require.NoError(t, decodeBytes([]byte("hello"), &data))
+	assert.Equal(t, []byte("hello"), data)
+
+	assert.Error(t, decodeBytes([]byte("hello"), &CustomType{}))
+}
+
+func TestProcessMessagePayloadDecoders(t *testing.T) {
+	customDecoder := func(payload []byte, target interface{}) error {
+		custom, ok := target.(*CustomType)
+		if !ok {
+			return errors.New("not a CustomType")
+		}
+		custom.ID = string(payload)
+		return nil
+	}
+
+	tests := []struct {
+		Name          string
+		TargetType    interface{}
+		ContentType   string
+		Expected      interface{}
+		ExpectedError bool
+	}{
+		{"Plain text", new(string), "text/plain; charset=utf-8", "payload", false},
+		{"Custom decoder", &CustomType{}, "application/x-custom", CustomType{ID: "payload"}, false},
+		{"Unsupported content type", &CustomType{}, "application/x-unknown", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var actual interface{}
+			capture := func(_ interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+				actual = data
+				return false, nil
+			}
+
+			runtime := GolangRuntime{TargetType: test.TargetType}
+			runtime.Initialize(nil)
+			runtime.SetTransforms([]interfaces.AppFunction{capture})
+			runtime.RegisterPayloadDecoder("application/x-custom", customDecoder)
+
+			envelope := types.MessageEnvelope{
+				CorrelationID: "123-234-345-456",
+				Payload:       []byte("payload"),
+				ContentType:   test.ContentType,
+			}
+			err := runtime.ProcessMessage(appfunction.NewContext("testing", dic, test.ContentType), envelope)
+			if test.ExpectedError {
+				require.NotNil(t, err)
+				assert.Nil(t, actual)
+				return
+			}
+
+			require.Nil(t, err)
+			assert.Equal(t, test.Expected, actual)
+		})
+	}
+}
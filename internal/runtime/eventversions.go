@@ -0,0 +1,65 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/util"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	edgexErrors "github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+)
+
+// validateApiVersion returns a KindContractInvalid error if the DTO received is of an API version other than the
+// version this SDK supports, rather than processing it as if it were
+func validateApiVersion(apiVersion string) error {
+	if apiVersion != common.ApiVersion {
+		return edgexErrors.NewCommonEdgeX(
+			edgexErrors.KindContractInvalid,
+			fmt.Sprintf("unsupported apiVersion '%s', expected '%s'", apiVersion, common.ApiVersion),
+			nil)
+	}
+	return nil
+}
+
+// decodeV1Event decodes the JSON payload as a V1 Event and converts it to an Event DTO with the profile name.
+// Payloads with an apiVersion aren't V1 Events so are rejected without being converted.
+func decodeV1Event(payload []byte, profileName string) (*dtos.Event, error) {
+	v1Event := struct {
+		util.V1Event
+		ApiVersion string `json:"apiVersion"`
+	}{}
+	if err := json.Unmarshal(payload, &v1Event); err != nil {
+		return nil, edgexErrors.NewCommonEdgeX(edgexErrors.KindContractInvalid, "failed to decode V1 Event", err)
+	}
+	if len(v1Event.ApiVersion) > 0 {
+		return nil, edgexErrors.NewCommonEdgeX(edgexErrors.KindContractInvalid, "payload is not a V1 Event", nil)
+	}
+
+	event, err := util.FromV1Event(v1Event.V1Event, profileName)
+	if err != nil {
+		return nil, edgexErrors.NewCommonEdgeX(edgexErrors.KindContractInvalid, "failed to convert V1 Event", err)
+	}
+	if err := common.Validate(event); err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
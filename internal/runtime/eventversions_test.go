@@ -0,0 +1,85 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package runtime
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/util"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/requests"
+	edgexErrors "github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessEventPayloadApiVersion(t *testing.T) {
+	event := dtos.NewEvent("profile", "device", "source")
+	require.NoError(t, event.AddSimpleReading("source", common.ValueTypeInt32, int32(1)))
+	request := requests.NewAddEventRequest(event)
+	request.ApiVersion = "v3"
+
+	payload, err := json.Marshal(request)
+	require.NoError(t, err)
+
+	runtime := GolangRuntime{}
+	envelope := types.MessageEnvelope{Payload: payload, ContentType: common.ContentTypeJSON}
+	_, err = runtime.processEventPayload(envelope, logger.NewMockClient())
+	require.Error(t, err)
+	assert.Equal(t, edgexErrors.KindContractInvalid, edgexErrors.Kind(err))
+	assert.Contains(t, err.Error(), "unsupported apiVersion 'v3'")
+}
+
+func TestProcessEventPayloadV1Event(t *testing.T) {
+	v1Event := util.V1Event{
+		Device:   "Random-Integer-Device",
+		Origin:   1600000000000000000,
+		Readings: []util.V1Reading{{Name: "Int32", Value: "42", ValueType: common.ValueTypeInt32}},
+	}
+	payload, err := json.Marshal(v1Event)
+	require.NoError(t, err)
+	envelope := types.MessageEnvelope{Payload: payload, ContentType: common.ContentTypeJSON}
+
+	// V1 Events are invalid unless their conversion is enabled
+	runtime := GolangRuntime{}
+	_, err = runtime.processEventPayload(envelope, logger.NewMockClient())
+	require.Error(t, err)
+	assert.Equal(t, edgexErrors.KindContractInvalid, edgexErrors.Kind(err))
+
+	runtime.V1ProfileName = "Random-Integer-Profile"
+	event, err := runtime.processEventPayload(envelope, logger.NewMockClient())
+	require.NoError(t, err)
+	assert.Equal(t, "Random-Integer-Device", event.DeviceName)
+	assert.Equal(t, "Random-Integer-Profile", event.ProfileName)
+	require.Len(t, event.Readings, 1)
+	assert.Equal(t, "42", event.Readings[0].Value)
+}
+
+func TestDecodeV1EventRejectsV2Event(t *testing.T) {
+	event := dtos.NewEvent("profile", "device", "source")
+	require.NoError(t, event.AddSimpleReading("source", common.ValueTypeInt32, int32(1)))
+	payload, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	_, err = decodeV1Event(payload, "profile")
+	assert.Error(t, err)
+}
@@ -0,0 +1,213 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package runtime
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// concurrencyLimiter limits the number of pipeline executions in progress at once. The limit can be changed while
+// executions are in progress and is unlimited when zero. The zero value is ready to use.
+type concurrencyLimiter struct {
+	mutex  sync.Mutex
+	cond   *sync.Cond
+	limit  int
+	active int
+
+	// The statistics of the current scaling interval, used to autoscale the limit
+	waiting    int
+	peakActive int
+	completed  int
+	totalTime  time.Duration
+}
+
+// acquire waits until an execution is allowed by the limit and counts it as active
+func (limiter *concurrencyLimiter) acquire() {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	limiter.waiting++
+	for limiter.limit > 0 && limiter.active >= limiter.limit {
+		limiter.condition().Wait()
+	}
+	limiter.waiting--
+
+	limiter.active++
+	if limiter.active > limiter.peakActive {
+		limiter.peakActive = limiter.active
+	}
+}
+
+// observe records the processing time of a completed execution
+func (limiter *concurrencyLimiter) observe(duration time.Duration) {
+	limiter.mutex.Lock()
+	limiter.completed++
+	limiter.totalTime += duration
+	limiter.mutex.Unlock()
+}
+
+// release stops counting an execution as active, allowing a waiting execution to proceed
+func (limiter *concurrencyLimiter) release() {
+	limiter.mutex.Lock()
+	limiter.active--
+	limiter.condition().Signal()
+	limiter.mutex.Unlock()
+}
+
+// setLimit changes the limit, releasing the waiting executions the new limit allows
+func (limiter *concurrencyLimiter) setLimit(limit int) {
+	limiter.mutex.Lock()
+	if limit < 0 {
+		limit = 0
+	}
+	limiter.limit = limit
+	limiter.condition().Broadcast()
+	limiter.mutex.Unlock()
+}
+
+// rescale sets the limit, between min and max, for the next scaling interval from the statistics of the interval
+// that ended and starts a new interval. Returns the previous and new limit.
+func (limiter *concurrencyLimiter) rescale(min int, max int, interval time.Duration) (int, int) {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	var meanTime time.Duration
+	if limiter.completed > 0 {
+		meanTime = limiter.totalTime / time.Duration(limiter.completed)
+	}
+
+	previous := limiter.limit
+	limiter.limit = nextLimit(previous, min, max, limiter.waiting, limiter.peakActive, meanTime, interval)
+	if limiter.limit > previous {
+		limiter.condition().Broadcast()
+	}
+
+	limiter.peakActive = limiter.active
+	limiter.completed = 0
+	limiter.totalTime = 0
+
+	return previous, limiter.limit
+}
+
+// nextLimit returns the limit, between min and max, for the next scaling interval given the current limit, the
+// executions waiting for it, the most executions in progress at once and their mean processing time. The limit is
+// raised enough to process the waiting executions within the next interval, while it's lowered one at a time when
+// it wasn't reached, so capacity isn't dropped during a short lull between bursts.
+func nextLimit(limit int, min int, max int, waiting int, peakActive int, meanTime time.Duration, interval time.Duration) int {
+	next := limit
+	switch {
+	case limit <= 0:
+		next = min
+	case waiting > 0:
+		extra := 1
+		if meanTime > 0 {
+			extra = int(math.Ceil(float64(waiting) * float64(meanTime) / float64(interval)))
+		}
+		next = limit + extra
+	case peakActive < limit:
+		next = limit - 1
+	}
+
+	if next < min {
+		next = min
+	}
+	if next > max {
+		next = max
+	}
+	return next
+}
+
+// condition returns the condition executions wait on, creating it on first use. Must be called with the mutex held.
+func (limiter *concurrencyLimiter) condition() *sync.Cond {
+	if limiter.cond == nil {
+		limiter.cond = sync.NewCond(&limiter.mutex)
+	}
+	return limiter.cond
+}
+
+// inFlightCounter counts the pipeline executions in progress, including the data queued for the workers. Unlike a
+// WaitGroup, executions can safely begin while waiting for those in progress. Once waiting has seen them all
+// complete no new executions begin, while those beginning before then, i.e. the data queued, are still counted
+// and waited for.
+type inFlightCounter struct {
+	mutex   sync.Mutex
+	count   int
+	closing bool
+	closed  bool
+	drained chan struct{}
+}
+
+// begin counts a new execution. Returns false if the executions in progress have completed after waiting started.
+func (counter *inFlightCounter) begin() bool {
+	counter.mutex.Lock()
+	defer counter.mutex.Unlock()
+
+	if counter.closed {
+		return false
+	}
+	counter.count++
+	return true
+}
+
+// end stops counting an execution
+func (counter *inFlightCounter) end() {
+	counter.mutex.Lock()
+	defer counter.mutex.Unlock()
+
+	counter.count--
+	if counter.count == 0 && counter.closing {
+		counter.close()
+	}
+}
+
+// wait waits for the executions in progress to complete, after which no new executions begin. Returns false if they
+// don't complete within the timeout.
+func (counter *inFlightCounter) wait(timeout time.Duration) bool {
+	counter.mutex.Lock()
+	counter.closing = true
+	if counter.count == 0 {
+		counter.close()
+	}
+	if counter.drained == nil {
+		counter.drained = make(chan struct{})
+		if counter.closed {
+			close(counter.drained)
+		}
+	}
+	drained := counter.drained
+	counter.mutex.Unlock()
+
+	select {
+	case <-drained:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// close stops new executions from beginning and releases the waiters. Must be called with the mutex held.
+func (counter *inFlightCounter) close() {
+	if counter.closed {
+		return
+	}
+	counter.closed = true
+	if counter.drained != nil {
+		close(counter.drained)
+	}
+}
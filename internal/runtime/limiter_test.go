@@ -0,0 +1,148 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package runtime
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	limiter := concurrencyLimiter{}
+	limiter.setLimit(1)
+
+	limiter.acquire()
+
+	var acquired int32
+	done := make(chan struct{})
+	go func() {
+		limiter.acquire()
+		atomic.StoreInt32(&acquired, 1)
+		limiter.release()
+		close(done)
+	}()
+
+	// Waits while the limit is reached
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&acquired))
+
+	// Raising the limit releases the waiting execution
+	limiter.setLimit(2)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		assert.Fail(t, "execution not released when the limit was raised")
+	}
+
+	limiter.release()
+
+	// Unlimited
+	limiter.setLimit(0)
+	for i := 0; i < 5; i++ {
+		limiter.acquire()
+	}
+	assert.Equal(t, 5, limiter.active)
+}
+
+func TestInFlightCounter(t *testing.T) {
+	counter := inFlightCounter{}
+
+	require.True(t, counter.begin())
+	assert.False(t, counter.wait(10*time.Millisecond))
+
+	// Executions begin while those in progress are waited for
+	require.True(t, counter.begin())
+
+	done := make(chan bool)
+	go func() { done <- counter.wait(time.Second) }()
+
+	counter.end()
+	counter.end()
+	assert.True(t, <-done)
+
+	// No new executions once those in progress have completed
+	assert.False(t, counter.begin())
+	assert.True(t, counter.wait(time.Millisecond))
+}
+
+func TestNextLimit(t *testing.T) {
+	tests := []struct {
+		Name       string
+		Limit      int
+		Waiting    int
+		PeakActive int
+		MeanTime   time.Duration
+		Expected   int
+	}{
+		{"Starts at min", 0, 0, 0, 0, 2},
+		{"Scales up for waiting executions", 4, 100, 4, 50 * time.Millisecond, 9},
+		{"Scales up by one when none completed", 4, 10, 4, 0, 5},
+		{"Scales up to no more than max", 4, 1000, 4, time.Second, 10},
+		{"Unchanged when limit reached without waiting", 4, 0, 4, 50 * time.Millisecond, 4},
+		{"Scales down by one when limit not reached", 8, 0, 3, 50 * time.Millisecond, 7},
+		{"Scales down to no less than min", 2, 0, 0, 0, 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			actual := nextLimit(test.Limit, 2, 10, test.Waiting, test.PeakActive, test.MeanTime, time.Second)
+			assert.Equal(t, test.Expected, actual)
+		})
+	}
+}
+
+func TestConcurrencyLimiterRescale(t *testing.T) {
+	limiter := concurrencyLimiter{}
+	limiter.setLimit(1)
+	limiter.acquire()
+
+	released := make(chan struct{})
+	go func() {
+		limiter.acquire()
+		close(released)
+	}()
+
+	// Wait for the second execution to be waiting for the limit
+	assert.Eventually(t, func() bool {
+		limiter.mutex.Lock()
+		defer limiter.mutex.Unlock()
+		return limiter.waiting == 1
+	}, time.Second, 10*time.Millisecond)
+
+	limiter.observe(100 * time.Millisecond)
+	previous, limit := limiter.rescale(1, 4, time.Second)
+	assert.Equal(t, 1, previous)
+	assert.Equal(t, 2, limit)
+
+	// Raising the limit releases the waiting execution
+	select {
+	case <-released:
+	case <-time.After(time.Second):
+		assert.Fail(t, "execution not released when the limit was scaled up")
+	}
+
+	// Scaled back down once the executions complete
+	limiter.release()
+	limiter.release()
+	limiter.rescale(1, 4, time.Second)
+	_, limit = limiter.rescale(1, 4, time.Second)
+	assert.Equal(t, 1, limit)
+}
@@ -0,0 +1,287 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package runtime
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+)
+
+// Names of the metrics the runtime registers with the MetricsManager
+const (
+	MessagesReceivedMetricName      = "PipelineMessagesReceived"
+	MessageErrorsMetricName         = "PipelineMessageErrors"
+	MessageProcessingTimeMetricName = "PipelineMessageProcessingTime"
+	FunctionDurationMetricName      = "PipelineFunctionDuration"
+	FunctionErrorsMetricName        = "PipelineFunctionErrors"
+	QueueDepthMetricName            = "PipelineQueueDepth"
+	TriggerMessagesMetricName       = "MessagesReceived"
+	TriggerInvalidMetricName        = "InvalidMessages"
+	TriggerBytesMetricName          = "BytesReceived"
+)
+
+// Tags of the pipeline function and trigger metrics
+const (
+	PipelineTag = "pipeline"
+	FunctionTag = "function"
+	PositionTag = "position"
+	TriggerTag  = "trigger"
+	TopicTag    = "topic"
+)
+
+// OtherTopics is the topic tag of the trigger metrics of the messages received on topics which match none of the
+// subscribed topic filters, once maxTopicSeries topics are tagged
+const OtherTopics = "other"
+
+// maxTopicSeries is the maximum number of topics the trigger metrics are tagged with, so that the metrics of the
+// messages received on topics which match none of the subscribed topic filters don't grow without bound
+const maxTopicSeries = 100
+
+// runtimeMetrics are the metrics of the messages processed by the pipelines. The nil value records nothing.
+type runtimeMetrics struct {
+	received       interfaces.Counter
+	errors         interfaces.Counter
+	processingTime interfaces.Timer
+	manager        interfaces.MetricsManager
+	lc             logger.LoggingClient
+	functions      map[string]*functionMetrics
+	mutex          sync.Mutex
+
+	queueDepth   interfaces.Gauge
+	queued       int64
+	triggerType  string
+	topicFilters []string
+	topics       map[string]*triggerMetrics
+}
+
+// functionMetrics are the metrics of a function at a position in a pipeline
+type functionMetrics struct {
+	duration interfaces.Timer
+	errors   interfaces.Counter
+	tags     map[string]string
+}
+
+// triggerMetrics are the metrics of the messages received by the trigger on a topic
+type triggerMetrics struct {
+	received interfaces.Counter
+	invalid  interfaces.Counter
+	bytes    interfaces.Counter
+}
+
+// newRuntimeMetrics creates the runtime metrics and registers them with the MetricsManager. The trigger metrics are
+// tagged with the trigger type and the subscribed topic filter the received topic matches.
+func newRuntimeMetrics(
+	manager interfaces.MetricsManager,
+	triggerType string,
+	topicFilters []string,
+	lc logger.LoggingClient) *runtimeMetrics {
+	metrics := &runtimeMetrics{
+		received:       manager.NewCounter(),
+		errors:         manager.NewCounter(),
+		processingTime: manager.NewTimer(),
+		manager:        manager,
+		lc:             lc,
+		functions:      make(map[string]*functionMetrics),
+		queueDepth:     manager.NewGauge(),
+		triggerType:    triggerType,
+		topicFilters:   topicFilters,
+		topics:         make(map[string]*triggerMetrics),
+	}
+
+	registrations := map[string]interface{}{
+		MessagesReceivedMetricName:      metrics.received,
+		MessageErrorsMetricName:         metrics.errors,
+		MessageProcessingTimeMetricName: metrics.processingTime,
+		QueueDepthMetricName:            metrics.queueDepth,
+	}
+
+	for name, metric := range registrations {
+		if err := manager.Register(name, metric, nil); err != nil {
+			lc.Warnf("Unable to register %s metric: %s", name, err.Error())
+		}
+	}
+
+	return metrics
+}
+
+// record records a message processed by the pipeline, which took the duration and failed or not
+func (metrics *runtimeMetrics) record(duration time.Duration, failed bool) {
+	if metrics == nil {
+		return
+	}
+
+	metrics.processingTime.Update(duration)
+	if failed {
+		metrics.errors.Inc(1)
+	}
+}
+
+// recordFunction records an execution of the function with the name at the position in the pipeline with the id,
+// which took the duration and failed or not
+func (metrics *runtimeMetrics) recordFunction(
+	pipelineId string,
+	position int,
+	functionName string,
+	duration time.Duration,
+	failed bool) {
+	if metrics == nil {
+		return
+	}
+
+	functionMetrics := metrics.function(pipelineId, position, functionName)
+	functionMetrics.duration.Update(duration)
+	if failed {
+		functionMetrics.errors.Inc(1)
+	}
+}
+
+// function returns the metrics of the function with the name at the position in the pipeline with the id, which are
+// created and registered the first time the function is executed
+func (metrics *runtimeMetrics) function(pipelineId string, position int, functionName string) *functionMetrics {
+	tags := map[string]string{
+		PipelineTag: pipelineId,
+		FunctionTag: functionName,
+		PositionTag: strconv.Itoa(position),
+	}
+	key := tags[PipelineTag] + "/" + tags[PositionTag] + "/" + tags[FunctionTag]
+
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	if functionMetrics, found := metrics.functions[key]; found {
+		return functionMetrics
+	}
+
+	functionMetrics := &functionMetrics{
+		duration: metrics.manager.NewTimer(),
+		errors:   metrics.manager.NewCounter(),
+		tags:     tags,
+	}
+	metrics.functions[key] = functionMetrics
+
+	metrics.registerLabeled(FunctionDurationMetricName, functionMetrics.duration, tags)
+	metrics.registerLabeled(FunctionErrorsMetricName, functionMetrics.errors, tags)
+
+	return functionMetrics
+}
+
+// resetFunctions unregisters the metrics of the functions executed so far, so the metrics of the functions removed
+// from the pipelines stop being reported when the pipelines are replaced
+func (metrics *runtimeMetrics) resetFunctions() {
+	if metrics == nil {
+		return
+	}
+
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	for key, functionMetrics := range metrics.functions {
+		metrics.manager.UnregisterLabeled(FunctionDurationMetricName, functionMetrics.tags)
+		metrics.manager.UnregisterLabeled(FunctionErrorsMetricName, functionMetrics.tags)
+		delete(metrics.functions, key)
+	}
+}
+
+// enqueue records a message received by the trigger on the topic, which waits to be processed by the pipeline. The
+// returned function must be called once the message has been processed.
+func (metrics *runtimeMetrics) enqueue(topic string, size int) func() {
+	if metrics == nil {
+		return func() {}
+	}
+
+	metrics.received.Inc(1)
+	triggerMetrics := metrics.trigger(topic)
+	triggerMetrics.received.Inc(1)
+	triggerMetrics.bytes.Inc(int64(size))
+
+	metrics.updateQueueDepth(1)
+	return func() { metrics.updateQueueDepth(-1) }
+}
+
+// recordInvalid records a message received by the trigger on the topic, which couldn't be decoded
+func (metrics *runtimeMetrics) recordInvalid(topic string) {
+	if metrics == nil {
+		return
+	}
+
+	metrics.trigger(topic).invalid.Inc(1)
+}
+
+// updateQueueDepth changes the number of messages waiting for or being processed by the pipelines
+func (metrics *runtimeMetrics) updateQueueDepth(delta int64) {
+	metrics.mutex.Lock()
+	metrics.queued += delta
+	metrics.queueDepth.Update(metrics.queued)
+	metrics.mutex.Unlock()
+}
+
+// trigger returns the metrics of the messages received on the topic, which are created and registered the first time
+// a message is received on a topic matching the same subscribed topic filter
+func (metrics *runtimeMetrics) trigger(topic string) *triggerMetrics {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	topic = metrics.topicTag(topic)
+	if triggerMetrics, found := metrics.topics[topic]; found {
+		return triggerMetrics
+	}
+
+	triggerMetrics := &triggerMetrics{
+		received: metrics.manager.NewCounter(),
+		invalid:  metrics.manager.NewCounter(),
+		bytes:    metrics.manager.NewCounter(),
+	}
+	metrics.topics[topic] = triggerMetrics
+
+	tags := map[string]string{
+		TriggerTag: metrics.triggerType,
+		TopicTag:   topic,
+	}
+	metrics.registerLabeled(TriggerMessagesMetricName, triggerMetrics.received, tags)
+	metrics.registerLabeled(TriggerInvalidMetricName, triggerMetrics.invalid, tags)
+	metrics.registerLabeled(TriggerBytesMetricName, triggerMetrics.bytes, tags)
+
+	return triggerMetrics
+}
+
+// topicTag returns the subscribed topic filter the topic matches. Topics matching none of the filters, such as those
+// of custom triggers, are tagged as received until maxTopicSeries topics are tagged and as OtherTopics after that.
+// Must be called with the mutex locked.
+func (metrics *runtimeMetrics) topicTag(topic string) string {
+	for _, filter := range metrics.topicFilters {
+		if topicMatches(filter, topic) {
+			return filter
+		}
+	}
+
+	if _, found := metrics.topics[topic]; !found && len(metrics.topics) >= maxTopicSeries {
+		return OtherTopics
+	}
+
+	return topic
+}
+
+func (metrics *runtimeMetrics) registerLabeled(name string, metric interface{}, tags map[string]string) {
+	if err := metrics.manager.RegisterLabeled(name, metric, tags); err != nil {
+		metrics.lc.Warnf("Unable to register %s metric: %s", name, err.Error())
+	}
+}
@@ -0,0 +1,199 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/telemetry"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuntimeMetrics(t *testing.T) {
+	manager := telemetry.NewMetricsManager()
+	metrics := newRuntimeMetrics(manager, "HTTP", nil, logger.NewMockClient())
+
+	assert.True(t, manager.IsRegistered(MessagesReceivedMetricName))
+	assert.True(t, manager.IsRegistered(MessageErrorsMetricName))
+	assert.True(t, manager.IsRegistered(MessageProcessingTimeMetricName))
+	assert.True(t, manager.IsRegistered(QueueDepthMetricName))
+
+	metrics.enqueue("edgex/events", 10)()
+	metrics.enqueue("edgex/events", 10)()
+	metrics.record(time.Second, false)
+	metrics.record(3*time.Second, true)
+
+	assert.Equal(t, int64(2), metrics.received.Count())
+	assert.Equal(t, int64(1), metrics.errors.Count())
+	assert.Equal(t, 2*time.Second, metrics.processingTime.Mean())
+
+	// The nil metrics record nothing, rather than panic, when no MetricsManager is available
+	var noMetrics *runtimeMetrics
+	assert.NotPanics(t, func() { noMetrics.record(time.Second, true) })
+}
+
+func TestRuntimeMetricsRecordFunction(t *testing.T) {
+	manager := telemetry.NewMetricsManager()
+	metrics := newRuntimeMetrics(manager, "HTTP", nil, logger.NewMockClient())
+
+	metrics.recordFunction("default", 0, "runtime.testFunction", time.Second, false)
+	metrics.recordFunction("default", 0, "runtime.testFunction", 3*time.Second, true)
+	metrics.recordFunction("other", 1, "runtime.testFunction", time.Second, false)
+
+	expectedTags := map[string]string{
+		PipelineTag: "default",
+		FunctionTag: "runtime.testFunction",
+		PositionTag: "0",
+	}
+
+	var duration, errors *telemetry.MetricSnapshot
+	snapshots := manager.Snapshot()
+	for index := range snapshots {
+		snapshot := snapshots[index]
+		if snapshot.Tags[PipelineTag] != "default" {
+			continue
+		}
+		switch snapshot.Name {
+		case FunctionDurationMetricName:
+			duration = &snapshot
+		case FunctionErrorsMetricName:
+			errors = &snapshot
+		}
+	}
+
+	require.NotNil(t, duration)
+	assert.Equal(t, expectedTags, duration.Tags)
+	assert.Equal(t, int64(2), duration.Values["count"])
+	assert.Equal(t, "2s", duration.Values["mean"])
+
+	require.NotNil(t, errors)
+	assert.Equal(t, expectedTags, errors.Tags)
+	assert.Equal(t, int64(1), errors.Values["count"])
+
+	// Registered once per pipeline and position, in addition to the message metrics
+	assert.Len(t, snapshots, 4+4)
+
+	var noMetrics *runtimeMetrics
+	assert.NotPanics(t, func() { noMetrics.recordFunction("default", 0, "runtime.testFunction", time.Second, true) })
+}
+
+func TestRuntimeMetricsResetFunctions(t *testing.T) {
+	manager := telemetry.NewMetricsManager()
+	metrics := newRuntimeMetrics(manager, "HTTP", nil, logger.NewMockClient())
+
+	metrics.recordFunction("default", 0, "transforms.Filter.FilterByDeviceName", time.Second, false)
+	require.Len(t, manager.Snapshot(), 4+2)
+
+	metrics.resetFunctions()
+	assert.Len(t, manager.Snapshot(), 4)
+	assert.Empty(t, metrics.functions)
+
+	// Registered again when executed after the reset
+	metrics.recordFunction("default", 0, "transforms.Filter.FilterByDeviceName", time.Second, false)
+	assert.Len(t, manager.Snapshot(), 4+2)
+
+	var noMetrics *runtimeMetrics
+	assert.NotPanics(t, noMetrics.resetFunctions)
+}
+
+func TestRuntimeMetricsTrigger(t *testing.T) {
+	manager := telemetry.NewMetricsManager()
+	metrics := newRuntimeMetrics(manager, "EDGEX-MESSAGEBUS", []string{"edgex/events/#"}, logger.NewMockClient())
+
+	dequeueFirst := metrics.enqueue("edgex/events/device/profile/Random-Float-Device", 10)
+	dequeueSecond := metrics.enqueue("edgex/events/device/profile/Random-Integer-Device", 5)
+	metrics.recordInvalid("edgex/events/device/profile/Random-Float-Device")
+	assert.Equal(t, int64(2), metrics.queueDepth.Value())
+
+	dequeueFirst()
+	dequeueSecond()
+	assert.Equal(t, int64(0), metrics.queueDepth.Value())
+
+	// Tagged with the subscribed topic filter rather than the received topics
+	expectedTags := map[string]string{TriggerTag: "EDGEX-MESSAGEBUS", TopicTag: "edgex/events/#"}
+	expectedCounts := map[string]int64{
+		TriggerMessagesMetricName: 2,
+		TriggerInvalidMetricName:  1,
+		TriggerBytesMetricName:    15,
+	}
+
+	for _, snapshot := range manager.Snapshot() {
+		expectedCount, found := expectedCounts[snapshot.Name]
+		if !found {
+			continue
+		}
+		assert.Equal(t, expectedTags, snapshot.Tags, snapshot.Name)
+		assert.Equal(t, expectedCount, snapshot.Values["count"], snapshot.Name)
+		delete(expectedCounts, snapshot.Name)
+	}
+	assert.Empty(t, expectedCounts, "trigger metrics not reported")
+
+	var noMetrics *runtimeMetrics
+	assert.NotPanics(t, func() {
+		noMetrics.enqueue("edgex/events", 10)()
+		noMetrics.recordInvalid("edgex/events")
+	})
+}
+
+func TestRuntimeEnqueueMetrics(t *testing.T) {
+	manager := telemetry.NewMetricsManager()
+	runtime := GolangRuntime{}
+	runtime.metrics = newRuntimeMetrics(manager, "EDGEX-MESSAGEBUS", nil, logger.NewMockClient())
+	runtime.workers = newWorkerQueue(1)
+	defer runtime.Stop()
+
+	release := make(chan struct{})
+	processed := make(chan struct{}, 2)
+	envelope := types.MessageEnvelope{ReceivedTopic: "edgex/events", Payload: []byte("data")}
+	process := func() {
+		<-release
+		processed <- struct{}{}
+	}
+
+	// Messages waiting for a worker are counted as well as the message being processed
+	require.True(t, runtime.Enqueue(context.Background(), envelope, process))
+	require.True(t, runtime.Enqueue(context.Background(), envelope, process))
+	assert.Equal(t, int64(2), runtime.metrics.queueDepth.Value())
+	assert.Equal(t, int64(2), runtime.metrics.received.Count())
+
+	close(release)
+	<-processed
+	<-processed
+	require.True(t, runtime.WaitForInFlight(time.Second))
+	assert.Equal(t, int64(0), runtime.metrics.queueDepth.Value())
+}
+
+func TestRuntimeMetricsTriggerTopicsCapped(t *testing.T) {
+	manager := telemetry.NewMetricsManager()
+	metrics := newRuntimeMetrics(manager, "HTTP", nil, logger.NewMockClient())
+
+	for index := 0; index < maxTopicSeries+10; index++ {
+		metrics.enqueue(fmt.Sprintf("topic/%d", index), 1)()
+	}
+	metrics.enqueue("topic/0", 1)()
+
+	assert.Len(t, metrics.topics, maxTopicSeries+1)
+	assert.Equal(t, int64(2), metrics.topics["topic/0"].received.Count())
+	assert.Equal(t, int64(10), metrics.topics[OtherTopics].received.Count())
+}
@@ -0,0 +1,152 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package runtime
+
+import (
+	"fmt"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+)
+
+// PipelineStatus describes a function pipeline the runtime executes
+type PipelineStatus struct {
+	Id            string
+	Topics        []string
+	FunctionCount int
+	Paused        bool
+}
+
+// Pipelines returns the status of the default pipeline followed by the per topic pipelines, in the order they are
+// matched against the received topic
+func (gr *GolangRuntime) Pipelines() []PipelineStatus {
+	gr.isBusyCopying.Lock()
+	defer gr.isBusyCopying.Unlock()
+
+	statuses := []PipelineStatus{{
+		Id:            defaultPipelineId,
+		FunctionCount: len(gr.transforms),
+		Paused:        gr.pausedPipelines[defaultPipelineId],
+	}}
+
+	for _, pipeline := range gr.topicPipelines {
+		statuses = append(statuses, PipelineStatus{
+			Id:            pipeline.Id,
+			Topics:        append([]string(nil), pipeline.Topics...),
+			FunctionCount: len(pipeline.Transforms),
+			Paused:        gr.pausedPipelines[pipeline.Id],
+		})
+	}
+
+	return statuses
+}
+
+// PausePipeline stops the pipeline with the id from processing data until it is resumed. The data received by the
+// MessageBus and MQTT triggers for the pipeline meanwhile is held until the pipeline is resumed, see WaitWhilePaused,
+// while the data received by the other triggers is rejected, so the HTTP trigger responds with 503 Service
+// Unavailable.
+func (gr *GolangRuntime) PausePipeline(id string) error {
+	return gr.setPipelinePaused(id, true)
+}
+
+// ResumePipeline resumes processing data with the pipeline with the id
+func (gr *GolangRuntime) ResumePipeline(id string) error {
+	return gr.setPipelinePaused(id, false)
+}
+
+func (gr *GolangRuntime) setPipelinePaused(id string, paused bool) error {
+	gr.isBusyCopying.Lock()
+	defer gr.isBusyCopying.Unlock()
+
+	if !gr.pipelineExists(id) {
+		return fmt.Errorf("pipeline '%s' not found", id)
+	}
+
+	if gr.pausedPipelines == nil {
+		gr.pausedPipelines = make(map[string]bool)
+	}
+
+	if paused {
+		gr.pausedPipelines[id] = true
+	} else {
+		delete(gr.pausedPipelines, id)
+		gr.releaseHeld()
+	}
+
+	return nil
+}
+
+// WaitWhilePaused waits while the pipeline for the topic is paused, so the triggers which receive data from a broker
+// hold the data rather than rejecting it. Holding the data stops the trigger from receiving more once the workers
+// are all waiting, so data for the other pipelines is then also held until the paused pipeline is resumed. Returns
+// false if the runtime is stopped while waiting, in which case the data held is dropped.
+func (gr *GolangRuntime) WaitWhilePaused(topic string) bool {
+	for {
+		gr.isBusyCopying.Lock()
+		id, _ := gr.stateOf(topic)
+		if !gr.pausedPipelines[id] {
+			gr.isBusyCopying.Unlock()
+			return true
+		}
+
+		if gr.heldDropped {
+			gr.isBusyCopying.Unlock()
+			return false
+		}
+
+		if gr.resumed == nil {
+			gr.resumed = make(chan struct{})
+		}
+		resumed := gr.resumed
+		gr.isBusyCopying.Unlock()
+
+		if gr.dic != nil {
+			bootstrapContainer.LoggingClientFrom(gr.dic.Get).Debugf(
+				"Pipeline '%s' is paused, holding the data received on topic '%s' until it is resumed", id, topic)
+		}
+		<-resumed
+	}
+}
+
+// releaseHeld wakes the data held by WaitWhilePaused, which checks again whether its pipeline is paused. Must be
+// called with isBusyCopying locked.
+func (gr *GolangRuntime) releaseHeld() {
+	if gr.resumed != nil {
+		close(gr.resumed)
+		gr.resumed = nil
+	}
+}
+
+// pipelineExists returns whether the pipeline with the id exists. Must be called with isBusyCopying locked.
+func (gr *GolangRuntime) pipelineExists(id string) bool {
+	if id == defaultPipelineId {
+		return true
+	}
+
+	for _, pipeline := range gr.topicPipelines {
+		if pipeline.Id == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (gr *GolangRuntime) isPipelinePaused(id string) bool {
+	gr.isBusyCopying.Lock()
+	defer gr.isBusyCopying.Unlock()
+	return gr.pausedPipelines[id]
+}
@@ -0,0 +1,108 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package runtime
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/appfunction"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPausePipeline(t *testing.T) {
+	executed := 0
+	transform := func(appContext interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		executed++
+		return true, data
+	}
+
+	runtime := GolangRuntime{}
+	runtime.Initialize(nil)
+	runtime.SetTransforms([]interfaces.AppFunction{transform})
+	runtime.SetTopicPipelines([]TopicPipeline{
+		{Id: "thermostats", Topics: []string{"edgex/events/#"}, Transforms: []interfaces.AppFunction{transform, transform}},
+	})
+
+	assert.Equal(t, []PipelineStatus{
+		{Id: interfaces.DefaultPipelineId, FunctionCount: 1},
+		{Id: "thermostats", Topics: []string{"edgex/events/#"}, FunctionCount: 2},
+	}, runtime.Pipelines())
+
+	assert.Error(t, runtime.PausePipeline("bogus"))
+	require.NoError(t, runtime.PausePipeline("thermostats"))
+	assert.True(t, runtime.Pipelines()[1].Paused)
+	assert.False(t, runtime.Pipelines()[0].Paused)
+
+	payload, err := json.Marshal(testAddEventRequest)
+	require.NoError(t, err)
+	envelope := types.MessageEnvelope{
+		CorrelationID: "123-234-345-456",
+		Payload:       payload,
+		ContentType:   common.ContentTypeJSON,
+		ReceivedTopic: "edgex/events/device",
+	}
+
+	result := runtime.ProcessMessage(appfunction.NewContext("testId", dic, ""), envelope)
+	require.NotNil(t, result)
+	assert.Equal(t, http.StatusServiceUnavailable, result.ErrorCode)
+	assert.Equal(t, 0, executed)
+
+	// The default pipeline isn't paused
+	envelope.ReceivedTopic = "other"
+	assert.Nil(t, runtime.ProcessMessage(appfunction.NewContext("testId", dic, ""), envelope))
+	assert.Equal(t, 1, executed)
+
+	require.NoError(t, runtime.ResumePipeline("thermostats"))
+	envelope.ReceivedTopic = "edgex/events/device"
+	assert.Nil(t, runtime.ProcessMessage(appfunction.NewContext("testId", dic, ""), envelope))
+	assert.Equal(t, 3, executed)
+}
+
+func TestWaitWhilePaused(t *testing.T) {
+	runtime := GolangRuntime{}
+	runtime.Initialize(nil)
+	runtime.SetTopicPipelines([]TopicPipeline{{Id: "thermostats", Topics: []string{"edgex/events/#"}}})
+	require.NoError(t, runtime.PausePipeline("thermostats"))
+
+	// Data for the other pipelines isn't held
+	assert.True(t, runtime.WaitWhilePaused("other"))
+
+	waiting := make(chan bool)
+	go func() { waiting <- runtime.WaitWhilePaused("edgex/events/device") }()
+	select {
+	case <-waiting:
+		require.Fail(t, "data not held while the pipeline is paused")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	require.NoError(t, runtime.ResumePipeline("thermostats"))
+	assert.True(t, <-waiting)
+
+	// The data held is dropped when the runtime is stopped
+	require.NoError(t, runtime.PausePipeline("thermostats"))
+	go func() { waiting <- runtime.WaitWhilePaused("edgex/events/device") }()
+	runtime.Stop()
+	assert.False(t, <-waiting)
+}
@@ -17,18 +17,25 @@
 package runtime
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/appfunction"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/bootstrap/container"
+	sdkCommon "github.com/edgexfoundry/app-functions-sdk-go/v2/internal/common"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/util"
 
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
@@ -37,18 +44,54 @@ import (
 	edgexErrors "github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
 	"github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
-
-	"github.com/fxamacker/cbor/v2"
 )
 
 // GolangRuntime represents the golang runtime environment
 type GolangRuntime struct {
-	TargetType    interface{}
-	ServiceKey    string
-	transforms    []interfaces.AppFunction
-	isBusyCopying sync.Mutex
-	storeForward  storeForwardInfo
-	dic           *di.Container
+	TargetType     interface{}
+	ServiceKey     string
+	Instance       string
+	transforms     []interfaces.AppFunction
+	topicPipelines []TopicPipeline
+	isBusyCopying  sync.Mutex
+	storeForward   storeForwardInfo
+	dic            *di.Container
+	pipelineStates map[string]*appfunction.StateStore
+	inFlight       inFlightCounter
+	limiter        concurrencyLimiter
+	scalerMutex    sync.Mutex
+	stopScaler     chan struct{}
+	workers        *workerQueue
+	stopped        bool
+	metrics        *runtimeMetrics
+	decoders       map[string]interfaces.PayloadDecoder
+	decodersMutex  sync.RWMutex
+	validator      eventValidator
+
+	// pausedPipelines are the ids of the pipelines paused
+	pausedPipelines map[string]bool
+	// resumed is closed when a pipeline is resumed or the runtime is stopped, to wake the data held while paused
+	resumed chan struct{}
+	// heldDropped is set when the runtime is stopped, so the data held while paused is dropped
+	heldDropped bool
+
+	// V1ProfileName enables the conversion of V1 Events received to Event DTOs, which are given this profile name
+	// since V1 Events have none. V1 Events are rejected as invalid when not set.
+	V1ProfileName string
+}
+
+// scaleInterval is how often the concurrency limit is rescaled when autoscaling
+const scaleInterval = time.Second
+
+// defaultPipelineId identifies the default pipeline
+const defaultPipelineId = interfaces.DefaultPipelineId
+
+// TopicPipeline is a function pipeline which processes the data received on specific topics rather than the
+// default pipeline
+type TopicPipeline struct {
+	Id         string
+	Topics     []string
+	Transforms []interfaces.AppFunction
 }
 
 type MessageError struct {
@@ -61,51 +104,387 @@ func (gr *GolangRuntime) Initialize(dic *di.Container) {
 	gr.dic = dic
 	gr.storeForward.runtime = gr
 	gr.storeForward.dic = dic
+
+	// Initialize is called again when Store and Forward is enabled, so the metrics are only registered once
+	if gr.metrics == nil && dic != nil {
+		if manager := container.MetricsManagerFrom(dic.Get); manager != nil {
+			config := container.ConfigurationFrom(dic.Get)
+			triggerType := strings.ToUpper(config.Trigger.Type)
+			gr.metrics = newRuntimeMetrics(
+				manager,
+				triggerType,
+				subscribedTopics(config, triggerType),
+				bootstrapContainer.LoggingClientFrom(dic.Get))
+		}
+	}
+}
+
+// subscribedTopics returns the topic filters the trigger of the type subscribes to, which the trigger metrics are
+// tagged with. The HTTP and custom triggers don't subscribe to topics.
+func subscribedTopics(config *sdkCommon.ConfigurationStruct, triggerType string) []string {
+	var topics string
+	switch triggerType {
+	case "EDGEX-MESSAGEBUS":
+		topics = config.Trigger.EdgexMessageBus.SubscribeHost.SubscribeTopics
+	case "EXTERNAL-MQTT":
+		topics = config.Trigger.ExternalMqtt.SubscribeTopics
+	}
+
+	return util.DeleteEmptyAndTrim(strings.FieldsFunc(topics, util.SplitComma))
 }
 
 // SetTransforms is thread safe to set transforms
 func (gr *GolangRuntime) SetTransforms(transforms []interfaces.AppFunction) {
 	gr.isBusyCopying.Lock()
 	gr.transforms = transforms
-	gr.storeForward.pipelineHash = gr.storeForward.calculatePipelineHash() // Only need to calculate hash when the pipeline changes.
+	gr.isBusyCopying.Unlock()
+}
+
+// SetTopicPipelines is thread safe to set the per topic pipelines. Pipelines are matched against the received
+// topic in order of their Id and data received on topics no pipeline matches is processed by the default pipeline.
+func (gr *GolangRuntime) SetTopicPipelines(pipelines []TopicPipeline) {
+	sorted := sortTopicPipelines(pipelines)
+
+	gr.isBusyCopying.Lock()
+	gr.topicPipelines = sorted
+	gr.isBusyCopying.Unlock()
+}
+
+// SetPipelines is thread safe to set the transforms of the default pipeline, the per topic pipelines and the target
+// type at once, so the messages being received are processed either entirely before or entirely after the change.
+// The metrics of the functions of the replaced pipelines are unregistered.
+func (gr *GolangRuntime) SetPipelines(transforms []interfaces.AppFunction, topicPipelines []TopicPipeline, targetType interface{}) {
+	sorted := sortTopicPipelines(topicPipelines)
+
+	gr.isBusyCopying.Lock()
+	gr.transforms = transforms
+	gr.topicPipelines = sorted
+	gr.TargetType = targetType
+	gr.isBusyCopying.Unlock()
+
+	gr.metrics.resetFunctions()
+}
+
+func sortTopicPipelines(pipelines []TopicPipeline) []TopicPipeline {
+	sorted := make([]TopicPipeline, len(pipelines))
+	copy(sorted, pipelines)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Id < sorted[j].Id })
+	return sorted
+}
+
+// pipelineTransforms returns a copy of the transforms of the pipeline for the topic so that updating the
+// pipelines from the registry doesn't disrupt execution of the pipeline
+func (gr *GolangRuntime) pipelineTransforms(topic string) []interfaces.AppFunction {
+	gr.isBusyCopying.Lock()
+	defer gr.isBusyCopying.Unlock()
+
+	return gr.copyTransforms(topic)
+}
+
+// selectPipeline returns a copy of the transforms of the pipeline for the topic and the target type, and sets the
+// pipeline's identity on the context, all read at once so that a concurrent update of the pipelines can't mix the
+// pipelines before and after it
+func (gr *GolangRuntime) selectPipeline(appContext *appfunction.Context, topic string) ([]interfaces.AppFunction, interface{}) {
+	gr.isBusyCopying.Lock()
+	defer gr.isBusyCopying.Unlock()
+
+	id, state := gr.stateOf(topic)
+	appContext.SetPipelineId(id)
+	appContext.SetPipelineState(state)
+
+	return gr.copyTransforms(topic), gr.TargetType
+}
+
+// copyTransforms returns a copy of the transforms of the pipeline for the topic. Must be called with isBusyCopying
+// locked.
+func (gr *GolangRuntime) copyTransforms(topic string) []interfaces.AppFunction {
+	selected := gr.transforms
+	if pipeline, found := gr.topicPipeline(topic); found {
+		selected = pipeline.Transforms
+	}
+
+	transforms := make([]interfaces.AppFunction, len(selected))
+	copy(transforms, selected)
+	return transforms
+}
+
+// setPipelineIdentity sets the Id and state store of the pipeline for the topic on the context. The state is kept
+// across the messages the pipeline processes and updates of the pipeline's functions.
+func (gr *GolangRuntime) setPipelineIdentity(appContext *appfunction.Context, topic string) {
+	id, state := gr.pipelineState(topic)
+	appContext.SetPipelineId(id)
+	appContext.SetPipelineState(state)
+}
+
+// pipelineState returns the Id and state store of the pipeline for the topic
+func (gr *GolangRuntime) pipelineState(topic string) (string, *appfunction.StateStore) {
+	gr.isBusyCopying.Lock()
+	defer gr.isBusyCopying.Unlock()
+
+	return gr.stateOf(topic)
+}
+
+// stateOf returns the Id and state store of the pipeline for the topic. Must be called with isBusyCopying locked.
+func (gr *GolangRuntime) stateOf(topic string) (string, *appfunction.StateStore) {
+	id := defaultPipelineId
+	if pipeline, found := gr.topicPipeline(topic); found {
+		id = pipeline.Id
+	}
+
+	if gr.pipelineStates == nil {
+		gr.pipelineStates = make(map[string]*appfunction.StateStore)
+	}
+
+	state, found := gr.pipelineStates[id]
+	if !found {
+		state = appfunction.NewStateStore()
+		gr.pipelineStates[id] = state
+	}
+
+	return id, state
+}
+
+// topicPipeline returns the first pipeline matching the topic. Must be called with isBusyCopying locked.
+func (gr *GolangRuntime) topicPipeline(topic string) (TopicPipeline, bool) {
+	for _, pipeline := range gr.topicPipelines {
+		if pipeline.matchesTopic(topic) {
+			return pipeline, true
+		}
+	}
+	return TopicPipeline{}, false
+}
+
+func (pipeline TopicPipeline) matchesTopic(topic string) bool {
+	for _, filter := range pipeline.Topics {
+		if topicMatches(filter, topic) {
+			return true
+		}
+	}
+	return false
+}
+
+// topicMatches determines if the topic matches the topic filter, which may contain the '+' single level and
+// '#' multi level wildcards
+func topicMatches(filter string, topic string) bool {
+	filterLevels := strings.Split(filter, "/")
+	topicLevels := strings.Split(topic, "/")
+
+	for index, level := range filterLevels {
+		if level == "#" {
+			return true
+		}
+
+		if index >= len(topicLevels) || (level != "+" && level != topicLevels[index]) {
+			return false
+		}
+	}
+
+	return len(filterLevels) == len(topicLevels)
+}
+
+// WaitForInFlight waits for the pipeline executions in progress, including the data queued for the workers, to
+// complete. Returns false if they don't complete within the timeout. Once they have completed, new executions are
+// rejected, so the triggers must be stopped first.
+func (gr *GolangRuntime) WaitForInFlight(timeout time.Duration) bool {
+	return gr.inFlight.wait(timeout)
+}
+
+// SetMaxConcurrency sets the maximum number of pipeline executions in progress at once, which is unlimited when
+// zero. Executions waiting for the limit are released immediately when it is raised.
+func (gr *GolangRuntime) SetMaxConcurrency(limit int) {
+	gr.SetConcurrency(0, limit)
+}
+
+// SetConcurrency sets the range of pipeline executions in progress at once. When min is greater than zero and less
+// than max, the limit is autoscaled between them every scaling interval, based on the executions waiting for the
+// limit and their processing time, so bursts are absorbed without always allowing the max. Otherwise the limit is
+// fixed at max.
+func (gr *GolangRuntime) SetConcurrency(min int, max int) {
+	gr.scalerMutex.Lock()
+	defer gr.scalerMutex.Unlock()
+
+	if gr.stopped {
+		return
+	}
+
+	if gr.stopScaler != nil {
+		close(gr.stopScaler)
+		gr.stopScaler = nil
+	}
+
+	// The queued data is processed by as many workers as the executions allowed at most
+	if gr.workers == nil {
+		gr.workers = newWorkerQueue(max)
+	} else {
+		gr.workers.resize(max)
+	}
+
+	if min <= 0 || min >= max {
+		gr.limiter.setLimit(max)
+		return
+	}
+
+	gr.limiter.setLimit(min)
+	gr.stopScaler = make(chan struct{})
+	go gr.autoscale(min, max, gr.stopScaler)
+}
+
+// autoscale rescales the concurrency limit between min and max every scaling interval until stopped
+func (gr *GolangRuntime) autoscale(min int, max int, stop chan struct{}) {
+	ticker := time.NewTicker(scaleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			previous, limit := gr.limiter.rescale(min, max, scaleInterval)
+			if previous != limit && gr.dic != nil {
+				bootstrapContainer.LoggingClientFrom(gr.dic.Get).Debugf(
+					"Pipeline concurrency scaled from %d to %d", previous, limit)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Enqueue queues the processing of the message received by a trigger for the pipeline workers, so the trigger doesn't
+// start a goroutine per message. Waits while the queue is full, so the trigger stops receiving until the pipelines
+// catch up. Returns false without queueing if the context is done or the runtime is stopped first. The message is
+// counted as received and queued from the time it's queued, so process must execute the pipeline with
+// ProcessQueuedMessage rather than ProcessMessage.
+func (gr *GolangRuntime) Enqueue(ctx context.Context, envelope types.MessageEnvelope, process func()) bool {
+	gr.scalerMutex.Lock()
+	if gr.workers == nil && !gr.stopped {
+		gr.workers = newWorkerQueue(0)
+	}
+	workers := gr.workers
+	gr.scalerMutex.Unlock()
+
+	// Counted as in flight from the time it's queued, so waiting for the executions in progress includes it
+	if workers == nil || !gr.inFlight.begin() {
+		return false
+	}
+
+	dequeue := gr.metrics.enqueue(envelope.ReceivedTopic, len(envelope.Payload))
+	queued := workers.enqueue(ctx, func() {
+		defer gr.inFlight.end()
+		defer dequeue()
+		process()
+	})
+	if !queued {
+		dequeue()
+		gr.inFlight.end()
+	}
+	return queued
+}
+
+// Stop stops autoscaling the concurrency and the pipeline workers. Called once the pipeline executions in progress
+// have completed when the service shuts down.
+func (gr *GolangRuntime) Stop() {
+	gr.scalerMutex.Lock()
+	defer gr.scalerMutex.Unlock()
+
+	gr.stopped = true
+	if gr.stopScaler != nil {
+		close(gr.stopScaler)
+		gr.stopScaler = nil
+	}
+	if gr.workers != nil {
+		gr.workers.close()
+	}
+
+	// The data held for paused pipelines is dropped
+	gr.isBusyCopying.Lock()
+	gr.heldDropped = true
+	gr.releaseHeld()
 	gr.isBusyCopying.Unlock()
 }
 
 // ProcessMessage sends the contents of the message thru the functions pipeline
 func (gr *GolangRuntime) ProcessMessage(appContext *appfunction.Context, envelope types.MessageEnvelope) *MessageError {
-	lc := appContext.LoggingClient()
+	if !gr.inFlight.begin() {
+		err := errors.New("pipeline executions have stopped as the service is shutting down")
+		logError(appContext.FlowLoggingClient(), err, envelope.CorrelationID)
+		return &MessageError{Err: err, ErrorCode: http.StatusServiceUnavailable}
+	}
+	defer gr.inFlight.end()
 
-	if len(gr.transforms) == 0 {
+	dequeue := gr.metrics.enqueue(envelope.ReceivedTopic, len(envelope.Payload))
+	defer dequeue()
+
+	return gr.execute(appContext, envelope)
+}
+
+// ProcessQueuedMessage sends the contents of the message queued by Enqueue thru the functions pipeline. The message
+// is already counted as received and in flight, so isn't counted again. The message is held while its pipeline is
+// paused, see WaitWhilePaused, before the concurrency limit so it doesn't hold back the other pipelines' executions.
+func (gr *GolangRuntime) ProcessQueuedMessage(appContext *appfunction.Context, envelope types.MessageEnvelope) *MessageError {
+	gr.WaitWhilePaused(envelope.ReceivedTopic)
+	return gr.execute(appContext, envelope)
+}
+
+// execute executes the pipeline for the message, limiting the number of concurrent executions
+func (gr *GolangRuntime) execute(appContext *appfunction.Context, envelope types.MessageEnvelope) *MessageError {
+	gr.limiter.acquire()
+	defer gr.limiter.release()
+
+	start := time.Now()
+	messageError := gr.processMessage(appContext, envelope)
+	duration := time.Since(start)
+	gr.limiter.observe(duration)
+	gr.metrics.record(duration, messageError != nil)
+
+	return messageError
+}
+
+func (gr *GolangRuntime) processMessage(appContext *appfunction.Context, envelope types.MessageEnvelope) *MessageError {
+	lc := appContext.FlowLoggingClient()
+
+	transforms, targetType := gr.selectPipeline(appContext, envelope.ReceivedTopic)
+	if len(transforms) == 0 {
 		err := errors.New("No transforms configured. Please check log for errors loading pipeline")
 		logError(lc, err, envelope.CorrelationID)
 		return &MessageError{Err: err, ErrorCode: http.StatusInternalServerError}
 	}
 
 	appContext.AddValue(interfaces.RECEIVEDTOPIC, envelope.ReceivedTopic)
+	if len(gr.Instance) > 0 {
+		appContext.AddValue(interfaces.INSTANCE, gr.Instance)
+	}
 
-	lc.Debugf("Processing message %d Transforms", len(gr.transforms))
+	if gr.isPipelinePaused(appContext.PipelineId()) {
+		err := fmt.Errorf("pipeline '%s' is paused", appContext.PipelineId())
+		logError(lc, err, envelope.CorrelationID)
+		return &MessageError{Err: err, ErrorCode: http.StatusServiceUnavailable}
+	}
+
+	lc.Debugf("Processing message %d Transforms", len(transforms))
 
 	// Default Target Type for the function pipeline is an Event DTO.
 	// The Event DTO can be wrapped in an AddEventRequest DTO or just be the un-wrapped Event DTO,
 	// which is handled dynamically below.
-	if gr.TargetType == nil {
-		gr.TargetType = &dtos.Event{}
+	if targetType == nil {
+		targetType = &dtos.Event{}
 	}
 
-	if reflect.TypeOf(gr.TargetType).Kind() != reflect.Ptr {
+	if reflect.TypeOf(targetType).Kind() != reflect.Ptr {
 		err := errors.New("TargetType must be a pointer, not a value of the target type")
 		logError(lc, err, envelope.CorrelationID)
 		return &MessageError{Err: err, ErrorCode: http.StatusInternalServerError}
 	}
 
+	// Raw byte data is passed to the pipeline as received, without being copied or unmarshaled
+	if _, isRawData := targetType.(*[]byte); isRawData {
+		lc.Debug("Pipeline is expecting raw byte data")
+		appContext.SetCorrelationID(envelope.CorrelationID)
+		return gr.ExecutePipeline(envelope.Payload, envelope.ContentType, appContext, transforms, 0, false)
+	}
+
 	// Must make a copy of the type so that data isn't retained between calls for custom types
-	target := reflect.New(reflect.ValueOf(gr.TargetType).Elem().Type()).Interface()
+	target := reflect.New(reflect.ValueOf(targetType).Elem().Type()).Interface()
 
 	switch target.(type) {
-	case *[]byte:
-		lc.Debug("Pipeline is expecting raw byte data")
-		target = &envelope.Payload
-
 	case *dtos.Event:
 		lc.Debug("Pipeline is expecting an AddEventRequest or Event DTO")
 
@@ -119,10 +498,22 @@ func (gr *GolangRuntime) ProcessMessage(appContext *appfunction.Context, envelop
 
 			err = fmt.Errorf("unable to process payload %s", err.Error())
 			logError(lc, err, envelope.CorrelationID)
+			gr.metrics.recordInvalid(envelope.ReceivedTopic)
 
 			return &MessageError{Err: err, ErrorCode: errorCode}
 		}
 
+		if settings := gr.validator.strictValidation(); settings.Enabled {
+			if err := gr.validator.validate(appContext, event, settings); err != nil {
+				err = fmt.Errorf("invalid Event received: %s", err.Error())
+				logError(lc, err, envelope.CorrelationID)
+				gr.metrics.recordInvalid(envelope.ReceivedTopic)
+				gr.validator.deadLetterPayload(appContext, envelope.Payload, settings)
+
+				return &MessageError{Err: err, ErrorCode: http.StatusBadRequest}
+			}
+		}
+
 		if lc.LogLevel() == models.DebugLog {
 			gr.debugLogEvent(lc, event)
 		}
@@ -130,6 +521,9 @@ func (gr *GolangRuntime) ProcessMessage(appContext *appfunction.Context, envelop
 		appContext.AddValue(interfaces.DEVICENAME, event.DeviceName)
 		appContext.AddValue(interfaces.PROFILENAME, event.ProfileName)
 		appContext.AddValue(interfaces.SOURCENAME, event.SourceName)
+		if mediaType := util.BinaryMediaType(*event); len(mediaType) > 0 {
+			appContext.AddValue(interfaces.MEDIATYPE, mediaType)
+		}
 
 		target = event
 
@@ -141,6 +535,7 @@ func (gr *GolangRuntime) ProcessMessage(appContext *appfunction.Context, envelop
 		if err := gr.unmarshalPayload(envelope, target); err != nil {
 			err = fmt.Errorf("unable to process custom object received of type '%s': %s", customTypeName, err.Error())
 			logError(lc, err, envelope.CorrelationID)
+			gr.metrics.recordInvalid(envelope.ReceivedTopic)
 			return &MessageError{Err: err, ErrorCode: http.StatusBadRequest}
 		}
 	}
@@ -151,12 +546,6 @@ func (gr *GolangRuntime) ProcessMessage(appContext *appfunction.Context, envelop
 	// dereference to pointer to the object
 	target = reflect.ValueOf(target).Elem().Interface()
 
-	// Make copy of transform functions to avoid disruption of pipeline when updating the pipeline from registry
-	gr.isBusyCopying.Lock()
-	transforms := make([]interfaces.AppFunction, len(gr.transforms))
-	copy(transforms, gr.transforms)
-	gr.isBusyCopying.Unlock()
-
 	return gr.ExecutePipeline(target, envelope.ContentType, appContext, transforms, 0, false)
 }
 
@@ -177,22 +566,35 @@ func (gr *GolangRuntime) ExecutePipeline(
 		}
 
 		appContext.SetRetryData(nil)
+		appContext.SetPipelineFunction(functionIndex, trxFunc)
 
+		start := time.Now()
 		if result == nil {
 			appContext.SetInputContentType(contentType)
 			continuePipeline, result = trxFunc(appContext, target)
 		} else {
 			continuePipeline, result = trxFunc(appContext, result)
 		}
+		_, isError := result.(error)
+		gr.metrics.recordFunction(
+			appContext.PipelineId(),
+			functionIndex,
+			appContext.FunctionName(),
+			time.Since(start),
+			!continuePipeline && isError)
 
 		if continuePipeline != true {
 			if result != nil {
 				if err, ok := result.(error); ok {
-					appContext.LoggingClient().Error(
+					appContext.FlowLoggingClient().Error(
 						fmt.Sprintf("Pipeline function #%d resulted in error", functionIndex),
 						"error", err.Error(), common.CorrelationHeader, appContext.CorrelationID)
 					if appContext.RetryData() != nil && !isRetry {
-						gr.storeForward.storeForLaterRetry(appContext.RetryData(), appContext, functionIndex)
+						gr.storeForward.storeForLaterRetry(
+							appContext.RetryData(),
+							appContext,
+							calculatePipelineHash(transforms),
+							functionIndex)
 					}
 
 					return &MessageError{Err: err, ErrorCode: http.StatusUnprocessableEntity}
@@ -200,11 +602,75 @@ func (gr *GolangRuntime) ExecutePipeline(
 			}
 			break
 		}
+
+		if fanOut, ok := result.(interfaces.PipelineFanOut); ok {
+			return gr.executeFanOut(fanOut, contentType, appContext, transforms, functionIndex+1, isRetry)
+		}
 	}
 
 	return nil
 }
 
+// executeFanOut executes the remainder of the pipeline once for each of the items in the fan out, each with its own
+// clone of the context so the context values, retry data and response of one item don't affect the others. Failed
+// items are stored for retry individually. All items are processed even if some fail, in which case the first error
+// is returned.
+func (gr *GolangRuntime) executeFanOut(
+	fanOut interfaces.PipelineFanOut,
+	contentType string,
+	appContext *appfunction.Context,
+	transforms []interfaces.AppFunction,
+	startPosition int,
+	isRetry bool) *MessageError {
+
+	appContext.FlowLoggingClient().Debugf("Executing remainder of pipeline for %d fanned out items", len(fanOut))
+
+	var firstErr *MessageError
+	var responses []*appfunction.Context
+	for _, item := range fanOut {
+		itemContext := appContext.Clone().(*appfunction.Context)
+		if err := gr.ExecutePipeline(item, contentType, itemContext, transforms, startPosition, isRetry); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		if itemContext.ResponseData() != nil {
+			responses = append(responses, itemContext)
+		}
+	}
+
+	setFanOutResponse(appContext, responses)
+
+	return firstErr
+}
+
+// setFanOutResponse sets the response of the fanned out items which set one. Several responses are combined into a
+// JSON array when they are all JSON, otherwise separated by new lines.
+func setFanOutResponse(appContext *appfunction.Context, responses []*appfunction.Context) {
+	switch len(responses) {
+	case 0:
+		return
+	case 1:
+		appContext.SetResponseData(responses[0].ResponseData())
+		appContext.SetResponseContentType(responses[0].ResponseContentType())
+		return
+	}
+
+	allJSON := true
+	combined := make([][]byte, len(responses))
+	for index, response := range responses {
+		combined[index] = response.ResponseData()
+		allJSON = allJSON && json.Valid(combined[index])
+	}
+
+	if allJSON {
+		appContext.SetResponseData(append(append([]byte{'['}, bytes.Join(combined, []byte{','})...), ']'))
+		appContext.SetResponseContentType(common.ContentTypeJSON)
+		return
+	}
+
+	appContext.SetResponseData(bytes.Join(combined, []byte{'\n'}))
+	appContext.SetResponseContentType(responses[0].ResponseContentType())
+}
+
 func (gr *GolangRuntime) StartStoreAndForward(
 	appWg *sync.WaitGroup,
 	appCtx context.Context,
@@ -217,13 +683,27 @@ func (gr *GolangRuntime) StartStoreAndForward(
 
 func (gr *GolangRuntime) processEventPayload(envelope types.MessageEnvelope, lc logger.LoggingClient) (*dtos.Event, error) {
 
+	contentType := PayloadMediaType(envelope.ContentType)
+	if contentType == common.ContentTypeCBOR {
+		lc.Debug("Decoding CBOR Payload as an AddEventRequest or Event DTO")
+		return decodeCBOREvent(envelope.Payload)
+	}
+
 	lc.Debug("Attempting to process Payload as an AddEventRequest DTO")
 	requestDto := requests.AddEventRequest{}
 
 	// Note that DTO validation is called during the unmarshaling
 	// which results in a KindContractInvalid error
 	requestDtoErr := gr.unmarshalPayload(envelope, &requestDto)
+	if requestDtoErr == nil && contentType != common.ContentTypeJSON {
+		// Only the JSON decoding validates the DTO, so those decoded by custom decoders are validated here
+		requestDtoErr = requestDto.Validate()
+	}
 	if requestDtoErr == nil {
+		if err := validateApiVersion(requestDto.ApiVersion); err != nil {
+			return nil, err
+		}
+
 		lc.Debug("Using Event DTO from AddEventRequest DTO")
 
 		// Determine that we have an AddEventRequest DTO
@@ -243,6 +723,10 @@ func (gr *GolangRuntime) processEventPayload(envelope types.MessageEnvelope, lc
 	if err == nil {
 		err = common.Validate(event)
 		if err == nil {
+			if err := validateApiVersion(event.ApiVersion); err != nil {
+				return nil, err
+			}
+
 			lc.Debug("Using Event DTO received")
 			return event, nil
 		}
@@ -253,25 +737,27 @@ func (gr *GolangRuntime) processEventPayload(envelope types.MessageEnvelope, lc
 		return nil, err
 	}
 
+	if len(gr.V1ProfileName) > 0 && contentType == common.ContentTypeJSON {
+		lc.Debug("Attempting to process Payload as a V1 Event")
+		if event, err := decodeV1Event(envelope.Payload, gr.V1ProfileName); err == nil {
+			lc.Debug("Using Event DTO converted from V1 Event received")
+			return event, nil
+		}
+	}
+
 	// Still unable to process so assume have invalid AddEventRequest DTO
 	return nil, requestDtoErr
 }
 
+// unmarshalPayload decodes the payload into the target with the decoder of the envelope's content type, see
+// RegisterPayloadDecoder
 func (gr *GolangRuntime) unmarshalPayload(envelope types.MessageEnvelope, target interface{}) error {
-	var err error
-
-	switch envelope.ContentType {
-	case common.ContentTypeJSON:
-		err = json.Unmarshal(envelope.Payload, target)
-
-	case common.ContentTypeCBOR:
-		err = cbor.Unmarshal(envelope.Payload, target)
-
-	default:
-		err = fmt.Errorf("unsupported content-type '%s' recieved", envelope.ContentType)
+	decoder, found := gr.payloadDecoder(envelope.ContentType)
+	if !found {
+		return fmt.Errorf("unsupported content-type '%s' recieved", envelope.ContentType)
 	}
 
-	return err
+	return decoder(envelope.Payload, target)
 }
 
 func (gr *GolangRuntime) debugLogEvent(lc logger.LoggingClient, event *dtos.Event) {
@@ -305,5 +791,5 @@ func (gr *GolangRuntime) debugLogEvent(lc logger.LoggingClient, event *dtos.Even
 }
 
 func logError(lc logger.LoggingClient, err error, correlationID string) {
-	lc.Errorf("%s. %s=%s", err.Error(), common.CorrelationHeader, correlationID)
+	lc.Error(err.Error(), common.CorrelationHeader, correlationID)
 }
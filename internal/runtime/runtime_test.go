@@ -16,10 +16,12 @@
 package runtime
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -473,6 +475,339 @@ func TestExecutePipelinePersist(t *testing.T) {
 	assert.Equal(t, context.CorrelationID(), storedObjects[0].CorrelationID, "CorrelationID not as expected")
 }
 
+func TestExecutePipelineFanOut(t *testing.T) {
+	context := appfunction.NewContext("testing", dic, "")
+
+	fanOut := func(appContext interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		return true, interfaces.PipelineFanOut{"one", "two", "three"}
+	}
+
+	var received []interface{}
+	var leaked []string
+	collect := func(appContext interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		received = append(received, data)
+		if previous, ok := appContext.GetValue("item"); ok {
+			leaked = append(leaked, previous)
+		}
+		appContext.AddValue("item", data.(string))
+		if data == "two" {
+			return false, fmt.Errorf("failed on %v", data)
+		}
+		appContext.SetResponseData([]byte(fmt.Sprintf(`"%s"`, data)))
+		return true, data
+	}
+
+	runtime := GolangRuntime{}
+	runtime.Initialize(nil)
+	runtime.SetTransforms([]interfaces.AppFunction{fanOut, collect})
+
+	actual := runtime.ExecutePipeline([]byte("My Payload"), "", context, runtime.transforms, 0, false)
+
+	require.NotNil(t, actual)
+	assert.EqualError(t, actual.Err, "failed on two")
+	assert.Equal(t, []interface{}{"one", "two", "three"}, received, "all fanned out items should be processed")
+	assert.Empty(t, leaked, "context values of one item should not be seen by the others")
+	_, found := context.GetValue("item")
+	assert.False(t, found, "context values of the items should not be set on the original context")
+	assert.Equal(t, `["one","three"]`, string(context.ResponseData()))
+	assert.Equal(t, common.ContentTypeJSON, context.ResponseContentType())
+}
+
+func TestSetFanOutResponse(t *testing.T) {
+	newResponse := func(data string, contentType string) *appfunction.Context {
+		response := appfunction.NewContext("testing", dic, "")
+		response.SetResponseData([]byte(data))
+		response.SetResponseContentType(contentType)
+		return response
+	}
+
+	context := appfunction.NewContext("testing", dic, "")
+	setFanOutResponse(context, nil)
+	assert.Nil(t, context.ResponseData())
+
+	setFanOutResponse(context, []*appfunction.Context{newResponse("a,b", "text/csv")})
+	assert.Equal(t, "a,b", string(context.ResponseData()))
+	assert.Equal(t, "text/csv", context.ResponseContentType())
+
+	setFanOutResponse(context, []*appfunction.Context{newResponse("a,b", "text/csv"), newResponse("c,d", "text/csv")})
+	assert.Equal(t, "a,b\nc,d", string(context.ResponseData()))
+	assert.Equal(t, "text/csv", context.ResponseContentType())
+}
+
+func TestTopicMatches(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Filter   string
+		Topic    string
+		Expected bool
+	}{
+		{"Exact", "edgex/events/device", "edgex/events/device", true},
+		{"Different", "edgex/events/device", "edgex/events/core", false},
+		{"Single level wildcard", "edgex/events/+/Random-Integer-Device", "edgex/events/device/Random-Integer-Device", true},
+		{"Single level wildcard too short", "edgex/events/+/+", "edgex/events/device", false},
+		{"Multi level wildcard", "edgex/events/#", "edgex/events/device/profile/Random-Integer-Device", true},
+		{"Multi level wildcard parent", "edgex/events/#", "edgex/events", true},
+		{"Topic longer than filter", "edgex/events", "edgex/events/device", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Expected, topicMatches(test.Filter, test.Topic))
+		})
+	}
+}
+
+func TestProcessMessageTopicPipelines(t *testing.T) {
+	payload, err := json.Marshal(testAddEventRequest)
+	require.NoError(t, err)
+
+	var called []string
+	newTransform := func(name string) interfaces.AppFunction {
+		return func(appContext interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+			called = append(called, name)
+			return true, data
+		}
+	}
+
+	runtime := GolangRuntime{}
+	runtime.Initialize(nil)
+	runtime.SetTransforms([]interfaces.AppFunction{newTransform("default")})
+	runtime.SetTopicPipelines([]TopicPipeline{
+		{Id: "b-integers", Topics: []string{"edgex/events/#"}, Transforms: []interfaces.AppFunction{newTransform("integers")}},
+		{Id: "a-floats", Topics: []string{"edgex/events/+/+/Random-Float-Device"}, Transforms: []interfaces.AppFunction{newTransform("floats")}},
+	})
+
+	tests := []struct {
+		Topic    string
+		Expected string
+	}{
+		{"edgex/events/device/profile/Random-Float-Device", "floats"},
+		{"edgex/events/device/profile/Random-Integer-Device", "integers"},
+		{"other/topic", "default"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Topic, func(t *testing.T) {
+			called = nil
+			envelope := types.MessageEnvelope{
+				CorrelationID: "123-234-345-456",
+				Payload:       payload,
+				ContentType:   common.ContentTypeJSON,
+				ReceivedTopic: test.Topic,
+			}
+
+			result := runtime.ProcessMessage(appfunction.NewContext("testId", dic, ""), envelope)
+			require.Nil(t, result)
+			assert.Equal(t, []string{test.Expected}, called)
+		})
+	}
+}
+
+func TestSetPipelines(t *testing.T) {
+	payload, err := json.Marshal(testAddEventRequest)
+	require.NoError(t, err)
+
+	var received []interface{}
+	transform := func(appContext interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		received = append(received, data)
+		return true, data
+	}
+
+	runtime := GolangRuntime{}
+	runtime.Initialize(nil)
+	runtime.SetPipelines([]interfaces.AppFunction{transform}, []TopicPipeline{
+		{Id: "floats", Topics: []string{"edgex/events/#"}, Transforms: []interfaces.AppFunction{transform, transform}},
+	}, &[]byte{})
+
+	envelope := types.MessageEnvelope{
+		CorrelationID: "123-234-345-456",
+		Payload:       payload,
+		ContentType:   common.ContentTypeJSON,
+		ReceivedTopic: "edgex/events/device",
+	}
+
+	appContext := appfunction.NewContext("testId", dic, "")
+	transforms, targetType := runtime.selectPipeline(appContext, envelope.ReceivedTopic)
+	assert.Len(t, transforms, 2)
+	assert.IsType(t, &[]byte{}, targetType)
+	assert.Equal(t, "floats", appContext.PipelineId())
+
+	require.Nil(t, runtime.ProcessMessage(appContext, envelope))
+	require.Len(t, received, 2)
+	assert.Equal(t, payload, received[0])
+
+	// The pipelines and the target type are replaced together
+	received = nil
+	runtime.SetPipelines([]interfaces.AppFunction{transform}, nil, nil)
+
+	require.Nil(t, runtime.ProcessMessage(appfunction.NewContext("testId", dic, ""), envelope))
+	require.Len(t, received, 1)
+	assert.IsType(t, &dtos.Event{}, received[0])
+}
+
+func TestProcessMessagePipelineState(t *testing.T) {
+	payload, err := json.Marshal(testAddEventRequest)
+	require.NoError(t, err)
+
+	count := func(appContext interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		return true, appContext.PipelineState().Update("count", func(current interface{}, found bool) interface{} {
+			if !found {
+				return 1
+			}
+			return current.(int) + 1
+		})
+	}
+
+	var counts []interface{}
+	record := func(appContext interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		counts = append(counts, data)
+		return true, data
+	}
+
+	runtime := GolangRuntime{}
+	runtime.Initialize(nil)
+	runtime.SetTransforms([]interfaces.AppFunction{count, record})
+	runtime.SetTopicPipelines([]TopicPipeline{
+		{Id: "floats", Topics: []string{"edgex/events/floats"}, Transforms: []interfaces.AppFunction{count, record}},
+	})
+
+	for _, topic := range []string{"other", "other", "edgex/events/floats", "other"} {
+		envelope := types.MessageEnvelope{
+			CorrelationID: "123-234-345-456",
+			Payload:       payload,
+			ContentType:   common.ContentTypeJSON,
+			ReceivedTopic: topic,
+		}
+
+		result := runtime.ProcessMessage(appfunction.NewContext("testId", dic, ""), envelope)
+		require.Nil(t, result)
+	}
+
+	// State is kept across messages and separately for each pipeline
+	assert.Equal(t, []interface{}{1, 2, 1, 3}, counts)
+}
+
+func TestWaitForInFlight(t *testing.T) {
+	payload, err := json.Marshal(testAddEventRequest)
+	require.NoError(t, err)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	blocking := func(appContext interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		close(started)
+		<-release
+		return false, nil
+	}
+
+	idle := GolangRuntime{}
+	assert.True(t, idle.WaitForInFlight(time.Millisecond))
+
+	runtime := GolangRuntime{}
+	runtime.Initialize(nil)
+	runtime.SetTransforms([]interfaces.AppFunction{blocking})
+
+	envelope := types.MessageEnvelope{CorrelationID: "123", Payload: payload, ContentType: common.ContentTypeJSON}
+	go runtime.ProcessMessage(appfunction.NewContext("testId", dic, ""), envelope)
+	<-started
+
+	// Data queued while waiting is still processed
+	processed := make(chan struct{})
+	require.True(t, runtime.Enqueue(context.Background(), envelope, func() { close(processed) }))
+
+	assert.False(t, runtime.WaitForInFlight(10*time.Millisecond))
+
+	close(release)
+	assert.True(t, runtime.WaitForInFlight(time.Second))
+	<-processed
+
+	// No new executions once those in progress have completed
+	result := runtime.ProcessMessage(appfunction.NewContext("testId", dic, ""), envelope)
+	require.NotNil(t, result)
+	assert.Equal(t, http.StatusServiceUnavailable, result.ErrorCode)
+	assert.False(t, runtime.Enqueue(context.Background(), envelope, func() {}))
+}
+
+func TestRuntimeStop(t *testing.T) {
+	runtime := GolangRuntime{}
+	runtime.SetConcurrency(1, 4)
+	require.NotNil(t, runtime.stopScaler)
+	require.NotNil(t, runtime.workers)
+
+	processed := make(chan struct{})
+	require.True(t, runtime.Enqueue(context.Background(), types.MessageEnvelope{}, func() { close(processed) }))
+	<-processed
+
+	runtime.Stop()
+	assert.Nil(t, runtime.stopScaler)
+	assert.False(t, runtime.Enqueue(context.Background(), types.MessageEnvelope{}, func() {}))
+
+	// Autoscaling isn't restarted once stopped
+	runtime.SetConcurrency(1, 4)
+	assert.Nil(t, runtime.stopScaler)
+}
+
+func TestProcessMessagePipelineIdentity(t *testing.T) {
+	payload, err := json.Marshal(testAddEventRequest)
+	require.NoError(t, err)
+
+	var identities []string
+	identify := func(appContext interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		identities = append(identities, fmt.Sprintf("%s/%d", appContext.PipelineId(), appContext.PipelinePosition()))
+		return true, data
+	}
+
+	runtime := GolangRuntime{}
+	runtime.Initialize(nil)
+	runtime.SetTransforms([]interfaces.AppFunction{identify, identify})
+	runtime.SetTopicPipelines([]TopicPipeline{
+		{Id: "floats", Topics: []string{"edgex/events/floats"}, Transforms: []interfaces.AppFunction{identify}},
+	})
+
+	for _, topic := range []string{"other", "edgex/events/floats"} {
+		envelope := types.MessageEnvelope{
+			CorrelationID: "123-234-345-456",
+			Payload:       payload,
+			ContentType:   common.ContentTypeJSON,
+			ReceivedTopic: topic,
+		}
+
+		result := runtime.ProcessMessage(appfunction.NewContext("testId", dic, ""), envelope)
+		require.Nil(t, result)
+	}
+
+	assert.Equal(t, []string{interfaces.DefaultPipelineId + "/0", interfaces.DefaultPipelineId + "/1", "floats/0"}, identities)
+}
+
+func TestProcessMessageInstance(t *testing.T) {
+	payload, err := json.Marshal(testAddEventRequest)
+	require.NoError(t, err)
+
+	var instance string
+	var found bool
+	transform := func(appContext interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		instance, found = appContext.GetValue(interfaces.INSTANCE)
+		return true, data
+	}
+
+	runtime := GolangRuntime{}
+	runtime.Initialize(nil)
+	runtime.SetTransforms([]interfaces.AppFunction{transform})
+
+	envelope := types.MessageEnvelope{
+		CorrelationID: "123-234-345-456",
+		Payload:       payload,
+		ContentType:   common.ContentTypeJSON,
+	}
+
+	require.Nil(t, runtime.ProcessMessage(appfunction.NewContext("testId", dic, ""), envelope))
+	assert.False(t, found)
+
+	runtime.Instance = "east"
+	require.Nil(t, runtime.ProcessMessage(appfunction.NewContext("testId", dic, ""), envelope))
+	assert.True(t, found)
+	assert.Equal(t, "east", instance)
+}
+
 func TestGolangRuntime_processEventPayload(t *testing.T) {
 	jsonV2AddEventPayload, _ := json.Marshal(testAddEventRequest)
 	cborV2AddEventPayload, _ := cbor.Marshal(testAddEventRequest)
@@ -522,3 +857,104 @@ func TestGolangRuntime_processEventPayload(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessMessageRawDataNotCopied(t *testing.T) {
+	payload := []byte("raw data")
+	envelope := types.MessageEnvelope{
+		CorrelationID: "123-234-345-456",
+		Payload:       payload,
+		ContentType:   "application/binary",
+	}
+
+	var received []byte
+	transform := func(appContext interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		received = data.([]byte)
+		return false, nil
+	}
+
+	runtime := GolangRuntime{TargetType: &[]byte{}}
+	runtime.Initialize(nil)
+	runtime.SetTransforms([]interfaces.AppFunction{transform})
+
+	context := appfunction.NewContext("testId", dic, "")
+	require.Nil(t, runtime.ProcessMessage(context, envelope))
+	assert.Equal(t, "123-234-345-456", context.CorrelationID())
+
+	// The pipeline receives the payload itself rather than a copy
+	require.Len(t, received, len(payload))
+	assert.Same(t, &payload[0], &received[0])
+}
+
+// benchmarkCompressAndForward benchmarks a pipeline which compresses the data received and forwards it as the
+// response data, with the target type of the runtime
+func benchmarkCompressAndForward(b *testing.B, targetType interface{}) {
+	payload, err := json.Marshal(testAddEventRequest)
+	require.NoError(b, err)
+
+	envelope := types.MessageEnvelope{
+		CorrelationID: "123-234-345-456",
+		Payload:       payload,
+		ContentType:   common.ContentTypeJSON,
+	}
+
+	compression := transforms.NewCompression()
+	forward := func(appContext interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		appContext.SetResponseData(data.([]byte))
+		return false, nil
+	}
+
+	runtime := GolangRuntime{TargetType: targetType}
+	runtime.Initialize(nil)
+	runtime.SetTransforms([]interfaces.AppFunction{compression.CompressWithGZIP, forward})
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(payload)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		context := appfunction.NewContext("testId", dic, "")
+		if messageError := runtime.ProcessMessage(context, envelope); messageError != nil {
+			b.Fatal(messageError.Err)
+		}
+	}
+}
+
+func BenchmarkCompressAndForwardRawData(b *testing.B) {
+	benchmarkCompressAndForward(b, &[]byte{})
+}
+
+func BenchmarkCompressAndForwardEvent(b *testing.B) {
+	benchmarkCompressAndForward(b, &dtos.Event{})
+}
+
+func BenchmarkProcessEventPayload(b *testing.B) {
+	addEventPayload, err := json.Marshal(testAddEventRequest)
+	require.NoError(b, err)
+	eventPayload, err := json.Marshal(testV2Event)
+	require.NoError(b, err)
+
+	benchmarks := []struct {
+		Name    string
+		Payload []byte
+	}{
+		{"AddEventRequest", addEventPayload},
+		{"Event", eventPayload},
+	}
+
+	target := GolangRuntime{}
+	lc := logger.NewMockClient()
+
+	for _, benchmark := range benchmarks {
+		b.Run(benchmark.Name, func(b *testing.B) {
+			envelope := types.MessageEnvelope{Payload: benchmark.Payload, ContentType: common.ContentTypeJSON}
+
+			b.ReportAllocs()
+			b.SetBytes(int64(len(benchmark.Payload)))
+			for i := 0; i < b.N; i++ {
+				if _, err := target.processEventPayload(envelope, lc); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -18,9 +18,11 @@ package runtime
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -28,6 +30,7 @@ import (
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/appfunction"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/bootstrap/container"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/store/contracts"
+	storeInterfaces "github.com/edgexfoundry/app-functions-sdk-go/v2/internal/store/db/interfaces"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
 
 	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
@@ -40,9 +43,10 @@ const (
 )
 
 type storeForwardInfo struct {
-	runtime      *GolangRuntime
-	dic          *di.Container
-	pipelineHash string
+	runtime *GolangRuntime
+	dic     *di.Container
+	// retryMutex prevents retries requested via the REST API overlapping those of the retry loop
+	retryMutex sync.Mutex
 }
 
 func (sf *storeForwardInfo) startStoreAndForwardRetryLoop(
@@ -109,9 +113,10 @@ func (sf *storeForwardInfo) startStoreAndForwardRetryLoop(
 func (sf *storeForwardInfo) storeForLaterRetry(
 	payload []byte,
 	appContext interfaces.AppFunctionContext,
+	pipelineHash string,
 	pipelinePosition int) {
 
-	item := contracts.NewStoredObject(sf.runtime.ServiceKey, payload, pipelinePosition, sf.pipelineHash, appContext.GetAllValues())
+	item := contracts.NewStoredObject(sf.runtime.ServiceKey, payload, pipelinePosition, pipelineHash, appContext.GetAllValues())
 	item.CorrelationID = appContext.CorrelationID()
 
 	appContext.LoggingClient().Trace("Storing data for later retry",
@@ -135,6 +140,8 @@ func (sf *storeForwardInfo) storeForLaterRetry(
 }
 
 func (sf *storeForwardInfo) retryStoredData(serviceKey string) {
+	sf.retryMutex.Lock()
+	defer sf.retryMutex.Unlock()
 
 	storeClient := container.StoreClientFrom(sf.dic.Get)
 	lc := bootstrapContainer.LoggingClientFrom(sf.dic.Get)
@@ -184,8 +191,10 @@ func (sf *storeForwardInfo) processRetryItems(items []contracts.StoredObject) ([
 	var itemsToUpdate []contracts.StoredObject
 
 	for _, item := range items {
-		if item.Version == sf.calculatePipelineHash() {
-			if !sf.retryExportFunction(item) {
+		// Retry with the pipeline for the topic the data was originally received on
+		transforms := sf.runtime.pipelineTransforms(item.ContextData[interfaces.RECEIVEDTOPIC])
+		if item.Version == calculatePipelineHash(transforms) {
+			if !sf.retryExportFunction(item, transforms) {
 				item.RetryCount++
 				if config.Writable.StoreAndForward.MaxRetryCount == 0 ||
 					item.RetryCount < config.Writable.StoreAndForward.MaxRetryCount {
@@ -228,12 +237,15 @@ func (sf *storeForwardInfo) processRetryItems(items []contracts.StoredObject) ([
 	return itemsToRemove, itemsToUpdate
 }
 
-func (sf *storeForwardInfo) retryExportFunction(item contracts.StoredObject) bool {
+func (sf *storeForwardInfo) retryExportFunction(item contracts.StoredObject, transforms []interfaces.AppFunction) bool {
 	appContext := appfunction.NewContext(item.CorrelationID, sf.dic, "")
 
 	for k, v := range item.ContextData {
 		appContext.AddValue(strings.ToLower(k), v)
 	}
+	appContext.AddValue(interfaces.RETRYATTEMPT, strconv.Itoa(item.RetryCount+1))
+
+	sf.runtime.setPipelineIdentity(appContext, item.ContextData[interfaces.RECEIVEDTOPIC])
 
 	appContext.LoggingClient().Trace("Retrying stored data", common.CorrelationHeader, appContext.CorrelationID)
 
@@ -241,17 +253,83 @@ func (sf *storeForwardInfo) retryExportFunction(item contracts.StoredObject) boo
 		item.Payload,
 		"",
 		appContext,
-		sf.runtime.transforms,
+		transforms,
 		item.PipelinePosition,
 		true) == nil
 }
 
-func (sf *storeForwardInfo) calculatePipelineHash() string {
+func calculatePipelineHash(transforms []interfaces.AppFunction) string {
 	hash := "Pipeline-functions: "
-	for _, item := range sf.runtime.transforms {
+	for _, item := range transforms {
 		name := runtime.FuncForPC(reflect.ValueOf(item).Pointer()).Name()
 		hash = hash + " " + name
 	}
 
 	return hash
 }
+
+// storeClient returns the StoreClient, or an error if Store and Forward isn't enabled
+func (sf *storeForwardInfo) storeClient() (storeInterfaces.StoreClient, error) {
+	config := container.ConfigurationFrom(sf.dic.Get)
+	storeClient := container.StoreClientFrom(sf.dic.Get)
+	if !config.Writable.StoreAndForward.Enabled || storeClient == nil {
+		return nil, errors.New("StoreAndForward not enabled")
+	}
+
+	return storeClient, nil
+}
+
+// StoredItems returns the data items currently stored for later retry
+func (gr *GolangRuntime) StoredItems() ([]contracts.StoredObject, error) {
+	storeClient, err := gr.storeForward.storeClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return storeClient.RetrieveFromStore(gr.ServiceKey)
+}
+
+// PurgeStoredItems removes the stored data items with the ids, or all the items if no ids are specified,
+// and returns the number of items removed. An error is returned if any of the ids aren't found.
+func (gr *GolangRuntime) PurgeStoredItems(ids ...string) (int, error) {
+	items, err := gr.StoredItems()
+	if err != nil {
+		return 0, err
+	}
+
+	toRemove := items
+	if len(ids) > 0 {
+		itemsById := make(map[string]contracts.StoredObject, len(items))
+		for _, item := range items {
+			itemsById[item.ID] = item
+		}
+
+		toRemove = nil
+		for _, id := range ids {
+			item, found := itemsById[id]
+			if !found {
+				return 0, fmt.Errorf("stored data item '%s' not found", id)
+			}
+			toRemove = append(toRemove, item)
+		}
+	}
+
+	storeClient, _ := gr.storeForward.storeClient()
+	for removed, item := range toRemove {
+		if err := storeClient.RemoveFromStore(item); err != nil {
+			return removed, fmt.Errorf("unable to remove stored data item '%s': %s", item.ID, err.Error())
+		}
+	}
+
+	return len(toRemove), nil
+}
+
+// RetryStoredItems retries the stored data items now rather than waiting for the next RetryInterval
+func (gr *GolangRuntime) RetryStoredItems() error {
+	if _, err := gr.storeForward.storeClient(); err != nil {
+		return err
+	}
+
+	gr.storeForward.retryStoredData(gr.ServiceKey)
+	return nil
+}
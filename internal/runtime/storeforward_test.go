@@ -112,7 +112,7 @@ func TestProcessRetryItems(t *testing.T) {
 			runtime.Initialize(dic)
 			runtime.SetTransforms([]interfaces.AppFunction{transformPassthru, transformPassthru, test.TargetTransform})
 
-			version := runtime.storeForward.pipelineHash
+			version := calculatePipelineHash(runtime.transforms)
 			if test.BadVersion {
 				version = "some bad version"
 			}
@@ -161,7 +161,7 @@ func TestDoStoreAndForwardRetry(t *testing.T) {
 			runtime.Initialize(updateDicWithMockStoreClient())
 			runtime.SetTransforms([]interfaces.AppFunction{transformPassthru, test.TargetTransform})
 
-			object := contracts.NewStoredObject(serviceKey, payload, 1, runtime.storeForward.calculatePipelineHash(), nil)
+			object := contracts.NewStoredObject(serviceKey, payload, 1, calculatePipelineHash(runtime.transforms), nil)
 			object.CorrelationID = "CorrelationID"
 			object.RetryCount = test.RetryCount
 
@@ -180,6 +180,43 @@ func TestDoStoreAndForwardRetry(t *testing.T) {
 	}
 }
 
+func TestStoredItemsManagement(t *testing.T) {
+	serviceKey := "AppService-UnitTest"
+
+	runtime := GolangRuntime{ServiceKey: serviceKey}
+	runtime.Initialize(updateDicWithMockStoreClient())
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		id, err := mockStoreObject(contracts.NewStoredObject(serviceKey, []byte("My Payload"), 1, "version", nil))
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	items, err := runtime.StoredItems()
+	require.NoError(t, err)
+	assert.Len(t, items, 3)
+
+	_, err = runtime.PurgeStoredItems(uuid.NewString())
+	require.Error(t, err)
+
+	count, err := runtime.PurgeStoredItems(ids[0])
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Len(t, mockRetrieveObjects(serviceKey), 2)
+
+	count, err = runtime.PurgeStoredItems()
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Len(t, mockRetrieveObjects(serviceKey), 0)
+
+	// Version doesn't match the pipeline, so the item is removed when retried
+	_, err = mockStoreObject(contracts.NewStoredObject(serviceKey, []byte("My Payload"), 1, "version", nil))
+	require.NoError(t, err)
+	require.NoError(t, runtime.RetryStoredItems())
+	assert.Len(t, mockRetrieveObjects(serviceKey), 0)
+}
+
 var mockObjectStore map[string]contracts.StoredObject
 
 func updateDicWithMockStoreClient() *di.Container {
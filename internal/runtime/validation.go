@@ -0,0 +1,291 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/appfunction"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/transforms"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	edgexErrors "github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+)
+
+// deadLetterFileName is the name of the file in the DeadLetterDir the Events failing strict validation are appended to
+const deadLetterFileName = "dead-letter.log"
+
+// Defaults of the strict validation settings which must be greater than zero
+const (
+	DefaultProfileCacheTTL    = 5 * time.Minute
+	DefaultDeadLetterMaxSize  = int64(10 * 1024 * 1024)
+	DefaultDeadLetterMaxFiles = 5
+)
+
+// profileRequestTimeout is how long retrieving a device profile from Core Metadata may take
+const profileRequestTimeout = 5 * time.Second
+
+// StrictValidation contains the settings of the strict validation of the Events received, beyond the validation of
+// their required fields when they are decoded, before the pipeline processes them
+type StrictValidation struct {
+	// Enabled validates the Events received. Invalid Events are counted as invalid messages and not processed.
+	Enabled bool
+	// ValidateProfiles checks the value type of each reading against the resource of the same name in the
+	// Event's device profile, retrieved from Core Metadata and cached for ProfileCacheTTL, DefaultProfileCacheTTL
+	// when not greater than zero. Events aren't rejected when Core Metadata is unavailable, only when their profile
+	// doesn't exist.
+	ValidateProfiles bool
+	ProfileCacheTTL  time.Duration
+	// MaxOriginSkew is how far the origins may be from the current time, in either direction. Not checked when zero.
+	MaxOriginSkew time.Duration
+	// DeadLetterDir is the directory the invalid Events are appended to, as received. Dropped when empty.
+	DeadLetterDir string
+	// DeadLetterMaxSize is the size in bytes the dead-letter file is rotated at and DeadLetterMaxFiles the number
+	// of rotated files retained. The defaults are used when not greater than zero.
+	DeadLetterMaxSize  int64
+	DeadLetterMaxFiles int
+}
+
+type profileCacheEntry struct {
+	valueTypes map[string]string
+	expires    time.Time
+}
+
+// eventValidator validates the Events received when strict validation is enabled
+type eventValidator struct {
+	settings   StrictValidation
+	profiles   map[string]profileCacheEntry
+	deadLetter *transforms.FileExporter
+	mutex      sync.Mutex
+	now        func() time.Time
+}
+
+// SetStrictValidation changes the strict validation of the Events received
+func (gr *GolangRuntime) SetStrictValidation(settings StrictValidation) {
+	validator := &gr.validator
+	validator.mutex.Lock()
+	defer validator.mutex.Unlock()
+
+	if settings.ProfileCacheTTL <= 0 {
+		settings.ProfileCacheTTL = DefaultProfileCacheTTL
+	}
+	if settings.DeadLetterMaxSize <= 0 {
+		settings.DeadLetterMaxSize = DefaultDeadLetterMaxSize
+	}
+	if settings.DeadLetterMaxFiles <= 0 {
+		settings.DeadLetterMaxFiles = DefaultDeadLetterMaxFiles
+	}
+
+	if validator.settings.DeadLetterDir != settings.DeadLetterDir ||
+		validator.settings.DeadLetterMaxSize != settings.DeadLetterMaxSize ||
+		validator.settings.DeadLetterMaxFiles != settings.DeadLetterMaxFiles {
+		validator.deadLetter = nil
+	}
+	if validator.settings.ProfileCacheTTL != settings.ProfileCacheTTL || !settings.ValidateProfiles {
+		validator.profiles = nil
+	}
+	validator.settings = settings
+}
+
+// strictValidation returns the current strict validation settings
+func (validator *eventValidator) strictValidation() StrictValidation {
+	validator.mutex.Lock()
+	defer validator.mutex.Unlock()
+	return validator.settings
+}
+
+// validate returns an error describing why the Event is invalid, or nil if it is valid. The value types aren't
+// validated against the device profile when it can't be retrieved, which is logged, so that valid Events aren't
+// rejected while Core Metadata is unavailable.
+func (validator *eventValidator) validate(appContext *appfunction.Context, event *dtos.Event, settings StrictValidation) error {
+	now := time.Now
+	if validator.now != nil {
+		now = validator.now
+	}
+
+	if err := checkOrigin("Event", event.Origin, now(), settings.MaxOriginSkew); err != nil {
+		return err
+	}
+
+	var valueTypes map[string]string
+	if settings.ValidateProfiles {
+		var err error
+		valueTypes, err = validator.profileValueTypes(appContext, event.ProfileName, now(), settings.ProfileCacheTTL)
+		if err != nil {
+			if edgexErrors.Kind(err) == edgexErrors.KindEntityDoesNotExist {
+				return fmt.Errorf("profile '%s' does not exist", event.ProfileName)
+			}
+			appContext.LoggingClient().Warnf("Event value types not validated against its profile: %s", err.Error())
+			valueTypes = nil
+		}
+	}
+
+	for index, reading := range event.Readings {
+		name := fmt.Sprintf("reading #%d (%s)", index, reading.ResourceName)
+
+		if reading.DeviceName != event.DeviceName || reading.ProfileName != event.ProfileName {
+			return fmt.Errorf("%s device '%s' and profile '%s' differ from the Event's", name, reading.DeviceName, reading.ProfileName)
+		}
+
+		if err := checkOrigin(name, reading.Origin, now(), settings.MaxOriginSkew); err != nil {
+			return err
+		}
+
+		if err := checkReadingValue(reading); err != nil {
+			return fmt.Errorf("%s %s", name, err.Error())
+		}
+
+		if valueTypes != nil {
+			valueType, found := valueTypes[reading.ResourceName]
+			if !found {
+				return fmt.Errorf("%s is not a resource of profile '%s'", name, event.ProfileName)
+			}
+			if !strings.EqualFold(valueType, reading.ValueType) {
+				return fmt.Errorf("%s value type %s is not the profile's %s", name, reading.ValueType, valueType)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkOrigin returns an error if the origin, in nanoseconds, is further than the max skew from now
+func checkOrigin(name string, origin int64, now time.Time, maxSkew time.Duration) error {
+	if maxSkew <= 0 {
+		return nil
+	}
+
+	skew := now.Sub(time.Unix(0, origin))
+	if skew > maxSkew || skew < -maxSkew {
+		return fmt.Errorf("%s origin %s is more than %s from the current time",
+			name, time.Unix(0, origin).UTC().Format(time.RFC3339Nano), maxSkew.String())
+	}
+	return nil
+}
+
+// checkReadingValue returns an error if the reading's value isn't a value of its value type
+func checkReadingValue(reading dtos.BaseReading) error {
+	var err error
+
+	switch reading.ValueType {
+	case common.ValueTypeBinary:
+		if len(reading.BinaryValue) == 0 || len(reading.MediaType) == 0 {
+			return fmt.Errorf("binary value must have a value and media type")
+		}
+	case common.ValueTypeBool:
+		_, err = strconv.ParseBool(reading.Value)
+	case common.ValueTypeUint8, common.ValueTypeUint16, common.ValueTypeUint32, common.ValueTypeUint64:
+		_, err = strconv.ParseUint(reading.Value, 10, valueTypeBits(reading.ValueType))
+	case common.ValueTypeInt8, common.ValueTypeInt16, common.ValueTypeInt32, common.ValueTypeInt64:
+		_, err = strconv.ParseInt(reading.Value, 10, valueTypeBits(reading.ValueType))
+	case common.ValueTypeFloat32, common.ValueTypeFloat64:
+		_, err = strconv.ParseFloat(reading.Value, valueTypeBits(reading.ValueType))
+	}
+
+	if err != nil {
+		return fmt.Errorf("value '%s' is not a valid %s", reading.Value, reading.ValueType)
+	}
+	return nil
+}
+
+// valueTypeBits returns the size in bits of the numeric value type, i.e. 16 for Int16
+func valueTypeBits(valueType string) int {
+	bits, err := strconv.Atoi(strings.TrimLeftFunc(valueType, unicode.IsLetter))
+	if err != nil {
+		return 64
+	}
+	return bits
+}
+
+// profileValueTypes returns the value types of the profile's resources, keyed by resource name
+func (validator *eventValidator) profileValueTypes(appContext *appfunction.Context, profileName string, now time.Time, cacheTTL time.Duration) (map[string]string, error) {
+	validator.mutex.Lock()
+	entry, found := validator.profiles[profileName]
+	validator.mutex.Unlock()
+
+	if found && now.Before(entry.expires) {
+		return entry.valueTypes, nil
+	}
+
+	client := appContext.DeviceProfileClient()
+	if client == nil {
+		return nil, fmt.Errorf("DeviceProfileClient not initialized. Core Metadata is missing from clients configuration")
+	}
+
+	requestCtx, cancel := context.WithTimeout(context.Background(), profileRequestTimeout)
+	defer cancel()
+
+	response, err := client.DeviceProfileByName(requestCtx, profileName)
+	if err != nil {
+		return nil, edgexErrors.NewCommonEdgeX(edgexErrors.Kind(err),
+			fmt.Sprintf("unable to retrieve device profile '%s' from Core Metadata", profileName), err)
+	}
+
+	valueTypes := make(map[string]string, len(response.Profile.DeviceResources))
+	for _, resource := range response.Profile.DeviceResources {
+		valueTypes[resource.Name] = resource.Properties.ValueType
+	}
+
+	validator.mutex.Lock()
+	if validator.profiles == nil {
+		validator.profiles = make(map[string]profileCacheEntry)
+	}
+	validator.profiles[profileName] = profileCacheEntry{valueTypes: valueTypes, expires: now.Add(cacheTTL)}
+	validator.mutex.Unlock()
+
+	return valueTypes, nil
+}
+
+// deadLetterPayload appends the payload of the invalid Event to the dead-letter file in the DeadLetterDir
+func (validator *eventValidator) deadLetterPayload(appContext *appfunction.Context, payload []byte, settings StrictValidation) {
+	if len(settings.DeadLetterDir) == 0 {
+		return
+	}
+
+	lc := appContext.LoggingClient()
+
+	validator.mutex.Lock()
+	if validator.deadLetter == nil {
+		exporter, err := transforms.NewFileExporter(transforms.FileExporterOptions{
+			Directory: settings.DeadLetterDir,
+			FileName:  deadLetterFileName,
+			MaxSize:   settings.DeadLetterMaxSize,
+			MaxFiles:  settings.DeadLetterMaxFiles,
+		})
+		if err != nil {
+			validator.mutex.Unlock()
+			lc.Errorf("Unable to create dead-letter FileExporter: %s", err.Error())
+			return
+		}
+		validator.deadLetter = exporter
+	}
+	deadLetter := validator.deadLetter
+	validator.mutex.Unlock()
+
+	if ok, result := deadLetter.ExportToFile(appContext, payload); !ok {
+		lc.Errorf("Unable to dead-letter invalid Event to %s: %v",
+			filepath.Join(settings.DeadLetterDir, deadLetterFileName), result)
+	}
+}
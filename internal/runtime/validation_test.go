@@ -0,0 +1,214 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package runtime
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/appfunction"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/bootstrap/container"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	clientMocks "github.com/edgexfoundry/go-mod-core-contracts/v2/clients/interfaces/mocks"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/responses"
+	edgexErrors "github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newValidationTestEvent(t *testing.T) dtos.Event {
+	event := dtos.NewEvent("Thermostat", "FamilyRoomThermostat", "Temperature")
+	require.NoError(t, event.AddSimpleReading("Temperature", common.ValueTypeInt16, int16(72)))
+	require.NoError(t, event.AddSimpleReading("Humidity", common.ValueTypeFloat64, 45.5))
+	return event
+}
+
+func TestValidateEvent(t *testing.T) {
+	now := time.Now()
+	validator := eventValidator{now: func() time.Time { return now }}
+	settings := StrictValidation{Enabled: true, MaxOriginSkew: time.Hour}
+
+	tests := []struct {
+		Name          string
+		Change        func(event *dtos.Event)
+		ExpectedError bool
+	}{
+		{"Valid", func(event *dtos.Event) {}, false},
+		{"Reading of another device", func(event *dtos.Event) { event.Readings[0].DeviceName = "Other" }, true},
+		{"Reading of another profile", func(event *dtos.Event) { event.Readings[1].ProfileName = "Other" }, true},
+		{"Value not of value type", func(event *dtos.Event) { event.Readings[0].Value = "warm" }, true},
+		{"Value out of range", func(event *dtos.Event) { event.Readings[0].Value = "70000" }, true},
+		{"Float value", func(event *dtos.Event) { event.Readings[1].Value = "4.550000e+01" }, false},
+		{"Binary value missing", func(event *dtos.Event) {
+			event.Readings[1].ValueType = common.ValueTypeBinary
+			event.Readings[1].Value = ""
+		}, true},
+		{"Event origin too old", func(event *dtos.Event) { event.Origin = now.Add(-2 * time.Hour).UnixNano() }, true},
+		{"Reading origin in the future", func(event *dtos.Event) {
+			event.Readings[0].Origin = now.Add(2 * time.Hour).UnixNano()
+		}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			event := newValidationTestEvent(t)
+			event.Origin = now.UnixNano()
+			for index := range event.Readings {
+				event.Readings[index].Origin = now.UnixNano()
+			}
+			test.Change(&event)
+
+			err := validator.validate(appfunction.NewContext("123", dic, ""), &event, settings)
+			if test.ExpectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	event := newValidationTestEvent(t)
+	event.Origin = now.Add(-2 * time.Hour).UnixNano()
+	settings.MaxOriginSkew = 0
+	assert.NoError(t, validator.validate(appfunction.NewContext("123", dic, ""), &event, settings),
+		"origins are not validated without a max skew")
+}
+
+func TestValidateEventProfiles(t *testing.T) {
+	profile := dtos.DeviceProfile{
+		Name: "Thermostat",
+		DeviceResources: []dtos.DeviceResource{
+			{Name: "Temperature", Properties: dtos.ResourceProperties{ValueType: common.ValueTypeInt16}},
+			{Name: "Humidity", Properties: dtos.ResourceProperties{ValueType: common.ValueTypeFloat64}},
+		},
+	}
+	profileClient := &clientMocks.DeviceProfileClient{}
+	profileClient.On("DeviceProfileByName", mock.Anything, "Thermostat").Return(responses.DeviceProfileResponse{Profile: profile}, nil)
+	profileClient.On("DeviceProfileByName", mock.Anything, "Unknown").Return(responses.DeviceProfileResponse{},
+		edgexErrors.NewCommonEdgeX(edgexErrors.KindEntityDoesNotExist, "not found", nil))
+	profileClient.On("DeviceProfileByName", mock.Anything, "Unavailable").Return(responses.DeviceProfileResponse{},
+		edgexErrors.NewCommonEdgeX(edgexErrors.KindServiceUnavailable, "connection refused", errors.New("dial tcp")))
+
+	profileDic := di.NewContainer(di.ServiceConstructorMap{
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return logger.NewMockClient()
+		},
+		container.DeviceProfileClientName: func(get di.Get) interface{} {
+			return profileClient
+		},
+	})
+	appContext := appfunction.NewContext("123", profileDic, "")
+
+	validator := eventValidator{}
+	settings := StrictValidation{Enabled: true, ValidateProfiles: true, ProfileCacheTTL: time.Minute}
+
+	event := newValidationTestEvent(t)
+	require.NoError(t, validator.validate(appContext, &event, settings))
+	require.NoError(t, validator.validate(appContext, &event, settings))
+	profileClient.AssertNumberOfCalls(t, "DeviceProfileByName", 1)
+
+	event.Readings[1].ValueType = common.ValueTypeFloat32
+	assert.Error(t, validator.validate(appContext, &event, settings), "value type is not the profile's")
+
+	event = newValidationTestEvent(t)
+	event.Readings[1].ResourceName = "Pressure"
+	assert.Error(t, validator.validate(appContext, &event, settings), "resource is not in the profile")
+
+	event = newValidationTestEvent(t)
+	event.ProfileName = "Unknown"
+	for index := range event.Readings {
+		event.Readings[index].ProfileName = "Unknown"
+	}
+	assert.Error(t, validator.validate(appContext, &event, settings), "profile does not exist")
+
+	event = newValidationTestEvent(t)
+	event.ProfileName = "Unavailable"
+	for index := range event.Readings {
+		event.Readings[index].ProfileName = "Unavailable"
+	}
+	event.Readings[1].ValueType = common.ValueTypeFloat32
+	assert.NoError(t, validator.validate(appContext, &event, settings), "value types not validated without Core Metadata")
+}
+
+func TestSetStrictValidationDefaults(t *testing.T) {
+	runtime := GolangRuntime{}
+	runtime.SetStrictValidation(StrictValidation{Enabled: true, ValidateProfiles: true})
+
+	assert.Equal(t, DefaultProfileCacheTTL, runtime.validator.settings.ProfileCacheTTL)
+	assert.Equal(t, DefaultDeadLetterMaxSize, runtime.validator.settings.DeadLetterMaxSize)
+	assert.Equal(t, DefaultDeadLetterMaxFiles, runtime.validator.settings.DeadLetterMaxFiles)
+}
+
+func TestProcessMessageStrictValidation(t *testing.T) {
+	deadLetterDir := t.TempDir()
+
+	transformCalled := false
+	transform := func(_ interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		transformCalled = true
+		return false, nil
+	}
+
+	runtime := GolangRuntime{}
+	runtime.Initialize(nil)
+	runtime.SetTransforms([]interfaces.AppFunction{transform})
+
+	invalidEvent := newValidationTestEvent(t)
+	invalidEvent.Readings[0].Value = "warm"
+	payload, err := json.Marshal(invalidEvent)
+	require.NoError(t, err)
+
+	envelope := types.MessageEnvelope{
+		CorrelationID: "123-234-345-456",
+		Payload:       payload,
+		ContentType:   common.ContentTypeJSON,
+	}
+
+	// Not validated until enabled
+	require.Nil(t, runtime.ProcessMessage(appfunction.NewContext("testing", dic, ""), envelope))
+	assert.True(t, transformCalled)
+
+	transformCalled = false
+	runtime.SetStrictValidation(StrictValidation{Enabled: true, DeadLetterDir: deadLetterDir})
+
+	msgErr := runtime.ProcessMessage(appfunction.NewContext("testing", dic, ""), envelope)
+	require.NotNil(t, msgErr)
+	assert.Equal(t, http.StatusBadRequest, msgErr.ErrorCode)
+	assert.False(t, transformCalled)
+
+	deadLetters, err := os.ReadFile(filepath.Join(deadLetterDir, deadLetterFileName))
+	require.NoError(t, err)
+	assert.Equal(t, string(payload)+"\n", string(deadLetters))
+
+	validEvent := newValidationTestEvent(t)
+	envelope.Payload, err = json.Marshal(validEvent)
+	require.NoError(t, err)
+	require.Nil(t, runtime.ProcessMessage(appfunction.NewContext("testing", dic, ""), envelope))
+	assert.True(t, transformCalled)
+}
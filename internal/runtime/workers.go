@@ -0,0 +1,123 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package runtime
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultWorkers is the number of workers processing the queued data when MaxConcurrency is unlimited
+const defaultWorkers = 64
+
+// workerQueueSize is the number of messages queued for the workers before queueing waits
+const workerQueueSize = 128
+
+// workerQueue is a bounded queue of the data received by a trigger, which is processed by a pool of workers.
+// Queueing waits while the queue is full, so the trigger stops receiving until the workers catch up rather than
+// starting a goroutine per message. The number of workers can be changed while data is processed.
+type workerQueue struct {
+	jobs    chan func()
+	stop    chan struct{}
+	mutex   sync.Mutex
+	target  int
+	running int
+	stopped bool
+}
+
+func newWorkerQueue(workers int) *workerQueue {
+	queue := &workerQueue{
+		jobs: make(chan func(), workerQueueSize),
+		stop: make(chan struct{}),
+	}
+	queue.resize(workers)
+	return queue
+}
+
+// resize sets the number of workers, which defaults to defaultWorkers when zero. New workers start at once, while
+// excess workers exit once they complete the data they are processing.
+func (queue *workerQueue) resize(workers int) {
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	queue.target = workers
+	for !queue.stopped && queue.running < queue.target {
+		queue.running++
+		go queue.work()
+	}
+}
+
+// enqueue queues the job, waiting while the queue is full. Returns false if the context is done or the queue is
+// stopped before the job is queued.
+func (queue *workerQueue) enqueue(ctx context.Context, job func()) bool {
+	// Checked first, since select picks at random when the queue also has room
+	select {
+	case <-queue.stop:
+		return false
+	default:
+	}
+
+	select {
+	case queue.jobs <- job:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-queue.stop:
+		return false
+	}
+}
+
+// close stops the workers once they complete the data they are processing. The data still queued isn't processed.
+func (queue *workerQueue) close() {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	if !queue.stopped {
+		queue.stopped = true
+		close(queue.stop)
+	}
+}
+
+func (queue *workerQueue) work() {
+	for {
+		select {
+		case job := <-queue.jobs:
+			job()
+			if queue.retire() {
+				return
+			}
+		case <-queue.stop:
+			return
+		}
+	}
+}
+
+// retire returns whether the worker must exit because there are more workers than needed
+func (queue *workerQueue) retire() bool {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	if queue.running > queue.target {
+		queue.running--
+		return true
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package runtime
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerQueue(t *testing.T) {
+	queue := newWorkerQueue(2)
+	defer queue.close()
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	processed := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		require.True(t, queue.enqueue(context.Background(), func() {
+			defer wg.Done()
+			mutex.Lock()
+			processed++
+			mutex.Unlock()
+		}))
+	}
+	wg.Wait()
+	assert.Equal(t, 10, processed)
+}
+
+func TestWorkerQueueBackpressure(t *testing.T) {
+	queue := newWorkerQueue(1)
+
+	// Blocks the only worker, so the jobs queued after it fill the queue
+	block := make(chan struct{})
+	require.True(t, queue.enqueue(context.Background(), func() { <-block }))
+	for i := 0; i < workerQueueSize; i++ {
+		require.True(t, queue.enqueue(context.Background(), func() {}))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.False(t, queue.enqueue(ctx, func() {}), "expected enqueue to wait while the queue is full")
+
+	close(block)
+	queue.close()
+	assert.False(t, queue.enqueue(context.Background(), func() {}), "expected enqueue to fail once closed")
+}
+
+func TestWorkerQueueResize(t *testing.T) {
+	queue := newWorkerQueue(0)
+	defer queue.close()
+	assert.Equal(t, defaultWorkers, queue.running)
+
+	queue.resize(2)
+	assert.Equal(t, 2, queue.target)
+
+	// Excess workers exit once they complete a job
+	var wg sync.WaitGroup
+	for i := 0; i < defaultWorkers*2; i++ {
+		wg.Add(1)
+		require.True(t, queue.enqueue(context.Background(), wg.Done))
+	}
+	wg.Wait()
+	require.Eventually(t, func() bool {
+		queue.mutex.Lock()
+		defer queue.mutex.Unlock()
+		return queue.running == 2
+	}, time.Second, 10*time.Millisecond)
+
+	queue.resize(4)
+	assert.Equal(t, 4, queue.running)
+}
@@ -0,0 +1,361 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package telemetry
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+)
+
+// Types of the custom metrics reported
+const (
+	MetricTypeCounter = "counter"
+	MetricTypeGauge   = "gauge"
+	MetricTypeTimer   = "timer"
+)
+
+// InstanceTag is the tag reporting the instance of the service with every custom metric
+const InstanceTag = "instance"
+
+// TimerBuckets are the upper bounds of the histogram buckets the durations recorded by the Timers are counted in
+var TimerBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// bucketedTimer is a Timer which also counts the durations recorded in the TimerBuckets
+type bucketedTimer interface {
+	interfaces.Timer
+	// Buckets returns the cumulative number of durations recorded less than or equal to each of the TimerBuckets
+	Buckets() []int64
+	// Sum returns the sum of the durations recorded
+	Sum() time.Duration
+}
+
+// MetricSnapshot is the value of a custom metric at the time it was reported
+// swagger:model
+type MetricSnapshot struct {
+	Name   string                 `json:"name"`
+	Type   string                 `json:"type"`
+	Tags   map[string]string      `json:"tags,omitempty"`
+	Values map[string]interface{} `json:"values"`
+}
+
+type registeredMetric struct {
+	name   string
+	metric interface{}
+	tags   map[string]string
+}
+
+// MetricsManager implements interfaces.MetricsManager and reports the custom metrics registered
+type MetricsManager struct {
+	metrics    map[string]registeredMetric
+	commonTags map[string]string
+	mutex      sync.RWMutex
+}
+
+// NewMetricsManager creates a new MetricsManager with no metrics registered
+func NewMetricsManager() *MetricsManager {
+	return NewMetricsManagerWithTags(nil)
+}
+
+// NewMetricsManagerWithTags creates a new MetricsManager with no metrics registered, which reports the common tags
+// with every metric. Tags registered with a metric take precedence over the common tags.
+func NewMetricsManagerWithTags(commonTags map[string]string) *MetricsManager {
+	copiedTags := make(map[string]string, len(commonTags))
+	for tag, value := range commonTags {
+		copiedTags[tag] = value
+	}
+
+	return &MetricsManager{
+		metrics:    make(map[string]registeredMetric),
+		commonTags: copiedTags,
+	}
+}
+
+// NewCounter creates a new Counter, which must be registered to be reported
+func (manager *MetricsManager) NewCounter() interfaces.Counter {
+	return &counter{}
+}
+
+// NewGauge creates a new Gauge, which must be registered to be reported
+func (manager *MetricsManager) NewGauge() interfaces.Gauge {
+	return &gauge{}
+}
+
+// NewTimer creates a new Timer, which must be registered to be reported
+func (manager *MetricsManager) NewTimer() interfaces.Timer {
+	return &timer{buckets: make([]int64, len(TimerBuckets))}
+}
+
+// Register registers the metric, which must be a Counter, Gauge or Timer, to be reported with the name and
+// optional tags
+func (manager *MetricsManager) Register(name string, metric interface{}, tags map[string]string) error {
+	name = strings.TrimSpace(name)
+	return manager.register(name, name, metric, tags)
+}
+
+// RegisterLabeled registers the metric, which must be a Counter, Gauge or Timer, to be reported with the name and
+// tags. Unlike Register, many metrics can be registered with the same name as long as their tags differ, i.e. the
+// duration of each pipeline function tagged with the function's name.
+func (manager *MetricsManager) RegisterLabeled(name string, metric interface{}, tags map[string]string) error {
+	name = strings.TrimSpace(name)
+	return manager.register(labeledKey(name, tags), name, metric, tags)
+}
+
+func (manager *MetricsManager) register(key string, name string, metric interface{}, tags map[string]string) error {
+	if len(name) == 0 {
+		return errors.New("metric name can not be empty")
+	}
+
+	switch metric.(type) {
+	case interfaces.Counter, interfaces.Gauge, interfaces.Timer:
+	default:
+		return fmt.Errorf("metric %s must be a Counter, Gauge or Timer, not %T", key, metric)
+	}
+
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	if _, found := manager.metrics[key]; found {
+		return fmt.Errorf("metric %s is already registered", key)
+	}
+
+	copiedTags := make(map[string]string, len(tags))
+	for tag, value := range tags {
+		copiedTags[tag] = value
+	}
+
+	manager.metrics[key] = registeredMetric{name: name, metric: metric, tags: copiedTags}
+	return nil
+}
+
+// labeledKey returns the key of the metric registered with the name and tags, i.e. 'Duration{function=Filter}'
+func labeledKey(name string, tags map[string]string) string {
+	tagNames := make([]string, 0, len(tags))
+	for tag := range tags {
+		tagNames = append(tagNames, tag)
+	}
+	sort.Strings(tagNames)
+
+	labels := make([]string, 0, len(tagNames))
+	for _, tag := range tagNames {
+		labels = append(labels, tag+"="+tags[tag])
+	}
+
+	return name + "{" + strings.Join(labels, ",") + "}"
+}
+
+// Unregister stops the metric with the name from being reported
+func (manager *MetricsManager) Unregister(name string) {
+	manager.mutex.Lock()
+	delete(manager.metrics, strings.TrimSpace(name))
+	manager.mutex.Unlock()
+}
+
+// UnregisterLabeled stops the metric registered with RegisterLabeled with the name and tags from being reported
+func (manager *MetricsManager) UnregisterLabeled(name string, tags map[string]string) {
+	manager.mutex.Lock()
+	delete(manager.metrics, labeledKey(strings.TrimSpace(name), tags))
+	manager.mutex.Unlock()
+}
+
+// IsRegistered returns whether a metric with the name is registered
+func (manager *MetricsManager) IsRegistered(name string) bool {
+	manager.mutex.RLock()
+	defer manager.mutex.RUnlock()
+
+	_, found := manager.metrics[strings.TrimSpace(name)]
+	return found
+}
+
+// Snapshot returns the current values of all the registered metrics, ordered by name and then tags
+func (manager *MetricsManager) Snapshot() []MetricSnapshot {
+	manager.mutex.RLock()
+	defer manager.mutex.RUnlock()
+
+	snapshots := make([]MetricSnapshot, 0, len(manager.metrics))
+	for _, key := range manager.sortedKeys() {
+		registered := manager.metrics[key]
+		snapshot := MetricSnapshot{Name: registered.name}
+		if len(registered.tags) > 0 || len(manager.commonTags) > 0 {
+			snapshot.Tags = make(map[string]string, len(registered.tags)+len(manager.commonTags))
+			for tag, value := range manager.commonTags {
+				snapshot.Tags[tag] = value
+			}
+			for tag, value := range registered.tags {
+				snapshot.Tags[tag] = value
+			}
+		}
+
+		switch metric := registered.metric.(type) {
+		case interfaces.Timer:
+			snapshot.Type = MetricTypeTimer
+			snapshot.Values = map[string]interface{}{
+				"count": metric.Count(),
+				"min":   metric.Min().String(),
+				"max":   metric.Max().String(),
+				"mean":  metric.Mean().String(),
+			}
+			if bucketed, ok := metric.(bucketedTimer); ok {
+				buckets := make(map[string]int64, len(TimerBuckets))
+				for index, count := range bucketed.Buckets() {
+					buckets[TimerBuckets[index].String()] = count
+				}
+				snapshot.Values["buckets"] = buckets
+			}
+		case interfaces.Counter:
+			snapshot.Type = MetricTypeCounter
+			snapshot.Values = map[string]interface{}{"count": metric.Count()}
+		case interfaces.Gauge:
+			snapshot.Type = MetricTypeGauge
+			snapshot.Values = map[string]interface{}{"value": metric.Value()}
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots
+}
+
+// sortedKeys returns the keys of the registered metrics in order. Must be called with the mutex locked.
+func (manager *MetricsManager) sortedKeys() []string {
+	keys := make([]string, 0, len(manager.metrics))
+	for key := range manager.metrics {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type counter struct {
+	count int64
+}
+
+func (c *counter) Inc(delta int64) {
+	atomic.AddInt64(&c.count, delta)
+}
+
+func (c *counter) Count() int64 {
+	return atomic.LoadInt64(&c.count)
+}
+
+type gauge struct {
+	value int64
+}
+
+func (g *gauge) Update(value int64) {
+	atomic.StoreInt64(&g.value, value)
+}
+
+func (g *gauge) Value() int64 {
+	return atomic.LoadInt64(&g.value)
+}
+
+type timer struct {
+	count   int64
+	min     time.Duration
+	max     time.Duration
+	total   time.Duration
+	buckets []int64
+	mutex   sync.Mutex
+}
+
+func (t *timer) Time(function func()) {
+	start := time.Now()
+	function()
+	t.Update(time.Since(start))
+}
+
+func (t *timer) Update(duration time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.count == 0 || duration < t.min {
+		t.min = duration
+	}
+	if duration > t.max {
+		t.max = duration
+	}
+	t.total += duration
+	t.count++
+
+	for index, bound := range TimerBuckets {
+		if duration <= bound {
+			t.buckets[index]++
+		}
+	}
+}
+
+func (t *timer) Buckets() []int64 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	buckets := make([]int64, len(t.buckets))
+	copy(buckets, t.buckets)
+	return buckets
+}
+
+func (t *timer) Sum() time.Duration {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.total
+}
+
+func (t *timer) Count() int64 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.count
+}
+
+func (t *timer) Min() time.Duration {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.min
+}
+
+func (t *timer) Max() time.Duration {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.max
+}
+
+func (t *timer) Mean() time.Duration {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.count == 0 {
+		return 0
+	}
+	return t.total / time.Duration(t.count)
+}
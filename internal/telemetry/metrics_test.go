@@ -0,0 +1,121 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package telemetry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsManagerRegister(t *testing.T) {
+	manager := NewMetricsManager()
+
+	require.NoError(t, manager.Register("EventsExported", manager.NewCounter(), nil))
+	assert.True(t, manager.IsRegistered("EventsExported"))
+
+	assert.EqualError(t, manager.Register("EventsExported", manager.NewCounter(), nil),
+		"metric EventsExported is already registered")
+	assert.Error(t, manager.Register(" ", manager.NewGauge(), nil))
+	assert.EqualError(t, manager.Register("Bogus", 10, nil), "metric Bogus must be a Counter, Gauge or Timer, not int")
+
+	manager.Unregister("EventsExported")
+	assert.False(t, manager.IsRegistered("EventsExported"))
+}
+
+func TestMetricsManagerRegisterLabeled(t *testing.T) {
+	manager := NewMetricsManager()
+
+	filterTags := map[string]string{"function": "Filter", "pipeline": "default"}
+	exportTags := map[string]string{"function": "Export", "pipeline": "default"}
+	require.NoError(t, manager.RegisterLabeled("FunctionErrors", manager.NewCounter(), filterTags))
+	require.NoError(t, manager.RegisterLabeled("FunctionErrors", manager.NewCounter(), exportTags))
+
+	assert.EqualError(t, manager.RegisterLabeled("FunctionErrors", manager.NewCounter(), filterTags),
+		"metric FunctionErrors{function=Filter,pipeline=default} is already registered")
+
+	snapshots := manager.Snapshot()
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, "FunctionErrors", snapshots[0].Name)
+	assert.Equal(t, exportTags, snapshots[0].Tags)
+	assert.Equal(t, "FunctionErrors", snapshots[1].Name)
+	assert.Equal(t, filterTags, snapshots[1].Tags)
+
+	manager.UnregisterLabeled("FunctionErrors", filterTags)
+	snapshots = manager.Snapshot()
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, exportTags, snapshots[0].Tags)
+}
+
+func TestMetricsManagerSnapshot(t *testing.T) {
+	manager := NewMetricsManager()
+
+	counter := manager.NewCounter()
+	gauge := manager.NewGauge()
+	timer := manager.NewTimer()
+
+	tags := map[string]string{"export": "cloud"}
+	require.NoError(t, manager.Register("ExportCount", counter, tags))
+	require.NoError(t, manager.Register("BatchSize", gauge, nil))
+	require.NoError(t, manager.Register("ExportDuration", timer, nil))
+
+	// Changing the tags after registering doesn't change the tags reported
+	tags["export"] = "changed"
+
+	counter.Inc(2)
+	counter.Inc(3)
+	gauge.Update(42)
+	timer.Update(10 * time.Millisecond)
+	timer.Update(30 * time.Millisecond)
+
+	expected := []MetricSnapshot{
+		{Name: "BatchSize", Type: MetricTypeGauge, Values: map[string]interface{}{"value": int64(42)}},
+		{Name: "ExportCount", Type: MetricTypeCounter, Tags: map[string]string{"export": "cloud"},
+			Values: map[string]interface{}{"count": int64(5)}},
+		{Name: "ExportDuration", Type: MetricTypeTimer, Values: map[string]interface{}{
+			"count": int64(2), "min": "10ms", "max": "30ms", "mean": "20ms", "buckets": map[string]int64{
+				"5ms": 0, "10ms": 1, "25ms": 1, "50ms": 2, "100ms": 2, "250ms": 2, "500ms": 2,
+				"1s": 2, "2.5s": 2, "5s": 2, "10s": 2}}},
+	}
+
+	assert.Equal(t, expected, manager.Snapshot())
+}
+
+func TestMetricsManagerCommonTags(t *testing.T) {
+	manager := NewMetricsManagerWithTags(map[string]string{InstanceTag: "east", "export": "common"})
+
+	require.NoError(t, manager.Register("ExportCount", manager.NewCounter(), map[string]string{"export": "cloud"}))
+	require.NoError(t, manager.Register("BatchSize", manager.NewGauge(), nil))
+
+	snapshots := manager.Snapshot()
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, map[string]string{InstanceTag: "east", "export": "common"}, snapshots[0].Tags)
+	assert.Equal(t, map[string]string{InstanceTag: "east", "export": "cloud"}, snapshots[1].Tags)
+}
+
+func TestTimerTime(t *testing.T) {
+	timer := NewMetricsManager().NewTimer()
+	assert.Equal(t, time.Duration(0), timer.Mean())
+
+	called := false
+	timer.Time(func() { called = true })
+	assert.True(t, called)
+	assert.Equal(t, int64(1), timer.Count())
+	assert.Equal(t, timer.Min(), timer.Max())
+}
@@ -0,0 +1,184 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package telemetry
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+)
+
+// PrometheusContentType is the content type of the Prometheus text exposition format
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// WritePrometheus writes the current values of all the registered metrics, including the system metrics, in the
+// Prometheus text exposition format. Counters are reported as counters, Gauges as gauges and Timers as histograms
+// in seconds, counted in the TimerBuckets, with additional min and max gauges. Timers not created by the
+// MetricsManager are reported as summaries. Metrics registered with the same name and different tags are reported
+// as one metric family.
+func (manager *MetricsManager) WritePrometheus(writer io.Writer) error {
+	prometheus := &prometheusWriter{writer: writer, families: make(map[string]*prometheusFamily)}
+
+	manager.mutex.RLock()
+	for _, key := range manager.sortedKeys() {
+		registered := manager.metrics[key]
+		tags := manager.mergedTags(registered.tags)
+		metricName := prometheusName(registered.name)
+
+		switch metric := registered.metric.(type) {
+		case bucketedTimer:
+			prometheus.addHistogram(metricName+"_seconds", tags, metric)
+			prometheus.add(metricName+"_seconds_min", "gauge", metricName+"_seconds_min", tags, metric.Min().Seconds())
+			prometheus.add(metricName+"_seconds_max", "gauge", metricName+"_seconds_max", tags, metric.Max().Seconds())
+		case interfaces.Timer:
+			count := metric.Count()
+			prometheus.add(metricName+"_seconds", "summary", metricName+"_seconds_sum", tags,
+				metric.Mean().Seconds()*float64(count))
+			prometheus.add(metricName+"_seconds", "summary", metricName+"_seconds_count", tags, float64(count))
+			prometheus.add(metricName+"_seconds_min", "gauge", metricName+"_seconds_min", tags, metric.Min().Seconds())
+			prometheus.add(metricName+"_seconds_max", "gauge", metricName+"_seconds_max", tags, metric.Max().Seconds())
+		case interfaces.Counter:
+			prometheus.add(metricName, "counter", metricName, tags, float64(metric.Count()))
+		case interfaces.Gauge:
+			prometheus.add(metricName, "gauge", metricName, tags, float64(metric.Value()))
+		}
+	}
+	manager.mutex.RUnlock()
+
+	return prometheus.flush()
+}
+
+// mergedTags returns the common tags merged with the metric's tags, which take precedence
+func (manager *MetricsManager) mergedTags(tags map[string]string) map[string]string {
+	merged := make(map[string]string, len(manager.commonTags)+len(tags))
+	for tag, value := range manager.commonTags {
+		merged[tag] = value
+	}
+	for tag, value := range tags {
+		merged[tag] = value
+	}
+	return merged
+}
+
+// prometheusFamily is the samples of a metric family, which must be written together after the family's type
+type prometheusFamily struct {
+	name       string
+	metricType string
+	samples    []string
+}
+
+// prometheusWriter collects the samples of the metric families and writes them in the Prometheus text exposition
+// format, in the order the families were first added
+type prometheusWriter struct {
+	writer   io.Writer
+	order    []*prometheusFamily
+	families map[string]*prometheusFamily
+}
+
+func (prometheus *prometheusWriter) add(
+	familyName string,
+	metricType string,
+	sampleName string,
+	tags map[string]string,
+	value float64) {
+	family, found := prometheus.families[familyName]
+	if !found {
+		family = &prometheusFamily{name: familyName, metricType: metricType}
+		prometheus.families[familyName] = family
+		prometheus.order = append(prometheus.order, family)
+	}
+
+	family.samples = append(family.samples, fmt.Sprintf("%s%s %v", sampleName, prometheusLabels(tags), value))
+}
+
+// addHistogram adds the cumulative bucket counts, sum and count of the timer to the histogram family with the name
+func (prometheus *prometheusWriter) addHistogram(familyName string, tags map[string]string, timer bucketedTimer) {
+	// The buckets are read first, so the +Inf bucket and count include the durations recorded since
+	buckets := timer.Buckets()
+	sum := timer.Sum()
+	count := timer.Count()
+
+	bucketTags := make(map[string]string, len(tags)+1)
+	for tag, value := range tags {
+		bucketTags[tag] = value
+	}
+
+	for index, bound := range TimerBuckets {
+		bucketTags["le"] = strconv.FormatFloat(bound.Seconds(), 'g', -1, 64)
+		prometheus.add(familyName, "histogram", familyName+"_bucket", bucketTags, float64(buckets[index]))
+	}
+	bucketTags["le"] = "+Inf"
+	prometheus.add(familyName, "histogram", familyName+"_bucket", bucketTags, float64(count))
+
+	prometheus.add(familyName, "histogram", familyName+"_sum", tags, sum.Seconds())
+	prometheus.add(familyName, "histogram", familyName+"_count", tags, float64(count))
+}
+
+func (prometheus *prometheusWriter) flush() error {
+	for _, family := range prometheus.order {
+		if _, err := fmt.Fprintf(prometheus.writer, "# TYPE %s %s\n", family.name, family.metricType); err != nil {
+			return err
+		}
+		for _, sample := range family.samples {
+			if _, err := fmt.Fprintln(prometheus.writer, sample); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// prometheusName replaces the characters not allowed in Prometheus metric and label names with underscores
+func prometheusName(name string) string {
+	var builder strings.Builder
+	for index, char := range name {
+		switch {
+		case char >= 'a' && char <= 'z', char >= 'A' && char <= 'Z', char == '_', char == ':':
+			builder.WriteRune(char)
+		case char >= '0' && char <= '9' && index > 0:
+			builder.WriteRune(char)
+		default:
+			builder.WriteRune('_')
+		}
+	}
+	return builder.String()
+}
+
+// prometheusLabels formats the tags as Prometheus labels ordered by name, escaping the values
+func prometheusLabels(tags map[string]string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	labels := make([]string, 0, len(names))
+	for _, name := range names {
+		labels = append(labels, fmt.Sprintf(`%s="%s"`, prometheusName(name), escaper.Replace(tags[name])))
+	}
+
+	return "{" + strings.Join(labels, ",") + "}"
+}
@@ -0,0 +1,91 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package telemetry
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWritePrometheus(t *testing.T) {
+	manager := NewMetricsManagerWithTags(map[string]string{InstanceTag: "1"})
+
+	counter := manager.NewCounter()
+	counter.Inc(3)
+	require.NoError(t, manager.Register("EventsExported", counter, map[string]string{"destination": "http \"one\""}))
+
+	gauge := manager.NewGauge()
+	gauge.Update(7)
+	require.NoError(t, manager.Register("Queue.Depth", gauge, nil))
+
+	timer := manager.NewTimer()
+	timer.Update(time.Second)
+	timer.Update(3 * time.Second)
+	require.NoError(t, manager.Register("ExportTime", timer, nil))
+
+	buffer := &bytes.Buffer{}
+	require.NoError(t, manager.WritePrometheus(buffer))
+	actual := buffer.String()
+
+	assert.Contains(t, actual,
+		"# TYPE EventsExported counter\nEventsExported{destination=\"http \\\"one\\\"\",instance=\"1\"} 3\n")
+	assert.Contains(t, actual, "# TYPE Queue_Depth gauge\nQueue_Depth{instance=\"1\"} 7\n")
+	assert.Contains(t, actual, "# TYPE ExportTime_seconds histogram\n"+
+		"ExportTime_seconds_bucket{instance=\"1\",le=\"0.005\"} 0\n"+
+		"ExportTime_seconds_bucket{instance=\"1\",le=\"0.01\"} 0\n"+
+		"ExportTime_seconds_bucket{instance=\"1\",le=\"0.025\"} 0\n"+
+		"ExportTime_seconds_bucket{instance=\"1\",le=\"0.05\"} 0\n"+
+		"ExportTime_seconds_bucket{instance=\"1\",le=\"0.1\"} 0\n"+
+		"ExportTime_seconds_bucket{instance=\"1\",le=\"0.25\"} 0\n"+
+		"ExportTime_seconds_bucket{instance=\"1\",le=\"0.5\"} 0\n"+
+		"ExportTime_seconds_bucket{instance=\"1\",le=\"1\"} 1\n"+
+		"ExportTime_seconds_bucket{instance=\"1\",le=\"2.5\"} 1\n"+
+		"ExportTime_seconds_bucket{instance=\"1\",le=\"5\"} 2\n"+
+		"ExportTime_seconds_bucket{instance=\"1\",le=\"10\"} 2\n"+
+		"ExportTime_seconds_bucket{instance=\"1\",le=\"+Inf\"} 2\n"+
+		"ExportTime_seconds_sum{instance=\"1\"} 4\n"+
+		"ExportTime_seconds_count{instance=\"1\"} 2\n")
+	assert.Contains(t, actual, "# TYPE ExportTime_seconds_min gauge\nExportTime_seconds_min{instance=\"1\"} 1\n")
+	assert.Contains(t, actual, "# TYPE ExportTime_seconds_max gauge\nExportTime_seconds_max{instance=\"1\"} 3\n")
+}
+
+func TestWritePrometheusLabeled(t *testing.T) {
+	manager := NewMetricsManager()
+
+	filterErrors := manager.NewCounter()
+	filterErrors.Inc(2)
+	require.NoError(t, manager.RegisterLabeled("FunctionErrors", filterErrors, map[string]string{"function": "Filter"}))
+	require.NoError(t, manager.RegisterLabeled("FunctionErrors", manager.NewCounter(),
+		map[string]string{"function": "Export"}))
+
+	buffer := &bytes.Buffer{}
+	require.NoError(t, manager.WritePrometheus(buffer))
+
+	assert.Equal(t, "# TYPE FunctionErrors counter\n"+
+		"FunctionErrors{function=\"Export\"} 0\n"+
+		"FunctionErrors{function=\"Filter\"} 2\n", buffer.String())
+}
+
+func TestPrometheusName(t *testing.T) {
+	assert.Equal(t, "EventsExported", prometheusName("EventsExported"))
+	assert.Equal(t, "Queue_Depth_2", prometheusName("Queue.Depth-2"))
+	assert.Equal(t, "_2xx:count", prometheusName("2xx:count"))
+}
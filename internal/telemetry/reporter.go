@@ -0,0 +1,197 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/bootstrap/container"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
+
+	"github.com/google/uuid"
+)
+
+// SystemUsageMetricName is the name of the metric reporting the service's memory and cpu utilization
+const SystemUsageMetricName = "SystemUsage"
+
+// configCheckInterval is the longest the reporter waits before checking the configuration for a changed interval, so
+// changes to the interval, i.e. to publish more often while diagnosing an issue, are applied promptly
+const configCheckInterval = 10 * time.Second
+
+// PublishedMetric is a metric published to the EdgeX MessageBus
+type PublishedMetric struct {
+	MetricSnapshot `json:",inline"`
+	Service        string `json:"service"`
+	Timestamp      int64  `json:"timestamp"`
+}
+
+// Reporter periodically publishes the enabled metrics to the EdgeX MessageBus as configured in Writable.Telemetry
+type Reporter struct {
+	dic        *di.Container
+	serviceKey string
+}
+
+// NewReporter creates a new Reporter, which publishes the metrics of the service with the service key
+func NewReporter(dic *di.Container, serviceKey string) *Reporter {
+	return &Reporter{
+		dic:        dic,
+		serviceKey: serviceKey,
+	}
+}
+
+// Start publishes the enabled metrics every Writable.Telemetry.Interval until the context is done. Changes to the
+// interval are applied within configCheckInterval, while changes to the other settings are picked up by the next
+// publish.
+func (reporter *Reporter) Start(ctx context.Context, wg *sync.WaitGroup) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		lc := bootstrapContainer.LoggingClientFrom(reporter.dic.Get)
+		lastPublished := time.Now()
+
+		for {
+			select {
+			case <-ctx.Done():
+				lc.Info("Exiting telemetry publishing")
+				return
+			case <-time.After(nextWait(reporter.interval(), time.Since(lastPublished))):
+			}
+
+			if interval := reporter.interval(); interval > 0 && time.Since(lastPublished) >= interval {
+				reporter.Publish()
+				lastPublished = time.Now()
+			}
+		}
+	}()
+}
+
+// nextWait returns how long to wait before the next publish is due for the interval, given the time since the last
+// publish, which is at most configCheckInterval so changes to the interval are applied promptly
+func nextWait(interval time.Duration, sincePublished time.Duration) time.Duration {
+	if interval <= 0 {
+		return configCheckInterval
+	}
+
+	wait := interval - sincePublished
+	if wait < 0 {
+		return 0
+	}
+	if wait > configCheckInterval {
+		return configCheckInterval
+	}
+	return wait
+}
+
+// Publish publishes the metrics enabled in Writable.Telemetry.Metrics. Nothing is published if the EdgeX
+// MessageBus isn't used.
+func (reporter *Reporter) Publish() {
+	lc := bootstrapContainer.LoggingClientFrom(reporter.dic.Get)
+	config := container.ConfigurationFrom(reporter.dic.Get)
+
+	client := container.MessagingClientFrom(reporter.dic.Get)
+	if client == nil {
+		lc.Debug("Telemetry not published since the EdgeX MessageBus is not used")
+		return
+	}
+
+	timestamp := time.Now().UnixNano()
+	for _, snapshot := range reporter.snapshots() {
+		if !config.Writable.Telemetry.Metrics[snapshot.Name] {
+			continue
+		}
+
+		payload, err := json.Marshal(PublishedMetric{
+			MetricSnapshot: snapshot,
+			Service:        reporter.serviceKey,
+			Timestamp:      timestamp,
+		})
+		if err != nil {
+			lc.Errorf("Unable to marshal %s metric for publishing: %s", snapshot.Name, err.Error())
+			continue
+		}
+
+		topic := strings.Join([]string{
+			strings.TrimSuffix(config.Writable.Telemetry.PublishTopicPrefix, "/"),
+			reporter.serviceKey,
+			snapshot.Name}, "/")
+
+		message := types.MessageEnvelope{
+			CorrelationID: uuid.NewString(),
+			Payload:       payload,
+			ContentType:   common.ContentTypeJSON,
+		}
+
+		if err := client.Publish(message, topic); err != nil {
+			lc.Errorf("Failed to publish %s metric to topic %s: %s", snapshot.Name, topic, err.Error())
+			continue
+		}
+
+		lc.Debugf("Published %s metric to topic %s", snapshot.Name, topic)
+	}
+}
+
+// snapshots returns the SDK's system usage metric followed by the custom metrics
+func (reporter *Reporter) snapshots() []MetricSnapshot {
+	usage := NewSystemUsage()
+	snapshots := []MetricSnapshot{
+		{
+			Name: SystemUsageMetricName,
+			Type: MetricTypeGauge,
+			Values: map[string]interface{}{
+				"memAlloc":       usage.Memory.Alloc,
+				"memFrees":       usage.Memory.Frees,
+				"memLiveObjects": usage.Memory.LiveObjects,
+				"memMallocs":     usage.Memory.Mallocs,
+				"memSys":         usage.Memory.Sys,
+				"memTotalAlloc":  usage.Memory.TotalAlloc,
+				"cpuBusyAvg":     usage.CpuBusyAvg,
+			},
+		},
+	}
+
+	if manager, ok := container.MetricsManagerFrom(reporter.dic.Get).(*MetricsManager); ok {
+		snapshots = append(snapshots, manager.Snapshot()...)
+	}
+
+	return snapshots
+}
+
+func (reporter *Reporter) interval() time.Duration {
+	config := container.ConfigurationFrom(reporter.dic.Get)
+	if len(strings.TrimSpace(config.Writable.Telemetry.Interval)) == 0 {
+		return 0
+	}
+
+	interval, err := time.ParseDuration(config.Writable.Telemetry.Interval)
+	if err != nil {
+		bootstrapContainer.LoggingClientFrom(reporter.dic.Get).Errorf(
+			"Telemetry Interval '%s' is invalid, metrics not published: %s",
+			config.Writable.Telemetry.Interval, err.Error())
+		return 0
+	}
+
+	return interval
+}
@@ -0,0 +1,142 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package telemetry
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/bootstrap/container"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/common"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-messaging/v2/messaging"
+	"github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// publishRecorder is a messaging.MessageClient that only records the messages published
+type publishRecorder struct {
+	messaging.MessageClient
+	topics   []string
+	messages []types.MessageEnvelope
+}
+
+func (recorder *publishRecorder) Publish(message types.MessageEnvelope, topic string) error {
+	recorder.topics = append(recorder.topics, topic)
+	recorder.messages = append(recorder.messages, message)
+	return nil
+}
+
+func TestReporterPublish(t *testing.T) {
+	config := &common.ConfigurationStruct{
+		Writable: common.WritableInfo{
+			Telemetry: common.TelemetryInfo{
+				Interval:           "30s",
+				PublishTopicPrefix: "edgex/telemetry/",
+				Metrics:            map[string]bool{"ExportCount": true, SystemUsageMetricName: false},
+			},
+		},
+	}
+
+	manager := NewMetricsManager()
+	counter := manager.NewCounter()
+	counter.Inc(3)
+	require.NoError(t, manager.Register("ExportCount", counter, nil))
+	require.NoError(t, manager.Register("BatchSize", manager.NewGauge(), nil))
+
+	dic := di.NewContainer(di.ServiceConstructorMap{
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return logger.NewMockClient()
+		},
+		container.ConfigurationName: func(get di.Get) interface{} {
+			return config
+		},
+		container.MetricsManagerName: func(get di.Get) interface{} {
+			return manager
+		},
+	})
+
+	reporter := NewReporter(dic, "app-test")
+
+	// Nothing published when the EdgeX MessageBus isn't used
+	reporter.Publish()
+
+	client := &publishRecorder{}
+	dic.Update(di.ServiceConstructorMap{
+		container.MessagingClientName: func(get di.Get) interface{} {
+			return client
+		},
+	})
+
+	reporter.Publish()
+	require.Equal(t, []string{"edgex/telemetry/app-test/ExportCount"}, client.topics)
+
+	published := PublishedMetric{}
+	require.NoError(t, json.Unmarshal(client.messages[0].Payload, &published))
+	assert.Equal(t, "ExportCount", published.Name)
+	assert.Equal(t, MetricTypeCounter, published.Type)
+	assert.Equal(t, "app-test", published.Service)
+	assert.Equal(t, float64(3), published.Values["count"])
+	assert.NotZero(t, published.Timestamp)
+}
+
+func TestReporterInterval(t *testing.T) {
+	config := &common.ConfigurationStruct{}
+	dic := di.NewContainer(di.ServiceConstructorMap{
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return logger.NewMockClient()
+		},
+		container.ConfigurationName: func(get di.Get) interface{} {
+			return config
+		},
+	})
+	reporter := NewReporter(dic, "app-test")
+
+	assert.Zero(t, reporter.interval())
+
+	config.Writable.Telemetry.Interval = "bogus"
+	assert.Zero(t, reporter.interval())
+
+	config.Writable.Telemetry.Interval = "15s"
+	assert.Equal(t, 15*time.Second, reporter.interval())
+}
+
+func TestReporterNextWait(t *testing.T) {
+	tests := []struct {
+		Name           string
+		Interval       time.Duration
+		SincePublished time.Duration
+		Expected       time.Duration
+	}{
+		{"Disabled", 0, time.Minute, configCheckInterval},
+		{"Due soon", 5 * time.Second, 2 * time.Second, 3 * time.Second},
+		{"Overdue after interval shortened", 5 * time.Second, time.Minute, 0},
+		{"Long interval", time.Hour, time.Second, configCheckInterval},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Expected, nextWait(test.Interval, test.SincePublished))
+		})
+	}
+}
@@ -0,0 +1,145 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package telemetry
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+)
+
+// Names of the system metrics registered with the MetricsManager
+const (
+	CpuBusyAvgMetricName        = "SystemCpuBusyAvg"
+	MemoryAllocMetricName       = "SystemMemoryAlloc"
+	MemoryTotalAllocMetricName  = "SystemMemoryTotalAlloc"
+	MemorySysMetricName         = "SystemMemorySys"
+	MemoryLiveObjectsMetricName = "SystemMemoryLiveObjects"
+	GoroutinesMetricName        = "SystemGoroutines"
+	GCCountMetricName           = "SystemGCCount"
+	GCPauseTotalMetricName      = "SystemGCPauseTotalNs"
+)
+
+// systemCollectInterval is how often the system metrics are collected
+const systemCollectInterval = 10 * time.Second
+
+// cpuUsageAvgBits is the latest CPU usage average, as float64 bits so it can be accessed atomically
+var cpuUsageAvgBits uint64
+
+func cpuUsageAvg() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&cpuUsageAvgBits))
+}
+
+// SystemMetrics periodically collects the service's CPU, memory, goroutine and garbage collection statistics into
+// gauges registered with the MetricsManager, so they are reported along with the custom metrics
+type SystemMetrics struct {
+	lc         logger.LoggingClient
+	cpuBusyAvg interfaces.Gauge
+	alloc      interfaces.Gauge
+	totalAlloc interfaces.Gauge
+	sys        interfaces.Gauge
+	liveObject interfaces.Gauge
+	goroutines interfaces.Gauge
+	gcCount    interfaces.Gauge
+	gcPause    interfaces.Gauge
+	lastCpu    CpuUsage
+}
+
+// NewSystemMetrics creates the system metrics and registers them with the MetricsManager
+func NewSystemMetrics(manager interfaces.MetricsManager, lc logger.LoggingClient) *SystemMetrics {
+	system := &SystemMetrics{
+		lc:         lc,
+		cpuBusyAvg: manager.NewGauge(),
+		alloc:      manager.NewGauge(),
+		totalAlloc: manager.NewGauge(),
+		sys:        manager.NewGauge(),
+		liveObject: manager.NewGauge(),
+		goroutines: manager.NewGauge(),
+		gcCount:    manager.NewGauge(),
+		gcPause:    manager.NewGauge(),
+	}
+
+	registrations := map[string]interface{}{
+		CpuBusyAvgMetricName:        system.cpuBusyAvg,
+		MemoryAllocMetricName:       system.alloc,
+		MemoryTotalAllocMetricName:  system.totalAlloc,
+		MemorySysMetricName:         system.sys,
+		MemoryLiveObjectsMetricName: system.liveObject,
+		GoroutinesMetricName:        system.goroutines,
+		GCCountMetricName:           system.gcCount,
+		GCPauseTotalMetricName:      system.gcPause,
+	}
+
+	for name, metric := range registrations {
+		if err := manager.Register(name, metric, nil); err != nil {
+			lc.Warnf("Unable to register %s metric: %s", name, err.Error())
+		}
+	}
+
+	return system
+}
+
+// Start collects the system metrics now and every 10 seconds until the context is done
+func (system *SystemMetrics) Start(ctx context.Context, wg *sync.WaitGroup) {
+	system.lastCpu = PollCpu()
+	system.Collect()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		system.lc.Info("Starting system metrics collection")
+
+		for {
+			select {
+			case <-ctx.Done():
+				system.lc.Info("Exiting system metrics collection")
+				return
+
+			case <-time.After(systemCollectInterval):
+				nextCpu := PollCpu()
+				atomic.StoreUint64(&cpuUsageAvgBits, math.Float64bits(AvgCpuUsage(system.lastCpu, nextCpu)))
+				system.lastCpu = nextCpu
+
+				system.Collect()
+			}
+		}
+	}()
+}
+
+// Collect updates the system metrics with the current statistics. The CPU usage is the average over the last
+// collection interval.
+func (system *SystemMetrics) Collect() {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	system.cpuBusyAvg.Update(int64(math.Round(cpuUsageAvg())))
+	system.alloc.Update(int64(memStats.Alloc))
+	system.totalAlloc.Update(int64(memStats.TotalAlloc))
+	system.sys.Update(int64(memStats.Sys))
+	system.liveObject.Update(int64(memStats.Mallocs - memStats.Frees))
+	system.goroutines.Update(int64(runtime.NumGoroutine()))
+	system.gcCount.Update(int64(memStats.NumGC))
+	system.gcPause.Update(int64(memStats.PauseTotalNs))
+}
@@ -0,0 +1,70 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package telemetry
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemMetrics(t *testing.T) {
+	manager := NewMetricsManager()
+	system := NewSystemMetrics(manager, logger.NewMockClient())
+
+	for _, name := range []string{
+		CpuBusyAvgMetricName,
+		MemoryAllocMetricName,
+		MemoryTotalAllocMetricName,
+		MemorySysMetricName,
+		MemoryLiveObjectsMetricName,
+		GoroutinesMetricName,
+		GCCountMetricName,
+		GCPauseTotalMetricName,
+	} {
+		assert.True(t, manager.IsRegistered(name), name)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+	system.Start(ctx, wg)
+
+	// The metrics are collected as soon as collection starts
+	values := make(map[string]interface{})
+	for _, snapshot := range manager.Snapshot() {
+		values[snapshot.Name] = snapshot.Values["value"]
+	}
+	assert.NotZero(t, values[MemoryAllocMetricName])
+	assert.NotZero(t, values[MemorySysMetricName])
+	assert.NotZero(t, values[GoroutinesMetricName])
+
+	cancel()
+	wg.Wait()
+}
+
+func TestSystemMetricsAlreadyRegistered(t *testing.T) {
+	manager := NewMetricsManager()
+	require.NoError(t, manager.Register(GoroutinesMetricName, manager.NewCounter(), nil))
+
+	// The metrics which can't be registered are still collected, just not reported
+	system := NewSystemMetrics(manager, logger.NewMockClient())
+	assert.NotPanics(t, system.Collect)
+}
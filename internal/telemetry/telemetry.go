@@ -15,12 +15,7 @@
 package telemetry
 
 import (
-	"context"
 	"runtime"
-	"sync"
-	"time"
-
-	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
 )
 
 // SystemUsage
@@ -46,9 +41,6 @@ type CpuUsage struct {
 	Total uint64 // reported sum total of all usage
 }
 
-var lastSample CpuUsage
-var usageAvg float64
-
 func NewSystemUsage() (s SystemUsage) {
 	// The micro-service is to be considered the System Of Record (SOR) in terms of accurate information.
 	// Fetch metrics for the metadata service.
@@ -67,26 +59,7 @@ func NewSystemUsage() (s SystemUsage) {
 	// Live objects = Mallocs - Frees
 	s.Memory.LiveObjects = s.Memory.Mallocs - s.Memory.Frees
 
-	s.CpuBusyAvg = usageAvg
+	s.CpuBusyAvg = cpuUsageAvg()
 
 	return s
 }
-
-func StartCpuUsageAverage(appWg *sync.WaitGroup, appCtx context.Context, logger logger.LoggingClient) {
-	defer appWg.Done()
-
-	logger.Info("Starting CPU Usage Average loop")
-
-	for {
-		select {
-		case <-appCtx.Done():
-			logger.Info("Exiting CPU Usage Average loop")
-			return
-
-		case <-time.After(time.Second * 10):
-			nextUsage := PollCpu()
-			usageAvg = AvgCpuUsage(lastSample, nextUsage)
-			lastSample = nextUsage
-		}
-	}
-}
@@ -0,0 +1,282 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/appfunction"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/bootstrap/container"
+	sdkCommon "github.com/edgexfoundry/app-functions-sdk-go/v2/internal/common"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/runtime"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap"
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/requests"
+	"github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultRate           = 100
+	defaultReadingCount   = 1
+	defaultReadingSize    = 16
+	defaultReportInterval = 10 * time.Second
+
+	// generateInterval is how often the events due since the last interval are generated
+	generateInterval = 10 * time.Millisecond
+)
+
+// Trigger implements Trigger to generate events at a fixed rate and report the throughput and latency percentiles of
+// the pipeline, in order to size hardware and compare pipeline configurations
+type Trigger struct {
+	dic      *di.Container
+	lc       logger.LoggingClient
+	runtime  *runtime.GolangRuntime
+	stats    *latencyStats
+	inFlight int64
+}
+
+// settings are the BenchmarkConfig settings with the defaults applied and durations parsed
+type settings struct {
+	rate           int
+	readingCount   int
+	readingSize    int
+	binary         bool
+	duration       time.Duration
+	reportInterval time.Duration
+}
+
+func NewTrigger(dic *di.Container, runtime *runtime.GolangRuntime) *Trigger {
+	return &Trigger{
+		dic:     dic,
+		runtime: runtime,
+		lc:      bootstrapContainer.LoggingClientFrom(dic.Get),
+		stats:   newLatencyStats(),
+	}
+}
+
+// Initialize initializes the Trigger and starts generating events
+func (trigger *Trigger) Initialize(appWg *sync.WaitGroup, appCtx context.Context, background <-chan interfaces.BackgroundMessage) (bootstrap.Deferred, error) {
+	lc := trigger.lc
+	config := container.ConfigurationFrom(trigger.dic.Get)
+
+	lc.Info("Initializing Benchmark Trigger")
+
+	if background != nil {
+		return nil, errors.New("background publishing not supported for services using Benchmark trigger")
+	}
+
+	benchmarkSettings, err := newSettings(config.Trigger.Benchmark)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, contentType, err := generatePayload(benchmarkSettings)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate Benchmark Trigger event: %s", err.Error())
+	}
+
+	lc.Infof("Benchmark Trigger generating %d events/s with %d reading(s) of %d bytes (%d byte %s payload)",
+		benchmarkSettings.rate,
+		benchmarkSettings.readingCount,
+		benchmarkSettings.readingSize,
+		len(payload),
+		contentType)
+
+	appWg.Add(1)
+	go func() {
+		defer appWg.Done()
+		trigger.run(appCtx, benchmarkSettings, payload, contentType)
+	}()
+
+	return nil, nil
+}
+
+func newSettings(config sdkCommon.BenchmarkConfig) (settings, error) {
+	result := settings{
+		rate:           config.Rate,
+		readingCount:   config.ReadingCount,
+		readingSize:    config.ReadingSize,
+		binary:         config.Binary,
+		reportInterval: defaultReportInterval,
+	}
+
+	if result.rate < 0 || result.readingCount < 0 || result.readingSize < 0 {
+		return result, errors.New("Benchmark Rate, ReadingCount and ReadingSize can not be negative")
+	}
+	if result.rate == 0 {
+		result.rate = defaultRate
+	}
+	if result.readingCount == 0 {
+		result.readingCount = defaultReadingCount
+	}
+	if result.readingSize == 0 {
+		result.readingSize = defaultReadingSize
+	}
+
+	var err error
+	if len(strings.TrimSpace(config.Duration)) > 0 {
+		if result.duration, err = time.ParseDuration(config.Duration); err != nil {
+			return result, fmt.Errorf("invalid Benchmark Duration '%s': %s", config.Duration, err.Error())
+		}
+	}
+
+	if len(strings.TrimSpace(config.ReportInterval)) > 0 {
+		if result.reportInterval, err = time.ParseDuration(config.ReportInterval); err != nil {
+			return result, fmt.Errorf("invalid Benchmark ReportInterval '%s': %s", config.ReportInterval, err.Error())
+		}
+		if result.reportInterval <= 0 {
+			return result, errors.New("Benchmark ReportInterval must be greater than zero")
+		}
+	}
+
+	return result, nil
+}
+
+// generatePayload returns the AddEventRequest sent to the pipeline, encoded as CBOR when it has binary readings
+func generatePayload(benchmarkSettings settings) ([]byte, string, error) {
+	event := dtos.NewEvent("benchmark-profile", "benchmark-device", "benchmark")
+	for index := 0; index < benchmarkSettings.readingCount; index++ {
+		resourceName := fmt.Sprintf("reading-%d", index+1)
+		if benchmarkSettings.binary {
+			value := make([]byte, benchmarkSettings.readingSize)
+			_, _ = rand.Read(value)
+			event.AddBinaryReading(resourceName, value, "application/octet-stream")
+			continue
+		}
+
+		value := strings.Repeat("x", benchmarkSettings.readingSize)
+		if err := event.AddSimpleReading(resourceName, common.ValueTypeString, value); err != nil {
+			return nil, "", err
+		}
+	}
+
+	request := requests.NewAddEventRequest(event)
+	if benchmarkSettings.binary {
+		payload, err := cbor.Marshal(request)
+		return payload, common.ContentTypeCBOR, err
+	}
+
+	payload, err := json.Marshal(request)
+	return payload, common.ContentTypeJSON, err
+}
+
+// run generates the events at the rate, reporting every report interval, until the duration has passed or the
+// service is stopped, and then reports the results of the whole run
+func (trigger *Trigger) run(ctx context.Context, benchmarkSettings settings, payload []byte, contentType string) {
+	lc := trigger.lc
+
+	var end <-chan time.Time
+	if benchmarkSettings.duration > 0 {
+		timer := time.NewTimer(benchmarkSettings.duration)
+		defer timer.Stop()
+		end = timer.C
+	}
+
+	generateTicker := time.NewTicker(generateInterval)
+	defer generateTicker.Stop()
+	reportTicker := time.NewTicker(benchmarkSettings.reportInterval)
+	defer reportTicker.Stop()
+
+	// Events are skipped, rather than queued without limit, when the pipeline is more than a second behind
+	maxInFlight := int64(benchmarkSettings.rate)
+
+	start := time.Now()
+	lastReport := start
+	var generated, failed, skipped int64
+	executions := sync.WaitGroup{}
+
+	for {
+		select {
+		case now := <-generateTicker.C:
+			due := int64(now.Sub(start).Seconds()*float64(benchmarkSettings.rate)) - generated
+			for ; due > 0; due-- {
+				generated++
+				if atomic.LoadInt64(&trigger.inFlight) >= maxInFlight {
+					trigger.stats.skip()
+					continue
+				}
+
+				atomic.AddInt64(&trigger.inFlight, 1)
+				executions.Add(1)
+				go func() {
+					defer executions.Done()
+					defer atomic.AddInt64(&trigger.inFlight, -1)
+					trigger.process(payload, contentType)
+				}()
+			}
+
+		case now := <-reportTicker.C:
+			intervalReport := trigger.stats.intervalReport(now.Sub(lastReport))
+			failed += intervalReport.failed
+			skipped += intervalReport.skipped
+			lastReport = now
+			lc.Infof("Benchmark interval: %s", intervalReport)
+
+		case <-end:
+			lc.Info("Benchmark duration has passed, waiting for the pipeline executions in progress")
+			executions.Wait()
+			trigger.reportRun(start, failed, skipped)
+			return
+
+		case <-ctx.Done():
+			executions.Wait()
+			trigger.reportRun(start, failed, skipped)
+			return
+		}
+	}
+}
+
+func (trigger *Trigger) reportRun(start time.Time, failed int64, skipped int64) {
+	elapsed := time.Since(start)
+	lastInterval := trigger.stats.intervalReport(0)
+	runReport := trigger.stats.runReport(elapsed, failed+lastInterval.failed, skipped+lastInterval.skipped)
+	trigger.lc.Infof("Benchmark complete: %s", runReport)
+}
+
+// process executes the pipeline for a generated event and records its latency
+func (trigger *Trigger) process(payload []byte, contentType string) {
+	correlationID := uuid.New().String()
+	appContext := appfunction.NewContext(correlationID, trigger.dic, contentType)
+
+	// Each execution gets its own copy since decoded binary readings reference the payload
+	envelope := types.MessageEnvelope{
+		CorrelationID: correlationID,
+		ContentType:   contentType,
+		Payload:       append([]byte{}, payload...),
+	}
+
+	start := time.Now()
+	messageError := trigger.runtime.ProcessMessage(appContext, envelope)
+	trigger.stats.record(time.Since(start), messageError != nil)
+}
@@ -0,0 +1,170 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package benchmark
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/bootstrap/container"
+	sdkCommon "github.com/edgexfoundry/app-functions-sdk-go/v2/internal/common"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/runtime"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSettings(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Config      sdkCommon.BenchmarkConfig
+		Expected    settings
+		ExpectError bool
+	}{
+		{"Defaults", sdkCommon.BenchmarkConfig{}, settings{rate: 100, readingCount: 1, readingSize: 16, reportInterval: 10 * time.Second}, false},
+		{"All settings", sdkCommon.BenchmarkConfig{Rate: 500, ReadingCount: 3, ReadingSize: 1024, Binary: true, Duration: "1m", ReportInterval: "5s"},
+			settings{rate: 500, readingCount: 3, readingSize: 1024, binary: true, duration: time.Minute, reportInterval: 5 * time.Second}, false},
+		{"Negative rate", sdkCommon.BenchmarkConfig{Rate: -1}, settings{}, true},
+		{"Invalid duration", sdkCommon.BenchmarkConfig{Duration: "bogus"}, settings{}, true},
+		{"Invalid report interval", sdkCommon.BenchmarkConfig{ReportInterval: "bogus"}, settings{}, true},
+		{"Zero report interval", sdkCommon.BenchmarkConfig{ReportInterval: "0s"}, settings{}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			actual, err := newSettings(test.Config)
+			if test.ExpectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.Expected, actual)
+		})
+	}
+}
+
+func TestGeneratePayload(t *testing.T) {
+	payload, contentType, err := generatePayload(settings{readingCount: 2, readingSize: 8})
+	require.NoError(t, err)
+	assert.Equal(t, common.ContentTypeJSON, contentType)
+	assert.Contains(t, string(payload), `"value":"xxxxxxxx"`)
+
+	_, contentType, err = generatePayload(settings{readingCount: 1, readingSize: 1024, binary: true})
+	require.NoError(t, err)
+	assert.Equal(t, common.ContentTypeCBOR, contentType)
+}
+
+func TestPercentile(t *testing.T) {
+	var latencies []time.Duration
+	for i := 1; i <= 100; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+
+	assert.Equal(t, 50*time.Millisecond, percentile(latencies, 50))
+	assert.Equal(t, 99*time.Millisecond, percentile(latencies, 99))
+	assert.Equal(t, 100*time.Millisecond, percentile(latencies, 100))
+	assert.Equal(t, time.Duration(0), percentile(nil, 50))
+}
+
+func TestLatencyStats(t *testing.T) {
+	stats := newLatencyStats()
+	stats.record(time.Millisecond, false)
+	stats.record(2*time.Millisecond, true)
+	stats.skip()
+
+	intervalReport := stats.intervalReport(time.Second)
+	assert.Equal(t, int64(2), intervalReport.processed)
+	assert.Equal(t, int64(1), intervalReport.failed)
+	assert.Equal(t, int64(1), intervalReport.skipped)
+	assert.Equal(t, 2.0, intervalReport.throughput())
+
+	// A new interval is started while the run keeps its samples
+	assert.Equal(t, int64(0), stats.intervalReport(time.Second).processed)
+	runReport := stats.runReport(2*time.Second, 1, 1)
+	assert.Equal(t, int64(2), runReport.processed)
+	assert.Len(t, runReport.latencies, 2)
+
+	for i := 0; i < maxRunSamples*2; i++ {
+		stats.record(time.Millisecond, false)
+	}
+	assert.Len(t, stats.runReport(time.Second, 0, 0).latencies, maxRunSamples)
+}
+
+func TestTriggerGeneratesEvents(t *testing.T) {
+	config := &sdkCommon.ConfigurationStruct{
+		Trigger: sdkCommon.TriggerInfo{
+			Benchmark: sdkCommon.BenchmarkConfig{Rate: 200, Duration: "250ms", ReportInterval: "100ms"},
+		},
+	}
+
+	dic := di.NewContainer(di.ServiceConstructorMap{
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return logger.NewMockClient()
+		},
+		container.ConfigurationName: func(get di.Get) interface{} {
+			return config
+		},
+	})
+
+	var executions int64
+	transform := func(appContext interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		event, ok := data.(dtos.Event)
+		assert.True(t, ok)
+		assert.Equal(t, "benchmark-device", event.DeviceName)
+		atomic.AddInt64(&executions, 1)
+		return false, nil
+	}
+
+	goRuntime := &runtime.GolangRuntime{}
+	goRuntime.Initialize(dic)
+	goRuntime.SetTransforms([]interfaces.AppFunction{transform})
+
+	trigger := NewTrigger(dic, goRuntime)
+	wg := &sync.WaitGroup{}
+	_, err := trigger.Initialize(wg, context.Background(), nil)
+	require.NoError(t, err)
+
+	// The trigger stops generating once the duration has passed
+	wg.Wait()
+	assert.InDelta(t, 50, atomic.LoadInt64(&executions), 10)
+	assert.Equal(t, atomic.LoadInt64(&executions), trigger.stats.runReport(0, 0, 0).processed)
+}
+
+func TestTriggerBackgroundNotSupported(t *testing.T) {
+	dic := di.NewContainer(di.ServiceConstructorMap{
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return logger.NewMockClient()
+		},
+		container.ConfigurationName: func(get di.Get) interface{} {
+			return &sdkCommon.ConfigurationStruct{}
+		},
+	})
+
+	trigger := NewTrigger(dic, &runtime.GolangRuntime{})
+	_, err := trigger.Initialize(&sync.WaitGroup{}, context.Background(), make(chan interfaces.BackgroundMessage))
+	assert.Error(t, err)
+}
@@ -0,0 +1,153 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package benchmark
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxRunSamples is the number of latencies sampled over the whole run to estimate its percentiles, which bounds the
+// memory used by long runs
+const maxRunSamples = 10000
+
+// latencyStats collects the latencies of the pipeline executions for the current report interval and a uniform
+// sample of them for the whole run
+type latencyStats struct {
+	mutex     sync.Mutex
+	interval  []time.Duration
+	failed    int64
+	skipped   int64
+	samples   []time.Duration
+	processed int64
+	random    *rand.Rand
+}
+
+func newLatencyStats() *latencyStats {
+	return &latencyStats{random: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+// record records the latency of a pipeline execution, which failed when failed is true
+func (stats *latencyStats) record(latency time.Duration, failed bool) {
+	stats.mutex.Lock()
+	defer stats.mutex.Unlock()
+
+	stats.interval = append(stats.interval, latency)
+	if failed {
+		stats.failed++
+	}
+
+	// Reservoir sampling keeps every execution of the run equally likely to be in the sample
+	stats.processed++
+	if len(stats.samples) < maxRunSamples {
+		stats.samples = append(stats.samples, latency)
+	} else if index := stats.random.Int63n(stats.processed); index < maxRunSamples {
+		stats.samples[index] = latency
+	}
+}
+
+// skip records an event which wasn't generated since the pipeline is too far behind
+func (stats *latencyStats) skip() {
+	stats.mutex.Lock()
+	stats.skipped++
+	stats.mutex.Unlock()
+}
+
+// report is the throughput and latency of the pipeline executions over a period
+type report struct {
+	processed int64
+	failed    int64
+	skipped   int64
+	elapsed   time.Duration
+	latencies []time.Duration
+}
+
+// intervalReport returns the report of the executions since the last interval report and starts a new interval
+func (stats *latencyStats) intervalReport(elapsed time.Duration) report {
+	stats.mutex.Lock()
+	defer stats.mutex.Unlock()
+
+	result := report{
+		processed: int64(len(stats.interval)),
+		failed:    stats.failed,
+		skipped:   stats.skipped,
+		elapsed:   elapsed,
+		latencies: stats.interval,
+	}
+
+	stats.interval = nil
+	stats.failed = 0
+	stats.skipped = 0
+	return result
+}
+
+// runReport returns the report of the executions of the whole run, given the failed and skipped totals which are
+// accumulated from the interval reports
+func (stats *latencyStats) runReport(elapsed time.Duration, failed int64, skipped int64) report {
+	stats.mutex.Lock()
+	defer stats.mutex.Unlock()
+
+	return report{
+		processed: stats.processed,
+		failed:    failed,
+		skipped:   skipped,
+		elapsed:   elapsed,
+		latencies: append([]time.Duration{}, stats.samples...),
+	}
+}
+
+// throughput returns the executions per second
+func (r report) throughput() float64 {
+	if r.elapsed <= 0 {
+		return 0
+	}
+	return float64(r.processed) / r.elapsed.Seconds()
+}
+
+// percentile returns the latency which the percent of executions completed within, where percent is from 0 to 100
+func percentile(sorted []time.Duration, percent float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	index := int(float64(len(sorted))*percent/100+0.5) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+	return sorted[index]
+}
+
+func (r report) String() string {
+	sort.Slice(r.latencies, func(i, j int) bool { return r.latencies[i] < r.latencies[j] })
+
+	return fmt.Sprintf("%d events processed in %s (%.1f/s), %d failed, %d skipped, latency p50=%s p90=%s p99=%s max=%s",
+		r.processed,
+		r.elapsed.Round(time.Millisecond),
+		r.throughput(),
+		r.failed,
+		r.skipped,
+		percentile(r.latencies, 50),
+		percentile(r.latencies, 90),
+		percentile(r.latencies, 99),
+		percentile(r.latencies, 100))
+}
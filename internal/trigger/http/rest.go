@@ -17,12 +17,14 @@
 package http
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
 	"io"
 	"net/http"
+	"strings"
 	"sync"
 
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal"
@@ -37,6 +39,10 @@ import (
 	"github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
 )
 
+// maxPreallocatedBody is the largest request body, in bytes, the buffer it's read into is allocated for up front from
+// the Content-Length. Larger bodies are read into a buffer which grows as they're read.
+const maxPreallocatedBody = 4 * 1024 * 1024
+
 // Trigger implements Trigger to support Triggers
 type Trigger struct {
 	dic        *di.Container
@@ -74,7 +80,7 @@ func (trigger *Trigger) requestHandler(writer http.ResponseWriter, r *http.Reque
 
 	contentType := r.Header.Get(common.ContentType)
 
-	data, err := io.ReadAll(r.Body)
+	data, err := readBody(r)
 	if err != nil {
 		lc.Error("Error reading HTTP Body", "error", err)
 		writer.WriteHeader(http.StatusBadRequest)
@@ -87,8 +93,12 @@ func (trigger *Trigger) requestHandler(writer http.ResponseWriter, r *http.Reque
 	correlationID := r.Header.Get(common.CorrelationHeader)
 
 	appContext := appfunction.NewContext(correlationID, trigger.dic, contentType)
+	for name, values := range r.Header {
+		appContext.AddValue(interfaces.HTTPHEADERPREFIX+name, strings.Join(values, ","))
+	}
 
-	lc.Trace("Received message from http", common.CorrelationHeader, correlationID)
+	// Adds the correlation ID, pipeline, function and device fields to the messages logged
+	lc = appContext.FlowLoggingClient()
 	lc.Debug("Received message from http", common.ContentType, contentType)
 
 	envelope := types.MessageEnvelope{
@@ -116,8 +126,21 @@ func (trigger *Trigger) requestHandler(writer http.ResponseWriter, r *http.Reque
 	}
 
 	if appContext.ResponseData() != nil {
-		lc.Trace("Sent http response message", common.CorrelationHeader, correlationID)
+		lc.Debug("Sent http response message", "byte count", len(appContext.ResponseData()))
 	}
 
 	trigger.outputData = nil
 }
+
+// readBody reads the request body into a buffer allocated for the Content-Length, when known, so the body isn't
+// copied as the buffer grows while being read
+func readBody(request *http.Request) ([]byte, error) {
+	if request.ContentLength <= 0 || request.ContentLength > maxPreallocatedBody {
+		return io.ReadAll(request.Body)
+	}
+
+	// ReadFrom grows the buffer unless it has at least MinRead bytes free
+	buffer := bytes.NewBuffer(make([]byte, 0, request.ContentLength+bytes.MinRead))
+	_, err := buffer.ReadFrom(request.Body)
+	return buffer.Bytes(), err
+}
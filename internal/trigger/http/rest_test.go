@@ -18,13 +18,20 @@
 package http
 
 import (
-	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/runtime"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
 	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTriggerInitializeWitBackgroundChannel(t *testing.T) {
@@ -42,3 +49,51 @@ func TestTriggerInitializeWitBackgroundChannel(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Equal(t, "background publishing not supported for services using HTTP trigger", err.Error())
 }
+
+func TestRequestHandlerAddsHeadersToContext(t *testing.T) {
+	dic := di.NewContainer(di.ServiceConstructorMap{
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return logger.NewMockClient()
+		},
+	})
+
+	var tenant string
+	var found bool
+	transform := func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		tenant, found = ctx.GetValue(interfaces.HTTPHEADERPREFIX + "X-Tenant")
+		return false, nil
+	}
+
+	target := &runtime.GolangRuntime{TargetType: &[]byte{}}
+	target.Initialize(dic)
+	target.SetTransforms([]interfaces.AppFunction{transform})
+	trigger := NewTrigger(dic, target, nil)
+
+	request := httptest.NewRequest(http.MethodPost, internal.ApiTriggerRoute, strings.NewReader("data"))
+	request.Header.Add("X-Tenant", "tenant1")
+	request.Header.Add("X-Tenant", "tenant2")
+	recorder := httptest.NewRecorder()
+
+	trigger.requestHandler(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.True(t, found)
+	assert.Equal(t, "tenant1,tenant2", tenant)
+}
+
+func TestReadBody(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPost, internal.ApiTriggerRoute, strings.NewReader("data"))
+	require.Equal(t, int64(4), request.ContentLength)
+
+	data, err := readBody(request)
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+
+	// Read as it arrives when the Content-Length isn't known
+	request = httptest.NewRequest(http.MethodPost, internal.ApiTriggerRoute, strings.NewReader("data"))
+	request.ContentLength = -1
+
+	data, err = readBody(request)
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+}
@@ -0,0 +1,163 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package messagebus
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"sync"
+
+	"github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
+)
+
+const (
+	// ContentEncodingGzip is the Compression and content encoding of payloads compressed with gzip
+	ContentEncodingGzip = "gzip"
+
+	// contentEncodingParameter is the content type parameter flagging the encoding of the payload, since the
+	// envelope has no field for it, i.e. 'application/json; content-encoding=gzip'
+	contentEncodingParameter = "content-encoding"
+
+	// minCompressedSize is the size of the smallest payload compressed, since compressing smaller payloads doesn't
+	// reduce them enough to be worth it
+	minCompressedSize = 256
+
+	// defaultMaxDecompressedSize is the size payloads are decompressed up to when Service.MaxRequestSize isn't
+	// specified, so a small compressed payload can't exhaust memory once decompressed
+	defaultMaxDecompressedSize = 32 * 1024 * 1024
+)
+
+// gzipWriters and gzipReaders pool the gzip compressors and decompressors, which allocate large buffers and tables
+// when created, so compressing or decompressing each payload only allocates the payload
+var (
+	gzipWriters = sync.Pool{
+		New: func() interface{} {
+			return gzip.NewWriter(nil)
+		},
+	}
+	gzipReaders sync.Pool
+)
+
+// maxDecompressedSize returns the size payloads are decompressed up to given Service.MaxRequestSize in KB, which
+// limits the payloads received from the MessageBus once decompressed like it limits the HTTP request bodies
+func maxDecompressedSize(maxRequestSize int64) int64 {
+	if maxRequestSize <= 0 {
+		return defaultMaxDecompressedSize
+	}
+	return maxRequestSize * 1024
+}
+
+// validateCompression returns an error if the Compression configured isn't supported
+func validateCompression(compression string) error {
+	switch strings.ToLower(strings.TrimSpace(compression)) {
+	case "", ContentEncodingGzip:
+		return nil
+	default:
+		return fmt.Errorf("unsupported MessageBus Compression '%s', must be '%s' or empty", compression, ContentEncodingGzip)
+	}
+}
+
+// compressEnvelope compresses the envelope's payload with the compression and flags the encoding in its content type.
+// Nothing is done when the compression is empty or the payload is too small to be worth compressing.
+func compressEnvelope(envelope *types.MessageEnvelope, compression string) error {
+	if strings.ToLower(strings.TrimSpace(compression)) != ContentEncodingGzip || len(envelope.Payload) < minCompressedSize {
+		return nil
+	}
+
+	// The content type may already have parameters, i.e. a charset, which are kept
+	mediaType, parameters, err := mime.ParseMediaType(envelope.ContentType)
+	if err != nil {
+		return fmt.Errorf("invalid content type '%s': %s", envelope.ContentType, err.Error())
+	}
+	parameters[contentEncodingParameter] = ContentEncodingGzip
+
+	buffer := bytes.NewBuffer(make([]byte, 0, len(envelope.Payload)/2))
+	writer := gzipWriters.Get().(*gzip.Writer)
+	defer gzipWriters.Put(writer)
+	writer.Reset(buffer)
+	if _, err := writer.Write(envelope.Payload); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	envelope.Payload = buffer.Bytes()
+	envelope.ContentType = mime.FormatMediaType(mediaType, parameters)
+	return nil
+}
+
+// decompressEnvelope decompresses the envelope's payload when its content type flags it as compressed, restoring
+// the content type of the uncompressed payload. Envelopes which aren't compressed are left as received. An error is
+// returned if the payload is larger than maxSize bytes once decompressed.
+func decompressEnvelope(envelope *types.MessageEnvelope, maxSize int64) error {
+	if !strings.Contains(envelope.ContentType, contentEncodingParameter) {
+		return nil
+	}
+
+	mediaType, parameters, err := mime.ParseMediaType(envelope.ContentType)
+	if err != nil {
+		return fmt.Errorf("invalid content type '%s': %s", envelope.ContentType, err.Error())
+	}
+
+	encoding, found := parameters[contentEncodingParameter]
+	if !found {
+		return nil
+	}
+	delete(parameters, contentEncodingParameter)
+
+	switch encoding {
+	case ContentEncodingGzip:
+		reader, err := newGzipReader(envelope.Payload)
+		if err != nil {
+			return fmt.Errorf("unable to decompress gzip payload: %s", err.Error())
+		}
+		defer gzipReaders.Put(reader)
+
+		// Reads one byte more than the maximum to detect payloads which exceed it
+		payload, err := io.ReadAll(io.LimitReader(reader, maxSize+1))
+		if err != nil {
+			return fmt.Errorf("unable to decompress gzip payload: %s", err.Error())
+		}
+		if int64(len(payload)) > maxSize {
+			return fmt.Errorf("decompressed gzip payload exceeds the maximum size of %d bytes", maxSize)
+		}
+
+		envelope.Payload = payload
+		envelope.ContentType = mime.FormatMediaType(mediaType, parameters)
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported content encoding '%s'", encoding)
+	}
+}
+
+// newGzipReader returns a pooled gzip decompressor reading the payload, which is put back in the pool once read
+func newGzipReader(payload []byte) (*gzip.Reader, error) {
+	if reader, ok := gzipReaders.Get().(*gzip.Reader); ok {
+		if err := reader.Reset(bytes.NewReader(payload)); err != nil {
+			gzipReaders.Put(reader)
+			return nil, err
+		}
+		return reader, nil
+	}
+	return gzip.NewReader(bytes.NewReader(payload))
+}
@@ -0,0 +1,132 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package messagebus
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCompression(t *testing.T) {
+	assert.NoError(t, validateCompression(""))
+	assert.NoError(t, validateCompression("GZIP"))
+	assert.Error(t, validateCompression("brotli"))
+}
+
+func TestCompressDecompressEnvelope(t *testing.T) {
+	payload := bytes.Repeat([]byte(`{"value":1}`), 100)
+	envelope := types.MessageEnvelope{Payload: payload, ContentType: common.ContentTypeJSON}
+
+	require.NoError(t, compressEnvelope(&envelope, ContentEncodingGzip))
+	assert.Less(t, len(envelope.Payload), len(payload))
+	assert.Equal(t, common.ContentTypeJSON+"; content-encoding=gzip", envelope.ContentType)
+
+	require.NoError(t, decompressEnvelope(&envelope, defaultMaxDecompressedSize))
+	assert.Equal(t, payload, envelope.Payload)
+	assert.Equal(t, common.ContentTypeJSON, envelope.ContentType)
+}
+
+func TestCompressEnvelopeSkipped(t *testing.T) {
+	large := bytes.Repeat([]byte{1}, 1000)
+	small := []byte(`{"value":1}`)
+
+	tests := []struct {
+		Name        string
+		Payload     []byte
+		Compression string
+	}{
+		{"No compression", large, ""},
+		{"Payload too small", small, ContentEncodingGzip},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			envelope := types.MessageEnvelope{Payload: test.Payload, ContentType: common.ContentTypeCBOR}
+			require.NoError(t, compressEnvelope(&envelope, test.Compression))
+			assert.Equal(t, test.Payload, envelope.Payload)
+			assert.Equal(t, common.ContentTypeCBOR, envelope.ContentType)
+		})
+	}
+}
+
+func TestDecompressEnvelopeErrors(t *testing.T) {
+	tests := []struct {
+		Name        string
+		ContentType string
+	}{
+		{"Unsupported encoding", common.ContentTypeJSON + "; content-encoding=brotli"},
+		{"Not gzip data", common.ContentTypeJSON + "; content-encoding=gzip"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			envelope := types.MessageEnvelope{Payload: []byte(`{"value":1}`), ContentType: test.ContentType}
+			assert.Error(t, decompressEnvelope(&envelope, defaultMaxDecompressedSize))
+		})
+	}
+
+	// Envelopes which aren't compressed are left as received
+	envelope := types.MessageEnvelope{Payload: []byte(`{"value":1}`), ContentType: common.ContentTypeJSON}
+	require.NoError(t, decompressEnvelope(&envelope, defaultMaxDecompressedSize))
+	assert.Equal(t, []byte(`{"value":1}`), envelope.Payload)
+
+	// The pooled decompressors still work after failing to decompress
+	payload := bytes.Repeat([]byte(`{"value":1}`), 100)
+	envelope = types.MessageEnvelope{Payload: payload, ContentType: common.ContentTypeJSON}
+	require.NoError(t, compressEnvelope(&envelope, ContentEncodingGzip))
+	require.NoError(t, decompressEnvelope(&envelope, defaultMaxDecompressedSize))
+	assert.Equal(t, payload, envelope.Payload)
+}
+
+func TestDecompressEnvelopeMaxSize(t *testing.T) {
+	payload := bytes.Repeat([]byte(`{"value":1}`), 100)
+	envelope := types.MessageEnvelope{Payload: payload, ContentType: common.ContentTypeJSON}
+	require.NoError(t, compressEnvelope(&envelope, ContentEncodingGzip))
+
+	exact := envelope
+	require.NoError(t, decompressEnvelope(&exact, int64(len(payload))))
+	assert.Equal(t, payload, exact.Payload)
+
+	// Payloads larger than the maximum once decompressed are rejected
+	assert.Error(t, decompressEnvelope(&envelope, int64(len(payload)-1)))
+}
+
+func TestMaxDecompressedSize(t *testing.T) {
+	assert.Equal(t, int64(defaultMaxDecompressedSize), maxDecompressedSize(0))
+	assert.Equal(t, int64(2048), maxDecompressedSize(2))
+}
+
+func BenchmarkCompressDecompressEnvelope(b *testing.B) {
+	payload := bytes.Repeat([]byte(`{"value":1}`), 100)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(payload)))
+	for i := 0; i < b.N; i++ {
+		envelope := types.MessageEnvelope{Payload: payload, ContentType: common.ContentTypeJSON}
+		if err := compressEnvelope(&envelope, ContentEncodingGzip); err != nil {
+			b.Fatal(err)
+		}
+		if err := decompressEnvelope(&envelope, defaultMaxDecompressedSize); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
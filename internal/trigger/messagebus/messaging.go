@@ -18,6 +18,8 @@ package messagebus
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
@@ -63,14 +65,29 @@ func (trigger *Trigger) Initialize(appWg *sync.WaitGroup, appCtx context.Context
 
 	lc.Infof("Initializing Message Bus Trigger for '%s'", config.Trigger.EdgexMessageBus.Type)
 
+	if err := validateCompression(config.Trigger.EdgexMessageBus.PublishHost.Compression); err != nil {
+		return nil, err
+	}
+
 	clientConfig := trigger.createMessagingClientConfig(config.Trigger.EdgexMessageBus)
 
+	if err := validateRedisConfig(&clientConfig); err != nil {
+		return nil, err
+	}
+
+	if err := validateNATSConfig(&clientConfig); err != nil {
+		return nil, err
+	}
+
 	if err := trigger.setOptionalAuthData(&clientConfig, lc); err != nil {
 		return nil, err
 	}
 
 	trigger.client, err = messaging.NewMessageClient(clientConfig)
 	if err != nil {
+		if isNATS(clientConfig.Type) {
+			return nil, fmt.Errorf("unable to create NATS MessageBus client, which requires go-mod-messaging built with NATS support: %w", err)
+		}
 		return nil, err
 	}
 
@@ -122,7 +139,12 @@ func (trigger *Trigger) Initialize(appWg *sync.WaitGroup, appCtx context.Context
 					lc.Infof("Exiting waiting for MessageBus '%s' topic messages", triggerTopic.Topic)
 					return
 				case msgs := <-triggerTopic.Messages:
-					go trigger.processMessage(lc, triggerTopic, msgs)
+					// Waits while the pipeline workers are busy, so messages aren't received faster than processed
+					message := msgs
+					if !trigger.runtime.Enqueue(appCtx, message, func() { trigger.processMessage(triggerTopic, message) }) {
+						lc.Infof("Exiting waiting for MessageBus '%s' topic messages", triggerTopic.Topic)
+						return
+					}
 				}
 			}
 		}(topic)
@@ -163,6 +185,13 @@ func (trigger *Trigger) Initialize(appWg *sync.WaitGroup, appCtx context.Context
 		return nil, fmt.Errorf("failed to subscribe to topic(s) '%s': %s", subscribeTopics, err.Error())
 	}
 
+	// Makes the connected client available for publishing other than by the pipeline, i.e. telemetry
+	trigger.dic.Update(di.ServiceConstructorMap{
+		container.MessagingClientName: func(get di.Get) interface{} {
+			return trigger.client
+		},
+	})
+
 	deferred := func() {
 		lc.Info("Disconnecting from the message bus")
 		err := trigger.client.Disconnect()
@@ -173,13 +202,26 @@ func (trigger *Trigger) Initialize(appWg *sync.WaitGroup, appCtx context.Context
 	return deferred, nil
 }
 
-func (trigger *Trigger) processMessage(logger logger.LoggingClient, triggerTopic types.TopicChannel, message types.MessageEnvelope) {
-	logger.Debugf("Received message from MessageBus on topic '%s'. Content-Type=%s", triggerTopic.Topic, message.ContentType)
-	logger.Tracef("%s=%s", common.CorrelationHeader, message.CorrelationID)
+func (trigger *Trigger) processMessage(triggerTopic types.TopicChannel, message types.MessageEnvelope) {
+	// Compressed messages, i.e. from chained app services, are decompressed before the context is created so it has
+	// the content type of the uncompressed data
+	config := container.ConfigurationFrom(trigger.dic.Get)
+	decompressErr := decompressEnvelope(&message, maxDecompressedSize(int64(config.Service.MaxRequestSize)))
 
 	appContext := appfunction.NewContext(message.CorrelationID, trigger.dic, message.ContentType)
 
-	messageError := trigger.runtime.ProcessMessage(appContext, message)
+	// Adds the correlation ID, pipeline, function and device fields to the messages logged
+	lc := appContext.FlowLoggingClient()
+	if decompressErr != nil {
+		lc.Errorf("Unable to decompress message received on '%s' topic: %s", triggerTopic.Topic, decompressErr.Error())
+		return
+	}
+
+	lc.Debug("Received message from MessageBus",
+		"topic", triggerTopic.Topic,
+		common.ContentType, message.ContentType)
+
+	messageError := trigger.runtime.ProcessQueuedMessage(appContext, message)
 	if messageError != nil {
 		// ProcessMessage logs the error, so no need to log it here.
 		return
@@ -203,22 +245,24 @@ func (trigger *Trigger) processMessage(logger logger.LoggingClient, triggerTopic
 			ContentType:   contentType,
 		}
 
-		config := container.ConfigurationFrom(trigger.dic.Get)
+		if err := compressEnvelope(&outputEnvelope, config.Trigger.EdgexMessageBus.PublishHost.Compression); err != nil {
+			lc.Errorf("Unable to compress message, publishing it uncompressed: %s", err.Error())
+		}
+
 		publishTopic, err := appContext.ApplyValues(config.Trigger.EdgexMessageBus.PublishHost.PublishTopic)
 
 		if err != nil {
-			logger.Errorf("Unable to format output topic '%s': %s", config.Trigger.EdgexMessageBus.PublishHost.PublishTopic, err.Error())
+			lc.Errorf("Unable to format output topic '%s': %s", config.Trigger.EdgexMessageBus.PublishHost.PublishTopic, err.Error())
 			return
 		}
 
 		err = trigger.client.Publish(outputEnvelope, publishTopic)
 		if err != nil {
-			logger.Errorf("Failed to publish Message to bus, %v", err)
+			lc.Errorf("Failed to publish Message to bus, %v", err)
 			return
 		}
 
-		logger.Debugf("Published message to bus on '%s' topic", publishTopic)
-		logger.Tracef("%s=%s", common.CorrelationHeader, message.CorrelationID)
+		lc.Debug("Published message to bus", "topic", publishTopic)
 	}
 }
 
@@ -235,7 +279,13 @@ func (_ *Trigger) createMessagingClientConfig(localConfig sdkCommon.MessageBusCo
 			Protocol: localConfig.SubscribeHost.Protocol,
 		},
 		Type:     localConfig.Type,
-		Optional: localConfig.Optional,
+		Optional: make(map[string]string, len(localConfig.Optional)),
+	}
+
+	// Copied so the secrets added by setOptionalAuthData aren't added to the service's configuration, which is
+	// reported by the config endpoint
+	for key, value := range localConfig.Optional {
+		clientConfig.Optional[key] = value
 	}
 
 	return clientConfig
@@ -269,15 +319,27 @@ func (trigger *Trigger) setOptionalAuthData(messageBusConfig *types.MessageBusCo
 		messageBusConfig.Optional = map[string]string{}
 	}
 
-	// Since already validated, these are the only modes that can be set at this point.
+	// Since already validated, these are the only modes that can be set at this point. The same client both
+	// subscribes and publishes, so the credentials are used for both the SubscribeHost and PublishHost.
 	switch authMode {
 	case bootstrapMessaging.AuthModeUsernamePassword:
 		messageBusConfig.Optional[bootstrapMessaging.OptionsUsernameKey] = secretData.Username
 		messageBusConfig.Optional[bootstrapMessaging.OptionsPasswordKey] = secretData.Password
 	case bootstrapMessaging.AuthModeCert:
+		// Checked here so invalid secrets fail the trigger's initialization rather than each connection attempt
+		if _, err := tls.X509KeyPair(secretData.CertPemBlock, secretData.KeyPemBlock); err != nil {
+			return fmt.Errorf("Secret Data for secure message bus invalid: client certificate and key: %w", err)
+		}
 		messageBusConfig.Optional[bootstrapMessaging.OptionsCertPEMBlockKey] = string(secretData.CertPemBlock)
 		messageBusConfig.Optional[bootstrapMessaging.OptionsKeyPEMBlockKey] = string(secretData.KeyPemBlock)
-	case bootstrapMessaging.AuthModeCA:
+	}
+
+	// If a CA Cert exists in the secret then it is used for all modes, i.e. for TLS connections to brokers with
+	// certificates signed by a private CA when authenticating with a username and password
+	if len(secretData.CaPemBlock) > 0 {
+		if !x509.NewCertPool().AppendCertsFromPEM(secretData.CaPemBlock) {
+			return errors.New("Secret Data for secure message bus invalid: CA certificate is not a valid PEM block")
+		}
 		messageBusConfig.Optional[bootstrapMessaging.OptionsCaPEMBlockKey] = string(secretData.CaPemBlock)
 	}
 
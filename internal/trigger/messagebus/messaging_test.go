@@ -18,7 +18,14 @@ package messagebus
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"encoding/pem"
+	"math/big"
 	"sync"
 	"testing"
 	"time"
@@ -165,6 +172,79 @@ func TestInitializeSecure(t *testing.T) {
 	assert.Equal(t, 1, len(trigger.topics))
 	assert.Equal(t, "events", trigger.topics[0].Topic)
 	assert.NotNil(t, trigger.topics[0].Messages)
+	// The credentials are only given to the client, not added to the service's configuration
+	assert.NotContains(t, config.Trigger.EdgexMessageBus.Optional, bootstrapMessaging.OptionsPasswordKey)
+}
+
+func TestSetOptionalAuthData(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	caCert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+
+	tests := []struct {
+		Name          string
+		Secrets       map[string]string
+		ExpectedCA    string
+		ExpectedError bool
+	}{
+		{"Without CA", map[string]string{
+			bootstrapMessaging.SecretUsernameKey: "user",
+			bootstrapMessaging.SecretPasswordKey: "password",
+		}, "", false},
+		{"With CA", map[string]string{
+			bootstrapMessaging.SecretUsernameKey: "user",
+			bootstrapMessaging.SecretPasswordKey: "password",
+			bootstrapMessaging.AuthModeCA:        caCert,
+		}, caCert, false},
+		{"Invalid CA", map[string]string{
+			bootstrapMessaging.SecretUsernameKey: "user",
+			bootstrapMessaging.SecretPasswordKey: "password",
+			bootstrapMessaging.AuthModeCA:        "not a cert",
+		}, "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			mock := mocks.SecretProvider{}
+			mock.On("GetSecret", "mqtt-bus").Return(test.Secrets, nil)
+			dic.Update(di.ServiceConstructorMap{
+				bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+					return &mock
+				},
+			})
+
+			trigger := NewTrigger(dic, &runtime.GolangRuntime{})
+			clientConfig := trigger.createMessagingClientConfig(sdkCommon.MessageBusConfig{
+				Type: "mqtt",
+				Optional: map[string]string{
+					bootstrapMessaging.AuthModeKey:   bootstrapMessaging.AuthModeUsernamePassword,
+					bootstrapMessaging.SecretNameKey: "mqtt-bus",
+				},
+			})
+
+			err := trigger.setOptionalAuthData(&clientConfig, logger.NewMockClient())
+			if test.ExpectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "user", clientConfig.Optional[bootstrapMessaging.OptionsUsernameKey])
+			assert.Equal(t, "password", clientConfig.Optional[bootstrapMessaging.OptionsPasswordKey])
+			assert.Equal(t, test.ExpectedCA, clientConfig.Optional[bootstrapMessaging.OptionsCaPEMBlockKey])
+		})
+	}
 }
 
 func TestInitializeBadConfiguration(t *testing.T) {
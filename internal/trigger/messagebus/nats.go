@@ -0,0 +1,113 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package messagebus
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
+)
+
+const (
+	// TypeNATSCore is the MessageBus Type of the NATS implementation of the MessageBus
+	TypeNATSCore = "nats-core"
+	// TypeNATSJetStream is the MessageBus Type of the NATS JetStream implementation of the MessageBus, which persists
+	// the messages so they are redelivered until acknowledged
+	TypeNATSJetStream = "nats-jetstream"
+
+	// NATSDurableKey is the Optional property naming the durable JetStream consumer, so messages published while the
+	// service is stopped are delivered once it restarts
+	NATSDurableKey = "Durable"
+	// NATSAckWaitKey is the Optional property with the duration, i.e. '30s', JetStream waits for a message to be
+	// acknowledged before redelivering it
+	NATSAckWaitKey = "AckWait"
+
+	// natsProtocol is the Protocol of the NATS hosts, used when not specified
+	natsProtocol = "tcp"
+)
+
+// isNATS returns whether the MessageBus Type is one of the NATS implementations
+func isNATS(messageBusType string) bool {
+	switch strings.ToLower(strings.TrimSpace(messageBusType)) {
+	case TypeNATSCore, TypeNATSJetStream:
+		return true
+	default:
+		return false
+	}
+}
+
+// validateNATSConfig validates the client configuration of the NATS MessageBus, defaulting the unspecified
+// Protocols and PublishHost. Nothing is done for other MessageBus Types.
+//
+// The NATS client connects to a single server for both subscribing and publishing, so the PublishHost defaults to
+// the SubscribeHost. The JetStream Durable and AckWait Optional properties are only valid for 'nats-jetstream'.
+func validateNATSConfig(clientConfig *types.MessageBusConfig) error {
+	if !isNATS(clientConfig.Type) {
+		return nil
+	}
+
+	subscribeHost := &clientConfig.SubscribeHost
+	publishHost := &clientConfig.PublishHost
+
+	if len(subscribeHost.Host) == 0 || subscribeHost.Port <= 0 {
+		return fmt.Errorf("NATS MessageBus SubscribeHost must have a Host and Port, not '%s:%d'",
+			subscribeHost.Host, subscribeHost.Port)
+	}
+
+	if len(subscribeHost.Protocol) == 0 {
+		subscribeHost.Protocol = natsProtocol
+	}
+
+	if len(publishHost.Host) == 0 {
+		publishHost.Host = subscribeHost.Host
+		publishHost.Port = subscribeHost.Port
+		publishHost.Protocol = subscribeHost.Protocol
+	}
+	if len(publishHost.Protocol) == 0 {
+		publishHost.Protocol = natsProtocol
+	}
+
+	durable := strings.TrimSpace(clientConfig.Optional[NATSDurableKey])
+	ackWait := strings.TrimSpace(clientConfig.Optional[NATSAckWaitKey])
+
+	if !strings.EqualFold(strings.TrimSpace(clientConfig.Type), TypeNATSJetStream) {
+		if len(durable) > 0 || len(ackWait) > 0 {
+			return fmt.Errorf("NATS MessageBus %s and %s are only valid for Type '%s'",
+				NATSDurableKey, NATSAckWaitKey, TypeNATSJetStream)
+		}
+		return nil
+	}
+
+	// Durable names become part of the JetStream subjects, so can't contain the subject separator or wildcards
+	if strings.ContainsAny(durable, ".*> \t") {
+		return fmt.Errorf("NATS MessageBus %s '%s' can not contain '.', '*', '>' or whitespace", NATSDurableKey, durable)
+	}
+
+	if len(ackWait) > 0 {
+		duration, err := time.ParseDuration(ackWait)
+		if err != nil {
+			return fmt.Errorf("NATS MessageBus %s '%s' is not a valid duration: %s", NATSAckWaitKey, ackWait, err.Error())
+		}
+		if duration <= 0 {
+			return fmt.Errorf("NATS MessageBus %s '%s' must be greater than zero", NATSAckWaitKey, ackWait)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package messagebus
+
+import (
+	"testing"
+
+	sdkCommon "github.com/edgexfoundry/app-functions-sdk-go/v2/internal/common"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateNATSConfig(t *testing.T) {
+	subscribeHost := sdkCommon.SubscribeHostInfo{Host: "localhost", Port: 4222, SubscribeTopics: "edgex/events/#"}
+
+	tests := []struct {
+		Name          string
+		Type          string
+		SubscribeHost sdkCommon.SubscribeHostInfo
+		Optional      map[string]string
+		ExpectedError bool
+	}{
+		{"Core", TypeNATSCore, subscribeHost, nil, false},
+		{"JetStream", TypeNATSJetStream, subscribeHost, map[string]string{NATSDurableKey: "app-service", NATSAckWaitKey: "30s"}, false},
+		{"No SubscribeHost", TypeNATSCore, sdkCommon.SubscribeHostInfo{}, nil, true},
+		{"Durable with Core", TypeNATSCore, subscribeHost, map[string]string{NATSDurableKey: "app-service"}, true},
+		{"Invalid Durable", TypeNATSJetStream, subscribeHost, map[string]string{NATSDurableKey: "app.service"}, true},
+		{"Invalid AckWait", TypeNATSJetStream, subscribeHost, map[string]string{NATSAckWaitKey: "30"}, true},
+		{"Negative AckWait", TypeNATSJetStream, subscribeHost, map[string]string{NATSAckWaitKey: "-1s"}, true},
+		{"Not NATS", "mqtt", sdkCommon.SubscribeHostInfo{}, map[string]string{NATSAckWaitKey: "30"}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			trigger := Trigger{}
+			clientConfig := trigger.createMessagingClientConfig(sdkCommon.MessageBusConfig{
+				Type:          test.Type,
+				SubscribeHost: test.SubscribeHost,
+				PublishHost:   sdkCommon.PublishHostInfo{PublishTopic: "out"},
+				Optional:      test.Optional,
+			})
+
+			err := validateNATSConfig(&clientConfig)
+			if test.ExpectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			if isNATS(test.Type) {
+				assert.Equal(t, "localhost", clientConfig.PublishHost.Host)
+				assert.Equal(t, 4222, clientConfig.PublishHost.Port)
+				assert.Equal(t, "tcp", clientConfig.PublishHost.Protocol)
+			}
+		})
+	}
+}
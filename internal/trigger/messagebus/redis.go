@@ -0,0 +1,86 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package messagebus
+
+import (
+	"fmt"
+	"strings"
+
+	bootstrapMessaging "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/messaging"
+	"github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
+)
+
+const (
+	// TypeRedis is the MessageBus Type of the Redis Pub/Sub implementation of the MessageBus
+	TypeRedis = "redis"
+
+	// redisProtocol is the Protocol of the Redis hosts, used when not specified
+	redisProtocol = "redis"
+)
+
+// validateRedisConfig validates the client configuration of the Redis Pub/Sub MessageBus, defaulting the unspecified
+// Protocols and PublishHost. Nothing is done for other MessageBus Types.
+//
+// The Redis client connects to a single server for both subscribing and publishing, so a PublishHost other than the
+// SubscribeHost isn't supported. Redis only authenticates with a password, which is retrieved from the secret store
+// with AuthMode 'usernamepassword', or not at all with AuthMode 'none'.
+func validateRedisConfig(clientConfig *types.MessageBusConfig) error {
+	if !strings.EqualFold(strings.TrimSpace(clientConfig.Type), TypeRedis) {
+		return nil
+	}
+
+	subscribeHost := &clientConfig.SubscribeHost
+	publishHost := &clientConfig.PublishHost
+
+	if len(subscribeHost.Host) == 0 || subscribeHost.Port <= 0 {
+		return fmt.Errorf("redis MessageBus SubscribeHost must have a Host and Port, not '%s:%d'",
+			subscribeHost.Host, subscribeHost.Port)
+	}
+
+	if len(subscribeHost.Protocol) == 0 {
+		subscribeHost.Protocol = redisProtocol
+	}
+
+	if len(publishHost.Host) == 0 {
+		// Publishing, i.e. by background publishers, still uses the one connection
+		publishHost.Host = subscribeHost.Host
+		publishHost.Port = subscribeHost.Port
+	}
+	if len(publishHost.Protocol) == 0 {
+		publishHost.Protocol = redisProtocol
+	}
+
+	if publishHost.Host != subscribeHost.Host || publishHost.Port != subscribeHost.Port {
+		return fmt.Errorf("redis MessageBus PublishHost '%s:%d' must be the same as the SubscribeHost '%s:%d'",
+			publishHost.Host, publishHost.Port, subscribeHost.Host, subscribeHost.Port)
+	}
+
+	for _, protocol := range []string{subscribeHost.Protocol, publishHost.Protocol} {
+		if !strings.EqualFold(protocol, redisProtocol) {
+			return fmt.Errorf("redis MessageBus Protocol must be '%s', not '%s'", redisProtocol, protocol)
+		}
+	}
+
+	authMode := strings.ToLower(strings.TrimSpace(clientConfig.Optional[bootstrapMessaging.AuthModeKey]))
+	switch authMode {
+	case "", bootstrapMessaging.AuthModeNone, bootstrapMessaging.AuthModeUsernamePassword:
+		return nil
+	default:
+		return fmt.Errorf("redis MessageBus AuthMode must be '%s' or '%s', not '%s'",
+			bootstrapMessaging.AuthModeUsernamePassword, bootstrapMessaging.AuthModeNone, authMode)
+	}
+}
@@ -0,0 +1,74 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package messagebus
+
+import (
+	"testing"
+
+	sdkCommon "github.com/edgexfoundry/app-functions-sdk-go/v2/internal/common"
+
+	bootstrapMessaging "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRedisConfig(t *testing.T) {
+	subscribeHost := sdkCommon.SubscribeHostInfo{Host: "localhost", Port: 6379, SubscribeTopics: "edgex/events/#"}
+
+	tests := []struct {
+		Name          string
+		Type          string
+		SubscribeHost sdkCommon.SubscribeHostInfo
+		PublishHost   sdkCommon.PublishHostInfo
+		AuthMode      string
+		ExpectedError bool
+	}{
+		{"Defaults", TypeRedis, subscribeHost, sdkCommon.PublishHostInfo{PublishTopic: "out"}, "", false},
+		{"Same PublishHost", TypeRedis, subscribeHost, sdkCommon.PublishHostInfo{Host: "localhost", Port: 6379, Protocol: "redis"}, bootstrapMessaging.AuthModeUsernamePassword, false},
+		{"Other PublishHost", TypeRedis, subscribeHost, sdkCommon.PublishHostInfo{Host: "other", Port: 6379}, "", true},
+		{"No SubscribeHost", TypeRedis, sdkCommon.SubscribeHostInfo{}, sdkCommon.PublishHostInfo{}, "", true},
+		{"Bad Protocol", TypeRedis, sdkCommon.SubscribeHostInfo{Host: "localhost", Port: 6379, Protocol: "tcp"}, sdkCommon.PublishHostInfo{}, "", true},
+		{"Client Cert AuthMode", TypeRedis, subscribeHost, sdkCommon.PublishHostInfo{}, bootstrapMessaging.AuthModeCert, true},
+		{"Not Redis", "mqtt", sdkCommon.SubscribeHostInfo{}, sdkCommon.PublishHostInfo{Host: "other"}, bootstrapMessaging.AuthModeCert, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			trigger := Trigger{}
+			clientConfig := trigger.createMessagingClientConfig(sdkCommon.MessageBusConfig{
+				Type:          test.Type,
+				SubscribeHost: test.SubscribeHost,
+				PublishHost:   test.PublishHost,
+				Optional:      map[string]string{bootstrapMessaging.AuthModeKey: test.AuthMode},
+			})
+
+			err := validateRedisConfig(&clientConfig)
+			if test.ExpectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			if test.Type == TypeRedis {
+				assert.Equal(t, clientConfig.SubscribeHost.Host, clientConfig.PublishHost.Host)
+				assert.Equal(t, clientConfig.SubscribeHost.Port, clientConfig.PublishHost.Port)
+				assert.Equal(t, "redis", clientConfig.SubscribeHost.Protocol)
+				assert.Equal(t, "redis", clientConfig.PublishHost.Protocol)
+			}
+		})
+	}
+}
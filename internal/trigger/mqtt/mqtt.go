@@ -149,31 +149,42 @@ func (trigger *Trigger) onConnectHandler(mqttClient pahoMqtt.Client) {
 
 func (trigger *Trigger) messageHandler(client pahoMqtt.Client, message pahoMqtt.Message) {
 	// Convenience short cuts
-	lc := trigger.lc
 	config := container.ConfigurationFrom(trigger.dic.Get)
 	brokerConfig := config.Trigger.ExternalMqtt
 	topic := config.Trigger.ExternalMqtt.PublishTopic
 
 	data := message.Payload()
-	contentType := common.ContentTypeJSON
-	if data[0] != byte('{') && data[0] != byte('[') {
-		// If not JSON then assume it is CBOR
-		contentType = common.ContentTypeCBOR
+	// MQTT 3.1.1 messages have no content type, so it is configured or inferred from the payload
+	contentType := brokerConfig.ContentType
+	if len(contentType) == 0 {
+		contentType = common.ContentTypeJSON
+		if len(data) > 0 && data[0] != byte('{') && data[0] != byte('[') {
+			// If not JSON then assume it is CBOR
+			contentType = common.ContentTypeCBOR
+		}
 	}
 
 	correlationID := uuid.New().String()
 
 	appContext := appfunction.NewContext(correlationID, trigger.dic, contentType)
 
-	lc.Debugf("Received message from MQTT Trigger with %d bytes from topic '%s'. Content-Type=%s", len(data), message.Topic(), contentType)
-	lc.Tracef("%s=%s", common.CorrelationHeader, correlationID)
+	// Adds the correlation ID, pipeline, function and device fields to the messages logged
+	lc := appContext.FlowLoggingClient()
+	lc.Debug("Received message from MQTT Trigger",
+		"topic", message.Topic(),
+		"byte count", len(data),
+		common.ContentType, contentType)
 
 	envelope := types.MessageEnvelope{
 		CorrelationID: correlationID,
 		ContentType:   contentType,
 		Payload:       data,
+		ReceivedTopic: message.Topic(),
 	}
 
+	// Held while the pipeline is paused, which stops receiving from the broker rather than dropping its messages
+	trigger.runtime.WaitWhilePaused(envelope.ReceivedTopic)
+
 	messageError := trigger.runtime.ProcessMessage(appContext, envelope)
 	if messageError != nil {
 		// ProcessMessage logs the error, so no need to log it here.
@@ -191,8 +202,9 @@ func (trigger *Trigger) messageHandler(client pahoMqtt.Client, message pahoMqtt.
 		if token := client.Publish(formattedTopic, brokerConfig.QoS, brokerConfig.Retain, appContext.ResponseData()); token.Wait() && token.Error() != nil {
 			lc.Errorf("could not publish to topic '%s' for MQTT trigger: %s", topic, token.Error().Error())
 		} else {
-			lc.Trace("Sent MQTT Trigger response message", common.CorrelationHeader, correlationID)
-			lc.Debugf("Sent MQTT Trigger response message on topic '%s' with %d bytes", topic, len(appContext.ResponseData()))
+			lc.Debug("Sent MQTT Trigger response message",
+				"topic", formattedTopic,
+				"byte count", len(appContext.ResponseData()))
 		}
 	}
 }
@@ -0,0 +1,193 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webserver
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	commonDtos "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
+)
+
+// Types of authentication supported by HttpServer.Auth
+const (
+	AuthTypeNone   = "none"
+	AuthTypeAPIKey = "apikey"
+	AuthTypeJWT    = "jwt"
+
+	defaultAPIKeyHeader = "X-API-Key"
+	apiKeySecretKey     = "apikey"
+	jwtSecretKey        = "jwtkey"
+	headerAuthorization = "Authorization"
+	bearerPrefix        = "Bearer "
+)
+
+// authHandler wraps the handler, which handles all the routes including custom routes, to reject the requests
+// which aren't authenticated as configured by HttpServer.Auth. The ping and health probe routes are never
+// authenticated so probes keep working.
+func (webserver *WebServer) authHandler(next http.Handler) http.Handler {
+	authConfig := webserver.config.HttpServer.Auth
+	authType := strings.ToLower(strings.TrimSpace(authConfig.Type))
+
+	var authenticate func(request *http.Request, secretData map[string]string) error
+	switch authType {
+	case "", AuthTypeNone:
+		return next
+	case AuthTypeAPIKey:
+		header := authConfig.APIKeyHeader
+		if len(header) == 0 {
+			header = defaultAPIKeyHeader
+		}
+		authenticate = func(request *http.Request, secretData map[string]string) error {
+			return validateAPIKey(request.Header.Get(header), secretData[apiKeySecretKey])
+		}
+	case AuthTypeJWT:
+		authenticate = func(request *http.Request, secretData map[string]string) error {
+			token := request.Header.Get(headerAuthorization)
+			if !strings.HasPrefix(token, bearerPrefix) {
+				return errors.New("bearer token required")
+			}
+			return validateJWT(strings.TrimPrefix(token, bearerPrefix), secretData[jwtSecretKey], time.Now())
+		}
+	default:
+		webserver.lc.Errorf("HttpServer Auth Type '%s' is unknown, all requests are rejected", authConfig.Type)
+		authenticate = func(_ *http.Request, _ map[string]string) error {
+			return fmt.Errorf("auth type '%s' is unknown", authConfig.Type)
+		}
+	}
+
+	webserver.lc.Infof("Web Server requests require '%s' authentication", authType)
+
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if isProbeRoute(request.URL.Path) {
+			next.ServeHTTP(writer, request)
+			return
+		}
+
+		secretData, err := bootstrapContainer.SecretProviderFrom(webserver.dic.Get).GetSecret(authConfig.SecretName)
+		if err == nil {
+			err = authenticate(request, secretData)
+		}
+
+		if err != nil {
+			webserver.lc.Debugf("Request to %s not authenticated: %s", request.URL.Path, err.Error())
+			sendUnauthorized(writer)
+			return
+		}
+
+		next.ServeHTTP(writer, request)
+	})
+}
+
+func sendUnauthorized(writer http.ResponseWriter) {
+	response := commonDtos.NewBaseResponse("", "request not authenticated", http.StatusUnauthorized)
+	writer.Header().Set(common.ContentType, common.ContentTypeJSON)
+	writer.WriteHeader(http.StatusUnauthorized)
+	_ = json.NewEncoder(writer).Encode(response)
+}
+
+// validateAPIKey validates the API key from the request against the expected key from the secret store
+func validateAPIKey(apiKey string, expected string) error {
+	if len(expected) == 0 {
+		return errors.New("no API key in the secret store")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(apiKey), []byte(expected)) != 1 {
+		return errors.New("API key not valid")
+	}
+
+	return nil
+}
+
+// jwtHeader is the header of a JWT
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+}
+
+// jwtClaims are the registered JWT claims validated
+type jwtClaims struct {
+	ExpiresAt *int64 `json:"exp"`
+	NotBefore *int64 `json:"nbf"`
+}
+
+// validateJWT validates the JWT is signed with HS256 using the key and, if the claims are present, is not expired
+// and is already valid
+func validateJWT(token string, key string, now time.Time) error {
+	if len(key) == 0 {
+		return errors.New("no JWT key in the secret store")
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return errors.New("JWT is malformed")
+	}
+
+	header := jwtHeader{}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return err
+	}
+	if header.Algorithm != "HS256" {
+		return fmt.Errorf("JWT algorithm '%s' not supported", header.Algorithm)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("JWT signature is malformed: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return errors.New("JWT signature not valid")
+	}
+
+	claims := jwtClaims{}
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return err
+	}
+	if claims.ExpiresAt != nil && now.Unix() >= *claims.ExpiresAt {
+		return errors.New("JWT expired")
+	}
+	if claims.NotBefore != nil && now.Unix() < *claims.NotBefore {
+		return errors.New("JWT not valid yet")
+	}
+
+	return nil
+}
+
+func decodeJWTPart(part string, target interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return fmt.Errorf("JWT is malformed: %w", err)
+	}
+
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("JWT is malformed: %w", err)
+	}
+
+	return nil
+}
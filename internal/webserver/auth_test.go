@@ -0,0 +1,142 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webserver
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/bootstrap/container"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/common"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/interfaces/mocks"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	commonConstants "github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+const testJWTKey = "jwt-S3cr3t"
+
+func signJWT(header string, claims string, key string) string {
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(claims))
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestValidateJWT(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+
+	tests := []struct {
+		name        string
+		token       string
+		key         string
+		expectError bool
+	}{
+		{"Valid", signJWT(`{"alg":"HS256","typ":"JWT"}`, `{"exp":1600000100,"nbf":1599999900}`, testJWTKey), testJWTKey, false},
+		{"Valid no claims", signJWT(`{"alg":"HS256"}`, `{}`, testJWTKey), testJWTKey, false},
+		{"Expired", signJWT(`{"alg":"HS256"}`, `{"exp":1599999999}`, testJWTKey), testJWTKey, true},
+		{"Not valid yet", signJWT(`{"alg":"HS256"}`, `{"nbf":1600000100}`, testJWTKey), testJWTKey, true},
+		{"Wrong key", signJWT(`{"alg":"HS256"}`, `{}`, "other"), testJWTKey, true},
+		{"Unsupported algorithm", signJWT(`{"alg":"none"}`, `{}`, testJWTKey), testJWTKey, true},
+		{"Malformed", "not.a-jwt", testJWTKey, true},
+		{"No key", signJWT(`{"alg":"HS256"}`, `{}`, ""), "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateJWT(test.token, test.key, now)
+			if test.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAuthHandler(t *testing.T) {
+	mockProvider := &mocks.SecretProvider{}
+	mockProvider.On("GetSecret", "webauth").Return(map[string]string{"apikey": "api-S3cr3t", "jwtkey": testJWTKey}, nil)
+
+	config := &common.ConfigurationStruct{}
+	config.HttpServer.Auth.SecretName = "webauth"
+
+	testDic := di.NewContainer(di.ServiceConstructorMap{
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return logger.NewMockClient()
+		},
+		container.ConfigurationName: func(get di.Get) interface{} {
+			return config
+		},
+		bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+			return mockProvider
+		},
+	})
+
+	webserver := NewWebServer(testDic, mux.NewRouter())
+	next := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {})
+
+	tests := []struct {
+		name           string
+		authType       string
+		path           string
+		header         string
+		value          string
+		expectedStatus int
+	}{
+		{"None", "", internal.ApiTriggerRoute, "", "", http.StatusOK},
+		{"API key valid", AuthTypeAPIKey, internal.ApiTriggerRoute, defaultAPIKeyHeader, "api-S3cr3t", http.StatusOK},
+		{"API key not valid", AuthTypeAPIKey, internal.ApiTriggerRoute, defaultAPIKeyHeader, "bogus", http.StatusUnauthorized},
+		{"API key missing", AuthTypeAPIKey, "/custom", "", "", http.StatusUnauthorized},
+		{"Ping not authenticated", AuthTypeAPIKey, commonConstants.ApiPingRoute, "", "", http.StatusOK},
+		{"Health not authenticated", AuthTypeJWT, internal.ApiHealthReadyRoute, "", "", http.StatusOK},
+		{"JWT valid", AuthTypeJWT, internal.ApiAddSecretRoute, headerAuthorization,
+			bearerPrefix + signJWT(`{"alg":"HS256"}`, `{}`, testJWTKey), http.StatusOK},
+		{"JWT not valid", AuthTypeJWT, internal.ApiAddSecretRoute, headerAuthorization,
+			bearerPrefix + signJWT(`{"alg":"HS256"}`, `{}`, "other"), http.StatusUnauthorized},
+		{"JWT not bearer", AuthTypeJWT, internal.ApiAddSecretRoute, headerAuthorization, "Basic abc", http.StatusUnauthorized},
+		{"Unknown type", "bogus", internal.ApiTriggerRoute, "", "", http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config.HttpServer.Auth.Type = test.authType
+			handler := webserver.authHandler(next)
+
+			request := httptest.NewRequest(http.MethodPost, test.path, nil)
+			if len(test.header) > 0 {
+				request.Header.Set(test.header, test.value)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webserver
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/util"
+)
+
+const (
+	headerOrigin                        = "Origin"
+	headerVary                          = "Vary"
+	headerAccessControlRequestMethod    = "Access-Control-Request-Method"
+	headerAccessControlAllowOrigin      = "Access-Control-Allow-Origin"
+	headerAccessControlAllowMethods     = "Access-Control-Allow-Methods"
+	headerAccessControlAllowHeaders     = "Access-Control-Allow-Headers"
+	headerAccessControlExposeHeaders    = "Access-Control-Expose-Headers"
+	headerAccessControlAllowCredentials = "Access-Control-Allow-Credentials"
+	headerAccessControlMaxAge           = "Access-Control-Max-Age"
+
+	anyOrigin = "*"
+)
+
+// corsHandler wraps the handler, which handles all the routes including custom routes, to add the CORS headers to
+// responses for allowed origins and to respond to preflight requests, when CORS is enabled. The origins listed are
+// reflected and may include credentials, any other origin is allowed without credentials when '*' is listed.
+func (webserver *WebServer) corsHandler(next http.Handler) http.Handler {
+	corsConfig := webserver.config.HttpServer.CORS
+	if !corsConfig.EnableCORS {
+		return next
+	}
+
+	webserver.lc.Infof("CORS enabled for origins '%s'", corsConfig.AllowedOrigins)
+
+	allowedOrigins := util.DeleteEmptyAndTrim(strings.FieldsFunc(corsConfig.AllowedOrigins, util.SplitComma))
+	allowAnyOrigin := originListed(anyOrigin, allowedOrigins)
+	if allowAnyOrigin && corsConfig.AllowCredentials {
+		// Reflecting any origin with credentials would let any site make authenticated requests
+		webserver.lc.Warn("CORS AllowCredentials is not applied to the origins allowed by '*', only to those listed")
+	}
+
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		origin := request.Header.Get(headerOrigin)
+		listed := originListed(origin, allowedOrigins)
+		if len(origin) == 0 || (!listed && !allowAnyOrigin) {
+			next.ServeHTTP(writer, request)
+			return
+		}
+
+		header := writer.Header()
+		header.Add(headerVary, headerOrigin)
+		if listed {
+			header.Set(headerAccessControlAllowOrigin, origin)
+			if corsConfig.AllowCredentials {
+				header.Set(headerAccessControlAllowCredentials, "true")
+			}
+		} else {
+			header.Set(headerAccessControlAllowOrigin, anyOrigin)
+		}
+
+		preflight := request.Method == http.MethodOptions && len(request.Header.Get(headerAccessControlRequestMethod)) > 0
+		if !preflight {
+			if len(corsConfig.ExposeHeaders) > 0 {
+				header.Set(headerAccessControlExposeHeaders, corsConfig.ExposeHeaders)
+			}
+			next.ServeHTTP(writer, request)
+			return
+		}
+
+		if len(corsConfig.AllowedMethods) > 0 {
+			header.Set(headerAccessControlAllowMethods, corsConfig.AllowedMethods)
+		}
+		if len(corsConfig.AllowedHeaders) > 0 {
+			header.Set(headerAccessControlAllowHeaders, corsConfig.AllowedHeaders)
+		}
+		if corsConfig.MaxAge > 0 {
+			header.Set(headerAccessControlMaxAge, strconv.Itoa(corsConfig.MaxAge))
+		}
+		writer.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// originListed returns whether the origin is one of the allowed origins
+func originListed(origin string, allowedOrigins []string) bool {
+	for _, allowed := range allowedOrigins {
+		if strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/common"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorsHandler(t *testing.T) {
+	webserver := NewWebServer(dic, mux.NewRouter())
+	webserver.config = &common.ConfigurationStruct{
+		HttpServer: common.HttpConfig{
+			CORS: common.CORSConfig{
+				EnableCORS:       true,
+				AllowedOrigins:   "https://localhost:3000, https://dashboard",
+				AllowedMethods:   "GET, POST",
+				AllowedHeaders:   "Content-Type",
+				ExposeHeaders:    "X-Correlation-ID",
+				AllowCredentials: true,
+				MaxAge:           600,
+			},
+		},
+	}
+
+	handlerCalled := false
+	handler := webserver.corsHandler(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		handlerCalled = true
+	}))
+
+	tests := []struct {
+		name                string
+		method              string
+		origin              string
+		requestMethod       string
+		expectedAllowOrigin string
+		expectedHandled     bool
+		expectedStatus      int
+	}{
+		{"No origin", http.MethodGet, "", "", "", true, http.StatusOK},
+		{"Origin not allowed", http.MethodGet, "https://other", "", "", true, http.StatusOK},
+		{"Origin allowed", http.MethodGet, "https://dashboard", "", "https://dashboard", true, http.StatusOK},
+		{"Preflight", http.MethodOptions, "https://localhost:3000", http.MethodPost, "https://localhost:3000", false, http.StatusNoContent},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handlerCalled = false
+			request := httptest.NewRequest(test.method, "/api/v2/trigger", nil)
+			if len(test.origin) > 0 {
+				request.Header.Set(headerOrigin, test.origin)
+			}
+			if len(test.requestMethod) > 0 {
+				request.Header.Set(headerAccessControlRequestMethod, test.requestMethod)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedHandled, handlerCalled)
+			assert.Equal(t, test.expectedAllowOrigin, recorder.Header().Get(headerAccessControlAllowOrigin))
+
+			if test.method == http.MethodOptions {
+				assert.Equal(t, "GET, POST", recorder.Header().Get(headerAccessControlAllowMethods))
+				assert.Equal(t, "Content-Type", recorder.Header().Get(headerAccessControlAllowHeaders))
+				assert.Equal(t, "600", recorder.Header().Get(headerAccessControlMaxAge))
+				assert.Equal(t, "true", recorder.Header().Get(headerAccessControlAllowCredentials))
+			} else if len(test.expectedAllowOrigin) > 0 {
+				assert.Equal(t, "X-Correlation-ID", recorder.Header().Get(headerAccessControlExposeHeaders))
+			}
+		})
+	}
+
+	// Any origin is allowed without credentials, only the origins listed are reflected with credentials
+	webserver.config.HttpServer.CORS.AllowedOrigins = "*, https://dashboard"
+	handler = webserver.corsHandler(http.NotFoundHandler())
+
+	request := httptest.NewRequest(http.MethodGet, "/api/v2/trigger", nil)
+	request.Header.Set(headerOrigin, "https://other")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	assert.Equal(t, "*", recorder.Header().Get(headerAccessControlAllowOrigin))
+	assert.Empty(t, recorder.Header().Get(headerAccessControlAllowCredentials))
+
+	request = httptest.NewRequest(http.MethodGet, "/api/v2/trigger", nil)
+	request.Header.Set(headerOrigin, "https://dashboard")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	assert.Equal(t, "https://dashboard", recorder.Header().Get(headerAccessControlAllowOrigin))
+	assert.Equal(t, "true", recorder.Header().Get(headerAccessControlAllowCredentials))
+
+	// Disabled
+	webserver.config.HttpServer.CORS.EnableCORS = false
+	request = httptest.NewRequest(http.MethodGet, "/api/v2/trigger", nil)
+	request.Header.Set(headerOrigin, "https://dashboard")
+	recorder = httptest.NewRecorder()
+	webserver.corsHandler(http.NotFoundHandler()).ServeHTTP(recorder, request)
+	assert.Empty(t, recorder.Header().Get(headerAccessControlAllowOrigin))
+}
@@ -0,0 +1,63 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	commonDtos "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
+)
+
+// StartDraining starts rejecting new requests, other than for the ping and health probe routes, with
+// 503 Service Unavailable while the requests in progress complete, so clients retry elsewhere during restarts
+func (webserver *WebServer) StartDraining() {
+	if atomic.CompareAndSwapInt32(&webserver.draining, 0, 1) {
+		webserver.lc.Info("Web Server draining, new requests are rejected")
+	}
+}
+
+func (webserver *WebServer) isDraining() bool {
+	return atomic.LoadInt32(&webserver.draining) == 1
+}
+
+// drainHandler wraps the handler, which handles all the routes including custom routes, to reject the new requests
+// once the webserver is draining
+func (webserver *WebServer) drainHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if !webserver.isDraining() || isProbeRoute(request.URL.Path) {
+			next.ServeHTTP(writer, request)
+			return
+		}
+
+		response := commonDtos.NewBaseResponse("", "service is shutting down", http.StatusServiceUnavailable)
+		writer.Header().Set(common.ContentType, common.ContentTypeJSON)
+		writer.Header().Set("Connection", "close")
+		writer.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(writer).Encode(response)
+	})
+}
+
+// isProbeRoute returns whether the path is the ping or a health probe route, which must keep working regardless
+func isProbeRoute(path string) bool {
+	return path == common.ApiPingRoute || strings.HasPrefix(path, internal.ApiHealthRoute)
+}
@@ -0,0 +1,57 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrainHandler(t *testing.T) {
+	router := mux.NewRouter()
+	okHandler := func(writer http.ResponseWriter, _ *http.Request) { writer.WriteHeader(http.StatusOK) }
+	router.HandleFunc(internal.ApiTriggerRoute, okHandler)
+	router.HandleFunc(common.ApiPingRoute, okHandler)
+	router.HandleFunc(internal.ApiHealthLiveRoute, okHandler)
+
+	webserver := NewWebServer(dic, router)
+	handler := webserver.drainHandler(router)
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder
+	}
+
+	assert.Equal(t, http.StatusOK, serve(internal.ApiTriggerRoute).Code)
+
+	webserver.StartDraining()
+	webserver.StartDraining()
+
+	recorder := serve(internal.ApiTriggerRoute)
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, "close", recorder.Header().Get("Connection"))
+	assert.Equal(t, http.StatusOK, serve(common.ApiPingRoute).Code)
+	assert.Equal(t, http.StatusOK, serve(internal.ApiHealthLiveRoute).Code)
+}
@@ -0,0 +1,145 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webserver
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	headerAcceptEncoding  = "Accept-Encoding"
+	headerContentEncoding = "Content-Encoding"
+	headerContentLength   = "Content-Length"
+	headerContentType     = "Content-Type"
+	encodingGzip          = "gzip"
+)
+
+// gzipHandler wraps the handler, which handles all the routes including custom routes, to gzip compress the
+// responses to the requests which accept gzip encoding, when enabled
+func (webserver *WebServer) gzipHandler(next http.Handler) http.Handler {
+	compressionConfig := webserver.config.HttpServer.Compression
+	if !compressionConfig.EnableGzip {
+		return next
+	}
+
+	level := compressionConfig.Level
+	if level < gzip.BestSpeed || level > gzip.BestCompression {
+		if level != 0 {
+			webserver.lc.Warnf("HttpServer Compression Level %d is invalid, using default level", level)
+		}
+		level = gzip.DefaultCompression
+	}
+
+	webserver.lc.Info("Gzip compression of responses enabled")
+
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Add(headerVary, headerAcceptEncoding)
+		if request.Method == http.MethodHead || !acceptsGzip(request.Header.Get(headerAcceptEncoding)) {
+			next.ServeHTTP(writer, request)
+			return
+		}
+
+		gzipWriter := &gzipResponseWriter{ResponseWriter: writer, level: level}
+		defer gzipWriter.close()
+
+		next.ServeHTTP(gzipWriter, request)
+	})
+}
+
+// acceptsGzip returns whether the Accept-Encoding header value accepts the gzip encoding
+func acceptsGzip(acceptEncoding string) bool {
+	for _, encoding := range strings.Split(acceptEncoding, ",") {
+		parameters := strings.Split(encoding, ";")
+		name := strings.TrimSpace(parameters[0])
+		if !strings.EqualFold(name, encodingGzip) && name != "*" {
+			continue
+		}
+
+		// A quality value of zero means the encoding is not acceptable
+		for _, parameter := range parameters[1:] {
+			keyValue := strings.SplitN(parameter, "=", 2)
+			if len(keyValue) == 2 && strings.TrimSpace(keyValue[0]) == "q" {
+				if quality, err := strconv.ParseFloat(strings.TrimSpace(keyValue[1]), 64); err == nil && quality == 0 {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// gzipResponseWriter compresses the body of responses which may have one and aren't already encoded
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	level       int
+	gzipWriter  *gzip.Writer
+	wroteHeader bool
+	compress    bool
+}
+
+func (writer *gzipResponseWriter) WriteHeader(statusCode int) {
+	if writer.wroteHeader {
+		return
+	}
+	writer.wroteHeader = true
+
+	header := writer.Header()
+	if statusCode != http.StatusNoContent && statusCode != http.StatusNotModified &&
+		len(header.Get(headerContentEncoding)) == 0 {
+		writer.compress = true
+		header.Del(headerContentLength)
+		header.Set(headerContentEncoding, encodingGzip)
+	}
+
+	writer.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (writer *gzipResponseWriter) Write(data []byte) (int, error) {
+	if !writer.wroteHeader {
+		// The content type must be detected from the uncompressed data, rather than by the server
+		if len(writer.Header().Get(headerContentType)) == 0 {
+			writer.Header().Set(headerContentType, http.DetectContentType(data))
+		}
+		writer.WriteHeader(http.StatusOK)
+	}
+
+	if !writer.compress {
+		return writer.ResponseWriter.Write(data)
+	}
+
+	if writer.gzipWriter == nil {
+		// The level is validated when the handler is created, so this can't fail
+		writer.gzipWriter, _ = gzip.NewWriterLevel(writer.ResponseWriter, writer.level)
+	}
+	return writer.gzipWriter.Write(data)
+}
+
+// close flushes the compressed data remaining to the response. The gzip encoding is sent with the header, so an
+// empty gzip stream is written for responses which have no body, rather than the client failing to decompress it.
+func (writer *gzipResponseWriter) close() {
+	if writer.compress && writer.gzipWriter == nil {
+		writer.gzipWriter, _ = gzip.NewWriterLevel(writer.ResponseWriter, writer.level)
+	}
+
+	if writer.gzipWriter != nil {
+		_ = writer.gzipWriter.Close()
+	}
+}
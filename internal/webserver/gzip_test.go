@@ -0,0 +1,121 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webserver
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/common"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGzipHandler(t *testing.T) {
+	expectedBody := `{"readings": ["one", "two", "three"]}`
+
+	tests := []struct {
+		Name             string
+		EnableGzip       bool
+		AcceptEncoding   string
+		Path             string
+		ExpectCompressed bool
+	}{
+		{"Disabled", false, "gzip", "/data", false},
+		{"Accepted", true, "gzip, deflate", "/data", true},
+		{"Accepted any", true, "*", "/data", true},
+		{"Not accepted", true, "deflate", "/data", false},
+		{"Refused", true, "gzip;q=0, deflate", "/data", false},
+		{"No content", true, "gzip", "/empty", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			router := mux.NewRouter()
+			router.HandleFunc("/data", func(writer http.ResponseWriter, _ *http.Request) {
+				writer.Header().Set("Content-Type", "application/json")
+				_, _ = writer.Write([]byte(expectedBody))
+			})
+			router.HandleFunc("/empty", func(writer http.ResponseWriter, _ *http.Request) {
+				writer.WriteHeader(http.StatusNoContent)
+			})
+
+			webserver := NewWebServer(dic, router)
+			webserver.config = &common.ConfigurationStruct{
+				HttpServer: common.HttpConfig{
+					Compression: common.CompressionConfig{EnableGzip: test.EnableGzip},
+				},
+			}
+
+			request := httptest.NewRequest(http.MethodGet, test.Path, nil)
+			request.Header.Set(headerAcceptEncoding, test.AcceptEncoding)
+			recorder := httptest.NewRecorder()
+			webserver.gzipHandler(router).ServeHTTP(recorder, request)
+
+			if !test.ExpectCompressed {
+				assert.Empty(t, recorder.Header().Get(headerContentEncoding))
+				if test.Path == "/data" {
+					assert.Equal(t, expectedBody, recorder.Body.String())
+				}
+				return
+			}
+
+			assert.Equal(t, encodingGzip, recorder.Header().Get(headerContentEncoding))
+			assert.Equal(t, "application/json", recorder.Header().Get(headerContentType))
+			assert.Equal(t, headerAcceptEncoding, recorder.Header().Get(headerVary))
+
+			reader, err := gzip.NewReader(recorder.Body)
+			require.NoError(t, err)
+			actual, err := ioutil.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, expectedBody, string(actual))
+		})
+	}
+}
+
+func TestGzipHandlerNoBody(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/created", func(writer http.ResponseWriter, _ *http.Request) {
+		writer.WriteHeader(http.StatusCreated)
+	})
+
+	webserver := NewWebServer(dic, router)
+	webserver.config = &common.ConfigurationStruct{
+		HttpServer: common.HttpConfig{
+			Compression: common.CompressionConfig{EnableGzip: true},
+		},
+	}
+
+	request := httptest.NewRequest(http.MethodPost, "/created", nil)
+	request.Header.Set(headerAcceptEncoding, encodingGzip)
+	recorder := httptest.NewRecorder()
+	webserver.gzipHandler(router).ServeHTTP(recorder, request)
+
+	// The response is gzip encoded once the header is written, so an empty gzip stream is the body
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, encodingGzip, recorder.Header().Get(headerContentEncoding))
+	reader, err := gzip.NewReader(recorder.Body)
+	require.NoError(t, err)
+	actual, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Empty(t, actual)
+}
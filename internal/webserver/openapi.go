@@ -0,0 +1,145 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webserver
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+)
+
+const contentTypeYAML = "application/x-yaml"
+
+// swaggerUIAssetsURL is the URL of the exact version of the Swagger UI assets loaded when HttpServer
+// SwaggerUIAssetsURL doesn't specify vendored assets, so the assets loaded don't change with new releases
+const swaggerUIAssetsURL = "https://unpkg.com/swagger-ui-dist@3.52.5"
+
+// swaggerUIScript points the Swagger UI at the OpenAPI document route
+const swaggerUIScript = `
+    window.onload = function() {
+      window.ui = SwaggerUIBundle({url: "` + internal.ApiOpenAPIRoute + `", dom_id: "#swagger-ui"});
+    };
+  `
+
+// swaggerUIPage loads the Swagger UI from the assets URL, which is formatted into the page twice
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Application Service API</title>
+  <link rel="stylesheet" href="%[1]s/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="%[1]s/swagger-ui-bundle.js"></script>
+  <script>%[2]s</script>
+</body>
+</html>
+`
+
+// openAPIDocument is the OpenAPI document served for the application service's custom routes
+type openAPIDocument struct {
+	content     []byte
+	contentType string
+	enableUI    bool
+}
+
+// SetOpenAPIDocument serves the OpenAPI document, in JSON or YAML, describing the custom routes on the
+// ApiOpenAPIRoute and, when enabled, the Swagger UI for it on the ApiOpenAPIUIRoute. Setting the document again
+// replaces the document served.
+func (webserver *WebServer) SetOpenAPIDocument(document []byte, enableUI bool) error {
+	if len(document) == 0 {
+		return errors.New("OpenAPI document can not be empty")
+	}
+
+	contentType := contentTypeYAML
+	if json.Valid(document) {
+		contentType = common.ContentTypeJSON
+	}
+
+	webserver.mutex.Lock()
+	defer webserver.mutex.Unlock()
+
+	if webserver.openAPI == nil {
+		webserver.router.HandleFunc(internal.ApiOpenAPIRoute, webserver.serveOpenAPIDocument).Methods(http.MethodGet)
+		webserver.router.HandleFunc(internal.ApiOpenAPIUIRoute, webserver.serveSwaggerUI).Methods(http.MethodGet)
+	}
+
+	webserver.openAPI = &openAPIDocument{
+		content:     append([]byte(nil), document...),
+		contentType: contentType,
+		enableUI:    enableUI,
+	}
+
+	return nil
+}
+
+func (webserver *WebServer) currentOpenAPIDocument() *openAPIDocument {
+	webserver.mutex.Lock()
+	defer webserver.mutex.Unlock()
+	return webserver.openAPI
+}
+
+func (webserver *WebServer) serveOpenAPIDocument(writer http.ResponseWriter, _ *http.Request) {
+	document := webserver.currentOpenAPIDocument()
+
+	writer.Header().Set(common.ContentType, document.contentType)
+	writer.WriteHeader(http.StatusOK)
+	_, _ = writer.Write(document.content)
+}
+
+func (webserver *WebServer) serveSwaggerUI(writer http.ResponseWriter, request *http.Request) {
+	if !webserver.currentOpenAPIDocument().enableUI {
+		http.NotFound(writer, request)
+		return
+	}
+
+	assetsURL := strings.TrimSuffix(strings.TrimSpace(webserver.config.HttpServer.SwaggerUIAssetsURL), "/")
+	if len(assetsURL) == 0 {
+		assetsURL = swaggerUIAssetsURL
+	}
+
+	writer.Header().Set(common.ContentType, "text/html; charset=utf-8")
+	writer.Header().Set("Content-Security-Policy", swaggerUIContentSecurityPolicy(assetsURL))
+	writer.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprintf(writer, swaggerUIPage, assetsURL, swaggerUIScript)
+}
+
+// swaggerUIContentSecurityPolicy only allows the Swagger UI page to load the scripts and styles under the assets URL,
+// the exact version when loaded from the CDN, and to run its own inline script
+func swaggerUIContentSecurityPolicy(assetsURL string) string {
+	assetsSource := assetsURL + "/"
+	if strings.HasPrefix(assetsURL, "/") {
+		// Vendored assets served by the service itself, i.e. with HttpServer StaticFiles
+		assetsSource = "'self'"
+	}
+
+	scriptHash := sha256.Sum256([]byte(swaggerUIScript))
+	return fmt.Sprintf(
+		"default-src 'none'; script-src %s 'sha256-%s'; style-src %s; img-src 'self' data:; connect-src 'self'",
+		assetsSource,
+		base64.StdEncoding.EncodeToString(scriptHash[:]),
+		assetsSource)
+}
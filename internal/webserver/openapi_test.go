@@ -0,0 +1,112 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webserver
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/common"
+)
+
+func TestSetOpenAPIDocument(t *testing.T) {
+	jsonDocument := []byte(`{"openapi": "3.0.0", "info": {"title": "test", "version": "1.0.0"}}`)
+	yamlDocument := []byte("openapi: 3.0.0\ninfo:\n  title: test\n  version: 1.0.0\n")
+
+	tests := []struct {
+		Name                string
+		Document            []byte
+		EnableUI            bool
+		ExpectedContentType string
+		ExpectedUIStatus    int
+	}{
+		{"JSON with UI", jsonDocument, true, "application/json", http.StatusOK},
+		{"YAML without UI", yamlDocument, false, contentTypeYAML, http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			router := mux.NewRouter()
+			webserver := NewWebServer(dic, router)
+
+			require.NoError(t, webserver.SetOpenAPIDocument(test.Document, test.EnableUI))
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, internal.ApiOpenAPIRoute, nil))
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, test.ExpectedContentType, recorder.Header().Get("Content-Type"))
+			assert.Equal(t, test.Document, recorder.Body.Bytes())
+
+			recorder = httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, internal.ApiOpenAPIUIRoute, nil))
+			assert.Equal(t, test.ExpectedUIStatus, recorder.Code)
+			if test.EnableUI {
+				assert.Contains(t, recorder.Body.String(), internal.ApiOpenAPIRoute)
+				assert.Contains(t, recorder.Body.String(), swaggerUIAssetsURL+"/swagger-ui-bundle.js")
+				assert.NotEmpty(t, recorder.Header().Get("Content-Security-Policy"))
+			}
+		})
+	}
+}
+
+func TestSetOpenAPIDocumentReplaced(t *testing.T) {
+	router := mux.NewRouter()
+	webserver := NewWebServer(dic, router)
+
+	assert.Error(t, webserver.SetOpenAPIDocument(nil, false))
+
+	require.NoError(t, webserver.SetOpenAPIDocument([]byte(`{"openapi": "3.0.0"}`), false))
+	require.NoError(t, webserver.SetOpenAPIDocument([]byte(`{"openapi": "3.0.1"}`), false))
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, internal.ApiOpenAPIRoute, nil))
+	assert.Equal(t, `{"openapi": "3.0.1"}`, recorder.Body.String())
+}
+
+func TestSwaggerUIVendoredAssets(t *testing.T) {
+	router := mux.NewRouter()
+	webserver := NewWebServer(dic, router)
+	webserver.config = &common.ConfigurationStruct{}
+	webserver.config.HttpServer.SwaggerUIAssetsURL = "/ui/swagger-ui/"
+
+	require.NoError(t, webserver.SetOpenAPIDocument([]byte(`{"openapi": "3.0.0"}`), true))
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, internal.ApiOpenAPIUIRoute, nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `<script src="/ui/swagger-ui/swagger-ui-bundle.js">`)
+	assert.NotContains(t, recorder.Body.String(), "unpkg.com")
+	assert.Contains(t, recorder.Header().Get("Content-Security-Policy"), "script-src 'self' 'sha256-")
+}
+
+func TestSwaggerUIContentSecurityPolicy(t *testing.T) {
+	scriptHash := sha256.Sum256([]byte(swaggerUIScript))
+	expectedHash := "'sha256-" + base64.StdEncoding.EncodeToString(scriptHash[:]) + "'"
+
+	policy := swaggerUIContentSecurityPolicy(swaggerUIAssetsURL)
+	assert.Contains(t, policy, "script-src "+swaggerUIAssetsURL+"/ "+expectedHash)
+	assert.Contains(t, policy, "style-src "+swaggerUIAssetsURL+"/;")
+	assert.Contains(t, policy, "default-src 'none'")
+}
@@ -0,0 +1,44 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webserver
+
+import (
+	"net/http"
+	"net/http/pprof"
+)
+
+// ProfilingRoute is the prefix of the net/http/pprof profiling routes
+const ProfilingRoute = "/debug/pprof/"
+
+// configureProfiling exposes the net/http/pprof profiling routes, when enabled by HttpServer.EnableProfiling.
+// The routes require the same authentication as the other routes. CPU profiles and traces must be requested with a
+// 'seconds' duration shorter than the Service RequestTimeout, otherwise the request times out.
+func (webserver *WebServer) configureProfiling() {
+	if !webserver.config.HttpServer.EnableProfiling {
+		return
+	}
+
+	router := webserver.router
+	router.HandleFunc(ProfilingRoute+"cmdline", pprof.Cmdline).Methods(http.MethodGet)
+	router.HandleFunc(ProfilingRoute+"profile", pprof.Profile).Methods(http.MethodGet)
+	router.HandleFunc(ProfilingRoute+"symbol", pprof.Symbol).Methods(http.MethodGet, http.MethodPost)
+	router.HandleFunc(ProfilingRoute+"trace", pprof.Trace).Methods(http.MethodGet)
+	// Index also serves the named profiles, i.e. heap and goroutine
+	router.PathPrefix(ProfilingRoute).HandlerFunc(pprof.Index).Methods(http.MethodGet)
+
+	webserver.lc.Warnf("Profiling routes are exposed on %s", ProfilingRoute)
+}
@@ -0,0 +1,58 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/common"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigureProfiling(t *testing.T) {
+	tests := []struct {
+		Name           string
+		Enabled        bool
+		Path           string
+		ExpectedStatus int
+	}{
+		{"Index", true, "/debug/pprof/", http.StatusOK},
+		{"Named profile", true, "/debug/pprof/goroutine?debug=1", http.StatusOK},
+		{"Command line", true, "/debug/pprof/cmdline", http.StatusOK},
+		{"Not enabled", false, "/debug/pprof/", http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			router := mux.NewRouter()
+			webserver := NewWebServer(dic, router)
+			webserver.config = &common.ConfigurationStruct{
+				HttpServer: common.HttpConfig{EnableProfiling: test.Enabled},
+			}
+
+			webserver.configureProfiling()
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, test.Path, nil))
+			assert.Equal(t, test.ExpectedStatus, recorder.Code)
+		})
+	}
+}
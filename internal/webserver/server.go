@@ -17,8 +17,13 @@
 package webserver
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal"
@@ -41,6 +46,10 @@ type WebServer struct {
 	lc         logger.LoggingClient
 	router     *mux.Router
 	controller *rest.Controller
+	server     *http.Server
+	openAPI    *openAPIDocument
+	draining   int32
+	mutex      sync.Mutex
 }
 
 // swagger:model
@@ -52,6 +61,7 @@ type Version struct {
 // NewWebServer returns a new instance of *WebServer
 func NewWebServer(dic *di.Container, router *mux.Router) *WebServer {
 	ws := &WebServer{
+		dic:        dic,
 		lc:         bootstrapContainer.LoggingClientFrom(dic.Get),
 		config:     container.ConfigurationFrom(dic.Get),
 		router:     router,
@@ -70,6 +80,11 @@ func (webserver *WebServer) AddRoute(routePath string, handler func(http.Respons
 	return nil
 }
 
+// AddMiddleware adds middleware which wraps the handlers of all the routes, in the order added
+func (webserver *WebServer) AddMiddleware(middleware ...mux.MiddlewareFunc) {
+	webserver.router.Use(middleware...)
+}
+
 // ConfigureStandardRoutes loads up the default routes
 func (webserver *WebServer) ConfigureStandardRoutes() {
 	router := webserver.router
@@ -80,8 +95,26 @@ func (webserver *WebServer) ConfigureStandardRoutes() {
 	router.HandleFunc(common.ApiPingRoute, controller.Ping).Methods(http.MethodGet)
 	router.HandleFunc(common.ApiVersionRoute, controller.Version).Methods(http.MethodGet)
 	router.HandleFunc(common.ApiMetricsRoute, controller.Metrics).Methods(http.MethodGet)
+	router.HandleFunc(internal.ApiCustomMetricsRoute, controller.CustomMetrics).Methods(http.MethodGet)
 	router.HandleFunc(common.ApiConfigRoute, controller.Config).Methods(http.MethodGet)
 	router.HandleFunc(internal.ApiAddSecretRoute, controller.AddSecret).Methods(http.MethodPost)
+	router.HandleFunc(internal.ApiStoreForwardRoute, controller.StoredItems).Methods(http.MethodGet)
+	router.HandleFunc(internal.ApiStoreForwardRoute, controller.PurgeStoredItems).Methods(http.MethodDelete)
+	router.HandleFunc(internal.ApiStoreForwardByIdRoute, controller.PurgeStoredItems).Methods(http.MethodDelete)
+	router.HandleFunc(internal.ApiStoreForwardRetryRoute, controller.RetryStoredItems).Methods(http.MethodPost)
+	router.HandleFunc(internal.ApiHealthReadyRoute, controller.Ready).Methods(http.MethodGet)
+	router.HandleFunc(internal.ApiHealthLiveRoute, controller.Live).Methods(http.MethodGet)
+	router.HandleFunc(internal.ApiPipelinesRoute, controller.Pipelines).Methods(http.MethodGet)
+	router.HandleFunc(internal.ApiPipelinesReloadRoute, controller.ReloadPipelines).Methods(http.MethodPost)
+	router.HandleFunc(internal.ApiPipelinePauseRoute, controller.PausePipeline).Methods(http.MethodPost)
+	router.HandleFunc(internal.ApiPipelineResumeRoute, controller.ResumePipeline).Methods(http.MethodPost)
+
+	if prometheusRoute := webserver.config.HttpServer.PrometheusRoute; len(prometheusRoute) > 0 {
+		router.HandleFunc(prometheusRoute, controller.PrometheusMetrics).Methods(http.MethodGet)
+	}
+
+	webserver.configureStaticFiles()
+	webserver.configureProfiling()
 
 	/// Trigger is not considered a standard route. Trigger route (when configured) is setup by the HTTP Trigger
 	//  in internal/trigger/http/rest.go
@@ -126,25 +159,161 @@ func (webserver *WebServer) listenAndServe(serviceTimeout time.Duration, errChan
 			return
 		}
 
-		httpsCert, ok := httpsSecretData[config.HttpServer.HTTPSCertName]
-		if !ok {
-			lc.Errorf("unable to find HTTPS Cert in Secret Data as %s. Check configuration", config.HttpServer.HTTPSCertName, err)
+		tlsConfig, err := serverTLSConfig(httpsSecretData, config.HttpServer)
+		if err != nil {
+			lc.Errorf("unable to setup HTTPS: %s", err.Error())
 			errChannel <- err
 			return
 		}
-
-		httpsKey, ok := httpsSecretData[config.HttpServer.HTTPSKeyName]
-		if !ok {
-			lc.Errorf("unable to find HTTPS Key in Secret Data as %s. Check configuration.", config.HttpServer.HTTPSKeyName, err)
-			errChannel <- err
-			return
+		if tlsConfig.ClientAuth == tls.RequireAndVerifyClientCert {
+			lc.Info("HTTPS Web Server requires client certificates signed by the configured CA")
 		}
 
+		server := webserver.newServer(addr, serviceTimeout)
+		server.TLSConfig = tlsConfig
+
 		lc.Infof("Starting HTTPS Web Server on address %s", addr)
 
-		errChannel <- http.ListenAndServeTLS(addr, httpsCert, httpsKey, http.TimeoutHandler(webserver.router, serviceTimeout, "Request timed out"))
+		// The certificate and key are in the TLS configuration, the file names are only used when it has none
+		webserver.sendServeError(server.ListenAndServeTLS("", ""), errChannel)
 	} else {
 		lc.Infof("Starting HTTP Web Server on address %s", addr)
-		errChannel <- http.ListenAndServe(addr, http.TimeoutHandler(webserver.router, serviceTimeout, "Request timed out"))
+
+		server := webserver.newServer(addr, serviceTimeout)
+		webserver.sendServeError(server.ListenAndServe(), errChannel)
+	}
+}
+
+func (webserver *WebServer) newServer(addr string, serviceTimeout time.Duration) *http.Server {
+	webserver.mutex.Lock()
+	defer webserver.mutex.Unlock()
+
+	handler := webserver.drainHandler(webserver.gzipHandler(
+		webserver.corsHandler(webserver.authHandler(webserver.requestSizeHandler(webserver.router)))))
+	httpConfig := webserver.config.HttpServer
+
+	webserver.server = &http.Server{
+		Addr:              addr,
+		Handler:           http.TimeoutHandler(handler, serviceTimeout, "Request timed out"),
+		ReadTimeout:       webserver.parseTimeout("ReadTimeout", httpConfig.ReadTimeout),
+		ReadHeaderTimeout: webserver.parseTimeout("ReadHeaderTimeout", httpConfig.ReadHeaderTimeout),
+		WriteTimeout:      webserver.parseTimeout("WriteTimeout", httpConfig.WriteTimeout),
+		IdleTimeout:       webserver.parseTimeout("IdleTimeout", httpConfig.IdleTimeout),
+		MaxHeaderBytes:    httpConfig.MaxHeaderBytes,
+	}
+	return webserver.server
+}
+
+// parseTimeout parses the HttpServer timeout setting, which is not limited (zero) when empty or invalid
+func (webserver *WebServer) parseTimeout(name string, value string) time.Duration {
+	if len(strings.TrimSpace(value)) == 0 {
+		return 0
 	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		webserver.lc.Warnf("HttpServer %s '%s' is invalid and not used: %s", name, value, err.Error())
+		return 0
+	}
+
+	return timeout
+}
+
+// requestSizeHandler wraps the handler to limit the size of the request bodies to Service.MaxRequestSize KB,
+// when specified, so reading an oversized body fails rather than exhausting memory
+func (webserver *WebServer) requestSizeHandler(next http.Handler) http.Handler {
+	maxRequestSize := int64(webserver.config.Service.MaxRequestSize) * 1024
+	if maxRequestSize <= 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.ContentLength > maxRequestSize {
+			http.Error(writer, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		request.Body = http.MaxBytesReader(writer, request.Body, maxRequestSize)
+		next.ServeHTTP(writer, request)
+	})
+}
+
+// serverTLSConfig returns the TLS configuration with the PEM encoded certificate and key in the secret data with the
+// configured names, which also requires client certificates when the client CA name is configured
+func serverTLSConfig(secretData map[string]string, config sdkCommon.HttpConfig) (*tls.Config, error) {
+	httpsCert, ok := secretData[config.HTTPSCertName]
+	if !ok {
+		return nil, fmt.Errorf("unable to find HTTPS Cert in Secret Data as %s. Check configuration", config.HTTPSCertName)
+	}
+
+	httpsKey, ok := secretData[config.HTTPSKeyName]
+	if !ok {
+		return nil, fmt.Errorf("unable to find HTTPS Key in Secret Data as %s. Check configuration", config.HTTPSKeyName)
+	}
+
+	certificate, err := tls.X509KeyPair([]byte(httpsCert), []byte(httpsKey))
+	if err != nil {
+		return nil, fmt.Errorf("HTTPS Cert %s and Key %s are not a valid PEM encoded key pair: %s",
+			config.HTTPSCertName, config.HTTPSKeyName, err.Error())
+	}
+
+	tlsConfig := &tls.Config{}
+	if len(config.ClientCAName) > 0 {
+		tlsConfig, err = clientAuthTLSConfig(secretData, config.ClientCAName)
+		if err != nil {
+			return nil, err
+		}
+	}
+	tlsConfig.Certificates = []tls.Certificate{certificate}
+
+	return tlsConfig, nil
+}
+
+// clientAuthTLSConfig returns the TLS configuration which requires clients to present a certificate signed by the
+// PEM encoded CA certificate in the secret data with the name
+func clientAuthTLSConfig(secretData map[string]string, caName string) (*tls.Config, error) {
+	caCert, ok := secretData[caName]
+	if !ok {
+		return nil, fmt.Errorf("unable to find client CA Cert in Secret Data as %s. Check configuration", caName)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(caCert)) {
+		return nil, fmt.Errorf("client CA Cert %s is not a valid PEM encoded certificate", caName)
+	}
+
+	return &tls.Config{
+		ClientCAs:  pool,
+		ClientAuth: tls.RequireAndVerifyClientCert,
+	}, nil
+}
+
+// sendServeError sends the error the server stopped with, unless it was stopped by StopWebServer
+func (webserver *WebServer) sendServeError(err error, errChannel chan error) {
+	if err == http.ErrServerClosed {
+		return
+	}
+	errChannel <- err
+}
+
+// StopWebServer gracefully stops the web server, rejecting new requests and waiting for the requests in progress to
+// complete until the context is done, when the connections remaining are closed
+func (webserver *WebServer) StopWebServer(ctx context.Context) error {
+	webserver.mutex.Lock()
+	server := webserver.server
+	webserver.mutex.Unlock()
+
+	if server == nil {
+		return nil
+	}
+
+	webserver.StartDraining()
+
+	webserver.lc.Info("Stopping Web Server")
+	if err := server.Shutdown(ctx); err != nil {
+		_ = server.Close()
+		return err
+	}
+
+	return nil
 }
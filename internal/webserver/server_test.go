@@ -17,10 +17,24 @@
 package webserver
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/interfaces/mocks"
@@ -89,3 +103,230 @@ func TestSetupTriggerRoute(t *testing.T) {
 	assert.Equal(t, "test", body)
 	assert.False(t, handlerFunctionNotCalled, "expected handler function to be called")
 }
+
+func TestStopWebServer(t *testing.T) {
+	config := &common.ConfigurationStruct{}
+	config.Service.Host = "localhost"
+	config.Service.Port = 0
+	config.Service.RequestTimeout = "5s"
+
+	testDic := di.NewContainer(di.ServiceConstructorMap{
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return logger.NewMockClient()
+		},
+		container.ConfigurationName: func(get di.Get) interface{} {
+			return config
+		},
+	})
+
+	webserver := NewWebServer(testDic, mux.NewRouter())
+
+	// Stopping before started is a no-op
+	require.NoError(t, webserver.StopWebServer(context.Background()))
+
+	errs := make(chan error, 1)
+	webserver.StartWebServer(errs)
+	require.Eventually(t, func() bool {
+		webserver.mutex.Lock()
+		defer webserver.mutex.Unlock()
+		return webserver.server != nil
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, webserver.StopWebServer(context.Background()))
+
+	// Stopping gracefully isn't reported as an error
+	select {
+	case err := <-errs:
+		assert.Fail(t, "unexpected error", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// newTestCertificate returns a PEM encoded self-signed certificate for localhost, and its key
+func newTestCertificate(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+}
+
+func TestClientAuthTLSConfig(t *testing.T) {
+	caCert, _ := newTestCertificate(t)
+
+	tlsConfig, err := clientAuthTLSConfig(map[string]string{"ca": caCert}, "ca")
+	require.NoError(t, err)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, tlsConfig.ClientAuth)
+	assert.NotNil(t, tlsConfig.ClientCAs)
+
+	_, err = clientAuthTLSConfig(map[string]string{"ca": caCert}, "bogus")
+	assert.Error(t, err)
+
+	_, err = clientAuthTLSConfig(map[string]string{"ca": "not a cert"}, "ca")
+	assert.Error(t, err)
+}
+
+func TestServerTLSConfig(t *testing.T) {
+	cert, key := newTestCertificate(t)
+	config := common.HttpConfig{HTTPSCertName: "cert", HTTPSKeyName: "key"}
+
+	tlsConfig, err := serverTLSConfig(map[string]string{"cert": cert, "key": key}, config)
+	require.NoError(t, err)
+	assert.Len(t, tlsConfig.Certificates, 1)
+	assert.Equal(t, tls.NoClientCert, tlsConfig.ClientAuth)
+
+	config.ClientCAName = "ca"
+	tlsConfig, err = serverTLSConfig(map[string]string{"cert": cert, "key": key, "ca": cert}, config)
+	require.NoError(t, err)
+	assert.Len(t, tlsConfig.Certificates, 1)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, tlsConfig.ClientAuth)
+
+	config.ClientCAName = ""
+	_, err = serverTLSConfig(map[string]string{"key": key}, config)
+	assert.Error(t, err, "missing cert")
+	_, err = serverTLSConfig(map[string]string{"cert": cert}, config)
+	assert.Error(t, err, "missing key")
+	_, err = serverTLSConfig(map[string]string{"cert": cert, "key": "not a key"}, config)
+	assert.Error(t, err, "invalid key")
+}
+
+func TestStartWebServerHTTPS(t *testing.T) {
+	cert, key := newTestCertificate(t)
+
+	// The port must be known to send the request
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	config := &common.ConfigurationStruct{}
+	config.Service.Host = "localhost"
+	config.Service.Port = port
+	config.Service.RequestTimeout = "5s"
+	config.HttpServer.Protocol = "https"
+	config.HttpServer.SecretName = "https"
+	config.HttpServer.HTTPSCertName = "cert"
+	config.HttpServer.HTTPSKeyName = "key"
+
+	secretProvider := &mocks.SecretProvider{}
+	secretProvider.On("GetSecret", "https").Return(map[string]string{"cert": cert, "key": key}, nil)
+
+	testDic := di.NewContainer(di.ServiceConstructorMap{
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return logger.NewMockClient()
+		},
+		container.ConfigurationName: func(get di.Get) interface{} {
+			return config
+		},
+		bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+			return secretProvider
+		},
+	})
+
+	router := mux.NewRouter()
+	router.HandleFunc("/test", func(writer http.ResponseWriter, _ *http.Request) {
+		_, _ = writer.Write([]byte("secure"))
+	})
+	webserver := NewWebServer(testDic, router)
+
+	errs := make(chan error, 1)
+	webserver.StartWebServer(errs)
+	defer func() {
+		_ = webserver.StopWebServer(context.Background())
+	}()
+
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM([]byte(cert)))
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}
+
+	var body []byte
+	require.Eventually(t, func() bool {
+		response, err := client.Get(fmt.Sprintf("https://localhost:%d/test", port))
+		if err != nil {
+			return false
+		}
+		defer response.Body.Close()
+		body, err = io.ReadAll(response.Body)
+		return err == nil && response.StatusCode == http.StatusOK
+	}, 5*time.Second, 50*time.Millisecond, "HTTPS request failed")
+	assert.Equal(t, "secure", string(body))
+	assert.Empty(t, errs, "web server failed")
+}
+
+func TestNewServerTimeouts(t *testing.T) {
+	config := &common.ConfigurationStruct{}
+	config.HttpServer.ReadTimeout = "30s"
+	config.HttpServer.ReadHeaderTimeout = "5s"
+	config.HttpServer.WriteTimeout = "bogus"
+	config.HttpServer.MaxHeaderBytes = 4096
+
+	testDic := di.NewContainer(di.ServiceConstructorMap{
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return logger.NewMockClient()
+		},
+		container.ConfigurationName: func(get di.Get) interface{} {
+			return config
+		},
+	})
+
+	server := NewWebServer(testDic, mux.NewRouter()).newServer("localhost:0", 5*time.Second)
+
+	assert.Equal(t, 30*time.Second, server.ReadTimeout)
+	assert.Equal(t, 5*time.Second, server.ReadHeaderTimeout)
+	assert.Equal(t, time.Duration(0), server.WriteTimeout)
+	assert.Equal(t, time.Duration(0), server.IdleTimeout)
+	assert.Equal(t, 4096, server.MaxHeaderBytes)
+}
+
+func TestRequestSizeHandler(t *testing.T) {
+	config := &common.ConfigurationStruct{}
+	config.Service.MaxRequestSize = 1
+
+	testDic := di.NewContainer(di.ServiceConstructorMap{
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return logger.NewMockClient()
+		},
+		container.ConfigurationName: func(get di.Get) interface{} {
+			return config
+		},
+	})
+
+	handler := NewWebServer(testDic, mux.NewRouter()).requestSizeHandler(
+		http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if _, err := io.ReadAll(request.Body); err != nil {
+				writer.WriteHeader(http.StatusBadRequest)
+			}
+		}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("a", 1024))))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("a", 1025))))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+
+	// Body larger than the Content-Length reported fails when read
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("a", 2048)))
+	request.ContentLength = -1
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
@@ -0,0 +1,90 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webserver
+
+import (
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+)
+
+const indexFile = "index.html"
+
+// configureStaticFiles serves the files in the HttpServer.StaticFiles Directory on the Route prefix, when configured
+func (webserver *WebServer) configureStaticFiles() {
+	staticConfig := webserver.config.HttpServer.StaticFiles
+	if len(staticConfig.Route) == 0 {
+		return
+	}
+
+	route := staticConfig.Route
+	if !strings.HasPrefix(route, "/") {
+		route = "/" + route
+	}
+	if !strings.HasSuffix(route, "/") {
+		route += "/"
+	}
+
+	if strings.HasPrefix(route, common.ApiBase) {
+		webserver.lc.Errorf("HttpServer StaticFiles Route '%s' can not be under %s, static files not served",
+			staticConfig.Route, common.ApiBase)
+		return
+	}
+
+	if info, err := os.Stat(staticConfig.Directory); err != nil || !info.IsDir() {
+		webserver.lc.Errorf("HttpServer StaticFiles Directory '%s' is not a directory, static files not served",
+			staticConfig.Directory)
+		return
+	}
+
+	fileServer := http.FileServer(indexOnlyFileSystem{http.Dir(staticConfig.Directory)})
+	webserver.router.PathPrefix(route).Handler(http.StripPrefix(route, fileServer)).Methods(http.MethodGet, http.MethodHead)
+
+	webserver.lc.Infof("Serving static files from '%s' on %s", staticConfig.Directory, route)
+}
+
+// indexOnlyFileSystem only opens the directories which contain an index file, so directory contents aren't listed
+type indexOnlyFileSystem struct {
+	fileSystem http.FileSystem
+}
+
+func (fs indexOnlyFileSystem) Open(name string) (http.File, error) {
+	file, err := fs.fileSystem.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	if info.IsDir() {
+		index, err := fs.fileSystem.Open(path.Join(name, indexFile))
+		if err != nil {
+			_ = file.Close()
+			return nil, os.ErrNotExist
+		}
+		_ = index.Close()
+	}
+
+	return file, nil
+}
@@ -0,0 +1,78 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webserver
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/common"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigureStaticFiles(t *testing.T) {
+	directory, err := ioutil.TempDir("", "static")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(directory) }()
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(directory, "index.html"), []byte("<html>index</html>"), 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(directory, "assets"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(directory, "assets", "app.js"), []byte("app"), 0644))
+
+	tests := []struct {
+		Name           string
+		Route          string
+		Path           string
+		ExpectedStatus int
+		ExpectedBody   string
+	}{
+		{"Index", "/ui/", "/ui/", http.StatusOK, "<html>index</html>"},
+		{"File", "ui", "/ui/assets/app.js", http.StatusOK, "app"},
+		{"Directory without index", "/ui/", "/ui/assets/", http.StatusNotFound, ""},
+		{"Missing file", "/ui/", "/ui/missing.js", http.StatusNotFound, ""},
+		{"API route not allowed", "/api/v2/ui/", "/api/v2/ui/", http.StatusNotFound, ""},
+		{"Not configured", "", "/ui/", http.StatusNotFound, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			router := mux.NewRouter()
+			webserver := NewWebServer(dic, router)
+			webserver.config = &common.ConfigurationStruct{
+				HttpServer: common.HttpConfig{
+					StaticFiles: common.StaticFilesConfig{Route: test.Route, Directory: directory},
+				},
+			}
+
+			webserver.configureStaticFiles()
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, test.Path, nil))
+			assert.Equal(t, test.ExpectedStatus, recorder.Code)
+			if len(test.ExpectedBody) > 0 {
+				assert.Equal(t, test.ExpectedBody, recorder.Body.String())
+			}
+		})
+	}
+}
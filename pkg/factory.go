@@ -30,23 +30,43 @@ import (
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
 )
 
-// NewAppService creates and returns a new ApplicationService with the default TargetType
-func NewAppService(serviceKey string) (interfaces.ApplicationService, bool) {
-	return NewAppServiceWithTargetType(serviceKey, nil)
-}
+// NewAppService creates and returns a new ApplicationService configured by the options, i.e. WithTargetType,
+// WithProfile or WithCustomConfig. The default TargetType is used if not specified.
+func NewAppService(serviceKey string, opts ...Option) (interfaces.ApplicationService, bool) {
+	options := newServiceOptions(opts)
+
+	service := app.NewService(serviceKey, options.targetType, interfaces.ProfileSuffixPlaceholder)
+	if options.args != nil {
+		service.SetCommandLineArgs(options.args)
+	}
+	if options.ctx != nil {
+		service.SetContext(options.ctx)
+	}
+	if options.customFlags != nil {
+		service.SetCustomFlags(options.customFlags)
+	}
 
-// NewAppServiceWithTargetType creates and returns a new ApplicationService with the specified TargetType
-func NewAppServiceWithTargetType(serviceKey string, targetType interface{}) (interfaces.ApplicationService, bool) {
-	service := app.NewService(serviceKey, targetType, interfaces.ProfileSuffixPlaceholder)
 	if err := service.Initialize(); err != nil {
 		err = fmt.Errorf("initialization failed: %s", err.Error())
 		service.LoggingClient().Errorf("App Service %s", err.Error())
 		return nil, false
 	}
 
+	if options.customConfig != nil {
+		if err := service.LoadCustomConfig(options.customConfig, options.customConfigSection); err != nil {
+			service.LoggingClient().Errorf("App Service failed to load custom configuration: %s", err.Error())
+			return nil, false
+		}
+	}
+
 	return service, true
 }
 
+// NewAppServiceWithTargetType creates and returns a new ApplicationService with the specified TargetType
+func NewAppServiceWithTargetType(serviceKey string, targetType interface{}) (interfaces.ApplicationService, bool) {
+	return NewAppService(serviceKey, WithTargetType(targetType))
+}
+
 // NewAppFuncContextForTest creates and returns a new AppFunctionContext to be used in unit tests for custom pipeline functions
 func NewAppFuncContextForTest(correlationID string, lc logger.LoggingClient) interfaces.AppFunctionContext {
 	dic := di.NewContainer(di.ServiceConstructorMap{
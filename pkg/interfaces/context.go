@@ -16,6 +16,7 @@
 package interfaces
 
 import (
+	"net/http"
 	"time"
 
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/interfaces"
@@ -29,6 +30,26 @@ const PROFILENAME = "profilename"
 const SOURCENAME = "sourcename"
 const RECEIVEDTOPIC = "receivedtopic"
 
+// INSTANCE is the instance of the service, when specified with -i/--instance, so it can be used in placeholders,
+// i.e. in publish topics, to keep the data of replicas of the same service apart
+const INSTANCE = "instance"
+
+// RETRYATTEMPT is the number of the attempt to export data stored by Store and Forward, which is only set when the
+// pipeline is retrying the stored data
+const RETRYATTEMPT = "retryattempt"
+
+// MEDIATYPE is the media type of the first binary reading of the Event received, i.e. 'image/jpeg', which is only set
+// for Events with binary readings
+const MEDIATYPE = "mediatype"
+
+// DefaultPipelineId is the Id of the default pipeline, which processes the data not processed by a per topic pipeline
+const DefaultPipelineId = "default"
+
+// HTTPHEADERPREFIX prefixes the lowercase names of the request headers stored as values in the context by the
+// HTTP trigger, i.e. the 'X-Tenant' header is stored as 'httpheader-x-tenant', so they can be used in placeholders
+// and propagated to exports. Multiple values for the same header are comma separated.
+const HTTPHEADERPREFIX = "httpheader-"
+
 // AppFunction is a type alias for a application pipeline function.
 // appCtx is a reference to the AppFunctionContext below.
 // data is the data to be operated on by the function.
@@ -37,6 +58,12 @@ const RECEIVEDTOPIC = "receivedtopic"
 // an error (stop executing due to error) or nil (done executing)
 type AppFunction = func(appCxt AppFunctionContext, data interface{}) (bool, interface{})
 
+// PipelineFanOut can be returned as the result of an AppFunction to have the remainder of the pipeline
+// executed once for each of the items it contains, i.e. one message per item rather than one for the batch.
+// Each item is processed with its own copy of the context, and is stored for retry on its own. The responses set
+// for the items are combined into a JSON array when they are all JSON, otherwise separated by new lines.
+type PipelineFanOut []interface{}
+
 // AppFunctionContext defines the interface for an Edgex Application Service Context provided to
 // App Functions when executing in the Functions Pipeline.
 type AppFunctionContext interface {
@@ -88,6 +115,22 @@ type AppFunctionContext interface {
 	// DeviceClient returns the Device client. Note if Core Metadata is not specified in the
 	// Clients configuration, this will return nil.
 	DeviceClient() interfaces.DeviceClient
+	// HttpClient returns the shared, connection pooled HTTP client configured by the HttpClient configuration,
+	// which functions making outbound HTTP requests should use rather than creating their own.
+	HttpClient() *http.Client
+	// PipelineId returns the Id of the pipeline processing the data, which is DefaultPipelineId unless the data is
+	// processed by a per topic pipeline.
+	PipelineId() string
+	// PipelinePosition returns the zero based position in the pipeline of the function being executed.
+	PipelinePosition() int
+	// FunctionName returns the name of the function being executed, i.e. 'transforms.Filter.FilterByDeviceName',
+	// so shared functions can identify where they ran in logs, metrics and errors.
+	FunctionName() string
+	// PipelineState returns the thread safe state store of the pipeline processing the data, which is kept across
+	// the messages the pipeline processes, so stateful functions don't have to manage their own globals and locking.
+	PipelineState() StateStore
+	// MetricsManager returns the manager used to register custom metrics, which are reported with the SDK's metrics.
+	MetricsManager() MetricsManager
 	// PushToCore pushes a new event to Core Data.
 	PushToCore(event dtos.Event) (common.BaseWithIdResponse, error)
 	// GetDeviceResource retrieves the DeviceResource for given profileName and resourceName.
@@ -107,4 +150,15 @@ type AppFunctionContext interface {
 	// the key in context storage.  An error will be returned if any placeholders
 	// are not matched to a value in the context.
 	ApplyValues(format string) (string, error)
+	// Clone returns an independent copy of the context, including the values stored in it, which is safe to use
+	// from goroutines started by a pipeline function, i.e. for a delayed publish, after the function has returned.
+	Clone() AppFunctionContext
+	// SerializedData returns the data as bytes, as util.CoerceType does, serializing it only once for consecutive
+	// functions in the pipeline, i.e. exporting the same Event via MQTT and to a file. The bytes returned are shared
+	// with the other functions, so must not be modified.
+	SerializedData(data interface{}) ([]byte, error)
+	// SerializedCBORData returns the data as bytes as SerializedData does, except Events with binary readings received
+	// as CBOR are serialized as CBOR, in which case true is also returned, so the binary values are exported as they
+	// were received. Exports must opt in to CBOR and send the content type of the encoding used.
+	SerializedCBORData(data interface{}) ([]byte, bool, error)
 }
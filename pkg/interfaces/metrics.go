@@ -0,0 +1,76 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package interfaces
+
+import "time"
+
+// Counter is a metric which counts occurrences, i.e. the number of Events exported.
+type Counter interface {
+	// Inc increments the count by delta
+	Inc(delta int64)
+	// Count returns the current count
+	Count() int64
+}
+
+// Gauge is a metric which holds the latest value of a measurement, i.e. the number of items in a batch.
+type Gauge interface {
+	// Update sets the value of the gauge
+	Update(value int64)
+	// Value returns the current value of the gauge
+	Value() int64
+}
+
+// Timer is a metric which records the durations of an operation, i.e. how long an export takes.
+type Timer interface {
+	// Time executes the function and records its duration
+	Time(function func())
+	// Update records the duration
+	Update(duration time.Duration)
+	// Count returns the number of durations recorded
+	Count() int64
+	// Min returns the shortest duration recorded
+	Min() time.Duration
+	// Max returns the longest duration recorded
+	Max() time.Duration
+	// Mean returns the mean of the durations recorded
+	Mean() time.Duration
+}
+
+// MetricsManager manages the custom metrics of the application service, which are reported by the SDK
+// along with its own metrics.
+type MetricsManager interface {
+	// NewCounter creates a new Counter, which must be registered to be reported
+	NewCounter() Counter
+	// NewGauge creates a new Gauge, which must be registered to be reported
+	NewGauge() Gauge
+	// NewTimer creates a new Timer, which must be registered to be reported
+	NewTimer() Timer
+	// Register registers the metric, which must be a Counter, Gauge or Timer, to be reported with the name and
+	// optional tags. An error is returned if the name is empty or already registered, or the metric isn't supported.
+	Register(name string, metric interface{}, tags map[string]string) error
+	// RegisterLabeled registers the metric like Register, except many metrics can be registered with the same name
+	// as long as their tags differ, i.e. the duration of each export tagged with its destination.
+	// An error is returned if the name is empty, the name and tags are already registered, or the metric isn't
+	// supported.
+	RegisterLabeled(name string, metric interface{}, tags map[string]string) error
+	// Unregister stops the metric with the name from being reported
+	Unregister(name string)
+	// UnregisterLabeled stops the metric registered with RegisterLabeled with the name and tags from being reported
+	UnregisterLabeled(name string, tags map[string]string)
+	// IsRegistered returns whether a metric with the name is registered
+	IsRegistered(name string) bool
+}
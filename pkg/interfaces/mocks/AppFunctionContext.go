@@ -8,10 +8,14 @@ import (
 
 	dtos "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
 
+	interfaces "github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
 	logger "github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
 
 	mock "github.com/stretchr/testify/mock"
 
+	http "net/http"
+
 	time "time"
 )
 
@@ -46,6 +50,22 @@ func (_m *AppFunctionContext) ApplyValues(format string) (string, error) {
 	return r0, r1
 }
 
+// Clone provides a mock function with given fields:
+func (_m *AppFunctionContext) Clone() interfaces.AppFunctionContext {
+	ret := _m.Called()
+
+	var r0 interfaces.AppFunctionContext
+	if rf, ok := ret.Get(0).(func() interfaces.AppFunctionContext); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interfaces.AppFunctionContext)
+		}
+	}
+
+	return r0
+}
+
 // CommandClient provides a mock function with given fields:
 func (_m *AppFunctionContext) CommandClient() clientsinterfaces.CommandClient {
 	ret := _m.Called()
@@ -140,6 +160,20 @@ func (_m *AppFunctionContext) EventClient() clientsinterfaces.EventClient {
 	return r0
 }
 
+// FunctionName provides a mock function with given fields:
+func (_m *AppFunctionContext) FunctionName() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 // GetAllValues provides a mock function with given fields:
 func (_m *AppFunctionContext) GetAllValues() map[string]string {
 	ret := _m.Called()
@@ -228,6 +262,22 @@ func (_m *AppFunctionContext) GetValue(key string) (string, bool) {
 	return r0, r1
 }
 
+// HttpClient provides a mock function with given fields:
+func (_m *AppFunctionContext) HttpClient() *http.Client {
+	ret := _m.Called()
+
+	var r0 *http.Client
+	if rf, ok := ret.Get(0).(func() *http.Client); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*http.Client)
+		}
+	}
+
+	return r0
+}
+
 // InputContentType provides a mock function with given fields:
 func (_m *AppFunctionContext) InputContentType() string {
 	ret := _m.Called()
@@ -258,6 +308,22 @@ func (_m *AppFunctionContext) LoggingClient() logger.LoggingClient {
 	return r0
 }
 
+// MetricsManager provides a mock function with given fields:
+func (_m *AppFunctionContext) MetricsManager() interfaces.MetricsManager {
+	ret := _m.Called()
+
+	var r0 interfaces.MetricsManager
+	if rf, ok := ret.Get(0).(func() interfaces.MetricsManager); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interfaces.MetricsManager)
+		}
+	}
+
+	return r0
+}
+
 // NotificationClient provides a mock function with given fields:
 func (_m *AppFunctionContext) NotificationClient() clientsinterfaces.NotificationClient {
 	ret := _m.Called()
@@ -274,6 +340,50 @@ func (_m *AppFunctionContext) NotificationClient() clientsinterfaces.Notificatio
 	return r0
 }
 
+// PipelineId provides a mock function with given fields:
+func (_m *AppFunctionContext) PipelineId() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// PipelinePosition provides a mock function with given fields:
+func (_m *AppFunctionContext) PipelinePosition() int {
+	ret := _m.Called()
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
+// PipelineState provides a mock function with given fields:
+func (_m *AppFunctionContext) PipelineState() interfaces.StateStore {
+	ret := _m.Called()
+
+	var r0 interfaces.StateStore
+	if rf, ok := ret.Get(0).(func() interfaces.StateStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interfaces.StateStore)
+		}
+	}
+
+	return r0
+}
+
 // PushToCore provides a mock function with given fields: event
 func (_m *AppFunctionContext) PushToCore(event dtos.Event) (common.BaseWithIdResponse, error) {
 	ret := _m.Called(event)
@@ -344,6 +454,59 @@ func (_m *AppFunctionContext) SecretsLastUpdated() time.Time {
 	return r0
 }
 
+// SerializedCBORData provides a mock function with given fields: data
+func (_m *AppFunctionContext) SerializedCBORData(data interface{}) ([]byte, bool, error) {
+	ret := _m.Called(data)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(interface{}) []byte); ok {
+		r0 = rf(data)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func(interface{}) bool); ok {
+		r1 = rf(data)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(interface{}) error); ok {
+		r2 = rf(data)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SerializedData provides a mock function with given fields: data
+func (_m *AppFunctionContext) SerializedData(data interface{}) ([]byte, error) {
+	ret := _m.Called(data)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(interface{}) []byte); ok {
+		r0 = rf(data)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(interface{}) error); ok {
+		r1 = rf(data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SetResponseContentType provides a mock function with given fields: _a0
 func (_m *AppFunctionContext) SetResponseContentType(_a0 string) {
 	_m.Called(_a0)
@@ -71,6 +71,17 @@ func (_m *ApplicationService) AddBackgroundPublisherWithTopic(capacity int, topi
 	return r0, r1
 }
 
+// AddMiddleware provides a mock function with given fields: middleware
+func (_m *ApplicationService) AddMiddleware(middleware ...interfaces.Middleware) {
+	_va := make([]interface{}, len(middleware))
+	for _i := range middleware {
+		_va[_i] = middleware[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	_m.Called(_ca...)
+}
+
 // AddRoute provides a mock function with given fields: route, handler, methods
 func (_m *ApplicationService) AddRoute(route string, handler func(http.ResponseWriter, *http.Request), methods ...string) error {
 	_va := make([]interface{}, len(methods))
@@ -92,6 +103,27 @@ func (_m *ApplicationService) AddRoute(route string, handler func(http.ResponseW
 	return r0
 }
 
+// AddRouteWithMiddleware provides a mock function with given fields: route, handler, middleware, methods
+func (_m *ApplicationService) AddRouteWithMiddleware(route string, handler func(http.ResponseWriter, *http.Request), middleware []interfaces.Middleware, methods ...string) error {
+	_va := make([]interface{}, len(methods))
+	for _i := range methods {
+		_va[_i] = methods[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, route, handler, middleware)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, func(http.ResponseWriter, *http.Request), []interfaces.Middleware, ...string) error); ok {
+		r0 = rf(route, handler, middleware, methods...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ApplicationSettings provides a mock function with given fields:
 func (_m *ApplicationService) ApplicationSettings() map[string]string {
 	ret := _m.Called()
@@ -348,6 +380,22 @@ func (_m *ApplicationService) MakeItStop() {
 	_m.Called()
 }
 
+// MetricsManager provides a mock function with given fields:
+func (_m *ApplicationService) MetricsManager() interfaces.MetricsManager {
+	ret := _m.Called()
+
+	var r0 interfaces.MetricsManager
+	if rf, ok := ret.Get(0).(func() interfaces.MetricsManager); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interfaces.MetricsManager)
+		}
+	}
+
+	return r0
+}
+
 // NotificationClient provides a mock function with given fields:
 func (_m *ApplicationService) NotificationClient() clientsinterfaces.NotificationClient {
 	ret := _m.Called()
@@ -364,6 +412,25 @@ func (_m *ApplicationService) NotificationClient() clientsinterfaces.Notificatio
 	return r0
 }
 
+// RegisterCustomConfigurableFunction provides a mock function with given fields: name, factory
+func (_m *ApplicationService) RegisterCustomConfigurableFunction(name string, factory interfaces.ConfigurableFunctionFactory) error {
+	ret := _m.Called(name, factory)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, interfaces.ConfigurableFunctionFactory) error); ok {
+		r0 = rf(name, factory)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RegisterCustomShutdownHook provides a mock function with given fields: hook
+func (_m *ApplicationService) RegisterCustomShutdownHook(hook func()) {
+	_m.Called(hook)
+}
+
 // RegisterCustomTriggerFactory provides a mock function with given fields: name, factory
 func (_m *ApplicationService) RegisterCustomTriggerFactory(name string, factory func(interfaces.TriggerConfig) (interfaces.Trigger, error)) error {
 	ret := _m.Called(name, factory)
@@ -378,6 +445,34 @@ func (_m *ApplicationService) RegisterCustomTriggerFactory(name string, factory
 	return r0
 }
 
+// RegisterPayloadDecoder provides a mock function with given fields: contentType, decoder
+func (_m *ApplicationService) RegisterPayloadDecoder(contentType string, decoder interfaces.PayloadDecoder) error {
+	ret := _m.Called(contentType, decoder)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, interfaces.PayloadDecoder) error); ok {
+		r0 = rf(contentType, decoder)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RegisterSecretUpdatedCallback provides a mock function with given fields: path, callback
+func (_m *ApplicationService) RegisterSecretUpdatedCallback(path string, callback func()) error {
+	ret := _m.Called(path, callback)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, func()) error); ok {
+		r0 = rf(path, callback)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RegistryClient provides a mock function with given fields:
 func (_m *ApplicationService) RegistryClient() registry.Client {
 	ret := _m.Called()
@@ -394,6 +489,22 @@ func (_m *ApplicationService) RegistryClient() registry.Client {
 	return r0
 }
 
+// RemainingArgs provides a mock function with given fields:
+func (_m *ApplicationService) RemainingArgs() []string {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
 // SetFunctionsPipeline provides a mock function with given fields: transforms
 func (_m *ApplicationService) SetFunctionsPipeline(transforms ...func(interfaces.AppFunctionContext, interface{}) (bool, interface{})) error {
 	_va := make([]interface{}, len(transforms))
@@ -414,6 +525,25 @@ func (_m *ApplicationService) SetFunctionsPipeline(transforms ...func(interfaces
 	return r0
 }
 
+// SetOpenAPIDocument provides a mock function with given fields: document, enableUI
+func (_m *ApplicationService) SetOpenAPIDocument(document []byte, enableUI bool) error {
+	ret := _m.Called(document, enableUI)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]byte, bool) error); ok {
+		r0 = rf(document, enableUI)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetVersion provides a mock function with given fields: version
+func (_m *ApplicationService) SetVersion(version string) {
+	_m.Called(version)
+}
+
 // StoreSecret provides a mock function with given fields: path, secretData
 func (_m *ApplicationService) StoreSecret(path string, secretData map[string]string) error {
 	ret := _m.Called(path, secretData)
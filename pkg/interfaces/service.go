@@ -39,6 +39,10 @@ const (
 	ProfileSuffixPlaceholder = "<profile>"
 )
 
+// Middleware wraps the handler of a route, i.e. to authenticate, rate limit or log the requests, calling the handler
+// to continue processing the request or responding itself to stop
+type Middleware func(next http.Handler) http.Handler
+
 // UpdatableConfig interface allows services to have custom configuration populated from configuration stored
 // in the Configuration Provider (aka Consul). Services using custom configuration must implement this interface
 // on their custom configuration, even if they do not use Configuration Provider. If they do not use the
@@ -49,12 +53,39 @@ type UpdatableConfig interface {
 	bootstrapInterfaces.UpdatableConfig
 }
 
+// ValidatableConfig may be implemented by a service's custom configuration to have it validated by
+// LoadCustomConfig once it is loaded, so invalid values are reported when the service starts.
+type ValidatableConfig interface {
+	// Validate returns an error describing the first invalid value found
+	Validate() error
+}
+
+// ConfigurableFunctionFactory creates the AppFunction for a custom configurable pipeline function from the
+// parameters specified for the function in the Pipeline.Functions configuration. Parameter names are lowercase.
+// util.BindParameters can be used to decode the parameters into a typed config struct with defaults.
+// An error is returned if the parameters are not valid.
+type ConfigurableFunctionFactory func(parameters map[string]string) (AppFunction, error)
+
+// PayloadDecoder decodes the payload received into the target, which is a pointer to the pipeline's TargetType or,
+// for Event pipelines, to an AddEventRequest or Event DTO. json.Unmarshal is an example of a PayloadDecoder.
+type PayloadDecoder func(payload []byte, target interface{}) error
+
 // ApplicationService defines the interface for an edgex Application Service
 type ApplicationService interface {
 	// AddRoute a custom REST route to the application service's internal webserver
 	// A reference to this ApplicationService is add the the context that is passed to the handler, which
 	// can be retrieved using the `AppService` key
 	AddRoute(route string, handler func(http.ResponseWriter, *http.Request), methods ...string) error
+	// SetOpenAPIDocument serves the OpenAPI document, in JSON or YAML, describing the custom routes on the
+	// /api/v2/openapi route and, when enableUI is true, the Swagger UI for it on the /api/v2/openapi/ui route.
+	// An error is returned if the document is empty.
+	SetOpenAPIDocument(document []byte, enableUI bool) error
+	// AddRouteWithMiddleware adds a custom REST route, like AddRoute, with the handler wrapped by the middleware.
+	// The first middleware is the outermost, so processes the request first. The route's middleware runs after the
+	// global middleware added with AddMiddleware.
+	AddRouteWithMiddleware(route string, handler func(http.ResponseWriter, *http.Request), middleware []Middleware, methods ...string) error
+	// AddMiddleware adds global middleware, which wraps the handlers of all the routes, in the order added
+	AddMiddleware(middleware ...Middleware)
 	// ApplicationSettings returns the key/value map of custom settings
 	ApplicationSettings() map[string]string
 	// GetAppSetting is a convenience function return a setting from the ApplicationSetting
@@ -65,6 +96,12 @@ type ApplicationService interface {
 	// application setting as a comma separated list. It returns the list of strings.
 	// An error is returned if the specified setting is not found.
 	GetAppSettingStrings(setting string) ([]string, error)
+	// RemainingArgs returns the command line arguments remaining after the service's flags, and those registered
+	// with the WithCustomFlags option, have been parsed.
+	RemainingArgs() []string
+	// SetVersion sets the version of the application, which is reported by the /version endpoint along with the
+	// SDK version. Use when the version isn't set at build time using ldflags.
+	SetVersion(version string)
 	// SetFunctionsPipeline set the functions pipeline with the specified list of Application Functions.
 	// Note that the functions are executed in the order provided in the list.
 	// An error is returned if the list is empty.
@@ -75,9 +112,16 @@ type ApplicationService interface {
 	// An error is returned if the trigger can not be create or initialized or if the internal webserver
 	// encounters an error.
 	MakeItRun() error
-	// MakeItStop stops the configured trigger so that the functions pipeline no longer executes.
-	// An error is returned
+	// MakeItStop stops the configured trigger so that the functions pipeline no longer executes, which causes
+	// MakeItRun to return once the web server is stopped, the pipeline executions in progress have completed
+	// (within the Trigger ShutdownTimeout) and the service has de-registered from the Registry.
+	// Allows embedding applications and tests to stop the service cleanly without sending OS signals.
 	MakeItStop()
+	// RegisterCustomShutdownHook registers a function which is called when the service is stopping, once the
+	// pipeline executions in progress have completed, so the application can close its connections, flush its
+	// buffers and stop its own go routines before the service exits. The hooks are called in the reverse order
+	// they were registered.
+	RegisterCustomShutdownHook(hook func())
 	// RegisterCustomTriggerFactory registers a trigger factory for a custom trigger to be used.
 	RegisterCustomTriggerFactory(name string, factory func(TriggerConfig) (Trigger, error)) error
 	// AddBackgroundPublisher Adds and returns a BackgroundPublisher which is used to publish
@@ -98,7 +142,13 @@ type ApplicationService interface {
 	//   - Not using the secure secret store, i.e. not valid with InsecureSecrets configuration
 	//   - Secure secret provider is not properly initialized
 	//   - Connection issues with Secret Store service.
-	StoreSecret(path string, secretData map[string]string) error // LoggingClient returns the Logger client
+	StoreSecret(path string, secretData map[string]string) error
+	// RegisterSecretUpdatedCallback registers a callback which is invoked when the secrets at the path are updated
+	// in the secret store, so long lived connections, i.e. MQTT clients or DB pools, can be rebuilt with rotated
+	// credentials rather than failing until the service is restarted.
+	// An error is returned if the path is empty or the callback is nil.
+	RegisterSecretUpdatedCallback(path string, callback func()) error
+	// LoggingClient returns the Logger client
 	LoggingClient() logger.LoggingClient
 	// EventClient returns the Event client. Note if Core Data is not specified in the Clients configuration,
 	// this will return nil.
@@ -124,15 +174,29 @@ type ApplicationService interface {
 	// RegistryClient returns the Registry client. Note the registry must been enable, otherwise this will return nil.
 	// Useful if service needs to add additional health checks or needs to get endpoint of another registered service
 	RegistryClient() registry.Client
+	// MetricsManager returns the manager used to register custom metrics, which are reported with the SDK's metrics.
+	MetricsManager() MetricsManager
 	// LoadConfigurablePipeline loads the function pipeline from configuration.
 	// An error is returned if the configuration is not valid, i.e. missing required function parameters,
 	// invalid function name, etc.
 	// Only useful if pipeline from configuration is always defined in configuration as in App Service Configurable.
 	LoadConfigurablePipeline() ([]AppFunction, error)
+	// RegisterCustomConfigurableFunction registers a factory for a custom function which can then be used in the
+	// function pipeline loaded from configuration by LoadConfigurablePipeline, in the same way as the built in
+	// configurable functions.
+	// An error is returned if the name is empty, is the name of a built in function or is already registered.
+	RegisterCustomConfigurableFunction(name string, factory ConfigurableFunctionFactory) error
+	// RegisterPayloadDecoder registers the decoder of the payloads received with the content type, i.e.
+	// 'application/x-protobuf', so custom payload formats can be processed by the pipeline. The decoder is selected
+	// by the content type of the message received, ignoring any parameters such as the charset.
+	// An error is returned if the content type is empty or already registered, or is one of the built in content types
+	// 'application/json', 'application/cbor', 'text/plain' and 'application/octet-stream'.
+	RegisterPayloadDecoder(contentType string, decoder PayloadDecoder) error
 	// LoadCustomConfig loads the service's custom configuration from local file or the Configuration Provider (if enabled)
 	// Configuration Provider will also be seeded with the custom configuration if service is using the Configuration Provider.
 	// UpdateFromRaw interface will be called on the custom configuration when the configuration is loaded from the
-	// Configuration Provider.
+	// Configuration Provider. If the custom configuration implements ValidatableConfig it is validated once loaded
+	// and the validation error is returned.
 	LoadCustomConfig(config UpdatableConfig, sectionName string) error
 	// ListenForCustomConfigChanges starts a listener on the Configuration Provider for changes to the specified
 	// section of the custom configuration. When changes are received from the Configuration Provider the
@@ -0,0 +1,32 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package interfaces
+
+// StateStore is a thread safe store of the state a pipeline's functions keep across the messages the pipeline
+// processes, i.e. the readings of the current window for an aggregation or the IDs already seen for de-duplication.
+// Keys are shared by all the functions in the pipeline, so should be prefixed with the function's name.
+type StateStore interface {
+	// Get returns the value stored for the key and whether it was found
+	Get(key string) (interface{}, bool)
+	// Set stores the value for the key, replacing any existing value
+	Set(key string, value interface{})
+	// Delete removes the value stored for the key
+	Delete(key string)
+	// Update atomically replaces the value stored for the key with the value returned by the update function, which
+	// receives the current value and whether it was found. The new value is returned.
+	Update(key string, update func(current interface{}, found bool) interface{}) interface{}
+}
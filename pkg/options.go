@@ -0,0 +1,95 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pkg
+
+import (
+	"context"
+	"flag"
+	"os"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+)
+
+// Option configures the ApplicationService created by NewAppService
+type Option func(options *serviceOptions)
+
+type serviceOptions struct {
+	targetType          interface{}
+	args                []string
+	customConfig        interfaces.UpdatableConfig
+	customConfigSection string
+	ctx                 context.Context
+	customFlags         func(flagSet *flag.FlagSet)
+}
+
+// WithTargetType sets the TargetType of the function pipeline, which must be a pointer to the type
+func WithTargetType(targetType interface{}) Option {
+	return func(options *serviceOptions) {
+		options.targetType = targetType
+	}
+}
+
+// WithArgs sets the command line arguments parsed by the service rather than those the process was started with
+func WithArgs(args ...string) Option {
+	return func(options *serviceOptions) {
+		options.args = append([]string{}, args...)
+	}
+}
+
+// WithProfile sets the configuration profile the service uses, overriding the -p/--profile command line argument
+func WithProfile(profile string) Option {
+	return func(options *serviceOptions) {
+		if options.args == nil {
+			options.args = append([]string{}, os.Args[1:]...)
+		}
+		options.args = append(options.args, "--profile="+profile)
+	}
+}
+
+// WithCustomConfig loads the service's custom configuration from the section once the service is initialized,
+// as done by ApplicationService.LoadCustomConfig
+func WithCustomConfig(config interfaces.UpdatableConfig, sectionName string) Option {
+	return func(options *serviceOptions) {
+		options.customConfig = config
+		options.customConfigSection = sectionName
+	}
+}
+
+// WithContext sets the context the service runs under, so the service stops, as if MakeItStop was called,
+// when the context is cancelled
+func WithContext(ctx context.Context) Option {
+	return func(options *serviceOptions) {
+		options.ctx = ctx
+	}
+}
+
+// WithCustomFlags registers the application's own command line flags with the service's flag set, so they are
+// parsed along with the service's flags. The arguments remaining after parsing are available from
+// ApplicationService.RemainingArgs.
+func WithCustomFlags(register func(flagSet *flag.FlagSet)) Option {
+	return func(options *serviceOptions) {
+		options.customFlags = register
+	}
+}
+
+func newServiceOptions(opts []Option) serviceOptions {
+	options := serviceOptions{}
+	for _, option := range opts {
+		option(&options)
+	}
+	return options
+}
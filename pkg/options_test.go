@@ -0,0 +1,73 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pkg
+
+import (
+	"context"
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCustomConfig struct{}
+
+func (c *testCustomConfig) UpdateFromRaw(_ interface{}) bool {
+	return true
+}
+
+func TestServiceOptions(t *testing.T) {
+	targetType := &[]byte{}
+	customConfig := &testCustomConfig{}
+	ctx := context.Background()
+
+	options := newServiceOptions([]Option{
+		WithTargetType(targetType),
+		WithArgs("-cp", "-r"),
+		WithProfile("mqtt-export"),
+		WithCustomConfig(customConfig, "AppCustom"),
+		WithContext(ctx),
+	})
+
+	assert.Equal(t, targetType, options.targetType)
+	assert.Equal(t, []string{"-cp", "-r", "--profile=mqtt-export"}, options.args)
+	assert.Equal(t, customConfig, options.customConfig)
+	assert.Equal(t, "AppCustom", options.customConfigSection)
+	assert.Equal(t, ctx, options.ctx)
+	assert.Nil(t, options.customFlags)
+
+	var verbose bool
+	options = newServiceOptions([]Option{WithCustomFlags(func(flagSet *flag.FlagSet) {
+		flagSet.BoolVar(&verbose, "verbose", false, "")
+	})})
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	options.customFlags(flagSet)
+	assert.NotNil(t, flagSet.Lookup("verbose"))
+}
+
+func TestServiceOptionsDefaults(t *testing.T) {
+	options := newServiceOptions(nil)
+	assert.Nil(t, options.targetType)
+	assert.Nil(t, options.args)
+	assert.Nil(t, options.customConfig)
+	assert.Nil(t, options.ctx)
+
+	// The profile is added to the arguments the process was started with
+	options = newServiceOptions([]Option{WithProfile("mqtt-export")})
+	assert.Equal(t, append(append([]string{}, os.Args[1:]...), "--profile=mqtt-export"), options.args)
+}
@@ -0,0 +1,353 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+)
+
+// WindowMode Enum for choosing the windowing behavior of Aggregation.
+type WindowMode int
+
+const (
+	TumblingWindow WindowMode = iota
+	SlidingWindow
+)
+
+// Aggregate functions supported by Aggregation
+const (
+	AggregateAverage = "avg"
+	AggregateMin     = "min"
+	AggregateMax     = "max"
+	AggregateCount   = "count"
+)
+
+type aggregateSample struct {
+	timestamp time.Time
+	value     float64
+}
+
+type aggregateWindow struct {
+	start        time.Time
+	lastEmitted  time.Time
+	lastReceived time.Time
+	profileName  string
+	sourceName   string
+	samples      map[string][]aggregateSample
+}
+
+// Aggregation computes aggregate statistics of numeric readings per device and resource over a
+// tumbling or sliding time window and emits a summary Event when the window completes.
+type Aggregation struct {
+	functions     []string
+	windowMode    WindowMode
+	windowSize    time.Duration
+	slideInterval time.Duration
+	mutex         sync.Mutex
+	windows       map[string]*aggregateWindow
+	now           func() time.Time
+}
+
+// NewTumblingWindowAggregation creates, initializes and returns a new instance of Aggregation which
+// emits the aggregates for consecutive, non-overlapping windows of the specified size.
+func NewTumblingWindowAggregation(windowSize string, functions []string) (*Aggregation, error) {
+	return newAggregation(TumblingWindow, windowSize, windowSize, functions)
+}
+
+// NewSlidingWindowAggregation creates, initializes and returns a new instance of Aggregation which
+// emits the aggregates for the last window size worth of readings every slide interval.
+func NewSlidingWindowAggregation(windowSize string, slideInterval string, functions []string) (*Aggregation, error) {
+	return newAggregation(SlidingWindow, windowSize, slideInterval, functions)
+}
+
+func newAggregation(mode WindowMode, windowSize string, slideInterval string, functions []string) (*Aggregation, error) {
+	aggregation := &Aggregation{
+		windowMode: mode,
+		windows:    make(map[string]*aggregateWindow),
+		now:        time.Now,
+	}
+
+	var err error
+	aggregation.windowSize, err = time.ParseDuration(windowSize)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse window size '%s': %s", windowSize, err.Error())
+	}
+
+	aggregation.slideInterval, err = time.ParseDuration(slideInterval)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse slide interval '%s': %s", slideInterval, err.Error())
+	}
+
+	if aggregation.windowSize <= 0 || aggregation.slideInterval <= 0 {
+		return nil, errors.New("window size and slide interval must be greater than zero")
+	}
+
+	if len(functions) == 0 {
+		return nil, errors.New("at least one aggregate function must be specified")
+	}
+
+	for _, function := range functions {
+		function = strings.ToLower(strings.TrimSpace(function))
+		switch function {
+		case AggregateAverage, AggregateMin, AggregateMax, AggregateCount:
+			aggregation.functions = append(aggregation.functions, function)
+		default:
+			return nil, fmt.Errorf(
+				"invalid aggregate function '%s'. Must be '%s', '%s', '%s' or '%s'",
+				function,
+				AggregateAverage,
+				AggregateMin,
+				AggregateMax,
+				AggregateCount)
+		}
+	}
+
+	return aggregation, nil
+}
+
+// Aggregate accumulates the numeric readings of the Event received into the window for the Event's device.
+// When the window completes, the pipeline continues with a summary Event for the device containing a reading
+// per resource and aggregate function, named '<resourceName>_<function>'. Otherwise the pipeline stops.
+// Readings with non-numeric values are ignored.
+// Pipeline functions only execute when data is received, so tumbling windows aren't emitted on a timer. Instead the
+// windows completed for any device are emitted when the next Event is received for any device, which also frees the
+// windows of devices no longer sending Events. Sliding windows of devices which haven't sent an Event for the window
+// size are freed the same way, since all their readings have slid out of the window. When the windows of several devices complete at once, the rest of the
+// pipeline is executed for each summary Event, using a PipelineFanOut.
+// It will return an error and stop the pipeline if a non-edgex event is received or if no data is received.
+func (agg *Aggregation) Aggregate(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, errors.New("No Event Received")
+	}
+
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return false, errors.New("type received is not an Event")
+	}
+
+	lc := ctx.LoggingClient()
+	lc.Debugf("Aggregating readings for device '%s'", event.DeviceName)
+
+	agg.mutex.Lock()
+	defer agg.mutex.Unlock()
+
+	now := agg.now()
+
+	var summaries []dtos.Event
+	var err error
+
+	switch agg.windowMode {
+	case TumblingWindow:
+		summaries, err = agg.flushCompleted(now)
+		if err != nil {
+			return false, err
+		}
+
+		window, exists := agg.windows[event.DeviceName]
+		if !exists {
+			window = newAggregateWindow(now)
+			agg.windows[event.DeviceName] = window
+		}
+		window.add(event, now)
+
+	case SlidingWindow:
+		agg.evictIdle(now)
+
+		window, exists := agg.windows[event.DeviceName]
+		if !exists {
+			window = newAggregateWindow(now)
+			agg.windows[event.DeviceName] = window
+		}
+
+		window.add(event, now)
+		window.prune(now.Add(-agg.windowSize))
+		if now.Sub(window.lastEmitted) >= agg.slideInterval {
+			summary, err := agg.summarize(event.DeviceName, window)
+			if err != nil {
+				return false, err
+			}
+			window.lastEmitted = now
+			if len(summary.Readings) > 0 {
+				summaries = append(summaries, summary)
+			}
+		}
+	}
+
+	switch len(summaries) {
+	case 0:
+		lc.Debugf("Aggregation window for device '%s' not complete", event.DeviceName)
+		return false, nil
+
+	case 1:
+		lc.Debugf("Aggregation window for device '%s' complete with %d aggregate reading(s)",
+			summaries[0].DeviceName, len(summaries[0].Readings))
+		return true, summaries[0]
+
+	default:
+		lc.Debugf("Aggregation windows for %d devices complete", len(summaries))
+		fanOut := make(interfaces.PipelineFanOut, len(summaries))
+		for index, summary := range summaries {
+			fanOut[index] = summary
+		}
+		return true, fanOut
+	}
+}
+
+// flushCompleted removes the tumbling windows of all the devices which have completed and returns their summary
+// Events, ordered by device name. Windows without numeric readings complete without a summary Event.
+func (agg *Aggregation) flushCompleted(now time.Time) ([]dtos.Event, error) {
+	deviceNames := make([]string, 0, len(agg.windows))
+	for deviceName, window := range agg.windows {
+		if now.Sub(window.start) >= agg.windowSize {
+			deviceNames = append(deviceNames, deviceName)
+		}
+	}
+	sort.Strings(deviceNames)
+
+	var summaries []dtos.Event
+	for _, deviceName := range deviceNames {
+		summary, err := agg.summarize(deviceName, agg.windows[deviceName])
+		if err != nil {
+			return nil, err
+		}
+
+		delete(agg.windows, deviceName)
+		if len(summary.Readings) > 0 {
+			summaries = append(summaries, summary)
+		}
+	}
+
+	return summaries, nil
+}
+
+// evictIdle removes the sliding windows of the devices which haven't sent an Event for the window size, whose readings
+// have all slid out of the window
+func (agg *Aggregation) evictIdle(now time.Time) {
+	for deviceName, window := range agg.windows {
+		if now.Sub(window.lastReceived) > agg.windowSize {
+			delete(agg.windows, deviceName)
+		}
+	}
+}
+
+func (agg *Aggregation) summarize(deviceName string, window *aggregateWindow) (dtos.Event, error) {
+	summary := dtos.NewEvent(window.profileName, deviceName, window.sourceName)
+
+	resourceNames := make([]string, 0, len(window.samples))
+	for resourceName := range window.samples {
+		resourceNames = append(resourceNames, resourceName)
+	}
+	sort.Strings(resourceNames)
+
+	for _, resourceName := range resourceNames {
+		samples := window.samples[resourceName]
+		if len(samples) == 0 {
+			continue
+		}
+
+		min := math.MaxFloat64
+		max := -math.MaxFloat64
+		sum := 0.0
+		for _, sample := range samples {
+			min = math.Min(min, sample.value)
+			max = math.Max(max, sample.value)
+			sum += sample.value
+		}
+
+		for _, function := range agg.functions {
+			readingName := resourceName + "_" + function
+
+			var err error
+			switch function {
+			case AggregateAverage:
+				err = summary.AddSimpleReading(readingName, common.ValueTypeFloat64, sum/float64(len(samples)))
+			case AggregateMin:
+				err = summary.AddSimpleReading(readingName, common.ValueTypeFloat64, min)
+			case AggregateMax:
+				err = summary.AddSimpleReading(readingName, common.ValueTypeFloat64, max)
+			case AggregateCount:
+				err = summary.AddSimpleReading(readingName, common.ValueTypeInt64, int64(len(samples)))
+			}
+			if err != nil {
+				return dtos.Event{}, fmt.Errorf("unable to add '%s' aggregate reading: %s", readingName, err.Error())
+			}
+		}
+	}
+
+	return summary, nil
+}
+
+func newAggregateWindow(start time.Time) *aggregateWindow {
+	return &aggregateWindow{
+		start:       start,
+		lastEmitted: start,
+		samples:     make(map[string][]aggregateSample),
+	}
+}
+
+func (window *aggregateWindow) add(event dtos.Event, timestamp time.Time) {
+	window.profileName = event.ProfileName
+	window.sourceName = event.SourceName
+	window.lastReceived = timestamp
+
+	for _, reading := range event.Readings {
+		if reading.ValueType == common.ValueTypeBinary || reading.ValueType == common.ValueTypeBool ||
+			reading.ValueType == common.ValueTypeString {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(reading.Value, 64)
+		if err != nil {
+			continue
+		}
+
+		window.samples[reading.ResourceName] = append(
+			window.samples[reading.ResourceName],
+			aggregateSample{timestamp: timestamp, value: value})
+	}
+}
+
+func (window *aggregateWindow) prune(oldest time.Time) {
+	for resourceName, samples := range window.samples {
+		index := 0
+		for index < len(samples) && samples[index].timestamp.Before(oldest) {
+			index++
+		}
+		if index == 0 {
+			continue
+		}
+
+		// Copied rather than resliced, so the expired samples don't keep the backing array growing without bound
+		if index == len(samples) {
+			delete(window.samples, resourceName)
+		} else {
+			window.samples[resourceName] = append([]aggregateSample(nil), samples[index:]...)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func aggregateReadingValue(t *testing.T, event dtos.Event, name string) float64 {
+	for _, reading := range event.Readings {
+		if reading.ResourceName == name {
+			value, err := strconv.ParseFloat(reading.Value, 64)
+			require.NoError(t, err)
+			return value
+		}
+	}
+
+	require.Failf(t, "reading not found", "reading '%s' not found in aggregate event", name)
+	return 0
+}
+
+func TestNewAggregation(t *testing.T) {
+	tests := []struct {
+		Name          string
+		WindowSize    string
+		SlideInterval string
+		Functions     []string
+		ExpectError   bool
+	}{
+		{"Valid", "10s", "5s", []string{"avg", "MIN", " max ", "count"}, false},
+		{"Bad window size", "bogus", "5s", []string{"avg"}, true},
+		{"Bad slide interval", "10s", "bogus", []string{"avg"}, true},
+		{"Zero window size", "0s", "5s", []string{"avg"}, true},
+		{"No functions", "10s", "5s", nil, true},
+		{"Bad function", "10s", "5s", []string{"median"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := NewSlidingWindowAggregation(test.WindowSize, test.SlideInterval, test.Functions)
+			assert.Equal(t, test.ExpectError, err != nil)
+		})
+	}
+}
+
+func TestAggregateTumblingWindow(t *testing.T) {
+	agg, err := NewTumblingWindowAggregation("10s", []string{AggregateAverage, AggregateMin, AggregateMax, AggregateCount})
+	require.NoError(t, err)
+
+	now := time.Now()
+	agg.now = func() time.Time { return now }
+
+	continuePipeline, result := agg.Aggregate(ctx, newTestEvent(t, deviceName1, nil, floatReading("temperature", 10)))
+	assert.False(t, continuePipeline)
+	assert.Nil(t, result)
+
+	now = now.Add(5 * time.Second)
+	continuePipeline, _ = agg.Aggregate(ctx, newTestEvent(t, deviceName1, nil, floatReading("temperature", 20)))
+	assert.False(t, continuePipeline)
+
+	// Different device has its own window
+	continuePipeline, _ = agg.Aggregate(ctx, newTestEvent(t, deviceName2, nil, floatReading("temperature", 100)))
+	assert.False(t, continuePipeline)
+
+	now = now.Add(5 * time.Second)
+	continuePipeline, result = agg.Aggregate(ctx, newTestEvent(t, deviceName1, nil, floatReading("temperature", 90)))
+	require.True(t, continuePipeline)
+	summary, ok := result.(dtos.Event)
+	require.True(t, ok)
+	assert.Equal(t, deviceName1, summary.DeviceName)
+	require.Len(t, summary.Readings, 4)
+	assert.Equal(t, 15.0, aggregateReadingValue(t, summary, "temperature_avg"))
+	assert.Equal(t, 10.0, aggregateReadingValue(t, summary, "temperature_min"))
+	assert.Equal(t, 20.0, aggregateReadingValue(t, summary, "temperature_max"))
+	assert.Equal(t, 2.0, aggregateReadingValue(t, summary, "temperature_count"))
+
+	// The event that completed the previous window starts the next window, and the windows of both devices are
+	// emitted at once
+	now = now.Add(10 * time.Second)
+	continuePipeline, result = agg.Aggregate(ctx, newTestEvent(t, deviceName1, nil, floatReading("temperature", 0)))
+	require.True(t, continuePipeline)
+	fanOut, ok := result.(interfaces.PipelineFanOut)
+	require.True(t, ok)
+	require.Len(t, fanOut, 2)
+	assert.Equal(t, deviceName1, fanOut[0].(dtos.Event).DeviceName)
+	assert.Equal(t, 90.0, aggregateReadingValue(t, fanOut[0].(dtos.Event), "temperature_avg"))
+	assert.Equal(t, deviceName2, fanOut[1].(dtos.Event).DeviceName)
+	assert.Equal(t, 100.0, aggregateReadingValue(t, fanOut[1].(dtos.Event), "temperature_avg"))
+}
+
+func TestAggregateTumblingWindowFlushedByOtherDevice(t *testing.T) {
+	agg, err := NewTumblingWindowAggregation("10s", []string{AggregateAverage})
+	require.NoError(t, err)
+
+	now := time.Now()
+	agg.now = func() time.Time { return now }
+
+	continuePipeline, _ := agg.Aggregate(ctx, newTestEvent(t, deviceName1, nil, floatReading("temperature", 10)))
+	assert.False(t, continuePipeline)
+
+	// The completed window of a device no longer sending Events is emitted on the next Event of another device
+	now = now.Add(10 * time.Second)
+	continuePipeline, result := agg.Aggregate(ctx, newTestEvent(t, deviceName2, nil, floatReading("temperature", 20)))
+	require.True(t, continuePipeline)
+	summary, ok := result.(dtos.Event)
+	require.True(t, ok)
+	assert.Equal(t, deviceName1, summary.DeviceName)
+	assert.Equal(t, 10.0, aggregateReadingValue(t, summary, "temperature_avg"))
+
+	_, found := agg.windows[deviceName1]
+	assert.False(t, found, "completed window not freed")
+}
+
+func TestAggregateSlidingWindow(t *testing.T) {
+	agg, err := NewSlidingWindowAggregation("10s", "5s", []string{AggregateAverage, AggregateCount})
+	require.NoError(t, err)
+
+	now := time.Now()
+	agg.now = func() time.Time { return now }
+
+	continuePipeline, _ := agg.Aggregate(ctx, newTestEvent(t, deviceName1, nil, floatReading("temperature", 10)))
+	assert.False(t, continuePipeline)
+
+	now = now.Add(5 * time.Second)
+	continuePipeline, result := agg.Aggregate(ctx, newTestEvent(t, deviceName1, nil, floatReading("temperature", 20)))
+	require.True(t, continuePipeline)
+	assert.Equal(t, 15.0, aggregateReadingValue(t, result.(dtos.Event), "temperature_avg"))
+	assert.Equal(t, 2.0, aggregateReadingValue(t, result.(dtos.Event), "temperature_count"))
+
+	// First sample has slid out of the window
+	now = now.Add(6 * time.Second)
+	continuePipeline, result = agg.Aggregate(ctx, newTestEvent(t, deviceName1, nil, floatReading("temperature", 30)))
+	require.True(t, continuePipeline)
+	assert.Equal(t, 25.0, aggregateReadingValue(t, result.(dtos.Event), "temperature_avg"))
+	assert.Equal(t, 2.0, aggregateReadingValue(t, result.(dtos.Event), "temperature_count"))
+	assert.Len(t, agg.windows[deviceName1].samples["temperature"], 2)
+	assert.Equal(t, 2, cap(agg.windows[deviceName1].samples["temperature"]), "pruned samples not copied")
+
+	// The window of a device no longer sending Events is freed once all its readings have slid out of the window
+	now = now.Add(11 * time.Second)
+	continuePipeline, _ = agg.Aggregate(ctx, newTestEvent(t, deviceName2, nil, floatReading("temperature", 40)))
+	assert.False(t, continuePipeline)
+	_, found := agg.windows[deviceName1]
+	assert.False(t, found, "idle window not freed")
+	assert.Contains(t, agg.windows, deviceName2)
+}
+
+func TestAggregateNonNumericReadingsIgnored(t *testing.T) {
+	agg, err := NewTumblingWindowAggregation("1s", []string{AggregateCount})
+	require.NoError(t, err)
+
+	now := time.Now()
+	agg.now = func() time.Time { return now }
+
+	invalid := dtos.BaseReading{ResourceName: "temperature", ValueType: common.ValueTypeFloat64}
+	invalid.Value = "not a number"
+	event := newTestEvent(t, deviceName1, nil)
+	event.Readings = append(
+		event.Readings,
+		invalid,
+		dtos.BaseReading{ResourceName: "status", ValueType: common.ValueTypeString})
+	agg.Aggregate(ctx, event)
+
+	now = now.Add(time.Second)
+	continuePipeline, result := agg.Aggregate(ctx, event)
+	assert.False(t, continuePipeline)
+	assert.Nil(t, result)
+}
+
+func TestAggregateNoData(t *testing.T) {
+	agg, err := NewTumblingWindowAggregation("1s", []string{AggregateCount})
+	require.NoError(t, err)
+
+	continuePipeline, result := agg.Aggregate(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.EqualError(t, result.(error), "No Event Received")
+
+	continuePipeline, result = agg.Aggregate(ctx, "not an event")
+	assert.False(t, continuePipeline)
+	assert.EqualError(t, result.(error), "type received is not an Event")
+}
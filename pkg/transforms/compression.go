@@ -24,6 +24,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/util"
@@ -31,6 +32,14 @@ import (
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
 )
 
+// compressionBuffers pools the buffers the data is compressed into, which are only needed until the compressed data
+// is base64 encoded, to avoid allocating and growing a new buffer for every message compressed
+var compressionBuffers = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
 type Compression struct {
 	gzipWriter *gzip.Writer
 	zlibWriter *zlib.Writer
@@ -53,12 +62,14 @@ func (compression *Compression) CompressWithGZIP(ctx interfaces.AppFunctionConte
 	if err != nil {
 		return false, err
 	}
-	var buf bytes.Buffer
+	buf := compressionBuffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer compressionBuffers.Put(buf)
 
 	if compression.gzipWriter == nil {
-		compression.gzipWriter = gzip.NewWriter(&buf)
+		compression.gzipWriter = gzip.NewWriter(buf)
 	} else {
-		compression.gzipWriter.Reset(&buf)
+		compression.gzipWriter.Reset(buf)
 	}
 
 	_, err = compression.gzipWriter.Write(rawData)
@@ -90,12 +101,14 @@ func (compression *Compression) CompressWithZLIB(ctx interfaces.AppFunctionConte
 	if err != nil {
 		return false, err
 	}
-	var buf bytes.Buffer
+	buf := compressionBuffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer compressionBuffers.Put(buf)
 
 	if compression.zlibWriter == nil {
-		compression.zlibWriter = zlib.NewWriter(&buf)
+		compression.zlibWriter = zlib.NewWriter(buf)
 	} else {
-		compression.zlibWriter.Reset(&buf)
+		compression.zlibWriter.Reset(buf)
 	}
 
 	_, err = compression.zlibWriter.Write(byteData)
@@ -115,7 +128,7 @@ func (compression *Compression) CompressWithZLIB(ctx interfaces.AppFunctionConte
 
 }
 
-func bytesBufferToBase64(buf bytes.Buffer) []byte {
+func bytesBufferToBase64(buf *bytes.Buffer) []byte {
 	dst := make([]byte, base64.StdEncoding.EncodedLen(buf.Len()))
 	base64.StdEncoding.Encode(dst, buf.Bytes())
 	return dst
@@ -17,17 +17,22 @@
 package transforms
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/util"
 
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
 )
 
-// Conversion houses various built in conversion transforms (XML, JSON, CSV)
+// ContentTypeNDJSON is the content type for newline-delimited JSON
+const ContentTypeNDJSON = "application/x-ndjson"
+
+// Conversion houses various built in conversion transforms (XML, JSON, CSV, NDJSON, V1 Event)
 type Conversion struct {
 }
 
@@ -76,3 +81,84 @@ func (f Conversion) TransformToJSON(ctx interfaces.AppFunctionContext, data inte
 	}
 	return false, errors.New("Unexpected type received")
 }
+
+// TransformToV1Event transforms an EdgeX event to the JSON of the equivalent EdgeX V1 Event, for V1 consumers of the
+// exported data. The device profile and source names, which V1 Events have no place for, are dropped.
+// It will return an error and stop the pipeline if a non-edgex event is received or if no data is received.
+func (f Conversion) TransformToV1Event(ctx interfaces.AppFunctionContext, data interface{}) (continuePipeline bool, stringType interface{}) {
+	if data == nil {
+		return false, errors.New("No Event Received")
+	}
+
+	ctx.LoggingClient().Debug("Transforming to V1 Event")
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return false, errors.New("Unexpected type received")
+	}
+
+	v1Event, err := json.Marshal(util.ToV1Event(event))
+	if err != nil {
+		return false, fmt.Errorf("unable to marshal V1 Event to JSON: %s", err.Error())
+	}
+
+	ctx.SetResponseContentType(common.ContentTypeJSON)
+	return true, string(v1Event)
+}
+
+// TransformToNDJSON transforms a batch of data, such as that output by the Batch function, to newline-delimited JSON.
+// Each item in the batch is written as a single line of compact JSON. Accepted data is [][]byte, []dtos.Event,
+// dtos.Event or a string/[]byte/json.Marshaller which contains a JSON array or a single JSON object.
+// It will return an error and stop the pipeline if no data is received or any item is not valid JSON.
+func (f Conversion) TransformToNDJSON(ctx interfaces.AppFunctionContext, data interface{}) (continuePipeline bool, result interface{}) {
+	if data == nil {
+		return false, errors.New("No Data Received")
+	}
+
+	ctx.LoggingClient().Debug("Transforming to NDJSON")
+
+	var items [][]byte
+
+	switch batch := data.(type) {
+	case [][]byte:
+		items = batch
+
+	case []dtos.Event:
+		for _, event := range batch {
+			item, err := json.Marshal(event)
+			if err != nil {
+				return false, fmt.Errorf("unable to marshal Event to JSON: %s", err.Error())
+			}
+			items = append(items, item)
+		}
+
+	default:
+		byteData, err := util.CoerceType(data)
+		if err != nil {
+			return false, err
+		}
+
+		byteData = bytes.TrimSpace(byteData)
+		if len(byteData) > 0 && byteData[0] == '[' {
+			var rawItems []json.RawMessage
+			if err := json.Unmarshal(byteData, &rawItems); err != nil {
+				return false, fmt.Errorf("unable to unmarshal JSON array: %s", err.Error())
+			}
+			for _, item := range rawItems {
+				items = append(items, item)
+			}
+		} else {
+			items = append(items, byteData)
+		}
+	}
+
+	var buffer bytes.Buffer
+	for index, item := range items {
+		if err := json.Compact(&buffer, item); err != nil {
+			return false, fmt.Errorf("item #%d is not valid JSON: %s", index, err.Error())
+		}
+		buffer.WriteByte('\n')
+	}
+
+	ctx.SetResponseContentType(ContentTypeNDJSON)
+	return true, buffer.Bytes()
+}
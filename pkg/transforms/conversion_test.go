@@ -17,8 +17,11 @@
 package transforms
 
 import (
+	"encoding/json"
 	"testing"
 
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/util"
+
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
 
@@ -88,3 +91,74 @@ func TestTransformToJSONNotAnEvent(t *testing.T) {
 	require.EqualError(t, result.(error), "Unexpected type received")
 	assert.False(t, continuePipeline)
 }
+
+func TestTransformToV1Event(t *testing.T) {
+	eventIn := dtos.NewEvent("profile", deviceName1, "source")
+	require.NoError(t, eventIn.AddSimpleReading("source", common.ValueTypeInt32, int32(42)))
+
+	conv := NewConversion()
+	continuePipeline, result := conv.TransformToV1Event(ctx, eventIn)
+	require.True(t, continuePipeline)
+	assert.Equal(t, common.ContentTypeJSON, ctx.ResponseContentType())
+
+	v1Event := util.V1Event{}
+	require.NoError(t, json.Unmarshal([]byte(result.(string)), &v1Event))
+	assert.Equal(t, eventIn.Id, v1Event.ID)
+	assert.Equal(t, deviceName1, v1Event.Device)
+	require.Len(t, v1Event.Readings, 1)
+	assert.Equal(t, "source", v1Event.Readings[0].Name)
+	assert.Equal(t, "42", v1Event.Readings[0].Value)
+}
+
+func TestTransformToV1EventNotAnEvent(t *testing.T) {
+	conv := NewConversion()
+	continuePipeline, result := conv.TransformToV1Event(ctx, "")
+	require.EqualError(t, result.(error), "Unexpected type received")
+	assert.False(t, continuePipeline)
+}
+
+func TestTransformToNDJSON(t *testing.T) {
+	eventOne := dtos.Event{DeviceName: deviceName1}
+	eventTwo := dtos.Event{DeviceName: deviceName2}
+	expectedEventLines := `{"apiVersion":"","id":"","deviceName":"device1","profileName":"","sourceName":"","origin":0,"readings":null}` + "\n" +
+		`{"apiVersion":"","id":"","deviceName":"device2","profileName":"","sourceName":"","origin":0,"readings":null}` + "\n"
+
+	tests := []struct {
+		Name     string
+		Data     interface{}
+		Expected string
+	}{
+		{"Batch of byte arrays", [][]byte{[]byte(`{"a": 1}`), []byte("{\n\"b\": 2\n}")}, "{\"a\":1}\n{\"b\":2}\n"},
+		{"Slice of Events", []dtos.Event{eventOne, eventTwo}, expectedEventLines},
+		{"JSON array string", `[{"a": 1}, {"b": 2}]`, "{\"a\":1}\n{\"b\":2}\n"},
+		{"Single JSON object", []byte(`{"a": 1}`), "{\"a\":1}\n"},
+		{"Single Event", eventOne, expectedEventLines[:len(expectedEventLines)/2]},
+	}
+
+	conv := NewConversion()
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			continuePipeline, result := conv.TransformToNDJSON(ctx, test.Data)
+			require.True(t, continuePipeline)
+			assert.Equal(t, test.Expected, string(result.([]byte)))
+			assert.Equal(t, ContentTypeNDJSON, ctx.ResponseContentType())
+		})
+	}
+}
+
+func TestTransformToNDJSONNoData(t *testing.T) {
+	conv := NewConversion()
+	continuePipeline, result := conv.TransformToNDJSON(ctx, nil)
+
+	require.EqualError(t, result.(error), "No Data Received")
+	assert.False(t, continuePipeline)
+}
+
+func TestTransformToNDJSONInvalidItem(t *testing.T) {
+	conv := NewConversion()
+	continuePipeline, result := conv.TransformToNDJSON(ctx, [][]byte{[]byte(`{"a": 1}`), []byte("not json")})
+
+	require.Error(t, result.(error))
+	assert.Contains(t, result.(error).Error(), "item #1 is not valid JSON")
+	assert.False(t, continuePipeline)
+}
@@ -0,0 +1,172 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+)
+
+// CorrelationKeyTag is the tag added to correlated Events holding the value of the grouping key
+const CorrelationKeyTag = "CorrelationKey"
+
+type correlationGroup struct {
+	started  time.Time
+	first    dtos.Event
+	readings map[string]dtos.BaseReading
+}
+
+// Correlator correlates readings from multiple Events, i.e. from paired sensors, that share the same grouping key
+// into a single combined Event. A combined Event is emitted once readings for all the expected resources have been
+// received within the timeout. Groups that time out before completing are discarded.
+type Correlator struct {
+	keyField      string
+	resourceNames []string
+	timeout       time.Duration
+	mutex         sync.Mutex
+	groups        map[string]*correlationGroup
+	now           func() time.Time
+}
+
+// NewCorrelator creates, initializes and returns a new instance of Correlator. The keyField is one of the
+// LookupKey fields, i.e. LookupKeyProfileName or LookupKeyTagPrefix followed by a tag name, and resourceNames
+// are the resources that must all be received for a group to be complete.
+func NewCorrelator(keyField string, resourceNames []string, timeout time.Duration) (*Correlator, error) {
+	normalizedKeyField, err := normalizeKeyField(keyField)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resourceNames) < 2 {
+		return nil, errors.New("at least two resource names must be specified to correlate")
+	}
+
+	if timeout <= 0 {
+		return nil, fmt.Errorf("timeout must be greater than zero, got %s", timeout.String())
+	}
+
+	return &Correlator{
+		keyField:      normalizedKeyField,
+		resourceNames: resourceNames,
+		timeout:       timeout,
+		groups:        make(map[string]*correlationGroup),
+		now:           time.Now,
+	}, nil
+}
+
+// Correlate adds the readings of interest from the Event received to the group for the Event's grouping key.
+// When the group is complete the pipeline continues with the combined Event, which has the names and origin of the
+// group's first Event, one reading per expected resource and the CorrelationKeyTag tag. Otherwise the pipeline stops.
+// The latest reading is kept when a resource is received more than once for a group.
+// It will return an error and stop the pipeline if a non-edgex event is received or if no data is received.
+func (correlator *Correlator) Correlate(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, errors.New("No Event Received")
+	}
+
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return false, errors.New("type received is not an Event")
+	}
+
+	lc := ctx.LoggingClient()
+
+	key := eventKeyValue(event, correlator.keyField)
+	if len(key) == 0 {
+		lc.Debugf("Event for device '%s' has no value for grouping key '%s'", event.DeviceName, correlator.keyField)
+		return false, nil
+	}
+
+	correlator.mutex.Lock()
+	defer correlator.mutex.Unlock()
+
+	now := correlator.now()
+	correlator.expireGroups(ctx, now)
+
+	group, exists := correlator.groups[key]
+	for _, reading := range event.Readings {
+		if !correlator.isResourceOfInterest(reading.ResourceName) {
+			continue
+		}
+
+		if !exists {
+			group = &correlationGroup{
+				started:  now,
+				first:    event,
+				readings: make(map[string]dtos.BaseReading),
+			}
+			correlator.groups[key] = group
+			exists = true
+		}
+
+		group.readings[reading.ResourceName] = reading
+	}
+
+	if !exists || len(group.readings) < len(correlator.resourceNames) {
+		return false, nil
+	}
+
+	delete(correlator.groups, key)
+
+	combined := dtos.NewEvent(group.first.ProfileName, group.first.DeviceName, group.first.SourceName)
+	combined.Origin = group.first.Origin
+	combined.Tags = map[string]string{CorrelationKeyTag: key}
+
+	resourceNames := make([]string, 0, len(group.readings))
+	for name := range group.readings {
+		resourceNames = append(resourceNames, name)
+	}
+	sort.Strings(resourceNames)
+
+	for _, name := range resourceNames {
+		combined.Readings = append(combined.Readings, group.readings[name])
+	}
+
+	lc.Debugf("Correlated %d readings for grouping key '%s'", len(combined.Readings), key)
+
+	return true, combined
+}
+
+func (correlator *Correlator) expireGroups(ctx interfaces.AppFunctionContext, now time.Time) {
+	for key, group := range correlator.groups {
+		if now.Sub(group.started) >= correlator.timeout {
+			ctx.LoggingClient().Debugf(
+				"Discarding incomplete correlation group for key '%s' with %d of %d readings after timeout",
+				key,
+				len(group.readings),
+				len(correlator.resourceNames))
+			delete(correlator.groups, key)
+		}
+	}
+}
+
+func (correlator *Correlator) isResourceOfInterest(resourceName string) bool {
+	for _, name := range correlator.resourceNames {
+		if name == resourceName {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,144 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCorrelator(t *testing.T) {
+	tests := []struct {
+		Name          string
+		KeyField      string
+		ResourceNames []string
+		Timeout       time.Duration
+		ExpectError   bool
+	}{
+		{"Valid", "tag:pairId", []string{"temperature", "humidity"}, time.Second, false},
+		{"Bad key field", "bogus", []string{"temperature", "humidity"}, time.Second, true},
+		{"Single resource", LookupKeyProfileName, []string{"temperature"}, time.Second, true},
+		{"Zero timeout", LookupKeyProfileName, []string{"temperature", "humidity"}, 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := NewCorrelator(test.KeyField, test.ResourceNames, test.Timeout)
+			assert.Equal(t, test.ExpectError, err != nil)
+		})
+	}
+}
+
+func TestCorrelate(t *testing.T) {
+	correlator, err := NewCorrelator("tag:pairId", []string{"temperature", "humidity"}, 10*time.Second)
+	require.NoError(t, err)
+
+	now := time.Now()
+	correlator.now = func() time.Time { return now }
+
+	event := newTestEvent(t, deviceName1, map[string]string{"pairId": "A"}, floatReading("temperature", 20))
+	continuePipeline, result := correlator.Correlate(ctx, event)
+	assert.False(t, continuePipeline)
+	assert.Nil(t, result)
+
+	// Different group
+	event = newTestEvent(t, deviceName1, map[string]string{"pairId": "B"}, floatReading("humidity", 60))
+	continuePipeline, _ = correlator.Correlate(ctx, event)
+	assert.False(t, continuePipeline)
+
+	// Latest reading for a resource is kept
+	now = now.Add(time.Second)
+	temperature := newTestEvent(t, deviceName1, map[string]string{"pairId": "A"}, floatReading("temperature", 21))
+	continuePipeline, _ = correlator.Correlate(ctx, temperature)
+	assert.False(t, continuePipeline)
+
+	humidity := newTestEvent(t, deviceName2, map[string]string{"pairId": "A"}, floatReading("humidity", 55))
+	continuePipeline, result = correlator.Correlate(ctx, humidity)
+	require.True(t, continuePipeline)
+
+	combined := result.(dtos.Event)
+	assert.Equal(t, deviceName1, combined.DeviceName)
+	assert.Equal(t, "A", combined.Tags[CorrelationKeyTag])
+	require.Len(t, combined.Readings, 2)
+	assert.Equal(t, "humidity", combined.Readings[0].ResourceName)
+	assert.Equal(t, deviceName2, combined.Readings[0].DeviceName)
+	assert.Equal(t, humidity.Readings[0].Value, combined.Readings[0].Value)
+	assert.Equal(t, "temperature", combined.Readings[1].ResourceName)
+	assert.Equal(t, temperature.Readings[0].Value, combined.Readings[1].Value)
+
+	// Completed group starts over
+	event = newTestEvent(t, deviceName2, map[string]string{"pairId": "A"}, floatReading("humidity", 56))
+	continuePipeline, _ = correlator.Correlate(ctx, event)
+	assert.False(t, continuePipeline)
+}
+
+func TestCorrelateTimeout(t *testing.T) {
+	correlator, err := NewCorrelator("tag:pairId", []string{"temperature", "humidity"}, 10*time.Second)
+	require.NoError(t, err)
+
+	now := time.Now()
+	correlator.now = func() time.Time { return now }
+
+	event := newTestEvent(t, deviceName1, map[string]string{"pairId": "A"}, floatReading("temperature", 20))
+	continuePipeline, _ := correlator.Correlate(ctx, event)
+	assert.False(t, continuePipeline)
+
+	// Group timed out so humidity starts a new group
+	now = now.Add(10 * time.Second)
+	event = newTestEvent(t, deviceName2, map[string]string{"pairId": "A"}, floatReading("humidity", 55))
+	continuePipeline, _ = correlator.Correlate(ctx, event)
+	assert.False(t, continuePipeline)
+
+	event = newTestEvent(t, deviceName1, map[string]string{"pairId": "A"}, floatReading("temperature", 22))
+	continuePipeline, result := correlator.Correlate(ctx, event)
+	require.True(t, continuePipeline)
+	assert.Equal(t, deviceName2, result.(dtos.Event).DeviceName)
+}
+
+func TestCorrelateIgnoresEvents(t *testing.T) {
+	correlator, err := NewCorrelator("tag:pairId", []string{"temperature", "humidity"}, 10*time.Second)
+	require.NoError(t, err)
+
+	// No grouping key
+	continuePipeline, result := correlator.Correlate(ctx, dtos.NewEvent("profile", deviceName1, "source"))
+	assert.False(t, continuePipeline)
+	assert.Nil(t, result)
+
+	// Resource not of interest doesn't start a group
+	event := newTestEvent(t, deviceName1, map[string]string{"pairId": "A"}, floatReading("pressure", 1013))
+	continuePipeline, _ = correlator.Correlate(ctx, event)
+	assert.False(t, continuePipeline)
+	assert.Len(t, correlator.groups, 0)
+}
+
+func TestCorrelateNoData(t *testing.T) {
+	correlator, err := NewCorrelator(LookupKeyProfileName, []string{"temperature", "humidity"}, time.Second)
+	require.NoError(t, err)
+
+	continuePipeline, result := correlator.Correlate(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.EqualError(t, result.(error), "No Event Received")
+
+	continuePipeline, result = correlator.Correlate(ctx, "not an event")
+	assert.False(t, continuePipeline)
+	assert.EqualError(t, result.(error), "type received is not an Event")
+}
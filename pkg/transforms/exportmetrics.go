@@ -0,0 +1,176 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+)
+
+// Names of the metrics recorded by the export functions, which are tagged with the sink and destination
+const (
+	ExportSucceededMetricName   = "ExportSucceeded"
+	ExportFailedMetricName      = "ExportFailed"
+	ExportRetriedMetricName     = "ExportRetried"
+	ExportPersistedMetricName   = "ExportPersisted"
+	ExportDroppedMetricName     = "ExportDropped"
+	ExportLastFailureMetricName = "ExportLastFailure"
+)
+
+// Tags of the export metrics
+const (
+	SinkTag        = "sink"
+	DestinationTag = "destination"
+)
+
+// Tags of the metrics of a pipeline function, i.e. SignatureMismatchesMetricName
+const (
+	PipelineTag = "pipeline"
+	FunctionTag = "function"
+	PositionTag = "position"
+)
+
+// Sinks the export metrics are tagged with
+const (
+	HTTPSink = "http"
+	MQTTSink = "mqtt"
+	FileSink = "file"
+)
+
+// exportMetrics are the metrics of the exports to a destination. The last failure is the Unix time in seconds of the
+// most recent failed export.
+type exportMetrics struct {
+	succeeded   interfaces.Counter
+	failed      interfaces.Counter
+	retried     interfaces.Counter
+	persisted   interfaces.Counter
+	dropped     interfaces.Counter
+	lastFailure interfaces.Gauge
+}
+
+// exportMetricsRegistry holds the export metrics created for each MetricsManager, sink and destination, so the
+// metrics are shared by all the export functions sending to the same destination
+var exportMetricsRegistry = struct {
+	mutex   sync.Mutex
+	metrics map[interfaces.MetricsManager]map[string]*exportMetrics
+}{metrics: make(map[interfaces.MetricsManager]map[string]*exportMetrics)}
+
+// recordExport records an export to the destination of the sink, which failed when the error isn't nil. Exports of
+// data stored for retry by Store and Forward are also recorded as retried.
+func recordExport(ctx interfaces.AppFunctionContext, sink string, destination string, err error) {
+	metrics := exportMetricsFor(ctx, sink, destination)
+	if metrics == nil {
+		return
+	}
+
+	if _, retrying := ctx.GetValue(interfaces.RETRYATTEMPT); retrying {
+		metrics.retried.Inc(1)
+	}
+
+	if err != nil {
+		metrics.failed.Inc(1)
+		metrics.lastFailure.Update(time.Now().Unix())
+		return
+	}
+
+	metrics.succeeded.Inc(1)
+}
+
+// recordExportPersisted records data which failed to export to the destination of the sink being stored for retry by
+// Store and Forward
+func recordExportPersisted(ctx interfaces.AppFunctionContext, sink string, destination string) {
+	if metrics := exportMetricsFor(ctx, sink, destination); metrics != nil {
+		metrics.persisted.Inc(1)
+	}
+}
+
+// recordExportDropped records the count of the data which failed to export to the destination of the sink being
+// dropped since it can't be stored for retry by Store and Forward, i.e. data exported in batches after the pipeline
+// completed
+func recordExportDropped(ctx interfaces.AppFunctionContext, sink string, destination string, count int) {
+	if metrics := exportMetricsFor(ctx, sink, destination); metrics != nil {
+		metrics.dropped.Inc(int64(count))
+	}
+}
+
+// exportMetricsFor returns the metrics of the destination of the sink, which are created and registered the first
+// time they are used. Returns nil when no MetricsManager is available.
+func exportMetricsFor(ctx interfaces.AppFunctionContext, sink string, destination string) *exportMetrics {
+	manager := ctx.MetricsManager()
+	if manager == nil {
+		return nil
+	}
+
+	exportMetricsRegistry.mutex.Lock()
+	defer exportMetricsRegistry.mutex.Unlock()
+
+	destinations, found := exportMetricsRegistry.metrics[manager]
+	if !found {
+		destinations = make(map[string]*exportMetrics)
+		exportMetricsRegistry.metrics[manager] = destinations
+	}
+
+	key := sink + "|" + destination
+	if metrics, found := destinations[key]; found {
+		return metrics
+	}
+
+	metrics := &exportMetrics{
+		succeeded:   manager.NewCounter(),
+		failed:      manager.NewCounter(),
+		retried:     manager.NewCounter(),
+		persisted:   manager.NewCounter(),
+		dropped:     manager.NewCounter(),
+		lastFailure: manager.NewGauge(),
+	}
+	destinations[key] = metrics
+
+	tags := map[string]string{
+		SinkTag:        sink,
+		DestinationTag: destination,
+	}
+	registrations := map[string]interface{}{
+		ExportSucceededMetricName:   metrics.succeeded,
+		ExportFailedMetricName:      metrics.failed,
+		ExportRetriedMetricName:     metrics.retried,
+		ExportPersistedMetricName:   metrics.persisted,
+		ExportDroppedMetricName:     metrics.dropped,
+		ExportLastFailureMetricName: metrics.lastFailure,
+	}
+	for name, metric := range registrations {
+		if err := manager.RegisterLabeled(name, metric, tags); err != nil {
+			ctx.LoggingClient().Warnf("Unable to register %s metric: %s", name, err.Error())
+		}
+	}
+
+	return metrics
+}
+
+// exportDestination returns the address the metrics of an export are tagged with, which excludes the credentials,
+// query and fragment of the address so they aren't reported
+func exportDestination(address string) string {
+	parsed, err := url.Parse(address)
+	if err != nil || len(parsed.Host) == 0 {
+		return address
+	}
+
+	destination := url.URL{Scheme: parsed.Scheme, Host: parsed.Host, Path: parsed.Path}
+	return destination.String()
+}
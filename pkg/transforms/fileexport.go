@@ -0,0 +1,248 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+)
+
+const rotatedFileTimeFormat = "20060102T150405.000000000"
+
+// FileExporterOptions contains all options available to the file exporter
+type FileExporterOptions struct {
+	// Directory the export files are written to. It is created if it doesn't exist.
+	Directory string `param:"outputdir,required"`
+	// FileName of the active export file. Rotated files are named with a timestamp between the base name and extension.
+	FileName string `param:"filename,required"`
+	// MaxSize in bytes of a file before it is rotated. Zero disables size based rotation.
+	MaxSize int64 `param:"maxsize"`
+	// RotationInterval is how long a file is written to before it is rotated. Zero disables time based rotation.
+	RotationInterval time.Duration `param:"rotationinterval"`
+	// Compress rotated files with gzip if true
+	Compress bool `param:"compressrotated"`
+	// MaxFiles is the number of rotated files retained, oldest are removed first. Zero retains all rotated files.
+	MaxFiles int `param:"maxfiles"`
+}
+
+// FileExporter appends pipeline output to a local file which is rotated based on size and/or time
+type FileExporter struct {
+	options FileExporterOptions
+	mutex   sync.Mutex
+	file    *os.File
+	size    int64
+	opened  time.Time
+	now     func() time.Time
+}
+
+// NewFileExporter creates, initializes and returns a new instance of FileExporter configured with provided options
+func NewFileExporter(options FileExporterOptions) (*FileExporter, error) {
+	if len(options.Directory) == 0 {
+		return nil, errors.New("export directory must be specified")
+	}
+
+	if len(options.FileName) == 0 || filepath.Base(options.FileName) != options.FileName {
+		return nil, fmt.Errorf("invalid export file name '%s'", options.FileName)
+	}
+
+	if options.MaxSize < 0 || options.RotationInterval < 0 || options.MaxFiles < 0 {
+		return nil, errors.New("max size, rotation interval and max files can not be negative")
+	}
+
+	return &FileExporter{
+		options: options,
+		now:     time.Now,
+	}, nil
+}
+
+// ExportToFile appends a string, []byte, or json.Marshaller type payload, followed by a newline, to the active
+// export file, first rotating the file if it has reached the max size or rotation interval. The data received is
+// passed on so that the export can be chained with other exports.
+func (exporter *FileExporter) ExportToFile(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, errors.New("No Data Received")
+	}
+
+	exportData, err := ctx.SerializedData(data)
+	if err != nil {
+		return false, err
+	}
+
+	exporter.mutex.Lock()
+	defer exporter.mutex.Unlock()
+
+	if exporter.file != nil && exporter.needsRotation(int64(len(exportData))+1) {
+		if err := exporter.rotate(ctx); err != nil {
+			return false, err
+		}
+	}
+
+	if exporter.file == nil {
+		if err := exporter.open(); err != nil {
+			return false, err
+		}
+	}
+
+	written, err := exporter.file.Write(append(exportData[:len(exportData):len(exportData)], '\n'))
+	exporter.size += int64(written)
+	recordExport(ctx, FileSink, exporter.activePath(), err)
+	if err != nil {
+		return false, fmt.Errorf("unable to write to export file: %s", err.Error())
+	}
+
+	ctx.LoggingClient().Debugf("Exported %d bytes to '%s'", written, exporter.file.Name())
+
+	return true, data
+}
+
+func (exporter *FileExporter) needsRotation(pendingSize int64) bool {
+	if exporter.options.MaxSize > 0 && exporter.size > 0 && exporter.size+pendingSize > exporter.options.MaxSize {
+		return true
+	}
+
+	if exporter.options.RotationInterval > 0 && exporter.now().Sub(exporter.opened) >= exporter.options.RotationInterval {
+		return true
+	}
+
+	return false
+}
+
+func (exporter *FileExporter) activePath() string {
+	return filepath.Join(exporter.options.Directory, exporter.options.FileName)
+}
+
+func (exporter *FileExporter) open() error {
+	if err := os.MkdirAll(exporter.options.Directory, 0750); err != nil {
+		return fmt.Errorf("unable to create export directory: %s", err.Error())
+	}
+
+	file, err := os.OpenFile(exporter.activePath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0640)
+	if err != nil {
+		return fmt.Errorf("unable to open export file: %s", err.Error())
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("unable to stat export file: %s", err.Error())
+	}
+
+	exporter.file = file
+	exporter.size = info.Size()
+	exporter.opened = exporter.now()
+	return nil
+}
+
+func (exporter *FileExporter) rotate(ctx interfaces.AppFunctionContext) error {
+	if err := exporter.file.Close(); err != nil {
+		return fmt.Errorf("unable to close export file: %s", err.Error())
+	}
+	exporter.file = nil
+
+	extension := filepath.Ext(exporter.options.FileName)
+	baseName := strings.TrimSuffix(exporter.options.FileName, extension)
+	rotatedPath := filepath.Join(
+		exporter.options.Directory,
+		fmt.Sprintf("%s-%s%s", baseName, exporter.now().UTC().Format(rotatedFileTimeFormat), extension))
+
+	if err := os.Rename(exporter.activePath(), rotatedPath); err != nil {
+		return fmt.Errorf("unable to rotate export file: %s", err.Error())
+	}
+
+	ctx.LoggingClient().Debugf("Rotated export file to '%s'", rotatedPath)
+
+	if exporter.options.Compress {
+		if err := compressFile(rotatedPath); err != nil {
+			// The rotated file is still intact so don't fail the export
+			ctx.LoggingClient().Errorf("Unable to compress rotated export file '%s': %s", rotatedPath, err.Error())
+		}
+	}
+
+	exporter.removeExpiredFiles(ctx, baseName, extension)
+	return nil
+}
+
+func (exporter *FileExporter) removeExpiredFiles(ctx interfaces.AppFunctionContext, baseName string, extension string) {
+	if exporter.options.MaxFiles == 0 {
+		return
+	}
+
+	entries, err := os.ReadDir(exporter.options.Directory)
+	if err != nil {
+		ctx.LoggingClient().Errorf("Unable to list export directory: %s", err.Error())
+		return
+	}
+
+	var rotated []string
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".gz")
+		if !entry.IsDir() && strings.HasPrefix(name, baseName+"-") && strings.HasSuffix(name, extension) {
+			rotated = append(rotated, entry.Name())
+		}
+	}
+
+	// Timestamp format sorts chronologically
+	sort.Strings(rotated)
+
+	for len(rotated) > exporter.options.MaxFiles {
+		expiredPath := filepath.Join(exporter.options.Directory, rotated[0])
+		if err := os.Remove(expiredPath); err != nil {
+			ctx.LoggingClient().Errorf("Unable to remove expired export file '%s': %s", expiredPath, err.Error())
+		}
+		rotated = rotated[1:]
+	}
+}
+
+func compressFile(path string) error {
+	source, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	target, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0640)
+	if err != nil {
+		_ = source.Close()
+		return err
+	}
+
+	writer := gzip.NewWriter(target)
+	if _, err = io.Copy(writer, source); err == nil {
+		err = writer.Close()
+	}
+	if closeErr := target.Close(); err == nil {
+		err = closeErr
+	}
+	// Source must be closed before it can be removed on all platforms
+	_ = source.Close()
+
+	if err != nil {
+		_ = os.Remove(path + ".gz")
+		return err
+	}
+
+	return os.Remove(path)
+}
@@ -0,0 +1,145 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func listExportFiles(t *testing.T, directory string) []string {
+	entries, err := os.ReadDir(directory)
+	require.NoError(t, err)
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestNewFileExporter(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Options     FileExporterOptions
+		ExpectError bool
+	}{
+		{"Valid", FileExporterOptions{Directory: "/tmp", FileName: "export.log", MaxSize: 1024, MaxFiles: 3}, false},
+		{"Missing directory", FileExporterOptions{FileName: "export.log"}, true},
+		{"Missing file name", FileExporterOptions{Directory: "/tmp"}, true},
+		{"File name with path", FileExporterOptions{Directory: "/tmp", FileName: "../export.log"}, true},
+		{"Negative max size", FileExporterOptions{Directory: "/tmp", FileName: "export.log", MaxSize: -1}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := NewFileExporter(test.Options)
+			assert.Equal(t, test.ExpectError, err != nil)
+		})
+	}
+}
+
+func TestExportToFileSizeRotation(t *testing.T) {
+	directory := t.TempDir()
+
+	exporter, err := NewFileExporter(FileExporterOptions{Directory: directory, FileName: "export.log", MaxSize: 10})
+	require.NoError(t, err)
+
+	now := time.Now()
+	exporter.now = func() time.Time { return now }
+
+	for _, data := range []string{"one", "two", "three"} {
+		continuePipeline, result := exporter.ExportToFile(ctx, data)
+		require.True(t, continuePipeline, "unexpected result: %v", result)
+		assert.Equal(t, data, result)
+		now = now.Add(time.Second)
+	}
+
+	files := listExportFiles(t, directory)
+	require.Len(t, files, 2)
+	assert.Equal(t, "export.log", files[1])
+	assert.True(t, strings.HasPrefix(files[0], "export-"))
+	assert.True(t, strings.HasSuffix(files[0], ".log"))
+
+	rotated, err := os.ReadFile(filepath.Join(directory, files[0]))
+	require.NoError(t, err)
+	assert.Equal(t, "one\ntwo\n", string(rotated))
+
+	active, err := os.ReadFile(filepath.Join(directory, files[1]))
+	require.NoError(t, err)
+	assert.Equal(t, "three\n", string(active))
+}
+
+func TestExportToFileTimeRotationCompressionAndRetention(t *testing.T) {
+	directory := t.TempDir()
+
+	exporter, err := NewFileExporter(FileExporterOptions{
+		Directory:        directory,
+		FileName:         "export.log",
+		RotationInterval: time.Minute,
+		Compress:         true,
+		MaxFiles:         2,
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	exporter.now = func() time.Time { return now }
+
+	for _, data := range []string{"one", "two", "three", "four"} {
+		continuePipeline, _ := exporter.ExportToFile(ctx, data)
+		require.True(t, continuePipeline)
+		now = now.Add(time.Minute)
+	}
+
+	// "one" has been removed by retention, "two" and "three" are rotated and compressed
+	files := listExportFiles(t, directory)
+	require.Len(t, files, 3)
+	assert.Equal(t, "export.log", files[2])
+
+	for index, expected := range []string{"two\n", "three\n"} {
+		require.True(t, strings.HasSuffix(files[index], ".log.gz"), files[index])
+
+		file, err := os.Open(filepath.Join(directory, files[index]))
+		require.NoError(t, err)
+		reader, err := gzip.NewReader(file)
+		require.NoError(t, err)
+		content, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		_ = file.Close()
+
+		assert.Equal(t, expected, string(content))
+	}
+}
+
+func TestExportToFileNoData(t *testing.T) {
+	exporter, err := NewFileExporter(FileExporterOptions{Directory: t.TempDir(), FileName: "export.log"})
+	require.NoError(t, err)
+
+	continuePipeline, result := exporter.ExportToFile(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.EqualError(t, result.(error), "No Data Received")
+}
@@ -25,7 +25,6 @@ import (
 	"net/url"
 
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
-	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/util"
 
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
 )
@@ -41,6 +40,8 @@ type HTTPSender struct {
 	secretName          string
 	secretPath          string
 	urlFormatter        StringValuesFormatter
+	signatureHeaderName string
+	preserveCBOR        bool
 }
 
 // NewHTTPSender creates, initializes and returns a new instance of HTTPSender
@@ -76,31 +77,39 @@ func NewHTTPSenderWithOptions(options HTTPSenderOptions) HTTPSender {
 		secretName:          options.SecretName,
 		secretPath:          options.SecretPath,
 		urlFormatter:        options.URLFormatter,
+		signatureHeaderName: options.SignatureHeaderName,
+		preserveCBOR:        options.PreserveCBOR,
 	}
 }
 
 // HTTPSenderOptions contains all options available to the sender
 type HTTPSenderOptions struct {
 	// URL of destination
-	URL string
+	URL string `param:"url,required"`
 	// MimeType to send to destination
-	MimeType string
+	MimeType string `param:"mimetype,required"`
 	// PersistOnError enables use of store & forward loop if true
-	PersistOnError bool
+	PersistOnError bool `param:"persistonerror"`
 	// HTTPHeaderName to use for passing configured secret
-	HTTPHeaderName string
+	HTTPHeaderName string `param:"headername"`
 	// SecretPath to search for configured secret
-	SecretPath string
+	SecretPath string `param:"secretpath"`
 	// SecretName for configured secret
-	SecretName string
+	SecretName string `param:"secretname"`
 	// URLFormatter specifies custom formatting behavior to be applied to configured URL.
 	// If nothing specified, default behavior is to attempt to replace placeholders in the
 	// form '{some-context-key}' with the values found in the context storage.
 	URLFormatter StringValuesFormatter
 	// ContinueOnSendError allows execution of subsequent chained senders after errors if true
-	ContinueOnSendError bool
+	ContinueOnSendError bool `param:"continueonsenderror"`
 	// ReturnInputData enables chaining multiple HTTP senders if true
-	ReturnInputData bool
+	ReturnInputData bool `param:"returninputdata"`
+	// SignatureHeaderName is the HTTP header used to send the signature stored in the context by a preceding
+	// HMACSigner using SignatureModeHeader. No signature header is sent if empty.
+	SignatureHeaderName string `param:"signatureheadername"`
+	// PreserveCBOR sends Events with binary readings received as CBOR in CBOR, rather than JSON, with the CBOR
+	// content type rather than MimeType
+	PreserveCBOR bool `param:"preservecbor"`
 }
 
 // HTTPPost will send data from the previous function to the specified Endpoint via http POST.
@@ -139,11 +148,22 @@ func (sender HTTPSender) httpSend(ctx interfaces.AppFunctionContext, data interf
 		sender.mimeType = "application/json"
 	}
 
-	exportData, err := util.CoerceType(data)
+	var exportData []byte
+	var isCBOR bool
+	var err error
+	if sender.preserveCBOR {
+		exportData, isCBOR, err = ctx.SerializedCBORData(data)
+	} else {
+		exportData, err = ctx.SerializedData(data)
+	}
 	if err != nil {
 		return false, err
 	}
 
+	if isCBOR {
+		sender.mimeType = common.ContentTypeCBOR
+	}
+
 	usingSecrets, err := sender.determineIfUsingSecrets()
 	if err != nil {
 		return false, err
@@ -161,7 +181,7 @@ func (sender HTTPSender) httpSend(ctx interfaces.AppFunctionContext, data interf
 		return false, err
 	}
 
-	client := &http.Client{}
+	client := ctx.HttpClient()
 	req, err := http.NewRequest(method, parsedUrl.String(), bytes.NewReader(exportData))
 	if err != nil {
 		return false, err
@@ -183,8 +203,18 @@ func (sender HTTPSender) httpSend(ctx interfaces.AppFunctionContext, data interf
 
 	req.Header.Set("Content-Type", sender.mimeType)
 
+	if len(sender.signatureHeaderName) != 0 {
+		signature, found := ctx.GetValue(SignatureContextKey)
+		if !found {
+			return false, fmt.Errorf("signature header '%s' specified but no signature found in context", sender.signatureHeaderName)
+		}
+		req.Header.Set(sender.signatureHeaderName, signature)
+	}
+
 	ctx.LoggingClient().Debugf("POSTing data to %s", sender.url)
 
+	destination := exportDestination(parsedUrl.String())
+
 	response, err := client.Do(req)
 	// Pipeline continues if we get a 2xx response, non-2xx response may stop pipeline
 	if err != nil || response.StatusCode < 200 || response.StatusCode >= 300 {
@@ -193,11 +223,12 @@ func (sender HTTPSender) httpSend(ctx interfaces.AppFunctionContext, data interf
 		} else {
 			err = fmt.Errorf("export failed: %w", err)
 		}
+		recordExport(ctx, HTTPSink, destination, err)
 
 		// If continuing on send error then can't be persisting on error since Store and Forward retries starting
 		// with the function that failed and stopped the execution of the pipeline.
 		if !sender.continueOnSendError {
-			sender.setRetryData(ctx, exportData)
+			sender.setRetryData(ctx, destination, exportData)
 			return false, err
 		}
 
@@ -211,6 +242,7 @@ func (sender HTTPSender) httpSend(ctx interfaces.AppFunctionContext, data interf
 
 	ctx.LoggingClient().Debugf("Sent %s bytes of data. Response status is %s", len(exportData), response.Status)
 	ctx.LoggingClient().Trace("Data exported", "Transport", "HTTP", common.CorrelationHeader, ctx.CorrelationID)
+	recordExport(ctx, HTTPSink, destination, nil)
 
 	// This allows multiple HTTP Exports to be chained in the pipeline to send the same data to different destinations
 	// Don't need to read the response data since not going to return it so just return now.
@@ -222,7 +254,7 @@ func (sender HTTPSender) httpSend(ctx interfaces.AppFunctionContext, data interf
 	responseData, errReadingBody := io.ReadAll(response.Body)
 	if errReadingBody != nil {
 		// Can't have continueOnSendError=true when returnInputData=false, so no need to check for it here
-		sender.setRetryData(ctx, exportData)
+		sender.setRetryData(ctx, destination, exportData)
 		return false, errReadingBody
 	}
 
@@ -255,8 +287,9 @@ func (sender HTTPSender) determineIfUsingSecrets() (bool, error) {
 	return true, nil
 }
 
-func (sender HTTPSender) setRetryData(ctx interfaces.AppFunctionContext, exportData []byte) {
+func (sender HTTPSender) setRetryData(ctx interfaces.AppFunctionContext, destination string, exportData []byte) {
 	if sender.persistOnError {
 		ctx.SetRetryData(exportData)
+		recordExportPersisted(ctx, HTTPSink, destination)
 	}
 }
@@ -17,6 +17,7 @@
 package transforms
 
 import (
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
@@ -26,8 +27,13 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/appfunction"
+
 	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/fxamacker/cbor/v2"
 
 	mocks2 "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/interfaces/mocks"
 	"github.com/stretchr/testify/assert"
@@ -284,3 +290,33 @@ func TestHTTPPutInvalidParameter(t *testing.T) {
 	assert.Equal(t, "marshaling input data to JSON failed, "+
 		"passed in data must be of type []byte, string, or support marshaling to JSON", result.(error).Error())
 }
+
+func TestHTTPPostPreserveCBOR(t *testing.T) {
+	var contentType string
+	var body []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		contentType = request.Header.Get("Content-Type")
+		body, _ = io.ReadAll(request.Body)
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	event := dtos.NewEvent("camera", "camera-1", "image")
+	event.AddBinaryReading("image", []byte{0xff, 0xd8, 0xff, 0xe0}, "image/jpeg")
+	cborCtx := appfunction.NewContext("123", dic, common.ContentTypeCBOR)
+
+	// CBOR is only sent when opted in, with the CBOR content type
+	sender := NewHTTPSender(ts.URL, common.ContentTypeJSON, false)
+	continuePipeline, result := sender.HTTPPost(cborCtx, event)
+	require.True(t, continuePipeline, "unexpected result: %v", result)
+	assert.Equal(t, common.ContentTypeJSON, contentType)
+	assert.True(t, json.Valid(body))
+
+	sender = NewHTTPSenderWithOptions(HTTPSenderOptions{URL: ts.URL, MimeType: common.ContentTypeJSON, PreserveCBOR: true})
+	continuePipeline, result = sender.HTTPPost(cborCtx, event)
+	require.True(t, continuePipeline, "unexpected result: %v", result)
+	assert.Equal(t, common.ContentTypeCBOR, contentType)
+	actual := dtos.Event{}
+	require.NoError(t, cbor.Unmarshal(body, &actual))
+	assert.Equal(t, event.Readings[0].BinaryValue, actual.Readings[0].BinaryValue)
+}
@@ -0,0 +1,251 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+)
+
+// HTTPBatchSenderOptions contains all options available to the batch sender
+type HTTPBatchSenderOptions struct {
+	// URL of destination the batches are POSTed to
+	URL string `param:"url,required"`
+	// HTTPHeaderName to use for passing configured secret
+	HTTPHeaderName string `param:"headername"`
+	// SecretPath to search for configured secret
+	SecretPath string `param:"secretpath"`
+	// SecretName for configured secret
+	SecretName string `param:"secretname"`
+	// MaxBatchSize is the most pipeline outputs sent in a single request
+	MaxBatchSize int `param:"maxbatchsize" default:"100"`
+	// MaxBatchInterval is the longest a pipeline output waits before the pending batch is sent
+	MaxBatchInterval time.Duration `param:"maxbatchinterval" default:"5s"`
+}
+
+// HTTPBatchSender coalesces the JSON output of many pipeline executions into a single JSON array which is POSTed
+// to the destination by a background worker, once the batch reaches the max size or max interval.
+type HTTPBatchSender struct {
+	options HTTPBatchSenderOptions
+	mutex   sync.Mutex
+	pending [][]byte
+	ctx     interfaces.AppFunctionContext
+	flush   chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// NewHTTPBatchSender creates, initializes and returns a new instance of HTTPBatchSender configured with provided options
+func NewHTTPBatchSender(options HTTPBatchSenderOptions) (*HTTPBatchSender, error) {
+	if len(options.URL) == 0 {
+		return nil, errors.New("batch export URL must be specified")
+	}
+
+	if options.MaxBatchSize <= 0 || options.MaxBatchInterval <= 0 {
+		return nil, errors.New("max batch size and max batch interval must be greater than zero")
+	}
+
+	secretOptions := HTTPSender{
+		httpHeaderName: options.HTTPHeaderName,
+		secretPath:     options.SecretPath,
+		secretName:     options.SecretName,
+	}
+	if _, err := secretOptions.determineIfUsingSecrets(); err != nil {
+		return nil, err
+	}
+
+	return &HTTPBatchSender{
+		options: options,
+		flush:   make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}, nil
+}
+
+// HTTPBatchPost adds the JSON data from the previous function to the pending batch, which is sent as a JSON array
+// via http POST by a background worker. The batch is sent once it holds the max batch size outputs or the max batch
+// interval has passed, whichever is first, or when the sender is closed. The data received is passed on so that the
+// export can be chained with other functions. Since the data is sent after the pipeline has completed, Store and
+// Forward doesn't apply: true is returned once the data is added to the batch, and data in batches which fail to
+// send is dropped, which is logged and recorded in the ExportDropped metric.
+func (sender *HTTPBatchSender) HTTPBatchPost(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, errors.New("No Data Received")
+	}
+
+	exportData, err := ctx.SerializedData(data)
+	if err != nil {
+		return false, err
+	}
+
+	if !json.Valid(exportData) {
+		return false, errors.New("batched HTTP export data must be JSON")
+	}
+
+	sender.mutex.Lock()
+	defer sender.mutex.Unlock()
+
+	select {
+	case <-sender.done:
+		return false, errors.New("batched HTTP export has been closed")
+	default:
+	}
+
+	if sender.ctx == nil {
+		// The worker outlives the pipeline execution, so uses its own copy of the context for the HTTP client,
+		// secrets, logging and metrics without the values specific to this execution.
+		sender.ctx = ctx.Clone()
+		sender.ctx.RemoveValue(interfaces.DEVICENAME)
+		sender.ctx.RemoveValue(interfaces.RETRYATTEMPT)
+		go sender.worker()
+	}
+
+	// CoerceType may return the data's own byte slice, which the caller is free to reuse.
+	sender.pending = append(sender.pending, append([]byte{}, exportData...))
+	if len(sender.pending) >= sender.options.MaxBatchSize {
+		select {
+		case sender.flush <- struct{}{}:
+		default:
+		}
+	}
+
+	ctx.LoggingClient().Debugf("Added data to HTTP export batch of %d", len(sender.pending))
+
+	return true, data
+}
+
+// Close sends any pending data and stops the background worker. The service closes the senders created from the
+// pipeline configuration when the pipeline is reloaded without them and when it stops.
+func (sender *HTTPBatchSender) Close() {
+	sender.mutex.Lock()
+	select {
+	case <-sender.done:
+		sender.mutex.Unlock()
+		return
+	default:
+	}
+	close(sender.done)
+	started := sender.ctx != nil
+	sender.mutex.Unlock()
+
+	if started {
+		<-sender.stopped
+	}
+}
+
+func (sender *HTTPBatchSender) worker() {
+	defer close(sender.stopped)
+
+	ticker := time.NewTicker(sender.options.MaxBatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			sender.send()
+		case <-sender.flush:
+			sender.send()
+			ticker.Reset(sender.options.MaxBatchInterval)
+		case <-sender.done:
+			sender.send()
+			return
+		}
+	}
+}
+
+// send POSTs the pending data in batches of up to the max batch size
+func (sender *HTTPBatchSender) send() {
+	sender.mutex.Lock()
+	pending := sender.pending
+	sender.pending = nil
+	sender.mutex.Unlock()
+
+	for len(pending) > 0 {
+		size := len(pending)
+		if size > sender.options.MaxBatchSize {
+			size = sender.options.MaxBatchSize
+		}
+
+		sender.sendBatch(pending[:size])
+		pending = pending[size:]
+	}
+}
+
+func (sender *HTTPBatchSender) sendBatch(batch [][]byte) {
+	lc := sender.ctx.LoggingClient()
+	destination := exportDestination(sender.options.URL)
+
+	body := make([]byte, 0, batchBodySize(batch))
+	body = append(body, '[')
+	body = append(body, bytes.Join(batch, []byte{','})...)
+	body = append(body, ']')
+
+	err := sender.post(body)
+	recordExport(sender.ctx, HTTPSink, destination, err)
+	if err != nil {
+		recordExportDropped(sender.ctx, HTTPSink, destination, len(batch))
+		lc.Errorf("Dropped HTTP export batch of %d to %s: %s", len(batch), destination, err.Error())
+		return
+	}
+
+	lc.Debugf("Sent HTTP export batch of %d (%d bytes) to %s", len(batch), len(body), destination)
+}
+
+func (sender *HTTPBatchSender) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, sender.options.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	if len(sender.options.HTTPHeaderName) != 0 {
+		secrets, err := sender.ctx.GetSecret(sender.options.SecretPath, sender.options.SecretName)
+		if err != nil {
+			return err
+		}
+		req.Header.Set(sender.options.HTTPHeaderName, secrets[sender.options.SecretName])
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	response, err := sender.ctx.HttpClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("export failed with %d HTTP status code", response.StatusCode)
+	}
+
+	return nil
+}
+
+// batchBodySize returns the size of the JSON array holding the batch
+func batchBodySize(batch [][]byte) int {
+	size := len(batch) + 1
+	for _, data := range batch {
+		size += len(data)
+	}
+	return size
+}
@@ -0,0 +1,184 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/telemetry"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchServer records the JSON arrays POSTed to it
+type batchServer struct {
+	mutex   sync.Mutex
+	batches [][]json.RawMessage
+	status  int
+}
+
+func (server *batchServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	var batch []json.RawMessage
+	if r.Header.Get("Content-Type") != "application/json" || json.Unmarshal(body, &batch) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.batches = append(server.batches, batch)
+	if server.status != 0 {
+		w.WriteHeader(server.status)
+	}
+}
+
+func (server *batchServer) batchSizes() []int {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	sizes := make([]int, 0, len(server.batches))
+	for _, batch := range server.batches {
+		sizes = append(sizes, len(batch))
+	}
+	return sizes
+}
+
+func TestNewHTTPBatchSender(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Options     HTTPBatchSenderOptions
+		ExpectError bool
+	}{
+		{"Valid", HTTPBatchSenderOptions{URL: "http://localhost", MaxBatchSize: 10, MaxBatchInterval: time.Second}, false},
+		{"Valid with secret", HTTPBatchSenderOptions{URL: "http://localhost", MaxBatchSize: 10, MaxBatchInterval: time.Second, HTTPHeaderName: "Authorization", SecretPath: "cloud", SecretName: "token"}, false},
+		{"Missing URL", HTTPBatchSenderOptions{MaxBatchSize: 10, MaxBatchInterval: time.Second}, true},
+		{"Zero max batch size", HTTPBatchSenderOptions{URL: "http://localhost", MaxBatchInterval: time.Second}, true},
+		{"Zero max batch interval", HTTPBatchSenderOptions{URL: "http://localhost", MaxBatchSize: 10}, true},
+		{"Missing secret path", HTTPBatchSenderOptions{URL: "http://localhost", MaxBatchSize: 10, MaxBatchInterval: time.Second, HTTPHeaderName: "Authorization", SecretName: "token"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := NewHTTPBatchSender(test.Options)
+			assert.Equal(t, test.ExpectError, err != nil)
+		})
+	}
+}
+
+func TestHTTPBatchPostBySize(t *testing.T) {
+	server := &batchServer{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	sender, err := NewHTTPBatchSender(HTTPBatchSenderOptions{URL: ts.URL, MaxBatchSize: 3, MaxBatchInterval: time.Hour})
+	require.NoError(t, err)
+
+	for i := 0; i < 6; i++ {
+		continuePipeline, result := sender.HTTPBatchPost(ctx, `{"value":1}`)
+		require.True(t, continuePipeline)
+		assert.Equal(t, `{"value":1}`, result)
+	}
+
+	assert.Eventually(t, func() bool {
+		sizes := server.batchSizes()
+		return len(sizes) == 2 && sizes[0]+sizes[1] == 6
+	}, time.Second, 10*time.Millisecond)
+
+	sender.Close()
+}
+
+func TestHTTPBatchPostByInterval(t *testing.T) {
+	server := &batchServer{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	sender, err := NewHTTPBatchSender(HTTPBatchSenderOptions{URL: ts.URL, MaxBatchSize: 100, MaxBatchInterval: 50 * time.Millisecond})
+	require.NoError(t, err)
+	defer sender.Close()
+
+	sender.HTTPBatchPost(ctx, []byte(`{"value":1}`))
+	sender.HTTPBatchPost(ctx, []byte(`{"value":2}`))
+
+	assert.Eventually(t, func() bool {
+		sizes := server.batchSizes()
+		return len(sizes) == 1 && sizes[0] == 2
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestHTTPBatchPostClose(t *testing.T) {
+	server := &batchServer{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	sender, err := NewHTTPBatchSender(HTTPBatchSenderOptions{URL: ts.URL, MaxBatchSize: 100, MaxBatchInterval: time.Hour})
+	require.NoError(t, err)
+
+	sender.HTTPBatchPost(ctx, `{"value":1}`)
+	sender.Close()
+
+	// Pending data is sent when closed
+	assert.Equal(t, []int{1}, server.batchSizes())
+
+	continuePipeline, result := sender.HTTPBatchPost(ctx, `{"value":2}`)
+	assert.False(t, continuePipeline)
+	assert.Error(t, result.(error))
+
+	// Closing again does nothing
+	sender.Close()
+}
+
+func TestHTTPBatchPostInvalidData(t *testing.T) {
+	sender, err := NewHTTPBatchSender(HTTPBatchSenderOptions{URL: "http://localhost", MaxBatchSize: 10, MaxBatchInterval: time.Hour})
+	require.NoError(t, err)
+	defer sender.Close()
+
+	continuePipeline, result := sender.HTTPBatchPost(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.Error(t, result.(error))
+
+	continuePipeline, result = sender.HTTPBatchPost(ctx, "not json")
+	assert.False(t, continuePipeline)
+	assert.Error(t, result.(error))
+}
+
+func TestHTTPBatchPostMetrics(t *testing.T) {
+	server := &batchServer{status: http.StatusInternalServerError}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	manager := telemetry.NewMetricsManager()
+	metricsCtx := newMetricsContext(manager)
+
+	sender, err := NewHTTPBatchSender(HTTPBatchSenderOptions{URL: ts.URL, MaxBatchSize: 100, MaxBatchInterval: time.Hour})
+	require.NoError(t, err)
+
+	sender.HTTPBatchPost(metricsCtx, `{"value":1}`)
+	sender.HTTPBatchPost(metricsCtx, `{"value":2}`)
+	sender.Close()
+
+	// The failed batch is recorded once rather than once per pipeline output
+	counts := exportCounts(manager, exportDestination(ts.URL))
+	assert.Equal(t, int64(1), counts[ExportFailedMetricName])
+	assert.Equal(t, int64(2), counts[ExportDroppedMetricName])
+}
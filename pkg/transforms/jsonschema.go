@@ -0,0 +1,512 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// jsonSchema is a compiled JSON Schema. The validation keywords commonly used for payload contracts of JSON Schema
+// draft-07 are supported: type, enum, const, properties, required, additionalProperties, items (a single schema),
+// minItems, maxItems, minimum, maximum, exclusiveMinimum, exclusiveMaximum, minLength, maxLength, pattern, allOf,
+// anyOf, oneOf, not and local $ref. The boolean exclusiveMinimum and exclusiveMaximum of draft-04 are also accepted.
+// Patterns use the RE2 syntax of the regexp package rather than ECMA-262, so patterns using lookarounds or
+// backreferences are rejected. Any other keyword, apart from the annotations in jsonSchemaAnnotations, is rejected
+// when the schema is compiled rather than ignored, so that the schema isn't silently enforced only in part.
+type jsonSchema struct {
+	boolean              *bool
+	types                []string
+	enum                 []interface{}
+	hasConst             bool
+	constValue           interface{}
+	properties           map[string]*jsonSchema
+	required             []string
+	additionalProperties *jsonSchema
+	items                *jsonSchema
+	minItems             *int
+	maxItems             *int
+	minimum              *float64
+	maximum              *float64
+	exclusiveMinimum     *float64
+	exclusiveMaximum     *float64
+	minLength            *int
+	maxLength            *int
+	pattern              *regexp.Regexp
+	allOf                []*jsonSchema
+	anyOf                []*jsonSchema
+	oneOf                []*jsonSchema
+	not                  *jsonSchema
+	ref                  *jsonSchema
+}
+
+// jsonSchemaDrafts are the $schema values of the drafts whose keywords are compatible with those supported
+var jsonSchemaDrafts = map[string]bool{
+	"http://json-schema.org/draft-04/schema": true,
+	"http://json-schema.org/draft-06/schema": true,
+	"http://json-schema.org/draft-07/schema": true,
+}
+
+// jsonSchemaAnnotations are the keywords which don't affect validation, so are accepted and ignored. The format
+// keyword is an annotation, as allowed by the specification, so formats aren't validated.
+var jsonSchemaAnnotations = map[string]bool{
+	"$id":              true,
+	"id":               true,
+	"$comment":         true,
+	"title":            true,
+	"description":      true,
+	"default":          true,
+	"examples":         true,
+	"readOnly":         true,
+	"writeOnly":        true,
+	"format":           true,
+	"contentMediaType": true,
+	"contentEncoding":  true,
+	"definitions":      true,
+}
+
+type jsonSchemaCompiler struct {
+	root interface{}
+	refs map[string]*jsonSchema
+}
+
+// compileJSONSchema parses and compiles the JSON Schema document provided
+func compileJSONSchema(document []byte) (*jsonSchema, error) {
+	var root interface{}
+	if err := json.Unmarshal(document, &root); err != nil {
+		return nil, fmt.Errorf("unable to parse JSON Schema: %s", err.Error())
+	}
+
+	compiler := jsonSchemaCompiler{root: root, refs: make(map[string]*jsonSchema)}
+	return compiler.compile(root, "#")
+}
+
+func (compiler *jsonSchemaCompiler) compile(raw interface{}, location string) (*jsonSchema, error) {
+	schema := &jsonSchema{}
+
+	if boolean, ok := raw.(bool); ok {
+		schema.boolean = &boolean
+		return schema, nil
+	}
+
+	object, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("schema at '%s' must be an object or boolean", location)
+	}
+
+	if ref, found := object["$ref"]; found {
+		refValue, ok := ref.(string)
+		if !ok {
+			return nil, fmt.Errorf("$ref at '%s' must be a string", location)
+		}
+		resolved, err := compiler.resolve(refValue)
+		if err != nil {
+			return nil, err
+		}
+		schema.ref = resolved
+	}
+
+	var err error
+	var exclusiveMinimum, exclusiveMaximum bool
+	for keyword, value := range object {
+		keywordLocation := location + "/" + keyword
+		switch keyword {
+		case "$ref":
+			// Resolved above
+		case "$schema":
+			draft, ok := value.(string)
+			if !ok || !jsonSchemaDrafts[strings.TrimSuffix(strings.TrimSuffix(draft, "#"), "/")] {
+				return nil, fmt.Errorf("unsupported $schema '%v' at '%s', only draft-04 to draft-07 are supported", value, location)
+			}
+		case "type":
+			schema.types, err = schemaStrings(value, keywordLocation)
+		case "enum":
+			values, ok := value.([]interface{})
+			if !ok {
+				err = fmt.Errorf("'%s' must be an array", keywordLocation)
+			}
+			schema.enum = values
+		case "const":
+			schema.hasConst = true
+			schema.constValue = value
+		case "properties":
+			properties, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("'%s' must be an object", keywordLocation)
+			}
+			schema.properties = make(map[string]*jsonSchema, len(properties))
+			for name, property := range properties {
+				if schema.properties[name], err = compiler.compile(property, keywordLocation+"/"+name); err != nil {
+					return nil, err
+				}
+			}
+		case "required":
+			schema.required, err = schemaStrings(value, keywordLocation)
+		case "additionalProperties":
+			schema.additionalProperties, err = compiler.compile(value, keywordLocation)
+		case "items":
+			schema.items, err = compiler.compile(value, keywordLocation)
+		case "minItems":
+			schema.minItems, err = schemaInt(value, keywordLocation)
+		case "maxItems":
+			schema.maxItems, err = schemaInt(value, keywordLocation)
+		case "minLength":
+			schema.minLength, err = schemaInt(value, keywordLocation)
+		case "maxLength":
+			schema.maxLength, err = schemaInt(value, keywordLocation)
+		case "minimum":
+			schema.minimum, err = schemaNumber(value, keywordLocation)
+		case "maximum":
+			schema.maximum, err = schemaNumber(value, keywordLocation)
+		case "exclusiveMinimum":
+			// draft-04 specifies a boolean making the minimum exclusive
+			if exclusive, ok := value.(bool); ok {
+				exclusiveMinimum = exclusive
+			} else {
+				schema.exclusiveMinimum, err = schemaNumber(value, keywordLocation)
+			}
+		case "exclusiveMaximum":
+			if exclusive, ok := value.(bool); ok {
+				exclusiveMaximum = exclusive
+			} else {
+				schema.exclusiveMaximum, err = schemaNumber(value, keywordLocation)
+			}
+		case "pattern":
+			pattern, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("'%s' must be a string", keywordLocation)
+			}
+			if schema.pattern, err = regexp.Compile(pattern); err != nil {
+				err = fmt.Errorf(
+					"unsupported pattern at '%s', patterns use the RE2 syntax which has no lookarounds or backreferences: %s",
+					keywordLocation,
+					err.Error())
+			}
+		case "allOf":
+			schema.allOf, err = compiler.compileList(value, keywordLocation)
+		case "anyOf":
+			schema.anyOf, err = compiler.compileList(value, keywordLocation)
+		case "oneOf":
+			schema.oneOf, err = compiler.compileList(value, keywordLocation)
+		case "not":
+			schema.not, err = compiler.compile(value, keywordLocation)
+		default:
+			if !jsonSchemaAnnotations[keyword] {
+				return nil, fmt.Errorf("unsupported keyword '%s' at '%s'", keyword, location)
+			}
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if exclusiveMinimum {
+		if schema.minimum == nil {
+			return nil, fmt.Errorf("'%s/exclusiveMinimum' requires minimum", location)
+		}
+		schema.exclusiveMinimum, schema.minimum = schema.minimum, nil
+	}
+	if exclusiveMaximum {
+		if schema.maximum == nil {
+			return nil, fmt.Errorf("'%s/exclusiveMaximum' requires maximum", location)
+		}
+		schema.exclusiveMaximum, schema.maximum = schema.maximum, nil
+	}
+
+	return schema, nil
+}
+
+func (compiler *jsonSchemaCompiler) compileList(raw interface{}, location string) ([]*jsonSchema, error) {
+	values, ok := raw.([]interface{})
+	if !ok || len(values) == 0 {
+		return nil, fmt.Errorf("'%s' must be a non-empty array", location)
+	}
+
+	schemas := make([]*jsonSchema, len(values))
+	for index, value := range values {
+		schema, err := compiler.compile(value, location+"/"+strconv.Itoa(index))
+		if err != nil {
+			return nil, err
+		}
+		schemas[index] = schema
+	}
+
+	return schemas, nil
+}
+
+// resolve compiles the schema at the local JSON Pointer reference, i.e. '#/definitions/name'. References are
+// cached before being compiled so recursive schemas resolve to the same instance.
+func (compiler *jsonSchemaCompiler) resolve(ref string) (*jsonSchema, error) {
+	if schema, found := compiler.refs[ref]; found {
+		return schema, nil
+	}
+
+	if ref != "#" && !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported $ref '%s', only local references are supported", ref)
+	}
+
+	target := compiler.root
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#"), "/")[1:] {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch value := target.(type) {
+		case map[string]interface{}:
+			target = value[token]
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(value) {
+				return nil, fmt.Errorf("unable to resolve $ref '%s'", ref)
+			}
+			target = value[index]
+		default:
+			target = nil
+		}
+
+		if target == nil {
+			return nil, fmt.Errorf("unable to resolve $ref '%s'", ref)
+		}
+	}
+
+	schema := &jsonSchema{}
+	compiler.refs[ref] = schema
+
+	compiled, err := compiler.compile(target, ref)
+	if err != nil {
+		return nil, err
+	}
+	*schema = *compiled
+	return schema, nil
+}
+
+// validate returns a description of each violation of the schema by the value at the specified path
+func (schema *jsonSchema) validate(value interface{}, path string) []string {
+	if schema.boolean != nil {
+		if *schema.boolean {
+			return nil
+		}
+		return []string{fmt.Sprintf("%s: not allowed", path)}
+	}
+
+	var violations []string
+	addViolation := func(format string, args ...interface{}) {
+		violations = append(violations, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if schema.ref != nil {
+		violations = append(violations, schema.ref.validate(value, path)...)
+	}
+
+	if len(schema.types) > 0 {
+		matched := false
+		for _, schemaType := range schema.types {
+			if jsonTypeMatches(schemaType, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			addViolation("expected type %s but found %s", strings.Join(schema.types, " or "), jsonTypeOf(value))
+		}
+	}
+
+	if schema.enum != nil {
+		matched := false
+		for _, allowed := range schema.enum {
+			if reflect.DeepEqual(allowed, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			addViolation("value is not one of the allowed values")
+		}
+	}
+
+	if schema.hasConst && !reflect.DeepEqual(schema.constValue, value) {
+		addViolation("value does not match the constant value")
+	}
+
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for _, name := range schema.required {
+			if _, found := typed[name]; !found {
+				addViolation("missing required property '%s'", name)
+			}
+		}
+
+		// Sort property names so violations are reported in a consistent order
+		names := make([]string, 0, len(typed))
+		for name := range typed {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			propertyPath := path + "." + name
+			if property, found := schema.properties[name]; found {
+				violations = append(violations, property.validate(typed[name], propertyPath)...)
+			} else if schema.additionalProperties != nil {
+				violations = append(violations, schema.additionalProperties.validate(typed[name], propertyPath)...)
+			}
+		}
+
+	case []interface{}:
+		if schema.minItems != nil && len(typed) < *schema.minItems {
+			addViolation("expected at least %d items but found %d", *schema.minItems, len(typed))
+		}
+		if schema.maxItems != nil && len(typed) > *schema.maxItems {
+			addViolation("expected at most %d items but found %d", *schema.maxItems, len(typed))
+		}
+		if schema.items != nil {
+			for index, item := range typed {
+				violations = append(violations, schema.items.validate(item, fmt.Sprintf("%s[%d]", path, index))...)
+			}
+		}
+
+	case float64:
+		if schema.minimum != nil && typed < *schema.minimum {
+			addViolation("%v is less than the minimum of %v", typed, *schema.minimum)
+		}
+		if schema.maximum != nil && typed > *schema.maximum {
+			addViolation("%v is greater than the maximum of %v", typed, *schema.maximum)
+		}
+		if schema.exclusiveMinimum != nil && typed <= *schema.exclusiveMinimum {
+			addViolation("%v is not greater than the exclusive minimum of %v", typed, *schema.exclusiveMinimum)
+		}
+		if schema.exclusiveMaximum != nil && typed >= *schema.exclusiveMaximum {
+			addViolation("%v is not less than the exclusive maximum of %v", typed, *schema.exclusiveMaximum)
+		}
+
+	case string:
+		length := utf8.RuneCountInString(typed)
+		if schema.minLength != nil && length < *schema.minLength {
+			addViolation("expected a length of at least %d but found %d", *schema.minLength, length)
+		}
+		if schema.maxLength != nil && length > *schema.maxLength {
+			addViolation("expected a length of at most %d but found %d", *schema.maxLength, length)
+		}
+		if schema.pattern != nil && !schema.pattern.MatchString(typed) {
+			addViolation("value does not match the pattern '%s'", schema.pattern.String())
+		}
+	}
+
+	for _, subSchema := range schema.allOf {
+		violations = append(violations, subSchema.validate(value, path)...)
+	}
+
+	if schema.anyOf != nil {
+		matched := false
+		for _, subSchema := range schema.anyOf {
+			if len(subSchema.validate(value, path)) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			addViolation("value does not match any of the anyOf schemas")
+		}
+	}
+
+	if schema.oneOf != nil {
+		matches := 0
+		for _, subSchema := range schema.oneOf {
+			if len(subSchema.validate(value, path)) == 0 {
+				matches++
+			}
+		}
+		if matches != 1 {
+			addViolation("value matches %d of the oneOf schemas, expected exactly 1", matches)
+		}
+	}
+
+	if schema.not != nil && len(schema.not.validate(value, path)) == 0 {
+		addViolation("value must not match the 'not' schema")
+	}
+
+	return violations
+}
+
+func jsonTypeMatches(schemaType string, value interface{}) bool {
+	if schemaType == "integer" {
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number)
+	}
+
+	return schemaType == jsonTypeOf(value)
+}
+
+func jsonTypeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func schemaStrings(raw interface{}, location string) ([]string, error) {
+	switch value := raw.(type) {
+	case string:
+		return []string{value}, nil
+	case []interface{}:
+		result := make([]string, len(value))
+		for index, item := range value {
+			text, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("'%s' must only contain strings", location)
+			}
+			result[index] = text
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("'%s' must be a string or array of strings", location)
+	}
+}
+
+func schemaNumber(raw interface{}, location string) (*float64, error) {
+	number, ok := raw.(float64)
+	if !ok {
+		return nil, fmt.Errorf("'%s' must be a number", location)
+	}
+	return &number, nil
+}
+
+func schemaInt(raw interface{}, location string) (*int, error) {
+	number, ok := raw.(float64)
+	if !ok || number < 0 || number != math.Trunc(number) {
+		return nil, fmt.Errorf("'%s' must be a non-negative integer", location)
+	}
+	result := int(number)
+	return &result, nil
+}
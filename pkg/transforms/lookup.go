@@ -0,0 +1,209 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+)
+
+const (
+	// LookupKeyDeviceName uses the Event's device name as the lookup key
+	LookupKeyDeviceName = "devicename"
+	// LookupKeyProfileName uses the Event's profile name as the lookup key
+	LookupKeyProfileName = "profilename"
+	// LookupKeySourceName uses the Event's source name as the lookup key
+	LookupKeySourceName = "sourcename"
+	// LookupKeyTagPrefix is prefixed to a tag name to use the value of that Event tag as the lookup key
+	LookupKeyTagPrefix = "tag:"
+	// LookupKeyPlaceholder is replaced in the lookup URL with the URL escaped lookup key
+	LookupKeyPlaceholder = "{key}"
+)
+
+// HTTPLookup enriches Events with business context retrieved from an external HTTP endpoint. The endpoint is
+// called with a key taken from the Event and must respond with a JSON object, whose top level fields are merged
+// into the Event's tags. Responses are cached per key for the configured TTL, for up to 1000 keys.
+type HTTPLookup struct {
+	url       string
+	keyField  string
+	tagPrefix string
+	client    *http.Client
+	cache     *tagCache
+}
+
+// HTTPLookupOptions contains all options available to the HTTP lookup
+type HTTPLookupOptions struct {
+	// URL of the lookup endpoint. LookupKeyPlaceholder is replaced with the lookup key for each Event.
+	URL string `param:"url,required"`
+	// KeyField specifies which Event field is used as the lookup key. One of LookupKeyDeviceName,
+	// LookupKeyProfileName, LookupKeySourceName or LookupKeyTagPrefix followed by a tag name.
+	KeyField string `param:"lookupkey,required"`
+	// TagPrefix is optionally prefixed to the names of the tags added from the lookup response
+	TagPrefix string `param:"tagprefix"`
+	// Timeout for each lookup request. Zero means no timeout.
+	Timeout time.Duration `param:"requesttimeout" default:"10s"`
+	// CacheTTL is how long a lookup response is cached. Zero disables caching.
+	CacheTTL time.Duration `param:"cachettl" default:"1m"`
+}
+
+// NewHTTPLookup creates, initializes and returns a new instance of HTTPLookup configured with provided options
+func NewHTTPLookup(options HTTPLookupOptions) (*HTTPLookup, error) {
+	if len(options.URL) == 0 {
+		return nil, errors.New("lookup URL must be specified")
+	}
+
+	if !strings.Contains(options.URL, LookupKeyPlaceholder) {
+		return nil, fmt.Errorf("lookup URL must contain the '%s' placeholder", LookupKeyPlaceholder)
+	}
+
+	keyField, err := normalizeKeyField(options.KeyField)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HTTPLookup{
+		url:       options.URL,
+		keyField:  keyField,
+		tagPrefix: options.TagPrefix,
+		client:    &http.Client{Timeout: options.Timeout},
+		cache:     newTagCache(options.CacheTTL, maxTagCacheEntries),
+	}, nil
+}
+
+// LookupAndEnrich calls the lookup endpoint with the key from the Event and adds the fields of the JSON object
+// returned to the Event's tags. String values are added as is, all other values are added as JSON.
+// It will return an error and stop the pipeline if a non-edgex event is received, if no data is received,
+// if the Event has no value for the key field or if the lookup fails.
+func (lookup *HTTPLookup) LookupAndEnrich(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, errors.New("No Event Received")
+	}
+
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return false, errors.New("type received is not an Event")
+	}
+
+	key := eventKeyValue(event, lookup.keyField)
+	if len(key) == 0 {
+		return false, fmt.Errorf("event has no value for lookup key field '%s'", lookup.keyField)
+	}
+
+	tags, err := lookup.lookupTags(ctx, key)
+	if err != nil {
+		return false, err
+	}
+
+	// Don't modify the tags of the Event received since they may be shared with other functions
+	eventTags := make(map[string]string, len(event.Tags)+len(tags))
+	for tag, value := range event.Tags {
+		eventTags[tag] = value
+	}
+	for tag, value := range tags {
+		eventTags[tag] = value
+	}
+	event.Tags = eventTags
+
+	return true, event
+}
+
+// normalizeKeyField validates the key field is one of the LookupKey fields and returns it in normalized form
+func normalizeKeyField(keyField string) (string, error) {
+	normalized := strings.TrimSpace(keyField)
+	switch {
+	case strings.EqualFold(normalized, LookupKeyDeviceName),
+		strings.EqualFold(normalized, LookupKeyProfileName),
+		strings.EqualFold(normalized, LookupKeySourceName):
+		return strings.ToLower(normalized), nil
+	case strings.HasPrefix(normalized, LookupKeyTagPrefix) && len(normalized) > len(LookupKeyTagPrefix):
+		return normalized, nil
+	default:
+		return "", fmt.Errorf("invalid key field '%s'", keyField)
+	}
+}
+
+// eventKeyValue returns the value of the normalized key field from the Event
+func eventKeyValue(event dtos.Event, keyField string) string {
+	switch keyField {
+	case LookupKeyDeviceName:
+		return event.DeviceName
+	case LookupKeyProfileName:
+		return event.ProfileName
+	case LookupKeySourceName:
+		return event.SourceName
+	default:
+		return event.Tags[strings.TrimPrefix(keyField, LookupKeyTagPrefix)]
+	}
+}
+
+func (lookup *HTTPLookup) lookupTags(ctx interfaces.AppFunctionContext, key string) (map[string]string, error) {
+	if tags, found := lookup.cache.get(key); found {
+		ctx.LoggingClient().Debugf("Using cached lookup response for key '%s'", key)
+		return tags, nil
+	}
+
+	lookupUrl := strings.ReplaceAll(lookup.url, LookupKeyPlaceholder, url.PathEscape(key))
+	ctx.LoggingClient().Debugf("Looking up enrichment data from %s", lookupUrl)
+
+	response, err := lookup.client.Get(lookupUrl)
+	if err != nil {
+		return nil, fmt.Errorf("lookup for key '%s' failed: %w", key, err)
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return nil, fmt.Errorf("lookup for key '%s' failed with %d HTTP status code", key, response.StatusCode)
+	}
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read lookup response for key '%s': %w", key, err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, fmt.Errorf("lookup response for key '%s' is not a JSON object: %w", key, err)
+	}
+
+	tags := make(map[string]string, len(fields))
+	for name, raw := range fields {
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			compacted := new(bytes.Buffer)
+			if err := json.Compact(compacted, raw); err != nil {
+				return nil, fmt.Errorf("unable to compact lookup response field '%s': %w", name, err)
+			}
+			value = compacted.String()
+		}
+		tags[lookup.tagPrefix+name] = value
+	}
+
+	lookup.cache.add(key, tags)
+
+	return tags, nil
+}
@@ -0,0 +1,175 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPLookup(t *testing.T) {
+	tests := []struct {
+		Name        string
+		URL         string
+		KeyField    string
+		ExpectError bool
+	}{
+		{"Valid device name", "http://host/assets/{key}", LookupKeyDeviceName, false},
+		{"Valid profile name", "http://host/assets/{key}", "ProfileName", false},
+		{"Valid tag", "http://host/assets?id={key}", "tag:assetId", false},
+		{"Missing URL", "", LookupKeyDeviceName, true},
+		{"Missing placeholder", "http://host/assets", LookupKeyDeviceName, true},
+		{"Bad key field", "http://host/assets/{key}", "bogus", true},
+		{"Missing tag name", "http://host/assets/{key}", LookupKeyTagPrefix, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := NewHTTPLookup(HTTPLookupOptions{URL: test.URL, KeyField: test.KeyField})
+			assert.Equal(t, test.ExpectError, err != nil)
+		})
+	}
+}
+
+func TestLookupAndEnrich(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if request.URL.Path != "/assets/device 1" {
+			writer.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writer.WriteHeader(http.StatusOK)
+		_, _ = writer.Write([]byte(`{"site": "plant-7", "owner": {"team": "ops"}, "critical": true}`))
+	}))
+	defer ts.Close()
+
+	lookup, err := NewHTTPLookup(HTTPLookupOptions{
+		URL:       ts.URL + "/assets/{key}",
+		KeyField:  LookupKeyDeviceName,
+		TagPrefix: "asset.",
+		CacheTTL:  time.Minute,
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	lookup.cache.now = func() time.Time { return now }
+
+	expectedTags := map[string]string{
+		"existing":       "tag",
+		"asset.site":     "plant-7",
+		"asset.owner":    `{"team":"ops"}`,
+		"asset.critical": "true",
+	}
+
+	for i := 0; i < 2; i++ {
+		event := dtos.NewEvent("profile", "device 1", "source")
+		event.Tags = map[string]string{"existing": "tag"}
+
+		continuePipeline, result := lookup.LookupAndEnrich(ctx, event)
+		require.True(t, continuePipeline, "unexpected result: %v", result)
+		assert.Equal(t, expectedTags, result.(dtos.Event).Tags)
+		assert.Equal(t, map[string]string{"existing": "tag"}, event.Tags, "tags of the Event received were modified")
+	}
+
+	// Second call served from cache
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// Cache expired
+	now = now.Add(2 * time.Minute)
+	continuePipeline, _ := lookup.LookupAndEnrich(ctx, dtos.NewEvent("profile", "device 1", "source"))
+	require.True(t, continuePipeline)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	// Lookup failure
+	continuePipeline, result := lookup.LookupAndEnrich(ctx, dtos.NewEvent("profile", "unknown", "source"))
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "404")
+}
+
+func TestLookupAndEnrichByTag(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "A-100", request.URL.Query().Get("id"))
+		_, _ = writer.Write([]byte(`{"site": "plant-7"}`))
+	}))
+	defer ts.Close()
+
+	lookup, err := NewHTTPLookup(HTTPLookupOptions{URL: ts.URL + "/assets?id={key}", KeyField: "tag:assetId"})
+	require.NoError(t, err)
+
+	event := dtos.NewEvent("profile", deviceName1, "source")
+	event.Tags = map[string]string{"assetId": "A-100"}
+
+	continuePipeline, result := lookup.LookupAndEnrich(ctx, event)
+	require.True(t, continuePipeline)
+	assert.Equal(t, "plant-7", result.(dtos.Event).Tags["site"])
+
+	// Missing key value
+	continuePipeline, result = lookup.LookupAndEnrich(ctx, dtos.NewEvent("profile", deviceName1, "source"))
+	assert.False(t, continuePipeline)
+	assert.Error(t, result.(error))
+}
+
+func TestLookupAndEnrichBadResponse(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		_, _ = writer.Write([]byte(`["not", "an", "object"]`))
+	}))
+	defer ts.Close()
+
+	lookup, err := NewHTTPLookup(HTTPLookupOptions{URL: ts.URL + "/{key}", KeyField: LookupKeyDeviceName})
+	require.NoError(t, err)
+
+	continuePipeline, result := lookup.LookupAndEnrich(ctx, dtos.NewEvent("profile", deviceName1, "source"))
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "is not a JSON object")
+}
+
+func TestLookupAndEnrichTimeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		_, _ = writer.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	lookup, err := NewHTTPLookup(HTTPLookupOptions{URL: ts.URL + "/{key}", KeyField: LookupKeyDeviceName, Timeout: 10 * time.Millisecond})
+	require.NoError(t, err)
+
+	continuePipeline, result := lookup.LookupAndEnrich(ctx, dtos.NewEvent("profile", deviceName1, "source"))
+	assert.False(t, continuePipeline)
+	assert.Error(t, result.(error))
+}
+
+func TestLookupAndEnrichNoData(t *testing.T) {
+	lookup, err := NewHTTPLookup(HTTPLookupOptions{URL: "http://host/{key}", KeyField: LookupKeyDeviceName})
+	require.NoError(t, err)
+
+	continuePipeline, result := lookup.LookupAndEnrich(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.EqualError(t, result.(error), "No Event Received")
+
+	continuePipeline, result = lookup.LookupAndEnrich(ctx, "not an event")
+	assert.False(t, continuePipeline)
+	assert.EqualError(t, result.(error), "type received is not an Event")
+}
@@ -0,0 +1,186 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+)
+
+const (
+	// MaskModeRedact replaces masked values with MaskRedactedValue
+	MaskModeRedact = "redact"
+	// MaskModeHash replaces masked values with their hex encoded SHA-256 hash, or HMAC-SHA256 when a key is configured
+	MaskModeHash = "hash"
+	// MaskRedactedValue is the value masked values are replaced with when using MaskModeRedact
+	MaskRedactedValue = "***"
+	// MaskReadingPrefix is prefixed to a resource name to mask the values of readings for that resource
+	MaskReadingPrefix = "reading:"
+)
+
+// Masker redacts or hashes selected Event fields, tags and reading values so privacy and compliance rules can be
+// enforced before data leaves the edge. Hashing allows masked values to still be correlated downstream.
+type Masker struct {
+	eventFields   []string
+	resourceNames map[string]bool
+	mode          string
+	secretPath    string
+	secretName    string
+}
+
+// NewMasker creates, initializes and returns a new instance of Masker. Each field is one of the LookupKey fields,
+// i.e. LookupKeyDeviceName or LookupKeyTagPrefix followed by a tag name, or MaskReadingPrefix followed by a resource
+// name. If secretPath and secretName are specified, hashing uses HMAC-SHA256 with the key from the Secret Store
+// so that masked values can't be recovered by hashing candidate values.
+func NewMasker(fields []string, mode string, secretPath string, secretName string) (*Masker, error) {
+	if len(fields) == 0 {
+		return nil, errors.New("at least one field to mask must be specified")
+	}
+
+	if mode != MaskModeRedact && mode != MaskModeHash {
+		return nil, fmt.Errorf("invalid mask mode '%s', must be '%s' or '%s'", mode, MaskModeRedact, MaskModeHash)
+	}
+
+	if (len(secretPath) == 0) != (len(secretName) == 0) {
+		return nil, errors.New("secretPath & secretName must both be specified when hashing with a key")
+	}
+
+	masker := &Masker{
+		resourceNames: make(map[string]bool),
+		mode:          mode,
+		secretPath:    secretPath,
+		secretName:    secretName,
+	}
+
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if strings.HasPrefix(field, MaskReadingPrefix) && len(field) > len(MaskReadingPrefix) {
+			masker.resourceNames[strings.TrimPrefix(field, MaskReadingPrefix)] = true
+			continue
+		}
+
+		eventField, err := normalizeKeyField(field)
+		if err != nil {
+			return nil, err
+		}
+		masker.eventFields = append(masker.eventFields, eventField)
+	}
+
+	return masker, nil
+}
+
+// MaskFields masks the configured fields of the Event received. Masked reading values are changed to the String
+// value type since the masked value no longer matches the original value type. Binary readings are masked by
+// clearing the binary value.
+// It will return an error and stop the pipeline if a non-edgex event is received or if no data is received.
+func (masker *Masker) MaskFields(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, errors.New("No Event Received")
+	}
+
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return false, errors.New("type received is not an Event")
+	}
+
+	ctx.LoggingClient().Debugf("Masking fields of Event for device '%s'", event.DeviceName)
+
+	var key []byte
+	if masker.mode == MaskModeHash && len(masker.secretPath) > 0 {
+		var err error
+		key, err = retrieveSigningKey(ctx, masker.secretPath, masker.secretName)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	mask := func(value string) string {
+		if len(value) == 0 {
+			return value
+		}
+
+		if masker.mode == MaskModeRedact {
+			return MaskRedactedValue
+		}
+
+		if key != nil {
+			return hex.EncodeToString(computeHMAC(key, []byte(value)))
+		}
+
+		hash := sha256.Sum256([]byte(value))
+		return hex.EncodeToString(hash[:])
+	}
+
+	// Don't modify the tags or readings of the Event received since they may be shared with other functions
+	if len(event.Tags) > 0 {
+		tags := make(map[string]string, len(event.Tags))
+		for tag, value := range event.Tags {
+			tags[tag] = value
+		}
+		event.Tags = tags
+	}
+
+	for _, field := range masker.eventFields {
+		switch field {
+		case LookupKeyDeviceName:
+			event.DeviceName = mask(event.DeviceName)
+		case LookupKeyProfileName:
+			event.ProfileName = mask(event.ProfileName)
+		case LookupKeySourceName:
+			event.SourceName = mask(event.SourceName)
+		default:
+			tag := strings.TrimPrefix(field, LookupKeyTagPrefix)
+			if value, found := event.Tags[tag]; found {
+				event.Tags[tag] = mask(value)
+			}
+		}
+	}
+
+	readings := make([]dtos.BaseReading, len(event.Readings))
+	for index, reading := range event.Readings {
+		for _, field := range masker.eventFields {
+			switch field {
+			case LookupKeyDeviceName:
+				reading.DeviceName = mask(reading.DeviceName)
+			case LookupKeyProfileName:
+				reading.ProfileName = mask(reading.ProfileName)
+			}
+		}
+
+		if masker.resourceNames[reading.ResourceName] {
+			if reading.ValueType == common.ValueTypeBinary {
+				reading.BinaryValue = nil
+			} else {
+				reading.Value = mask(reading.Value)
+				reading.ValueType = common.ValueTypeString
+			}
+		}
+
+		readings[index] = reading
+	}
+	event.Readings = readings
+
+	return true, event
+}
@@ -0,0 +1,123 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const maskTestDeviceName = "serial-12345"
+
+func TestNewMasker(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Fields      []string
+		Mode        string
+		SecretPath  string
+		SecretName  string
+		ExpectError bool
+	}{
+		{"Valid redact", []string{"devicename", "tag:gps", "reading:latitude"}, MaskModeRedact, "", "", false},
+		{"Valid hash with key", []string{"devicename"}, MaskModeHash, "mask", "key", false},
+		{"No fields", nil, MaskModeRedact, "", "", true},
+		{"Bad field", []string{"bogus"}, MaskModeRedact, "", "", true},
+		{"Bad mode", []string{"devicename"}, "bogus", "", "", true},
+		{"Missing secret name", []string{"devicename"}, MaskModeHash, "mask", "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := NewMasker(test.Fields, test.Mode, test.SecretPath, test.SecretName)
+			assert.Equal(t, test.ExpectError, err != nil)
+		})
+	}
+}
+
+func TestMaskFieldsRedact(t *testing.T) {
+	masker, err := NewMasker([]string{"devicename", "tag:gps", "reading:latitude"}, MaskModeRedact, "", "")
+	require.NoError(t, err)
+
+	event := newTestEvent(
+		t,
+		maskTestDeviceName,
+		map[string]string{"gps": "45.5,-122.6", "site": "plant-7"},
+		floatReading("latitude", 45.5),
+		testReading{resourceName: "temperature", valueType: common.ValueTypeInt64, value: int64(72)})
+	continuePipeline, result := masker.MaskFields(ctx, event)
+	require.True(t, continuePipeline)
+
+	masked := result.(dtos.Event)
+	assert.Equal(t, MaskRedactedValue, masked.DeviceName)
+	assert.Equal(t, "profile", masked.ProfileName)
+	assert.Equal(t, MaskRedactedValue, masked.Tags["gps"])
+	assert.Equal(t, "plant-7", masked.Tags["site"])
+
+	require.Len(t, masked.Readings, 2)
+	assert.Equal(t, MaskRedactedValue, masked.Readings[0].DeviceName)
+	assert.Equal(t, MaskRedactedValue, masked.Readings[0].Value)
+	assert.Equal(t, common.ValueTypeString, masked.Readings[0].ValueType)
+	assert.Equal(t, "72", masked.Readings[1].Value)
+	assert.Equal(t, common.ValueTypeInt64, masked.Readings[1].ValueType)
+
+	// Original Event not modified
+	assert.Equal(t, maskTestDeviceName, event.DeviceName)
+	assert.Equal(t, "45.5,-122.6", event.Tags["gps"])
+	assert.Equal(t, maskTestDeviceName, event.Readings[0].DeviceName)
+}
+
+func TestMaskFieldsHash(t *testing.T) {
+	masker, err := NewMasker([]string{"devicename"}, MaskModeHash, "", "")
+	require.NoError(t, err)
+
+	continuePipeline, result := masker.MaskFields(ctx, newTestEvent(t, maskTestDeviceName, nil))
+	require.True(t, continuePipeline)
+
+	hash := sha256.Sum256([]byte(maskTestDeviceName))
+	assert.Equal(t, hex.EncodeToString(hash[:]), result.(dtos.Event).DeviceName)
+}
+
+func TestMaskFieldsHashWithKey(t *testing.T) {
+	signingCtx := newSigningTestContext()
+
+	masker, err := NewMasker([]string{"devicename"}, MaskModeHash, signingSecretPath, signingSecretName)
+	require.NoError(t, err)
+
+	continuePipeline, result := masker.MaskFields(signingCtx, newTestEvent(t, maskTestDeviceName, nil))
+	require.True(t, continuePipeline)
+	assert.Equal(t, expectedHMAC(maskTestDeviceName), result.(dtos.Event).DeviceName)
+}
+
+func TestMaskFieldsNoData(t *testing.T) {
+	masker, err := NewMasker([]string{"devicename"}, MaskModeRedact, "", "")
+	require.NoError(t, err)
+
+	continuePipeline, result := masker.MaskFields(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.EqualError(t, result.(error), "No Event Received")
+
+	continuePipeline, result = masker.MaskFields(ctx, "not an event")
+	assert.False(t, continuePipeline)
+	assert.EqualError(t, result.(error), "type received is not an Event")
+}
@@ -0,0 +1,208 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+)
+
+// Device and Device Profile metadata fields that can be attached to Events by MetadataEnricher
+const (
+	MetadataDeviceLabels        = "devicelabels"
+	MetadataDeviceLocation      = "devicelocation"
+	MetadataDeviceDescription   = "devicedescription"
+	MetadataProfileLabels       = "profilelabels"
+	MetadataProfileDescription  = "profiledescription"
+	MetadataProfileManufacturer = "profilemanufacturer"
+	MetadataProfileModel        = "profilemodel"
+)
+
+var metadataTagNames = map[string]string{
+	MetadataDeviceLabels:        "DeviceLabels",
+	MetadataDeviceLocation:      "DeviceLocation",
+	MetadataDeviceDescription:   "DeviceDescription",
+	MetadataProfileLabels:       "ProfileLabels",
+	MetadataProfileDescription:  "ProfileDescription",
+	MetadataProfileManufacturer: "ProfileManufacturer",
+	MetadataProfileModel:        "ProfileModel",
+}
+
+// MetadataEnricher attaches selected Device and Device Profile metadata from Core Metadata to Events as tags.
+// Metadata retrieved is cached per device for the configured TTL so Core Metadata isn't called for every Event,
+// for up to 1000 devices.
+type MetadataEnricher struct {
+	fields []string
+	cache  *tagCache
+}
+
+// NewMetadataEnricher creates, initializes and returns a new instance of MetadataEnricher for the specified
+// metadata fields. A cacheTTL of zero disables caching.
+func NewMetadataEnricher(fields []string, cacheTTL time.Duration) (*MetadataEnricher, error) {
+	enricher := &MetadataEnricher{
+		cache: newTagCache(cacheTTL, maxTagCacheEntries),
+	}
+
+	if len(fields) == 0 {
+		return nil, errors.New("at least one metadata field must be specified")
+	}
+
+	for _, field := range fields {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if _, ok := metadataTagNames[field]; !ok {
+			return nil, fmt.Errorf("invalid metadata field '%s'", field)
+		}
+		enricher.fields = append(enricher.fields, field)
+	}
+
+	return enricher, nil
+}
+
+// EnrichWithMetadata adds the selected metadata for the Event's device to the Event's tags.
+// It will return an error and stop the pipeline if a non-edgex event is received, if no data is received
+// or if the metadata can not be retrieved.
+func (enricher *MetadataEnricher) EnrichWithMetadata(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, errors.New("No Event Received")
+	}
+
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return false, errors.New("type received is not an Event")
+	}
+
+	ctx.LoggingClient().Debugf("Enriching Event with metadata for device '%s'", event.DeviceName)
+
+	tags, err := enricher.metadataTags(ctx, event.DeviceName)
+	if err != nil {
+		return false, err
+	}
+
+	// Don't modify the tags of the Event received since they may be shared with other functions
+	eventTags := make(map[string]string, len(event.Tags)+len(tags))
+	for tag, value := range event.Tags {
+		eventTags[tag] = value
+	}
+	for tag, value := range tags {
+		eventTags[tag] = value
+	}
+	event.Tags = eventTags
+
+	return true, event
+}
+
+func (enricher *MetadataEnricher) metadataTags(ctx interfaces.AppFunctionContext, deviceName string) (map[string]string, error) {
+	if tags, found := enricher.cache.get(deviceName); found {
+		ctx.LoggingClient().Debugf("Using cached metadata for device '%s'", deviceName)
+		return tags, nil
+	}
+
+	deviceClient := ctx.DeviceClient()
+	if deviceClient == nil {
+		return nil, errors.New("DeviceClient not initialized. Core Metadata is missing from clients configuration")
+	}
+
+	deviceResponse, edgexErr := deviceClient.DeviceByName(context.Background(), deviceName)
+	if edgexErr != nil {
+		return nil, fmt.Errorf("unable to retrieve device '%s' from Core Metadata: %s", deviceName, edgexErr.Error())
+	}
+	device := deviceResponse.Device
+
+	var profile *dtos.DeviceProfile
+	var err error
+	tags := make(map[string]string)
+
+	for _, field := range enricher.fields {
+		var value string
+
+		switch field {
+		case MetadataDeviceLabels:
+			value = strings.Join(device.Labels, ",")
+		case MetadataDeviceDescription:
+			value = device.Description
+		case MetadataDeviceLocation:
+			if device.Location == nil {
+				continue
+			}
+			value, err = locationToString(device.Location)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			if profile == nil {
+				profile, err = enricher.retrieveProfile(ctx, device.ProfileName)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			switch field {
+			case MetadataProfileLabels:
+				value = strings.Join(profile.Labels, ",")
+			case MetadataProfileDescription:
+				value = profile.Description
+			case MetadataProfileManufacturer:
+				value = profile.Manufacturer
+			case MetadataProfileModel:
+				value = profile.Model
+			}
+		}
+
+		if len(value) > 0 {
+			tags[metadataTagNames[field]] = value
+		}
+	}
+
+	enricher.cache.add(deviceName, tags)
+
+	return tags, nil
+}
+
+func (enricher *MetadataEnricher) retrieveProfile(ctx interfaces.AppFunctionContext, profileName string) (*dtos.DeviceProfile, error) {
+	profileClient := ctx.DeviceProfileClient()
+	if profileClient == nil {
+		return nil, errors.New("DeviceProfileClient not initialized. Core Metadata is missing from clients configuration")
+	}
+
+	profileResponse, err := profileClient.DeviceProfileByName(context.Background(), profileName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve device profile '%s' from Core Metadata: %s", profileName, err.Error())
+	}
+
+	return &profileResponse.Profile, nil
+}
+
+func locationToString(location interface{}) (string, error) {
+	if value, ok := location.(string); ok {
+		return value, nil
+	}
+
+	data, err := json.Marshal(location)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal device location: %s", err.Error())
+	}
+
+	return string(data), nil
+}
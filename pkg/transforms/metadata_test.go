@@ -0,0 +1,157 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/appfunction"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/bootstrap/container"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	clientMocks "github.com/edgexfoundry/go-mod-core-contracts/v2/clients/interfaces/mocks"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/responses"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newMetadataTestContext(deviceClient *clientMocks.DeviceClient, profileClient *clientMocks.DeviceProfileClient) *appfunction.Context {
+	metadataDic := di.NewContainer(di.ServiceConstructorMap{
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return lc
+		},
+		container.DeviceClientName: func(get di.Get) interface{} {
+			return deviceClient
+		},
+		container.DeviceProfileClientName: func(get di.Get) interface{} {
+			return profileClient
+		},
+	})
+
+	return appfunction.NewContext("123", metadataDic, "")
+}
+
+func TestNewMetadataEnricher(t *testing.T) {
+	_, err := NewMetadataEnricher([]string{MetadataDeviceLabels, " ProfileModel "}, time.Minute)
+	assert.NoError(t, err)
+
+	_, err = NewMetadataEnricher(nil, time.Minute)
+	assert.Error(t, err)
+
+	_, err = NewMetadataEnricher([]string{"bogus"}, time.Minute)
+	assert.Error(t, err)
+}
+
+func TestEnrichWithMetadata(t *testing.T) {
+	device := dtos.Device{
+		Name:        deviceName1,
+		Description: "My Device",
+		Labels:      []string{"floor1", "hvac"},
+		Location:    map[string]interface{}{"lat": 45.0},
+		ProfileName: "MyProfile",
+	}
+	profile := dtos.DeviceProfile{
+		Name:         "MyProfile",
+		Manufacturer: "Acme",
+		Model:        "X1",
+	}
+
+	deviceClient := &clientMocks.DeviceClient{}
+	deviceClient.On("DeviceByName", mock.Anything, deviceName1).Return(responses.DeviceResponse{Device: device}, nil)
+	profileClient := &clientMocks.DeviceProfileClient{}
+	profileClient.On("DeviceProfileByName", mock.Anything, "MyProfile").Return(responses.DeviceProfileResponse{Profile: profile}, nil)
+
+	enricher, err := NewMetadataEnricher(
+		[]string{MetadataDeviceLabels, MetadataDeviceLocation, MetadataDeviceDescription, MetadataProfileManufacturer, MetadataProfileModel},
+		time.Minute)
+	require.NoError(t, err)
+
+	now := time.Now()
+	enricher.cache.now = func() time.Time { return now }
+	metadataCtx := newMetadataTestContext(deviceClient, profileClient)
+
+	expectedTags := map[string]string{
+		"existing":            "tag",
+		"DeviceLabels":        "floor1,hvac",
+		"DeviceLocation":      `{"lat":45}`,
+		"DeviceDescription":   "My Device",
+		"ProfileManufacturer": "Acme",
+		"ProfileModel":        "X1",
+	}
+
+	for i := 0; i < 2; i++ {
+		event := dtos.NewEvent("MyProfile", deviceName1, "source")
+		event.Tags = map[string]string{"existing": "tag"}
+
+		continuePipeline, result := enricher.EnrichWithMetadata(metadataCtx, event)
+		require.True(t, continuePipeline)
+		assert.Equal(t, expectedTags, result.(dtos.Event).Tags)
+		assert.Equal(t, map[string]string{"existing": "tag"}, event.Tags, "tags of the Event received were modified")
+	}
+
+	// Second call served from cache
+	deviceClient.AssertNumberOfCalls(t, "DeviceByName", 1)
+	profileClient.AssertNumberOfCalls(t, "DeviceProfileByName", 1)
+
+	// Cache expired
+	now = now.Add(2 * time.Minute)
+	continuePipeline, _ := enricher.EnrichWithMetadata(metadataCtx, dtos.NewEvent("MyProfile", deviceName1, "source"))
+	require.True(t, continuePipeline)
+	deviceClient.AssertNumberOfCalls(t, "DeviceByName", 2)
+}
+
+func TestEnrichWithMetadataDeviceNotFound(t *testing.T) {
+	deviceClient := &clientMocks.DeviceClient{}
+	deviceClient.On("DeviceByName", mock.Anything, mock.Anything).
+		Return(responses.DeviceResponse{}, errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, "not found", nil))
+
+	enricher, err := NewMetadataEnricher([]string{MetadataDeviceLabels}, time.Minute)
+	require.NoError(t, err)
+
+	continuePipeline, result := enricher.EnrichWithMetadata(newMetadataTestContext(deviceClient, nil), dtos.NewEvent("p", "unknown", "s"))
+	assert.False(t, continuePipeline)
+	require.Error(t, result.(error))
+	assert.Contains(t, result.(error).Error(), "unable to retrieve device 'unknown'")
+}
+
+func TestEnrichWithMetadataNoClient(t *testing.T) {
+	enricher, err := NewMetadataEnricher([]string{MetadataDeviceLabels}, time.Minute)
+	require.NoError(t, err)
+
+	continuePipeline, result := enricher.EnrichWithMetadata(ctx, dtos.NewEvent("p", deviceName1, "s"))
+	assert.False(t, continuePipeline)
+	assert.Error(t, result.(error))
+}
+
+func TestEnrichWithMetadataNoData(t *testing.T) {
+	enricher, err := NewMetadataEnricher([]string{MetadataDeviceLabels}, time.Minute)
+	require.NoError(t, err)
+
+	continuePipeline, result := enricher.EnrichWithMetadata(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.EqualError(t, result.(error), "No Event Received")
+
+	continuePipeline, result = enricher.EnrichWithMetadata(ctx, "not an event")
+	assert.False(t, continuePipeline)
+	assert.EqualError(t, result.(error), "type received is not an Event")
+}
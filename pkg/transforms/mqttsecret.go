@@ -28,7 +28,6 @@ import (
 
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/secure"
-	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/util"
 )
 
 // MQTTSecretSender ...
@@ -45,28 +44,31 @@ type MQTTSecretSender struct {
 // MQTTSecretConfig ...
 type MQTTSecretConfig struct {
 	// BrokerAddress should be set to the complete broker address i.e. mqtts://mosquitto:8883/mybroker
-	BrokerAddress string
+	BrokerAddress string `param:"brokeraddress,required"`
 	// ClientId to connect with the broker with.
-	ClientId string
+	ClientId string `param:"clientid,required"`
 	// The name of the path in secret provider to retrieve your secrets
-	SecretPath string
+	SecretPath string `param:"secretpath,required"`
 	// AutoReconnect indicated whether or not to retry connection if disconnected
-	AutoReconnect bool
+	AutoReconnect bool `param:"autoreconnect"`
 	// KeepAlive is the interval duration between client sending keepalive ping to broker
-	KeepAlive string
+	KeepAlive string `param:"keepalive"`
 	// ConnectTimeout is the duration for timing out on connecting to the broker
-	ConnectTimeout string
+	ConnectTimeout string `param:"connecttimeout"`
 	// Topic that you wish to publish to
-	Topic string
+	Topic string `param:"topic,required"`
 	// QoS for MQTT Connection
-	QoS byte
+	QoS byte `param:"qos"`
 	// Retain setting for MQTT Connection
-	Retain bool
+	Retain bool `param:"retain"`
 	// SkipCertVerify
-	SkipCertVerify bool
+	SkipCertVerify bool `param:"skipverify"`
 	// AuthMode indicates what to use when connecting to the broker. Options are "none", "cacert" , "usernamepassword", "clientcert".
 	// If a CA Cert exists in the SecretPath then it will be used for all modes except "none".
-	AuthMode string
+	AuthMode string `param:"authmode,required"`
+	// PreserveCBOR publishes Events with binary readings received as CBOR in CBOR rather than JSON. MQTT 3.1.1 has
+	// no content type, so the subscribers must expect CBOR.
+	PreserveCBOR bool `param:"preservecbor"`
 }
 
 // NewMQTTSecretSender ...
@@ -151,6 +153,7 @@ func (sender *MQTTSecretSender) connectToBroker(ctx interfaces.AppFunctionContex
 
 	ctx.LoggingClient().Info("Connecting to mqtt server for export")
 	if token := sender.client.Connect(); token.Wait() && token.Error() != nil {
+		recordExport(ctx, MQTTSink, sender.destination(), token.Error())
 		sender.setRetryData(ctx, exportData)
 		subMessage := "dropping event"
 		if sender.persistOnError {
@@ -170,7 +173,13 @@ func (sender *MQTTSecretSender) MQTTSend(ctx interfaces.AppFunctionContext, data
 		return false, errors.New("No Data Received")
 	}
 
-	exportData, err := util.CoerceType(data)
+	var exportData []byte
+	var err error
+	if sender.mqttConfig.PreserveCBOR {
+		exportData, _, err = ctx.SerializedCBORData(data)
+	} else {
+		exportData, err = ctx.SerializedData(data)
+	}
 	if err != nil {
 		return false, err
 	}
@@ -196,6 +205,7 @@ func (sender *MQTTSecretSender) MQTTSend(ctx interfaces.AppFunctionContext, data
 
 	token := sender.client.Publish(publishTopic, sender.mqttConfig.QoS, sender.mqttConfig.Retain, exportData)
 	token.Wait()
+	recordExport(ctx, MQTTSink, sender.destination(), token.Error())
 	if token.Error() != nil {
 		sender.setRetryData(ctx, exportData)
 		return false, token.Error()
@@ -210,5 +220,11 @@ func (sender *MQTTSecretSender) MQTTSend(ctx interfaces.AppFunctionContext, data
 func (sender *MQTTSecretSender) setRetryData(ctx interfaces.AppFunctionContext, exportData []byte) {
 	if sender.persistOnError {
 		ctx.SetRetryData(exportData)
+		recordExportPersisted(ctx, MQTTSink, sender.destination())
 	}
 }
+
+// destination returns the broker address the export metrics are tagged with
+func (sender *MQTTSecretSender) destination() string {
+	return exportDestination(sender.mqttConfig.BrokerAddress)
+}
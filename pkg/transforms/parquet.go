@@ -0,0 +1,254 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+)
+
+// ContentTypeParquet is the content type of Apache Parquet files
+const ContentTypeParquet = "application/vnd.apache.parquet"
+
+// Names of the Event columns written to every Parquet file. Reading columns whose resource name matches one of
+// these are prefixed with parquetReadingColumnPrefix.
+const (
+	parquetColumnId          = "id"
+	parquetColumnDeviceName  = "deviceName"
+	parquetColumnProfileName = "profileName"
+	parquetColumnSourceName  = "sourceName"
+	parquetColumnOrigin      = "origin"
+
+	parquetReadingColumnPrefix = "reading_"
+	parquetCreatedBy           = "EdgeX app-functions-sdk-go"
+)
+
+// ParquetWriter converts batches of Events into Apache Parquet files
+type ParquetWriter struct {
+	outputDir string
+	now       func() time.Time
+}
+
+// NewParquetWriter creates, initializes and returns a new instance of ParquetWriter. If outputDir is not empty
+// each Parquet file is also written to that directory.
+func NewParquetWriter(outputDir string) ParquetWriter {
+	return ParquetWriter{
+		outputDir: outputDir,
+		now:       time.Now,
+	}
+}
+
+// ConvertToParquet converts a batch of Events into a Parquet file with one row per Event. The schema is derived from
+// the batch: the Event id, deviceName, profileName, sourceName and origin columns are always present and an optional
+// column is added per reading resource name. Integer readings are written as INT64, float readings as DOUBLE, bool
+// readings as BOOLEAN, binary readings as BYTE_ARRAY and all others, or resources whose value type differs between
+// Events, as UTF8 strings. Values that can't be parsed as the column type are written as null.
+// Accepts an Event, a slice of Events or the [][]byte output of the Batch functions containing JSON Events.
+// The Parquet file is returned as []byte and, when an output directory is configured, also written to that directory.
+func (writer ParquetWriter) ConvertToParquet(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, errors.New("No Data Received")
+	}
+
+	events, err := toEventBatch(data)
+	if err != nil {
+		return false, err
+	}
+
+	if len(events) == 0 {
+		ctx.LoggingClient().Debug("No Events to convert to Parquet")
+		return false, nil
+	}
+
+	ctx.LoggingClient().Debugf("Converting %d Events to Parquet", len(events))
+
+	columns := buildParquetColumns(events)
+	file := encodeParquetFile(columns, len(events), parquetCreatedBy)
+
+	if len(writer.outputDir) > 0 {
+		fileName := filepath.Join(writer.outputDir, fmt.Sprintf("events-%d.parquet", writer.now().UnixNano()))
+		if err := os.WriteFile(fileName, file, 0640); err != nil {
+			return false, fmt.Errorf("unable to write Parquet file '%s': %s", fileName, err.Error())
+		}
+		ctx.LoggingClient().Debugf("Wrote %d bytes of Parquet to '%s'", len(file), fileName)
+	}
+
+	ctx.SetResponseContentType(ContentTypeParquet)
+
+	return true, file
+}
+
+func toEventBatch(data interface{}) ([]dtos.Event, error) {
+	switch batch := data.(type) {
+	case dtos.Event:
+		return []dtos.Event{batch}, nil
+	case []dtos.Event:
+		return batch, nil
+	case [][]byte:
+		events := make([]dtos.Event, len(batch))
+		for index, item := range batch {
+			if err := json.Unmarshal(item, &events[index]); err != nil {
+				return nil, fmt.Errorf("batch item #%d is not a JSON Event: %s", index, err.Error())
+			}
+		}
+		return events, nil
+	default:
+		return nil, errors.New("type received is not an Event or batch of Events")
+	}
+}
+
+func buildParquetColumns(events []dtos.Event) []*parquetColumn {
+	id := &parquetColumn{name: parquetColumnId, physical: parquetTypeByteArray, utf8: true}
+	deviceName := &parquetColumn{name: parquetColumnDeviceName, physical: parquetTypeByteArray, utf8: true}
+	profileName := &parquetColumn{name: parquetColumnProfileName, physical: parquetTypeByteArray, utf8: true}
+	sourceName := &parquetColumn{name: parquetColumnSourceName, physical: parquetTypeByteArray, utf8: true}
+	origin := &parquetColumn{name: parquetColumnOrigin, physical: parquetTypeInt64}
+
+	readingColumns := deriveReadingColumns(events)
+
+	for _, event := range events {
+		id.appendBinary([]byte(event.Id))
+		deviceName.appendBinary([]byte(event.DeviceName))
+		profileName.appendBinary([]byte(event.ProfileName))
+		sourceName.appendBinary([]byte(event.SourceName))
+		origin.appendInt64(event.Origin)
+
+		readings := make(map[string]dtos.BaseReading, len(event.Readings))
+		for _, reading := range event.Readings {
+			readings[reading.ResourceName] = reading
+		}
+
+		for resourceName, column := range readingColumns {
+			reading, found := readings[resourceName]
+			if !found {
+				column.appendNull()
+				continue
+			}
+			appendReadingValue(column, reading)
+		}
+	}
+
+	columns := []*parquetColumn{id, deviceName, profileName, sourceName, origin}
+
+	resourceNames := make([]string, 0, len(readingColumns))
+	for resourceName := range readingColumns {
+		resourceNames = append(resourceNames, resourceName)
+	}
+	sort.Strings(resourceNames)
+
+	for _, resourceName := range resourceNames {
+		columns = append(columns, readingColumns[resourceName])
+	}
+
+	return columns
+}
+
+// deriveReadingColumns returns an optional column per resource name typed as per the readings' value type
+func deriveReadingColumns(events []dtos.Event) map[string]*parquetColumn {
+	columns := make(map[string]*parquetColumn)
+
+	for _, event := range events {
+		for _, reading := range event.Readings {
+			physical, utf8 := parquetTypeForValueType(reading.ValueType)
+
+			column, exists := columns[reading.ResourceName]
+			if !exists {
+				name := reading.ResourceName
+				switch name {
+				case parquetColumnId, parquetColumnDeviceName, parquetColumnProfileName, parquetColumnSourceName, parquetColumnOrigin:
+					name = parquetReadingColumnPrefix + name
+				}
+
+				columns[reading.ResourceName] = &parquetColumn{
+					name:     name,
+					physical: physical,
+					utf8:     utf8,
+					optional: true,
+				}
+				continue
+			}
+
+			// Value type differs between Events so fall back to strings
+			if column.physical != physical || column.utf8 != utf8 {
+				column.physical = parquetTypeByteArray
+				column.utf8 = true
+			}
+		}
+	}
+
+	return columns
+}
+
+func parquetTypeForValueType(valueType string) (int32, bool) {
+	switch valueType {
+	case common.ValueTypeInt8, common.ValueTypeInt16, common.ValueTypeInt32, common.ValueTypeInt64,
+		common.ValueTypeUint8, common.ValueTypeUint16, common.ValueTypeUint32:
+		return parquetTypeInt64, false
+	case common.ValueTypeFloat32, common.ValueTypeFloat64:
+		return parquetTypeDouble, false
+	case common.ValueTypeBool:
+		return parquetTypeBoolean, false
+	case common.ValueTypeBinary:
+		return parquetTypeByteArray, false
+	default:
+		return parquetTypeByteArray, true
+	}
+}
+
+func appendReadingValue(column *parquetColumn, reading dtos.BaseReading) {
+	switch {
+	case column.physical == parquetTypeInt64:
+		value, err := strconv.ParseInt(reading.Value, 10, 64)
+		if err != nil {
+			column.appendNull()
+			return
+		}
+		column.appendInt64(value)
+	case column.physical == parquetTypeDouble:
+		value, err := strconv.ParseFloat(reading.Value, 64)
+		if err != nil {
+			column.appendNull()
+			return
+		}
+		column.appendDouble(value)
+	case column.physical == parquetTypeBoolean:
+		value, err := strconv.ParseBool(reading.Value)
+		if err != nil {
+			column.appendNull()
+			return
+		}
+		column.appendBoolean(value)
+	case !column.utf8:
+		column.appendBinary(reading.BinaryValue)
+	case reading.ValueType == common.ValueTypeBinary:
+		// Binary values can't be represented in a string column
+		column.appendNull()
+	default:
+		column.appendBinary([]byte(reading.Value))
+	}
+}
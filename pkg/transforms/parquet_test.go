@@ -0,0 +1,231 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newParquetTestEvents returns Events whose readings cover the column types, including a resource whose value
+// type differs between the Events and a reading named as one of the Event columns
+func newParquetTestEvents(t *testing.T) []dtos.Event {
+	return []dtos.Event{
+		newTestEvent(
+			t,
+			deviceName1,
+			nil,
+			floatReading("temperature", 21.5),
+			testReading{resourceName: "count", valueType: common.ValueTypeInt64, value: int64(7)},
+			testReading{resourceName: "id", valueType: common.ValueTypeString, value: "sensor-1"}),
+		newTestEvent(
+			t,
+			deviceName2,
+			nil,
+			floatReading("temperature", 22.5),
+			testReading{resourceName: "on", valueType: common.ValueTypeBool, value: true},
+			testReading{resourceName: "count", valueType: common.ValueTypeString, value: "many"}),
+	}
+}
+
+func TestThriftCompactWriter(t *testing.T) {
+	writer := newThriftCompactWriter()
+	writer.beginStruct()
+	writer.writeI32Field(1, 1)
+	writer.writeI64Field(3, -2)
+	writer.writeStringField(4, "ab")
+	writer.writeI32Field(20, 3)
+	writer.writeListFieldHeader(21, thriftTypeI32, 2)
+	writer.writeI32(0)
+	writer.writeI32(3)
+	writer.endStruct()
+
+	expected := []byte{
+		0x15, 0x02, // field 1 i32 delta 1, zigzag(1)
+		0x26, 0x03, // field 3 i64 delta 2, zigzag(-2)
+		0x18, 0x02, 'a', 'b', // field 4 binary delta 1, length 2
+		0x05, 0x28, 0x06, // field 20 i32 long form, zigzag(20), zigzag(3)
+		0x19, 0x25, 0x00, 0x06, // field 21 list delta 1, 2 x i32
+		0x00, // stop
+	}
+	assert.Equal(t, expected, writer.bytes())
+}
+
+func TestEncodeBitPackedHybrid(t *testing.T) {
+	levels := []bool{true, false, true, true, false, false, false, false, true}
+	assert.Equal(t, []byte{0x05, 0x0D, 0x01}, encodeBitPackedHybrid(levels))
+}
+
+func TestBuildParquetColumns(t *testing.T) {
+	columns := buildParquetColumns(newParquetTestEvents(t))
+
+	names := make([]string, len(columns))
+	for index, column := range columns {
+		names[index] = column.name
+		assert.Len(t, column.defined, 2, "column %s", column.name)
+	}
+	assert.Equal(t, []string{"id", "deviceName", "profileName", "sourceName", "origin", "count", "reading_id", "on", "temperature"}, names)
+
+	count := columns[5]
+	assert.Equal(t, parquetTypeByteArray, count.physical, "mixed value types should fall back to strings")
+	assert.True(t, count.utf8)
+	assert.Equal(t, [][]byte{[]byte("7"), []byte("many")}, count.binaries)
+
+	on := columns[7]
+	assert.Equal(t, parquetTypeBoolean, on.physical)
+	assert.Equal(t, []bool{false, true}, on.defined)
+	assert.Equal(t, []bool{true}, on.booleans)
+
+	temperature := columns[8]
+	assert.Equal(t, parquetTypeDouble, temperature.physical)
+	assert.Equal(t, []float64{21.5, 22.5}, temperature.doubles)
+}
+
+func TestConvertToParquet(t *testing.T) {
+	events := newParquetTestEvents(t)
+
+	var batch [][]byte
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		require.NoError(t, err)
+		batch = append(batch, data)
+	}
+
+	tests := []struct {
+		Name string
+		Data interface{}
+	}{
+		{"Event", events[0]},
+		{"Events", events},
+		{"Batch", batch},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			continuePipeline, result := NewParquetWriter("").ConvertToParquet(ctx, test.Data)
+			require.True(t, continuePipeline, "unexpected result: %v", result)
+
+			file := result.([]byte)
+			require.True(t, len(file) > 12)
+			assert.Equal(t, parquetMagic, string(file[:4]))
+			assert.Equal(t, parquetMagic, string(file[len(file)-4:]))
+
+			footerLength := int(binary.LittleEndian.Uint32(file[len(file)-8 : len(file)-4]))
+			require.True(t, footerLength < len(file)-12)
+			footer := file[len(file)-8-footerLength : len(file)-8]
+			assert.True(t, bytes.Contains(footer, []byte("temperature")))
+			assert.True(t, bytes.Contains(footer, []byte(parquetCreatedBy)))
+			assert.Equal(t, ContentTypeParquet, ctx.ResponseContentType())
+		})
+	}
+}
+
+func TestConvertToParquetRoundTrip(t *testing.T) {
+	events := newParquetTestEvents(t)
+	continuePipeline, result := NewParquetWriter("").ConvertToParquet(ctx, events)
+	require.True(t, continuePipeline, "unexpected result: %v", result)
+
+	read, err := readParquetTestFile(result.([]byte))
+	require.NoError(t, err)
+	assert.Equal(t, parquetCreatedBy, read.createdBy)
+
+	expectedSchema := map[string]string{
+		"id":          "string",
+		"deviceName":  "string",
+		"profileName": "string",
+		"sourceName":  "string",
+		"origin":      "int64",
+		"count":       "string",
+		"reading_id":  "string",
+		"on":          "boolean",
+		"temperature": "double",
+	}
+	assert.Equal(t, expectedSchema, read.schema)
+
+	expectedRows := []map[string]interface{}{
+		{
+			"id":          events[0].Id,
+			"deviceName":  deviceName1,
+			"profileName": "profile",
+			"sourceName":  "source",
+			"origin":      events[0].Origin,
+			"count":       "7",
+			"reading_id":  "sensor-1",
+			"on":          nil,
+			"temperature": 21.5,
+		},
+		{
+			"id":          events[1].Id,
+			"deviceName":  deviceName2,
+			"profileName": "profile",
+			"sourceName":  "source",
+			"origin":      events[1].Origin,
+			"count":       "many",
+			"reading_id":  nil,
+			"on":          true,
+			"temperature": 22.5,
+		},
+	}
+	assert.Equal(t, expectedRows, read.rows)
+}
+
+func TestConvertToParquetOutputDir(t *testing.T) {
+	outputDir := t.TempDir()
+
+	writer := NewParquetWriter(outputDir)
+	now := time.Now()
+	writer.now = func() time.Time { return now }
+
+	continuePipeline, result := writer.ConvertToParquet(ctx, newParquetTestEvents(t))
+	require.True(t, continuePipeline)
+
+	written, err := os.ReadFile(filepath.Join(outputDir, "events-"+strconv.FormatInt(now.UnixNano(), 10)+".parquet"))
+	require.NoError(t, err)
+	assert.Equal(t, result, written)
+}
+
+func TestConvertToParquetBadData(t *testing.T) {
+	writer := NewParquetWriter("")
+
+	continuePipeline, result := writer.ConvertToParquet(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.EqualError(t, result.(error), "No Data Received")
+
+	continuePipeline, result = writer.ConvertToParquet(ctx, "not events")
+	assert.False(t, continuePipeline)
+	assert.Error(t, result.(error))
+
+	continuePipeline, result = writer.ConvertToParquet(ctx, [][]byte{[]byte("not json")})
+	assert.False(t, continuePipeline)
+	assert.Error(t, result.(error))
+
+	continuePipeline, result = writer.ConvertToParquet(ctx, []dtos.Event{})
+	assert.False(t, continuePipeline)
+	assert.Nil(t, result)
+}
@@ -0,0 +1,323 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+// This file contains the minimal subset of the Apache Parquet file format needed to write a single row group of
+// flat, uncompressed, PLAIN encoded columns. The file metadata is serialized using the Thrift compact protocol.
+// See https://github.com/apache/parquet-format for the format specification.
+
+const parquetMagic = "PAR1"
+
+// Parquet physical types
+const (
+	parquetTypeBoolean   int32 = 0
+	parquetTypeInt64     int32 = 2
+	parquetTypeDouble    int32 = 5
+	parquetTypeByteArray int32 = 6
+)
+
+const (
+	parquetRepetitionRequired int32 = 0
+	parquetRepetitionOptional int32 = 1
+	parquetConvertedTypeUTF8  int32 = 0
+	parquetEncodingPlain      int32 = 0
+	parquetEncodingRLE        int32 = 3
+	parquetCodecUncompressed  int32 = 0
+	parquetPageTypeDataPage   int32 = 0
+	parquetFileVersion        int32 = 1
+)
+
+// Thrift compact protocol types
+const (
+	thriftTypeI32    byte = 5
+	thriftTypeI64    byte = 6
+	thriftTypeBinary byte = 8
+	thriftTypeList   byte = 9
+	thriftTypeStruct byte = 12
+)
+
+// parquetColumn holds the values of a single flat column. Only the values slice matching the physical type is used.
+// Whether each row has a value is tracked by defined, which is only encoded for optional columns.
+type parquetColumn struct {
+	name     string
+	physical int32
+	utf8     bool
+	optional bool
+	defined  []bool
+	int64s   []int64
+	doubles  []float64
+	booleans []bool
+	binaries [][]byte
+}
+
+func (column *parquetColumn) appendNull() {
+	column.defined = append(column.defined, false)
+}
+
+func (column *parquetColumn) appendInt64(value int64) {
+	column.defined = append(column.defined, true)
+	column.int64s = append(column.int64s, value)
+}
+
+func (column *parquetColumn) appendDouble(value float64) {
+	column.defined = append(column.defined, true)
+	column.doubles = append(column.doubles, value)
+}
+
+func (column *parquetColumn) appendBoolean(value bool) {
+	column.defined = append(column.defined, true)
+	column.booleans = append(column.booleans, value)
+}
+
+func (column *parquetColumn) appendBinary(value []byte) {
+	column.defined = append(column.defined, true)
+	column.binaries = append(column.binaries, value)
+}
+
+// encodePage returns the PLAIN encoded values of the column, preceded by the definition levels for optional columns
+func (column *parquetColumn) encodePage() []byte {
+	page := new(bytes.Buffer)
+
+	if column.optional {
+		levels := encodeBitPackedHybrid(column.defined)
+		_ = binary.Write(page, binary.LittleEndian, uint32(len(levels)))
+		page.Write(levels)
+	}
+
+	switch column.physical {
+	case parquetTypeBoolean:
+		page.Write(packBits(column.booleans))
+	case parquetTypeInt64:
+		for _, value := range column.int64s {
+			_ = binary.Write(page, binary.LittleEndian, value)
+		}
+	case parquetTypeDouble:
+		for _, value := range column.doubles {
+			_ = binary.Write(page, binary.LittleEndian, math.Float64bits(value))
+		}
+	default:
+		for _, value := range column.binaries {
+			_ = binary.Write(page, binary.LittleEndian, uint32(len(value)))
+			page.Write(value)
+		}
+	}
+
+	return page.Bytes()
+}
+
+// encodeParquetFile writes the columns as a single row group and returns the complete Parquet file
+func encodeParquetFile(columns []*parquetColumn, numRows int, createdBy string) []byte {
+	file := new(bytes.Buffer)
+	file.WriteString(parquetMagic)
+
+	chunkOffsets := make([]int64, len(columns))
+	chunkSizes := make([]int64, len(columns))
+	var totalSize int64
+
+	for index, column := range columns {
+		page := column.encodePage()
+
+		header := newThriftCompactWriter()
+		header.beginStruct()
+		header.writeI32Field(1, parquetPageTypeDataPage)
+		header.writeI32Field(2, int32(len(page)))
+		header.writeI32Field(3, int32(len(page)))
+		header.beginStructField(5)
+		header.writeI32Field(1, int32(len(column.defined)))
+		header.writeI32Field(2, parquetEncodingPlain)
+		header.writeI32Field(3, parquetEncodingRLE)
+		header.writeI32Field(4, parquetEncodingRLE)
+		header.endStruct()
+		header.endStruct()
+
+		chunkOffsets[index] = int64(file.Len())
+		file.Write(header.bytes())
+		file.Write(page)
+		chunkSizes[index] = int64(file.Len()) - chunkOffsets[index]
+		totalSize += chunkSizes[index]
+	}
+
+	footer := newThriftCompactWriter()
+	footer.beginStruct()
+	footer.writeI32Field(1, parquetFileVersion)
+
+	// Schema is a flattened tree with the root element first
+	footer.writeListFieldHeader(2, thriftTypeStruct, len(columns)+1)
+	footer.beginStruct()
+	footer.writeStringField(4, "schema")
+	footer.writeI32Field(5, int32(len(columns)))
+	footer.endStruct()
+	for _, column := range columns {
+		repetition := parquetRepetitionRequired
+		if column.optional {
+			repetition = parquetRepetitionOptional
+		}
+
+		footer.beginStruct()
+		footer.writeI32Field(1, column.physical)
+		footer.writeI32Field(3, repetition)
+		footer.writeStringField(4, column.name)
+		if column.utf8 {
+			footer.writeI32Field(6, parquetConvertedTypeUTF8)
+		}
+		footer.endStruct()
+	}
+
+	footer.writeI64Field(3, int64(numRows))
+
+	footer.writeListFieldHeader(4, thriftTypeStruct, 1)
+	footer.beginStruct()
+	footer.writeListFieldHeader(1, thriftTypeStruct, len(columns))
+	for index, column := range columns {
+		footer.beginStruct()
+		footer.writeI64Field(2, chunkOffsets[index])
+		footer.beginStructField(3)
+		footer.writeI32Field(1, column.physical)
+		footer.writeListFieldHeader(2, thriftTypeI32, 2)
+		footer.writeI32(parquetEncodingPlain)
+		footer.writeI32(parquetEncodingRLE)
+		footer.writeListFieldHeader(3, thriftTypeBinary, 1)
+		footer.writeString(column.name)
+		footer.writeI32Field(4, parquetCodecUncompressed)
+		footer.writeI64Field(5, int64(len(column.defined)))
+		footer.writeI64Field(6, chunkSizes[index])
+		footer.writeI64Field(7, chunkSizes[index])
+		footer.writeI64Field(9, chunkOffsets[index])
+		footer.endStruct()
+		footer.endStruct()
+	}
+	footer.writeI64Field(2, totalSize)
+	footer.writeI64Field(3, int64(numRows))
+	footer.endStruct()
+
+	footer.writeStringField(6, createdBy)
+	footer.endStruct()
+
+	file.Write(footer.bytes())
+	_ = binary.Write(file, binary.LittleEndian, uint32(len(footer.bytes())))
+	file.WriteString(parquetMagic)
+
+	return file.Bytes()
+}
+
+// encodeBitPackedHybrid encodes bit width 1 levels using bit-packed runs of the RLE/bit-packing hybrid encoding
+func encodeBitPackedHybrid(values []bool) []byte {
+	groups := (len(values) + 7) / 8
+	result := appendUvarint(nil, uint64(groups)<<1|1)
+	return append(result, packBits(values)...)
+}
+
+// packBits packs the values one bit per value, least significant bit first
+func packBits(values []bool) []byte {
+	packed := make([]byte, (len(values)+7)/8)
+	for index, value := range values {
+		if value {
+			packed[index/8] |= 1 << uint(index%8)
+		}
+	}
+	return packed
+}
+
+func appendUvarint(buffer []byte, value uint64) []byte {
+	var scratch [binary.MaxVarintLen64]byte
+	size := binary.PutUvarint(scratch[:], value)
+	return append(buffer, scratch[:size]...)
+}
+
+// thriftCompactWriter serializes structs using the Thrift compact protocol
+type thriftCompactWriter struct {
+	buffer       []byte
+	lastFieldIds []int16
+}
+
+func newThriftCompactWriter() *thriftCompactWriter {
+	return &thriftCompactWriter{}
+}
+
+func (writer *thriftCompactWriter) bytes() []byte {
+	return writer.buffer
+}
+
+func (writer *thriftCompactWriter) beginStruct() {
+	writer.lastFieldIds = append(writer.lastFieldIds, 0)
+}
+
+func (writer *thriftCompactWriter) beginStructField(id int16) {
+	writer.writeFieldHeader(id, thriftTypeStruct)
+	writer.beginStruct()
+}
+
+func (writer *thriftCompactWriter) endStruct() {
+	writer.buffer = append(writer.buffer, 0)
+	writer.lastFieldIds = writer.lastFieldIds[:len(writer.lastFieldIds)-1]
+}
+
+func (writer *thriftCompactWriter) writeFieldHeader(id int16, fieldType byte) {
+	last := &writer.lastFieldIds[len(writer.lastFieldIds)-1]
+	delta := id - *last
+	if delta > 0 && delta <= 15 {
+		writer.buffer = append(writer.buffer, byte(delta)<<4|fieldType)
+	} else {
+		writer.buffer = append(writer.buffer, fieldType)
+		writer.buffer = appendUvarint(writer.buffer, uint64(uint16((id<<1)^(id>>15))))
+	}
+	*last = id
+}
+
+func (writer *thriftCompactWriter) writeI32(value int32) {
+	writer.buffer = appendUvarint(writer.buffer, uint64(uint32((value<<1)^(value>>31))))
+}
+
+func (writer *thriftCompactWriter) writeI64(value int64) {
+	writer.buffer = appendUvarint(writer.buffer, uint64((value<<1)^(value>>63)))
+}
+
+func (writer *thriftCompactWriter) writeString(value string) {
+	writer.buffer = appendUvarint(writer.buffer, uint64(len(value)))
+	writer.buffer = append(writer.buffer, value...)
+}
+
+func (writer *thriftCompactWriter) writeI32Field(id int16, value int32) {
+	writer.writeFieldHeader(id, thriftTypeI32)
+	writer.writeI32(value)
+}
+
+func (writer *thriftCompactWriter) writeI64Field(id int16, value int64) {
+	writer.writeFieldHeader(id, thriftTypeI64)
+	writer.writeI64(value)
+}
+
+func (writer *thriftCompactWriter) writeStringField(id int16, value string) {
+	writer.writeFieldHeader(id, thriftTypeBinary)
+	writer.writeString(value)
+}
+
+func (writer *thriftCompactWriter) writeListFieldHeader(id int16, elementType byte, size int) {
+	writer.writeFieldHeader(id, thriftTypeList)
+	if size < 15 {
+		writer.buffer = append(writer.buffer, byte(size)<<4|elementType)
+	} else {
+		writer.buffer = append(writer.buffer, 0xF0|elementType)
+		writer.buffer = appendUvarint(writer.buffer, uint64(size))
+	}
+}
@@ -0,0 +1,370 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// This file contains a reader for the Parquet files written by ParquetWriter, used to check the files written can
+// be read back. It is written from the format specification, https://github.com/apache/parquet-format, rather than
+// the writer: the Thrift compact protocol is decoded generically, fields are looked up by the ids in parquet.thrift,
+// unknown fields are skipped, and both the RLE and bit-packed runs of the hybrid encoding are decoded.
+
+// parquetTestFile is the schema and rows read from a Parquet file. Undefined values are nil, UTF8 values strings.
+type parquetTestFile struct {
+	schema    map[string]string
+	rows      []map[string]interface{}
+	createdBy string
+}
+
+// thriftStruct is a decoded Thrift struct, keyed by field id
+type thriftStruct map[int16]interface{}
+
+type thriftCompactReader struct {
+	data   []byte
+	offset int
+}
+
+func (reader *thriftCompactReader) readByte() (byte, error) {
+	if reader.offset >= len(reader.data) {
+		return 0, errors.New("unexpected end of Thrift data")
+	}
+	value := reader.data[reader.offset]
+	reader.offset++
+	return value, nil
+}
+
+func (reader *thriftCompactReader) readBytes(size int) ([]byte, error) {
+	if size < 0 || reader.offset+size > len(reader.data) {
+		return nil, errors.New("unexpected end of Thrift data")
+	}
+	value := reader.data[reader.offset : reader.offset+size]
+	reader.offset += size
+	return value, nil
+}
+
+func (reader *thriftCompactReader) readUvarint() (uint64, error) {
+	value, size := binary.Uvarint(reader.data[reader.offset:])
+	if size <= 0 {
+		return 0, errors.New("invalid Thrift varint")
+	}
+	reader.offset += size
+	return value, nil
+}
+
+func (reader *thriftCompactReader) readZigZag() (int64, error) {
+	value, err := reader.readUvarint()
+	return int64(value>>1) ^ -int64(value&1), err
+}
+
+func (reader *thriftCompactReader) readStruct() (thriftStruct, error) {
+	fields := make(thriftStruct)
+	var lastId int16
+
+	for {
+		header, err := reader.readByte()
+		if err != nil {
+			return nil, err
+		}
+		if header == 0 {
+			return fields, nil
+		}
+
+		id := lastId + int16(header>>4)
+		if header>>4 == 0 {
+			longId, err := reader.readZigZag()
+			if err != nil {
+				return nil, err
+			}
+			id = int16(longId)
+		}
+		lastId = id
+
+		fieldType := header & 0x0F
+		switch fieldType {
+		// Booleans fields hold their value in the field type
+		case 1:
+			fields[id] = true
+		case 2:
+			fields[id] = false
+		default:
+			if fields[id], err = reader.readValue(fieldType); err != nil {
+				return nil, fmt.Errorf("field %d: %s", id, err.Error())
+			}
+		}
+	}
+}
+
+func (reader *thriftCompactReader) readValue(valueType byte) (interface{}, error) {
+	switch valueType {
+	case 1, 2:
+		value, err := reader.readByte()
+		return value == 1, err
+	case 3:
+		return reader.readByte()
+	case 4, 5, 6:
+		return reader.readZigZag()
+	case 7:
+		value, err := reader.readBytes(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(value)), nil
+	case 8:
+		size, err := reader.readUvarint()
+		if err != nil {
+			return nil, err
+		}
+		return reader.readBytes(int(size))
+	case 9, 10:
+		header, err := reader.readByte()
+		if err != nil {
+			return nil, err
+		}
+		size := uint64(header >> 4)
+		if size == 15 {
+			if size, err = reader.readUvarint(); err != nil {
+				return nil, err
+			}
+		}
+		values := make([]interface{}, size)
+		for index := range values {
+			if values[index], err = reader.readValue(header & 0x0F); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	case 12:
+		return reader.readStruct()
+	default:
+		return nil, fmt.Errorf("unsupported Thrift type %d", valueType)
+	}
+}
+
+// readParquetTestFile reads the schema and rows of the flat, uncompressed and PLAIN encoded Parquet file
+func readParquetTestFile(file []byte) (*parquetTestFile, error) {
+	if len(file) < 12 || string(file[:4]) != "PAR1" || string(file[len(file)-4:]) != "PAR1" {
+		return nil, errors.New("missing PAR1 magic")
+	}
+
+	footerLength := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	if footerLength > len(file)-12 {
+		return nil, errors.New("invalid footer length")
+	}
+	footerReader := &thriftCompactReader{data: file[len(file)-8-footerLength : len(file)-8]}
+	metadata, err := footerReader.readStruct()
+	if err != nil {
+		return nil, fmt.Errorf("invalid FileMetaData: %s", err.Error())
+	}
+
+	// FileMetaData: 2 schema, 3 num_rows, 4 row_groups, 6 created_by
+	schema, _ := metadata[2].([]interface{})
+	numRows, _ := metadata[3].(int64)
+	rowGroups, _ := metadata[4].([]interface{})
+	createdBy, _ := metadata[6].([]byte)
+	if len(schema) == 0 || len(rowGroups) != 1 {
+		return nil, errors.New("expected a schema and a single row group")
+	}
+
+	// SchemaElement: 1 type, 3 repetition_type, 4 name, 5 num_children, 6 converted_type
+	root := schema[0].(thriftStruct)
+	if root[5] != int64(len(schema)-1) {
+		return nil, errors.New("expected a flat schema")
+	}
+
+	result := &parquetTestFile{
+		schema:    make(map[string]string),
+		rows:      make([]map[string]interface{}, numRows),
+		createdBy: string(createdBy),
+	}
+	for index := range result.rows {
+		result.rows[index] = make(map[string]interface{})
+	}
+
+	// RowGroup: 1 columns, 3 num_rows
+	rowGroup := rowGroups[0].(thriftStruct)
+	chunks, _ := rowGroup[1].([]interface{})
+	if rowGroup[3] != numRows || len(chunks) != len(schema)-1 {
+		return nil, errors.New("row group doesn't match the schema")
+	}
+
+	for index, element := range schema[1:] {
+		column := element.(thriftStruct)
+		name := string(column[4].([]byte))
+		physical := column[1].(int64)
+		optional := column[3] == int64(1)
+		_, utf8 := column[6]
+
+		// ColumnChunk: 3 meta_data. ColumnMetaData: 1 type, 3 path_in_schema, 4 codec, 5 num_values,
+		// 9 data_page_offset
+		chunk := chunks[index].(thriftStruct)[3].(thriftStruct)
+		path, _ := chunk[3].([]interface{})
+		if chunk[1] != physical || len(path) != 1 || string(path[0].([]byte)) != name || chunk[4] != int64(0) {
+			return nil, fmt.Errorf("column chunk of '%s' doesn't match its schema", name)
+		}
+
+		values, err := readParquetTestColumn(file, chunk[9].(int64), physical, optional, int(numRows))
+		if err != nil {
+			return nil, fmt.Errorf("column '%s': %s", name, err.Error())
+		}
+
+		result.schema[name] = map[int64]string{0: "boolean", 2: "int64", 5: "double", 6: "binary"}[physical]
+		if utf8 {
+			result.schema[name] = "string"
+		}
+		for row, value := range values {
+			if bytesValue, isBytes := value.([]byte); isBytes && utf8 {
+				value = string(bytesValue)
+			}
+			result.rows[row][name] = value
+		}
+	}
+
+	return result, nil
+}
+
+// readParquetTestColumn reads the values of the column from its single data page
+func readParquetTestColumn(file []byte, offset int64, physical int64, optional bool, numRows int) ([]interface{}, error) {
+	if offset < 4 || offset >= int64(len(file)) {
+		return nil, errors.New("invalid data page offset")
+	}
+
+	// PageHeader: 1 type, 3 compressed_page_size, 5 data_page_header. DataPageHeader: 1 num_values, 2 encoding
+	headerReader := &thriftCompactReader{data: file[offset:]}
+	header, err := headerReader.readStruct()
+	if err != nil {
+		return nil, fmt.Errorf("invalid PageHeader: %s", err.Error())
+	}
+	dataPage, _ := header[5].(thriftStruct)
+	pageSize, _ := header[3].(int64)
+	if header[1] != int64(0) || dataPage == nil || dataPage[1] != int64(numRows) || dataPage[2] != int64(0) {
+		return nil, errors.New("expected a PLAIN encoded data page with a value per row")
+	}
+
+	start := int(offset) + headerReader.offset
+	if pageSize < 0 || start+int(pageSize) > len(file) {
+		return nil, errors.New("invalid page size")
+	}
+	page := bytes.NewReader(file[start : start+int(pageSize)])
+
+	defined := make([]bool, numRows)
+	for index := range defined {
+		defined[index] = true
+	}
+	if optional {
+		var levelsLength uint32
+		if err := binary.Read(page, binary.LittleEndian, &levelsLength); err != nil {
+			return nil, err
+		}
+		levels := make([]byte, levelsLength)
+		if _, err := page.Read(levels); err != nil {
+			return nil, err
+		}
+		if defined, err = decodeParquetTestLevels(levels, numRows); err != nil {
+			return nil, err
+		}
+	}
+
+	var booleans []byte
+	var booleanIndex int
+	values := make([]interface{}, numRows)
+	for row := range values {
+		if !defined[row] {
+			continue
+		}
+
+		switch physical {
+		case 0:
+			if booleans == nil {
+				booleans = make([]byte, page.Len())
+				_, _ = page.Read(booleans)
+			}
+			if booleanIndex/8 >= len(booleans) {
+				return nil, errors.New("missing boolean values")
+			}
+			values[row] = booleans[booleanIndex/8]&(1<<uint(booleanIndex%8)) != 0
+			booleanIndex++
+		case 2:
+			var value int64
+			err = binary.Read(page, binary.LittleEndian, &value)
+			values[row] = value
+		case 5:
+			var value float64
+			err = binary.Read(page, binary.LittleEndian, &value)
+			values[row] = value
+		case 6:
+			var length uint32
+			if err = binary.Read(page, binary.LittleEndian, &length); err == nil {
+				value := make([]byte, length)
+				_, err = page.Read(value)
+				values[row] = value
+			}
+		default:
+			return nil, fmt.Errorf("unsupported physical type %d", physical)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %s", row, err.Error())
+		}
+	}
+
+	if page.Len() > 0 && physical != 0 {
+		return nil, fmt.Errorf("%d bytes left over in the data page", page.Len())
+	}
+
+	return values, nil
+}
+
+// decodeParquetTestLevels decodes definition levels of bit width 1 from the RLE/bit-packing hybrid encoding
+func decodeParquetTestLevels(data []byte, count int) ([]bool, error) {
+	reader := &thriftCompactReader{data: data}
+	var levels []bool
+
+	for len(levels) < count {
+		header, err := reader.readUvarint()
+		if err != nil {
+			return nil, err
+		}
+
+		if header&1 == 1 {
+			packed, err := reader.readBytes(int(header >> 1))
+			if err != nil {
+				return nil, err
+			}
+			for index := 0; index < len(packed)*8; index++ {
+				levels = append(levels, packed[index/8]&(1<<uint(index%8)) != 0)
+			}
+			continue
+		}
+
+		value, err := reader.readByte()
+		if err != nil {
+			return nil, err
+		}
+		for index := uint64(0); index < header>>1; index++ {
+			levels = append(levels, value == 1)
+		}
+	}
+
+	// Bit-packed runs are padded to a multiple of 8 values
+	return levels[:count], nil
+}
@@ -18,12 +18,16 @@ package transforms
 
 import (
 	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
-	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/util"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
 )
 
 // ResponseData houses transform for outputting data to configured trigger response, i.e. message bus
 type ResponseData struct {
-	ResponseContentType string
+	ResponseContentType string `param:"responsecontenttype"`
+	// PreserveCBOR sets Events with binary readings received as CBOR as the response in CBOR, rather than JSON,
+	// with the CBOR content type
+	PreserveCBOR bool `param:"preservecbor"`
 }
 
 // NewResponseData creates, initializes and returns a new instance of ResponseData
@@ -42,12 +46,21 @@ func (f ResponseData) SetResponseData(ctx interfaces.AppFunctionContext, data in
 		return false, nil
 	}
 
-	byteData, err := util.CoerceType(data)
+	var byteData []byte
+	var isCBOR bool
+	var err error
+	if f.PreserveCBOR {
+		byteData, isCBOR, err = ctx.SerializedCBORData(data)
+	} else {
+		byteData, err = ctx.SerializedData(data)
+	}
 	if err != nil {
 		return false, err
 	}
 
-	if len(f.ResponseContentType) > 0 {
+	if isCBOR {
+		ctx.SetResponseContentType(common.ContentTypeCBOR)
+	} else if len(f.ResponseContentType) > 0 {
 		ctx.SetResponseContentType(f.ResponseContentType)
 	}
 
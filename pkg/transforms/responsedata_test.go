@@ -20,8 +20,11 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/appfunction"
+
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/fxamacker/cbor/v2"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -94,3 +97,26 @@ func getExpectedEventXml(t *testing.T) string {
 	require.NoError(t, err)
 	return xml
 }
+
+func TestSetResponseDataPreserveCBOR(t *testing.T) {
+	event := dtos.NewEvent("camera", "camera-1", "image")
+	event.AddBinaryReading("image", []byte{0xff, 0xd8, 0xff, 0xe0}, "image/jpeg")
+
+	// The configured content type is used for JSON
+	cborCtx := appfunction.NewContext("123", dic, common.ContentTypeCBOR)
+	target := ResponseData{ResponseContentType: "application/vnd.acme+json"}
+	continuePipeline, _ := target.SetResponseData(cborCtx, event)
+	require.True(t, continuePipeline)
+	assert.Equal(t, "application/vnd.acme+json", cborCtx.ResponseContentType())
+	assert.True(t, json.Valid(cborCtx.ResponseData()))
+
+	// The CBOR content type is used for CBOR, whatever content type is configured
+	cborCtx = appfunction.NewContext("123", dic, common.ContentTypeCBOR)
+	target.PreserveCBOR = true
+	continuePipeline, _ = target.SetResponseData(cborCtx, event)
+	require.True(t, continuePipeline)
+	assert.Equal(t, common.ContentTypeCBOR, cborCtx.ResponseContentType())
+	actual := dtos.Event{}
+	require.NoError(t, cbor.Unmarshal(cborCtx.ResponseData(), &actual))
+	assert.Equal(t, event.Readings[0].BinaryValue, actual.Readings[0].BinaryValue)
+}
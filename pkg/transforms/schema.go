@@ -0,0 +1,122 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/util"
+)
+
+// SchemaViolationsContextKey is the context key the schema violations are stored under when invalid data is
+// passed to the dead-letter function
+const SchemaViolationsContextKey = "schemaviolations"
+
+// SchemaValidator validates data against a JSON Schema to enforce the data contract before export
+type SchemaValidator struct {
+	schema     *jsonSchema
+	registry   *schemaRegistry
+	deadLetter interfaces.AppFunction
+}
+
+// NewSchemaValidator creates, initializes and returns a new instance of SchemaValidator for the JSON Schema
+// document provided. If a dead-letter function is specified, data which fails validation is passed to it
+// rather than stopping the pipeline with an error.
+func NewSchemaValidator(schema []byte, deadLetter interfaces.AppFunction) (*SchemaValidator, error) {
+	compiled, err := compileJSONSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SchemaValidator{
+		schema:     compiled,
+		deadLetter: deadLetter,
+	}, nil
+}
+
+// NewSchemaValidatorWithRegistry creates, initializes and returns a new instance of SchemaValidator for the JSON
+// Schema of the subject and version in the schema registry, rather than a schema bundled with the service. The
+// schema is retrieved when the first data is validated and cached as specified by the options. If a dead-letter
+// function is specified, data which fails validation is passed to it rather than stopping the pipeline with an error.
+func NewSchemaValidatorWithRegistry(options SchemaRegistryOptions, deadLetter interfaces.AppFunction) (*SchemaValidator, error) {
+	registry, err := newSchemaRegistry(options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SchemaValidator{
+		registry:   registry,
+		deadLetter: deadLetter,
+	}, nil
+}
+
+// ValidateSchema validates the string, []byte, or json.Marshaller type data received against the JSON Schema and
+// passes on the data unchanged if it is valid. If the data isn't valid the pipeline is stopped with an error
+// describing the violations, or if a dead-letter function is configured the violations are stored in the context
+// under SchemaViolationsContextKey, the data is passed to the dead-letter function and the pipeline is stopped.
+func (validator *SchemaValidator) ValidateSchema(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, errors.New("No Data Received")
+	}
+
+	payload, err := util.CoerceType(data)
+	if err != nil {
+		return false, err
+	}
+
+	schema := validator.schema
+	if validator.registry != nil {
+		schema, err = validator.registry.retrieveSchema(ctx)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	var violations []string
+	var value interface{}
+	if err := json.Unmarshal(payload, &value); err != nil {
+		violations = []string{fmt.Sprintf("data is not valid JSON: %s", err.Error())}
+	} else {
+		violations = schema.validate(value, "$")
+	}
+
+	if len(violations) == 0 {
+		ctx.LoggingClient().Debugf("Data passed schema validation")
+		return true, data
+	}
+
+	description := strings.Join(violations, "; ")
+
+	if validator.deadLetter == nil {
+		return false, fmt.Errorf("data failed schema validation: %s", description)
+	}
+
+	ctx.LoggingClient().Warnf("Data failed schema validation and is being dead-lettered: %s", description)
+	ctx.AddValue(SchemaViolationsContextKey, description)
+
+	if ok, result := validator.deadLetter(ctx, data); !ok {
+		if err, isError := result.(error); isError {
+			return false, fmt.Errorf("unable to dead-letter data which failed schema validation: %s", err.Error())
+		}
+	}
+
+	return false, nil
+}
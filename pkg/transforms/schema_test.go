@@ -0,0 +1,184 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testEventSchema = `{
+	"type": "object",
+	"required": ["deviceName", "readings"],
+	"properties": {
+		"deviceName": {"type": "string", "pattern": "^[a-z]+-[0-9]+$"},
+		"readings": {"type": "array", "minItems": 1, "items": {"$ref": "#/definitions/reading"}}
+	},
+	"definitions": {
+		"reading": {
+			"type": "object",
+			"required": ["resourceName", "value"],
+			"properties": {
+				"resourceName": {"enum": ["temperature", "humidity"]},
+				"value": {"type": "string", "minLength": 1}
+			}
+		}
+	}
+}`
+
+func TestJSONSchemaValidate(t *testing.T) {
+	tests := []struct {
+		Name               string
+		Schema             string
+		Data               string
+		ExpectedViolations []string
+	}{
+		{"Type", `{"type": "integer"}`, `1.5`, []string{"$: expected type integer but found number"}},
+		{"Multiple types", `{"type": ["string", "null"]}`, `null`, nil},
+		{"Required and additional", `{"required": ["a"], "additionalProperties": false}`, `{"b": 1}`,
+			[]string{"$: missing required property 'a'", "$.b: not allowed"}},
+		{"Const", `{"const": {"a": [1, 2]}}`, `{"a": [1, 2]}`, nil},
+		{"Numeric bounds", `{"minimum": 1, "exclusiveMaximum": 10}`, `10`, []string{"$: 10 is not less than the exclusive maximum of 10"}},
+		{"Array bounds", `{"maxItems": 1, "items": {"type": "boolean"}}`, `[true, 1]`,
+			[]string{"$: expected at most 1 items but found 2", "$[1]: expected type boolean but found number"}},
+		{"String length", `{"maxLength": 2}`, `"äöü"`, []string{"$: expected a length of at most 2 but found 3"}},
+		{"AnyOf", `{"anyOf": [{"type": "string"}, {"minimum": 5}]}`, `3`, []string{"$: value does not match any of the anyOf schemas"}},
+		{"OneOf", `{"oneOf": [{"type": "number"}, {"minimum": 5}]}`, `6`, []string{"$: value matches 2 of the oneOf schemas, expected exactly 1"}},
+		{"Not", `{"not": {"type": "null"}}`, `null`, []string{"$: value must not match the 'not' schema"}},
+		{"Draft-04 exclusive minimum", `{"minimum": 0, "exclusiveMinimum": true}`, `0`,
+			[]string{"$: 0 is not greater than the exclusive minimum of 0"}},
+		{"Draft-04 inclusive maximum", `{"maximum": 10, "exclusiveMaximum": false}`, `10`, nil},
+		{"Annotations", `{"$schema": "http://json-schema.org/draft-07/schema#", "title": "t", "format": "date-time"}`, `"now"`, nil},
+		{"Recursive ref", `{"properties": {"child": {"$ref": "#"}}, "required": ["id"]}`, `{"id": 1, "child": {"id": 2, "child": {}}}`,
+			[]string{"$.child.child: missing required property 'id'"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			schema, err := compileJSONSchema([]byte(test.Schema))
+			require.NoError(t, err)
+
+			validator := SchemaValidator{schema: schema}
+			continuePipeline, result := validator.ValidateSchema(ctx, test.Data)
+			if test.ExpectedViolations == nil {
+				assert.True(t, continuePipeline, "unexpected result: %v", result)
+				return
+			}
+
+			require.False(t, continuePipeline)
+			var value interface{}
+			require.NoError(t, json.Unmarshal([]byte(test.Data), &value))
+			assert.Equal(t, test.ExpectedViolations, schema.validate(value, "$"))
+		})
+	}
+}
+
+func TestNewSchemaValidatorBadSchema(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Schema string
+	}{
+		{"Not JSON", `{`},
+		{"Not an object", `"string"`},
+		{"Bad pattern", `{"pattern": "["}`},
+		{"Bad minLength", `{"minLength": -1}`},
+		{"Empty anyOf", `{"anyOf": []}`},
+		{"Remote ref", `{"$ref": "http://example.com/schema.json"}`},
+		{"Unresolved ref", `{"$ref": "#/definitions/missing"}`},
+		{"Unsupported keyword", `{"properties": {"a": {"uniqueItems": true}}}`},
+		{"Misspelt keyword", `{"requierd": ["a"]}`},
+		{"Tuple items", `{"items": [{"type": "string"}]}`},
+		{"Unsupported draft", `{"$schema": "https://json-schema.org/draft/2020-12/schema"}`},
+		{"Draft-04 exclusive minimum without minimum", `{"exclusiveMinimum": true}`},
+		{"ECMA-262 lookahead", `{"pattern": "^(?=.*[0-9])"}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := NewSchemaValidator([]byte(test.Schema), nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestValidateSchemaEvent(t *testing.T) {
+	validator, err := NewSchemaValidator([]byte(testEventSchema), nil)
+	require.NoError(t, err)
+
+	event := newTestEvent(
+		t,
+		"sensor-1",
+		nil,
+		testReading{resourceName: "temperature", valueType: common.ValueTypeInt64, value: int64(72)})
+
+	continuePipeline, result := validator.ValidateSchema(ctx, event)
+	require.True(t, continuePipeline, "unexpected result: %v", result)
+	assert.Equal(t, event, result)
+
+	event.DeviceName = "Sensor 1"
+	event.Readings[0].ResourceName = "pressure"
+	continuePipeline, result = validator.ValidateSchema(ctx, event)
+	require.False(t, continuePipeline)
+	assert.EqualError(t, result.(error),
+		"data failed schema validation: $.deviceName: value does not match the pattern '^[a-z]+-[0-9]+$'; "+
+			"$.readings[0].resourceName: value is not one of the allowed values")
+}
+
+func TestValidateSchemaDeadLetter(t *testing.T) {
+	var deadLettered interface{}
+	var deadLetterErr error
+	deadLetter := func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		deadLettered = data
+		if deadLetterErr != nil {
+			return false, deadLetterErr
+		}
+		return true, data
+	}
+
+	validator, err := NewSchemaValidator([]byte(`{"type": "object"}`), deadLetter)
+	require.NoError(t, err)
+
+	continuePipeline, result := validator.ValidateSchema(ctx, "not json")
+	assert.False(t, continuePipeline)
+	assert.Nil(t, result)
+	assert.Equal(t, "not json", deadLettered)
+	violations, found := ctx.GetValue(SchemaViolationsContextKey)
+	require.True(t, found)
+	assert.Contains(t, violations, "data is not valid JSON")
+
+	deadLetterErr = errors.New("disk full")
+	continuePipeline, result = validator.ValidateSchema(ctx, "[]")
+	assert.False(t, continuePipeline)
+	assert.EqualError(t, result.(error), "unable to dead-letter data which failed schema validation: disk full")
+}
+
+func TestValidateSchemaNoData(t *testing.T) {
+	validator, err := NewSchemaValidator([]byte(`true`), nil)
+	require.NoError(t, err)
+
+	continuePipeline, result := validator.ValidateSchema(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.EqualError(t, result.(error), "No Data Received")
+}
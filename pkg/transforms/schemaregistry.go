@@ -0,0 +1,300 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+)
+
+const (
+	// SchemaRegistryConfluent is a Confluent Schema Registry, which the JSON Schemas are retrieved from with
+	// its REST API. Only schemas with the JSON schema type can be used.
+	SchemaRegistryConfluent = "confluent"
+	// SchemaRegistryHTTP is a plain HTTP store which responds with the JSON Schema document at the registry URL,
+	// in which SchemaSubjectPlaceholder and SchemaVersionPlaceholder are replaced
+	SchemaRegistryHTTP = "http"
+	// SchemaSubjectPlaceholder is replaced in the URL of a plain HTTP store with the URL escaped subject
+	SchemaSubjectPlaceholder = "{subject}"
+	// SchemaVersionPlaceholder is replaced in the URL of a plain HTTP store with the URL escaped version
+	SchemaVersionPlaceholder = "{version}"
+	// SchemaVersionLatest is the version of the latest schema registered for a subject
+	SchemaVersionLatest = "latest"
+)
+
+// confluentSchemaContentType is the content type of the Confluent Schema Registry REST API
+const confluentSchemaContentType = "application/vnd.schemaregistry.v1+json"
+
+// The schema is retrieved again after a failure once the retry interval has passed, which doubles after each
+// consecutive failure up to the max retry interval
+const (
+	schemaRetryInterval    = time.Second
+	maxSchemaRetryInterval = time.Minute
+)
+
+// SchemaRegistryOptions contains all options available to retrieve the JSON Schema from a schema registry
+type SchemaRegistryOptions struct {
+	// URL of the schema registry. For a plain HTTP store it must contain SchemaSubjectPlaceholder.
+	URL string `param:"registryurl,required"`
+	// Type of the schema registry, either SchemaRegistryConfluent or SchemaRegistryHTTP
+	Type string `param:"registrytype" default:"confluent"`
+	// Subject the schema is registered under
+	Subject string `param:"subject,required"`
+	// Version of the schema, either a version number or SchemaVersionLatest
+	Version string `param:"version" default:"latest"`
+	// HeaderName is the optional name of the header sent with the value of the secret at SecretPath/SecretName,
+	// i.e. 'Authorization' with a 'Basic ...' secret for a Confluent Schema Registry
+	HeaderName string `param:"headername"`
+	SecretPath string `param:"secretpath"`
+	SecretName string `param:"secretname"`
+	// Timeout for each schema request. Zero means no timeout.
+	Timeout time.Duration `param:"requesttimeout" default:"10s"`
+	// CacheTTL is how long the latest schema is cached before it is retrieved again. Schemas of a specific version
+	// never change, so are retrieved once.
+	CacheTTL time.Duration `param:"cachettl" default:"5m"`
+}
+
+// schemaRegistry retrieves and caches the compiled JSON Schema of a subject and version from a schema registry.
+// Only one pipeline execution retrieves the schema at a time, without holding the mutex, while the others continue
+// with the cached schema or wait for it when none has been retrieved yet.
+type schemaRegistry struct {
+	options       SchemaRegistryOptions
+	mutex         sync.Mutex
+	schema        *jsonSchema
+	expires       time.Time
+	retrieving    chan struct{}
+	lastErr       error
+	retryAt       time.Time
+	retryInterval time.Duration
+	now           func() time.Time
+}
+
+// confluentSchemaResponse is the response of the Confluent Schema Registry for a version of a subject
+type confluentSchemaResponse struct {
+	Subject    string `json:"subject"`
+	Version    int    `json:"version"`
+	ID         int    `json:"id"`
+	SchemaType string `json:"schemaType"`
+	Schema     string `json:"schema"`
+}
+
+func newSchemaRegistry(options SchemaRegistryOptions) (*schemaRegistry, error) {
+	options.Type = strings.ToLower(strings.TrimSpace(options.Type))
+	options.Subject = strings.TrimSpace(options.Subject)
+	options.Version = strings.TrimSpace(options.Version)
+	if len(options.Version) == 0 {
+		options.Version = SchemaVersionLatest
+	}
+
+	if len(options.URL) == 0 {
+		return nil, errors.New("schema registry URL must be specified")
+	}
+
+	if len(options.Subject) == 0 {
+		return nil, errors.New("schema subject must be specified")
+	}
+
+	switch options.Type {
+	case SchemaRegistryConfluent:
+	case SchemaRegistryHTTP:
+		if !strings.Contains(options.URL, SchemaSubjectPlaceholder) {
+			return nil, fmt.Errorf("schema registry URL must contain the '%s' placeholder", SchemaSubjectPlaceholder)
+		}
+	default:
+		return nil, fmt.Errorf("invalid schema registry type '%s'. Must be '%s' or '%s'",
+			options.Type, SchemaRegistryConfluent, SchemaRegistryHTTP)
+	}
+
+	if len(options.HeaderName) > 0 && (len(options.SecretPath) == 0 || len(options.SecretName) == 0) {
+		return nil, errors.New("secret path and secret name must be specified with the header name")
+	}
+
+	return &schemaRegistry{
+		options:       options,
+		retryInterval: schemaRetryInterval,
+		now:           time.Now,
+	}, nil
+}
+
+// retrieveSchema returns the cached schema, retrieving it from the registry first if not cached or expired.
+// The expired schema continues to be used while it is retrieved again and if it can't be, in which case it isn't
+// retrieved again until the retry interval has passed.
+func (registry *schemaRegistry) retrieveSchema(ctx interfaces.AppFunctionContext) (*jsonSchema, error) {
+	for {
+		registry.mutex.Lock()
+		cached := registry.schema
+		if cached != nil && (registry.options.Version != SchemaVersionLatest || registry.now().Before(registry.expires)) {
+			registry.mutex.Unlock()
+			return cached, nil
+		}
+
+		if retrieving := registry.retrieving; retrieving != nil {
+			registry.mutex.Unlock()
+			if cached != nil {
+				return cached, nil
+			}
+
+			// No schema to use until the retrieval in progress completes
+			<-retrieving
+			continue
+		}
+
+		if registry.now().Before(registry.retryAt) {
+			lastErr := registry.lastErr
+			registry.mutex.Unlock()
+			if cached != nil {
+				return cached, nil
+			}
+			return nil, lastErr
+		}
+
+		retrieving := make(chan struct{})
+		registry.retrieving = retrieving
+		registry.mutex.Unlock()
+
+		compiled, err := registry.retrieve(ctx)
+
+		registry.mutex.Lock()
+		registry.retrieving = nil
+		close(retrieving)
+		if err == nil {
+			registry.schema = compiled
+			registry.expires = registry.now().Add(registry.options.CacheTTL)
+			registry.lastErr = nil
+			registry.retryInterval = schemaRetryInterval
+		} else {
+			registry.lastErr = err
+			registry.retryAt = registry.now().Add(registry.retryInterval)
+			registry.retryInterval *= 2
+			if registry.retryInterval > maxSchemaRetryInterval {
+				registry.retryInterval = maxSchemaRetryInterval
+			}
+		}
+		registry.mutex.Unlock()
+
+		if err == nil {
+			return compiled, nil
+		}
+
+		if cached == nil {
+			return nil, err
+		}
+
+		ctx.LoggingClient().Warnf("Using the previously retrieved schema: %s", err.Error())
+		return cached, nil
+	}
+}
+
+// retrieve requests the schema from the registry and compiles it
+func (registry *schemaRegistry) retrieve(ctx interfaces.AppFunctionContext) (*jsonSchema, error) {
+	document, err := registry.requestSchema(ctx)
+	if err == nil {
+		var compiled *jsonSchema
+		compiled, err = compileJSONSchema(document)
+		if err == nil {
+			return compiled, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unable to retrieve schema for subject '%s' version '%s': %w",
+		registry.options.Subject, registry.options.Version, err)
+}
+
+// requestSchema requests the JSON Schema document from the registry
+func (registry *schemaRegistry) requestSchema(ctx interfaces.AppFunctionContext) ([]byte, error) {
+	subject := url.PathEscape(registry.options.Subject)
+	version := url.PathEscape(registry.options.Version)
+
+	var schemaUrl string
+	if registry.options.Type == SchemaRegistryConfluent {
+		schemaUrl = fmt.Sprintf("%s/subjects/%s/versions/%s", strings.TrimSuffix(registry.options.URL, "/"), subject, version)
+	} else {
+		schemaUrl = strings.ReplaceAll(registry.options.URL, SchemaSubjectPlaceholder, subject)
+		schemaUrl = strings.ReplaceAll(schemaUrl, SchemaVersionPlaceholder, version)
+	}
+
+	requestCtx := context.Background()
+	if registry.options.Timeout > 0 {
+		var cancel context.CancelFunc
+		requestCtx, cancel = context.WithTimeout(requestCtx, registry.options.Timeout)
+		defer cancel()
+	}
+
+	request, err := http.NewRequestWithContext(requestCtx, http.MethodGet, schemaUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if registry.options.Type == SchemaRegistryConfluent {
+		request.Header.Set("Accept", confluentSchemaContentType)
+	}
+
+	if len(registry.options.HeaderName) > 0 {
+		secrets, err := ctx.GetSecret(registry.options.SecretPath, registry.options.SecretName)
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Set(registry.options.HeaderName, secrets[registry.options.SecretName])
+	}
+
+	ctx.LoggingClient().Debugf("Retrieving schema from %s", schemaUrl)
+
+	response, err := ctx.HttpClient().Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return nil, fmt.Errorf("request failed with %d HTTP status code", response.StatusCode)
+	}
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read response: %w", err)
+	}
+
+	if registry.options.Type == SchemaRegistryHTTP {
+		return body, nil
+	}
+
+	var schemaResponse confluentSchemaResponse
+	if err := json.Unmarshal(body, &schemaResponse); err != nil {
+		return nil, fmt.Errorf("response is not a schema registry response: %w", err)
+	}
+
+	// The schema type is omitted for Avro schemas, which is the default schema type of the Confluent Schema Registry
+	if !strings.EqualFold(schemaResponse.SchemaType, "JSON") {
+		schemaType := schemaResponse.SchemaType
+		if len(schemaType) == 0 {
+			schemaType = "AVRO"
+		}
+		return nil, fmt.Errorf("schema type is %s rather than JSON", schemaType)
+	}
+
+	return []byte(schemaResponse.Schema), nil
+}
@@ -0,0 +1,257 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSchemaValidatorWithRegistry(t *testing.T) {
+	tests := []struct {
+		Name          string
+		Options       SchemaRegistryOptions
+		ExpectedError bool
+	}{
+		{"Valid - confluent", SchemaRegistryOptions{URL: "http://localhost:8081", Type: "Confluent", Subject: "events-value"}, false},
+		{"Valid - http", SchemaRegistryOptions{URL: "http://localhost/{subject}.json", Type: SchemaRegistryHTTP, Subject: "events"}, false},
+		{"Missing URL", SchemaRegistryOptions{Type: SchemaRegistryConfluent, Subject: "events-value"}, true},
+		{"Missing subject", SchemaRegistryOptions{URL: "http://localhost:8081", Type: SchemaRegistryConfluent}, true},
+		{"Bad type", SchemaRegistryOptions{URL: "http://localhost:8081", Type: "bogus", Subject: "events-value"}, true},
+		{"Missing subject placeholder", SchemaRegistryOptions{URL: "http://localhost/events.json", Type: SchemaRegistryHTTP, Subject: "events"}, true},
+		{"Header without secret", SchemaRegistryOptions{URL: "http://localhost:8081", Type: SchemaRegistryConfluent, Subject: "events-value", HeaderName: "Authorization"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := NewSchemaValidatorWithRegistry(test.Options, nil)
+			if test.ExpectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func newConfluentRegistryServer(t *testing.T, calls *int32, schemaType *atomic.Value, unavailable *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		atomic.AddInt32(calls, 1)
+		assert.Equal(t, confluentSchemaContentType, request.Header.Get("Accept"))
+
+		if atomic.LoadInt32(unavailable) != 0 {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		version := 0
+		switch request.URL.Path {
+		case "/subjects/events-value/versions/3":
+			version = 3
+		case "/subjects/events-value/versions/latest":
+			version = 4
+		default:
+			writer.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		body, _ := json.Marshal(confluentSchemaResponse{
+			Subject:    "events-value",
+			Version:    version,
+			ID:         100 + version,
+			SchemaType: schemaType.Load().(string),
+			Schema:     testEventSchema,
+		})
+		writer.Header().Set("Content-Type", confluentSchemaContentType)
+		_, _ = writer.Write(body)
+	}))
+}
+
+func TestValidateSchemaConfluentRegistry(t *testing.T) {
+	var calls int32
+	var schemaType atomic.Value
+	schemaType.Store("JSON")
+	var unavailable int32
+	ts := newConfluentRegistryServer(t, &calls, &schemaType, &unavailable)
+	defer ts.Close()
+
+	validator, err := NewSchemaValidatorWithRegistry(SchemaRegistryOptions{
+		URL:     ts.URL + "/",
+		Type:    SchemaRegistryConfluent,
+		Subject: "events-value",
+		Version: "3",
+	}, nil)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		continuePipeline, result := validator.ValidateSchema(ctx, `{"deviceName": "sensor-1", "readings": [{"resourceName": "humidity", "value": "45"}]}`)
+		require.True(t, continuePipeline, "unexpected result: %v", result)
+
+		continuePipeline, result = validator.ValidateSchema(ctx, `{"deviceName": "Sensor 1"}`)
+		require.False(t, continuePipeline)
+		assert.Contains(t, result.(error).Error(), "data failed schema validation")
+	}
+
+	// Versions never change, so the schema is only retrieved once
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// Avro schemas have no schema type
+	schemaType.Store("")
+	avroValidator, err := NewSchemaValidatorWithRegistry(SchemaRegistryOptions{
+		URL:     ts.URL,
+		Type:    SchemaRegistryConfluent,
+		Subject: "events-value",
+		Version: "3",
+	}, nil)
+	require.NoError(t, err)
+
+	continuePipeline, result := avroValidator.ValidateSchema(ctx, `{}`)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "schema type is AVRO rather than JSON")
+}
+
+func TestValidateSchemaLatestFromRegistry(t *testing.T) {
+	var calls int32
+	var schemaType atomic.Value
+	schemaType.Store("JSON")
+	var unavailable int32
+	ts := newConfluentRegistryServer(t, &calls, &schemaType, &unavailable)
+	defer ts.Close()
+
+	validator, err := NewSchemaValidatorWithRegistry(SchemaRegistryOptions{
+		URL:      ts.URL,
+		Type:     SchemaRegistryConfluent,
+		Subject:  "events-value",
+		CacheTTL: time.Minute,
+	}, nil)
+	require.NoError(t, err)
+
+	now := time.Now()
+	validator.registry.now = func() time.Time { return now }
+
+	event := dtos.NewEvent("profile", "sensor-1", "source")
+	continuePipeline, result := validator.ValidateSchema(ctx, event)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "readings")
+
+	continuePipeline, _ = validator.ValidateSchema(ctx, event)
+	require.False(t, continuePipeline)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// Cache expired
+	now = now.Add(2 * time.Minute)
+	continuePipeline, _ = validator.ValidateSchema(ctx, event)
+	require.False(t, continuePipeline)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	// The expired schema is used when the registry is unavailable
+	atomic.StoreInt32(&unavailable, 1)
+	now = now.Add(2 * time.Minute)
+	continuePipeline, result = validator.ValidateSchema(ctx, event)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "data failed schema validation")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	// The registry isn't requested again until the retry interval has passed, which doubles after each failure
+	continuePipeline, result = validator.ValidateSchema(ctx, event)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "data failed schema validation")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	now = now.Add(schemaRetryInterval)
+	validator.ValidateSchema(ctx, event)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+
+	now = now.Add(schemaRetryInterval)
+	validator.ValidateSchema(ctx, event)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+
+	// Retrieved again once the registry is available
+	atomic.StoreInt32(&unavailable, 0)
+	now = now.Add(schemaRetryInterval)
+	validator.ValidateSchema(ctx, event)
+	assert.Equal(t, int32(5), atomic.LoadInt32(&calls))
+	validator.ValidateSchema(ctx, event)
+	assert.Equal(t, int32(5), atomic.LoadInt32(&calls))
+}
+
+func TestValidateSchemaRegistryUnavailable(t *testing.T) {
+	var calls int32
+	var schemaType atomic.Value
+	schemaType.Store("JSON")
+	unavailable := int32(1)
+	ts := newConfluentRegistryServer(t, &calls, &schemaType, &unavailable)
+	defer ts.Close()
+
+	validator, err := NewSchemaValidatorWithRegistry(SchemaRegistryOptions{
+		URL:     ts.URL,
+		Type:    SchemaRegistryConfluent,
+		Subject: "events-value",
+		Version: "3",
+	}, nil)
+	require.NoError(t, err)
+
+	// Without a schema retrieved the data can't be validated until the registry is available
+	for i := 0; i < 2; i++ {
+		continuePipeline, result := validator.ValidateSchema(ctx, `{}`)
+		require.False(t, continuePipeline)
+		assert.Contains(t, result.(error).Error(), "503")
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestValidateSchemaHTTPStore(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/schemas/edgex events/v2.json" {
+			writer.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = writer.Write([]byte(`{"type": "object", "required": ["deviceName"]}`))
+	}))
+	defer ts.Close()
+
+	validator, err := NewSchemaValidatorWithRegistry(SchemaRegistryOptions{
+		URL:     ts.URL + "/schemas/{subject}/{version}.json",
+		Type:    SchemaRegistryHTTP,
+		Subject: "edgex events",
+		Version: "v2",
+	}, nil)
+	require.NoError(t, err)
+
+	continuePipeline, result := validator.ValidateSchema(ctx, dtos.NewEvent("profile", "sensor-1", "source"))
+	require.True(t, continuePipeline, "unexpected result: %v", result)
+
+	missing, err := NewSchemaValidatorWithRegistry(SchemaRegistryOptions{
+		URL:     ts.URL + "/schemas/{subject}.json",
+		Type:    SchemaRegistryHTTP,
+		Subject: "unknown",
+	}, nil)
+	require.NoError(t, err)
+
+	continuePipeline, result = missing.ValidateSchema(ctx, `{}`)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "404")
+}
@@ -0,0 +1,373 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/util"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+)
+
+const (
+	// SignatureModeHeader leaves the payload unchanged and stores the signature in the context storage under
+	// SignatureContextKey so it can be sent as a header by the export function, i.e. HTTPSender
+	SignatureModeHeader = "header"
+	// SignatureModeEnvelope wraps the payload and its signature in a SignedEnvelope
+	SignatureModeEnvelope = "envelope"
+	// SignatureModeJWS expects the payload to be a JWS in compact serialization signed with HS256
+	SignatureModeJWS = "jws"
+	// SignatureContextKey is the context storage key the signature is stored under when using SignatureModeHeader
+	SignatureContextKey = "signature"
+	// SignatureAlgorithmHMACSHA256 identifies HMAC-SHA256 signatures
+	SignatureAlgorithmHMACSHA256 = "HMAC-SHA256"
+	// DefaultSignatureHeaderName is the request header a SignatureVerifier using SignatureModeHeader expects the
+	// signature in when it isn't in the context storage
+	DefaultSignatureHeaderName = "X-Signature"
+	// SignatureMismatchesMetricName is the name of the counter of the payloads received by a SignatureVerifier whose
+	// signature did not match, which is tagged with the pipeline, function and position the verifier executed at
+	SignatureMismatchesMetricName = "SignatureMismatches"
+
+	jwsAlgorithmHS256 = "HS256"
+)
+
+// SignedEnvelope is the JSON envelope produced when signing with SignatureModeEnvelope.
+// The Payload is base64 encoded when marshaled to JSON and the Signature is hex encoded.
+type SignedEnvelope struct {
+	Payload   []byte `json:"payload"`
+	Algorithm string `json:"algorithm"`
+	Signature string `json:"signature"`
+}
+
+// HMACSigner signs payloads with HMAC-SHA256 using a key retrieved from the Secret Store
+type HMACSigner struct {
+	secretPath string
+	secretName string
+	mode       string
+}
+
+// NewHMACSigner creates, initializes and returns a new instance of HMACSigner which retrieves the signing key
+// from the Secret Store at the specified path and name and attaches the signature as per the specified mode.
+func NewHMACSigner(secretPath string, secretName string, mode string) (HMACSigner, error) {
+	if len(secretPath) == 0 || len(secretName) == 0 {
+		return HMACSigner{}, errors.New("secretPath & secretName must be specified for HMAC signing")
+	}
+
+	if mode != SignatureModeHeader && mode != SignatureModeEnvelope {
+		return HMACSigner{}, fmt.Errorf("invalid signature mode '%s', must be '%s' or '%s'",
+			mode, SignatureModeHeader, SignatureModeEnvelope)
+	}
+
+	return HMACSigner{
+		secretPath: secretPath,
+		secretName: secretName,
+		mode:       mode,
+	}, nil
+}
+
+// SignWithHMAC computes the HMAC-SHA256 signature of a string, []byte, or json.Marshaller type payload.
+// With SignatureModeHeader the payload is passed on unchanged and the hex encoded signature is stored in the
+// context storage under SignatureContextKey. With SignatureModeEnvelope the payload is wrapped in a SignedEnvelope
+// which is passed on as JSON.
+func (signer HMACSigner) SignWithHMAC(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, errors.New("No Data Received")
+	}
+
+	ctx.LoggingClient().Debug("Signing with HMAC-SHA256")
+
+	payload, err := util.CoerceType(data)
+	if err != nil {
+		return false, err
+	}
+
+	key, err := retrieveSigningKey(ctx, signer.secretPath, signer.secretName)
+	if err != nil {
+		return false, err
+	}
+
+	signature := hex.EncodeToString(computeHMAC(key, payload))
+
+	if signer.mode == SignatureModeHeader {
+		ctx.AddValue(SignatureContextKey, signature)
+		return true, payload
+	}
+
+	envelope, err := json.Marshal(SignedEnvelope{
+		Payload:   payload,
+		Algorithm: SignatureAlgorithmHMACSHA256,
+		Signature: signature,
+	})
+	if err != nil {
+		return false, fmt.Errorf("unable to marshal signed envelope: %s", err.Error())
+	}
+
+	ctx.SetResponseContentType(common.ContentTypeJSON)
+
+	return true, envelope
+}
+
+// SignatureVerifier verifies HMAC-SHA256 signatures on incoming payloads using a key retrieved from the
+// Secret Store. The number of signature mismatches is tracked separately from other failures so that
+// tampered or unauthorized payloads can be distinguished from malformed ones, and is reported as the
+// SignatureMismatchesMetricName counter.
+type SignatureVerifier struct {
+	secretPath string
+	secretName string
+	mode       string
+	headerName string
+	mismatches uint64
+	mutex      sync.Mutex
+	counters   map[mismatchCounterKey]interfaces.Counter
+}
+
+// mismatchCounterKey identifies the mismatch counter registered with a MetricsManager for the tags of a pipeline
+// function
+type mismatchCounterKey struct {
+	manager interfaces.MetricsManager
+	tags    string
+}
+
+// NewSignatureVerifier creates, initializes and returns a new instance of SignatureVerifier which retrieves the
+// verification key from the Secret Store at the specified path and name and expects signatures as per the
+// specified mode, which is one of SignatureModeHeader, SignatureModeEnvelope or SignatureModeJWS.
+// With SignatureModeHeader the hex encoded signature is expected in the context storage under SignatureContextKey or
+// in the DefaultSignatureHeaderName request header.
+func NewSignatureVerifier(secretPath string, secretName string, mode string) (*SignatureVerifier, error) {
+	return NewSignatureVerifierWithHeader(secretPath, secretName, mode, DefaultSignatureHeaderName)
+}
+
+// NewSignatureVerifierWithHeader creates a SignatureVerifier like NewSignatureVerifier, which with
+// SignatureModeHeader expects the signature in the request header with the specified name when it isn't in the
+// context storage. The request headers are stored in the context by the HTTP trigger. The MessageBus envelope has no
+// headers, so data received from the MessageBus must be signed with SignatureModeEnvelope or SignatureModeJWS.
+func NewSignatureVerifierWithHeader(secretPath string, secretName string, mode string, headerName string) (*SignatureVerifier, error) {
+	if len(secretPath) == 0 || len(secretName) == 0 {
+		return nil, errors.New("secretPath & secretName must be specified for signature verification")
+	}
+
+	if mode != SignatureModeHeader && mode != SignatureModeEnvelope && mode != SignatureModeJWS {
+		return nil, fmt.Errorf("invalid signature mode '%s', must be '%s', '%s' or '%s'",
+			mode, SignatureModeHeader, SignatureModeEnvelope, SignatureModeJWS)
+	}
+
+	if len(headerName) == 0 {
+		headerName = DefaultSignatureHeaderName
+	}
+
+	return &SignatureVerifier{
+		secretPath: secretPath,
+		secretName: secretName,
+		mode:       mode,
+		headerName: headerName,
+		counters:   make(map[mismatchCounterKey]interfaces.Counter),
+	}, nil
+}
+
+// VerifySignature verifies the signature of a string, []byte, or json.Marshaller type payload and passes on the
+// verified payload, unwrapped from the envelope or JWS, as []byte. It will return an error and stop the pipeline
+// if no data is received, if the payload is malformed or if the signature does not match.
+func (verifier *SignatureVerifier) VerifySignature(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, errors.New("No Data Received")
+	}
+
+	ctx.LoggingClient().Debugf("Verifying %s signature", verifier.mode)
+
+	input, err := util.CoerceType(data)
+	if err != nil {
+		return false, err
+	}
+
+	key, err := retrieveSigningKey(ctx, verifier.secretPath, verifier.secretName)
+	if err != nil {
+		return false, err
+	}
+
+	var payload []byte
+	var valid bool
+
+	switch verifier.mode {
+	case SignatureModeHeader:
+		payload, valid, err = verifyHeaderSignature(ctx, key, input, verifier.headerName)
+	case SignatureModeEnvelope:
+		payload, valid, err = verifyEnvelopeSignature(key, input)
+	default:
+		payload, valid, err = verifyJWSSignature(key, input)
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	if !valid {
+		atomic.AddUint64(&verifier.mismatches, 1)
+		if counter := verifier.mismatchCounter(ctx); counter != nil {
+			counter.Inc(1)
+		}
+		return false, errors.New("signature verification failed: signature mismatch")
+	}
+
+	return true, payload
+}
+
+// Mismatches returns the number of payloads received whose signature did not match
+func (verifier *SignatureVerifier) Mismatches() uint64 {
+	return atomic.LoadUint64(&verifier.mismatches)
+}
+
+// mismatchCounter returns the mismatch counter of the pipeline function the verifier is executing as, which is
+// created and registered the first time a mismatch is counted. Returns nil when no MetricsManager is available.
+func (verifier *SignatureVerifier) mismatchCounter(ctx interfaces.AppFunctionContext) interfaces.Counter {
+	manager := ctx.MetricsManager()
+	if manager == nil {
+		return nil
+	}
+
+	tags := map[string]string{
+		PipelineTag: ctx.PipelineId(),
+		FunctionTag: ctx.FunctionName(),
+		PositionTag: strconv.Itoa(ctx.PipelinePosition()),
+	}
+	key := mismatchCounterKey{
+		manager: manager,
+		tags:    tags[PipelineTag] + "/" + tags[PositionTag] + "/" + tags[FunctionTag],
+	}
+
+	verifier.mutex.Lock()
+	defer verifier.mutex.Unlock()
+
+	if counter, found := verifier.counters[key]; found {
+		return counter
+	}
+
+	counter := manager.NewCounter()
+	if err := manager.RegisterLabeled(SignatureMismatchesMetricName, counter, tags); err != nil {
+		ctx.LoggingClient().Warnf("Unable to register %s metric: %s", SignatureMismatchesMetricName, err.Error())
+	}
+	verifier.counters[key] = counter
+
+	return counter
+}
+
+// verifyHeaderSignature verifies the signature in the context storage under SignatureContextKey, or else in the
+// request header with the name stored in the context by the HTTP trigger
+func verifyHeaderSignature(ctx interfaces.AppFunctionContext, key []byte, payload []byte, headerName string) ([]byte, bool, error) {
+	signature, found := ctx.GetValue(SignatureContextKey)
+	if !found {
+		signature, found = ctx.GetValue(interfaces.HTTPHEADERPREFIX + headerName)
+	}
+	if !found {
+		return nil, false, fmt.Errorf("no signature found in context under '%s' or in the '%s' header",
+			SignatureContextKey, headerName)
+	}
+
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to decode signature: %s", err.Error())
+	}
+
+	return payload, hmac.Equal(expected, computeHMAC(key, payload)), nil
+}
+
+func verifyEnvelopeSignature(key []byte, input []byte) ([]byte, bool, error) {
+	envelope := SignedEnvelope{}
+	if err := json.Unmarshal(input, &envelope); err != nil {
+		return nil, false, fmt.Errorf("unable to unmarshal signed envelope: %s", err.Error())
+	}
+
+	if envelope.Algorithm != SignatureAlgorithmHMACSHA256 {
+		return nil, false, fmt.Errorf("unsupported signature algorithm '%s'", envelope.Algorithm)
+	}
+
+	expected, err := hex.DecodeString(envelope.Signature)
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to decode signature: %s", err.Error())
+	}
+
+	return envelope.Payload, hmac.Equal(expected, computeHMAC(key, envelope.Payload)), nil
+}
+
+func verifyJWSSignature(key []byte, input []byte) ([]byte, bool, error) {
+	parts := strings.Split(strings.TrimSpace(string(input)), ".")
+	if len(parts) != 3 {
+		return nil, false, errors.New("payload is not a JWS in compact serialization")
+	}
+
+	headerData, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to decode JWS header: %s", err.Error())
+	}
+
+	header := struct {
+		Algorithm string `json:"alg"`
+	}{}
+	if err := json.Unmarshal(headerData, &header); err != nil {
+		return nil, false, fmt.Errorf("unable to unmarshal JWS header: %s", err.Error())
+	}
+
+	// Only accept the expected algorithm so 'none' or asymmetric algorithms can't be substituted
+	if header.Algorithm != jwsAlgorithmHS256 {
+		return nil, false, fmt.Errorf("unsupported JWS algorithm '%s'", header.Algorithm)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to decode JWS payload: %s", err.Error())
+	}
+
+	expected, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to decode JWS signature: %s", err.Error())
+	}
+
+	return payload, hmac.Equal(expected, computeHMAC(key, []byte(parts[0]+"."+parts[1]))), nil
+}
+
+func computeHMAC(key []byte, payload []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+func retrieveSigningKey(ctx interfaces.AppFunctionContext, secretPath string, secretName string) ([]byte, error) {
+	// Note secrets are cached so this call doesn't result in unneeded calls to SecretStore Service and
+	// the cache is invalidated when StoreSecrets is used.
+	secretData, err := ctx.GetSecret(secretPath, secretName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve signing key at secret path=%s and name=%s", secretPath, secretName)
+	}
+
+	key, ok := secretData[secretName]
+	if !ok || len(key) == 0 {
+		return nil, fmt.Errorf("unable find signing key in secret data for name=%s", secretName)
+	}
+
+	return []byte(key), nil
+}
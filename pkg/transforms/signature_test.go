@@ -0,0 +1,328 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/appfunction"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/bootstrap/container"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/internal/telemetry"
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/interfaces/mocks"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	signingSecretPath = "hmac"
+	signingSecretName = "signingKey"
+	signingKey        = "my-signing-key"
+	signingPayload    = `{"value":"some data"}`
+)
+
+func expectedHMAC(payload string) string {
+	mac := hmac.New(sha256.New, []byte(signingKey))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newSigningTestContext returns a context whose container has a mock SecretProvider with the signing key. The
+// container is created per test so that the mock doesn't replace the SecretProvider of the package's container.
+func newSigningTestContext() *appfunction.Context {
+	mockSP := &mocks.SecretProvider{}
+	mockSP.On("GetSecret", signingSecretPath, signingSecretName).Return(map[string]string{signingSecretName: signingKey}, nil)
+	mockSP.On("GetSecret", signingSecretPath, "bogus").Return(nil, errors.New("FAKE NOT FOUND ERROR"))
+
+	signingDic := di.NewContainer(di.ServiceConstructorMap{
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return lc
+		},
+		bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+			return mockSP
+		},
+	})
+
+	return appfunction.NewContext("123", signingDic, "")
+}
+
+func TestNewHMACSigner(t *testing.T) {
+	_, err := NewHMACSigner(signingSecretPath, signingSecretName, SignatureModeHeader)
+	assert.NoError(t, err)
+
+	_, err = NewHMACSigner("", signingSecretName, SignatureModeHeader)
+	assert.Error(t, err)
+
+	_, err = NewHMACSigner(signingSecretPath, signingSecretName, "bogus")
+	assert.Error(t, err)
+}
+
+func TestSignWithHMACHeaderMode(t *testing.T) {
+	signingCtx := newSigningTestContext()
+
+	signer, err := NewHMACSigner(signingSecretPath, signingSecretName, SignatureModeHeader)
+	require.NoError(t, err)
+
+	continuePipeline, result := signer.SignWithHMAC(signingCtx, signingPayload)
+	require.True(t, continuePipeline)
+	assert.Equal(t, []byte(signingPayload), result)
+
+	signature, found := signingCtx.GetValue(SignatureContextKey)
+	require.True(t, found)
+	assert.Equal(t, expectedHMAC(signingPayload), signature)
+}
+
+func TestSignWithHMACEnvelopeMode(t *testing.T) {
+	signingCtx := newSigningTestContext()
+
+	signer, err := NewHMACSigner(signingSecretPath, signingSecretName, SignatureModeEnvelope)
+	require.NoError(t, err)
+
+	continuePipeline, result := signer.SignWithHMAC(signingCtx, []byte(signingPayload))
+	require.True(t, continuePipeline)
+
+	envelope := SignedEnvelope{}
+	require.NoError(t, json.Unmarshal(result.([]byte), &envelope))
+	assert.Equal(t, signingPayload, string(envelope.Payload))
+	assert.Equal(t, SignatureAlgorithmHMACSHA256, envelope.Algorithm)
+	assert.Equal(t, expectedHMAC(signingPayload), envelope.Signature)
+
+	_, found := signingCtx.GetValue(SignatureContextKey)
+	assert.False(t, found)
+}
+
+func TestSignWithHMACSendsHeader(t *testing.T) {
+	signingCtx := newSigningTestContext()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, expectedHMAC(signingPayload), request.Header.Get("X-Signature"))
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	signer, err := NewHMACSigner(signingSecretPath, signingSecretName, SignatureModeHeader)
+	require.NoError(t, err)
+	sender := NewHTTPSenderWithOptions(HTTPSenderOptions{URL: ts.URL, SignatureHeaderName: "X-Signature"})
+
+	continuePipeline, result := signer.SignWithHMAC(signingCtx, signingPayload)
+	require.True(t, continuePipeline)
+
+	continuePipeline, result = sender.HTTPPost(signingCtx, result)
+	assert.True(t, continuePipeline, "unexpected result: %v", result)
+
+	// Signature header requested without a preceding signer
+	continuePipeline, result = sender.HTTPPost(appfunction.NewContext("123", dic, ""), signingPayload)
+	assert.False(t, continuePipeline)
+	assert.Error(t, result.(error))
+}
+
+func TestSignWithHMACErrors(t *testing.T) {
+	signingCtx := newSigningTestContext()
+
+	signer, err := NewHMACSigner(signingSecretPath, signingSecretName, SignatureModeHeader)
+	require.NoError(t, err)
+
+	continuePipeline, result := signer.SignWithHMAC(signingCtx, nil)
+	assert.False(t, continuePipeline)
+	assert.EqualError(t, result.(error), "No Data Received")
+
+	signer, err = NewHMACSigner(signingSecretPath, "bogus", SignatureModeHeader)
+	require.NoError(t, err)
+
+	continuePipeline, result = signer.SignWithHMAC(signingCtx, signingPayload)
+	assert.False(t, continuePipeline)
+	assert.Error(t, result.(error))
+}
+
+func newTestJWS(header string, payload string, key string) string {
+	signingInput := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(payload))
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestNewSignatureVerifier(t *testing.T) {
+	_, err := NewSignatureVerifier(signingSecretPath, signingSecretName, SignatureModeJWS)
+	assert.NoError(t, err)
+
+	_, err = NewSignatureVerifier(signingSecretPath, "", SignatureModeJWS)
+	assert.Error(t, err)
+
+	_, err = NewSignatureVerifier(signingSecretPath, signingSecretName, "bogus")
+	assert.Error(t, err)
+}
+
+func TestVerifySignatureRoundTrip(t *testing.T) {
+	for _, mode := range []string{SignatureModeHeader, SignatureModeEnvelope} {
+		t.Run(mode, func(t *testing.T) {
+			signingCtx := newSigningTestContext()
+
+			signer, err := NewHMACSigner(signingSecretPath, signingSecretName, mode)
+			require.NoError(t, err)
+			verifier, err := NewSignatureVerifier(signingSecretPath, signingSecretName, mode)
+			require.NoError(t, err)
+
+			continuePipeline, signed := signer.SignWithHMAC(signingCtx, signingPayload)
+			require.True(t, continuePipeline)
+
+			continuePipeline, result := verifier.VerifySignature(signingCtx, signed)
+			require.True(t, continuePipeline, "unexpected result: %v", result)
+			assert.Equal(t, []byte(signingPayload), result)
+			assert.Equal(t, uint64(0), verifier.Mismatches())
+		})
+	}
+}
+
+func TestVerifySignatureMismatch(t *testing.T) {
+	signingCtx := newSigningTestContext()
+
+	verifier, err := NewSignatureVerifier(signingSecretPath, signingSecretName, SignatureModeEnvelope)
+	require.NoError(t, err)
+
+	tampered, err := json.Marshal(SignedEnvelope{
+		Payload:   []byte(`{"value":"tampered"}`),
+		Algorithm: SignatureAlgorithmHMACSHA256,
+		Signature: expectedHMAC(signingPayload),
+	})
+	require.NoError(t, err)
+
+	continuePipeline, result := verifier.VerifySignature(signingCtx, tampered)
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "signature mismatch")
+	assert.Equal(t, uint64(1), verifier.Mismatches())
+
+	// Malformed data isn't counted as a mismatch
+	continuePipeline, result = verifier.VerifySignature(signingCtx, "not an envelope")
+	assert.False(t, continuePipeline)
+	assert.Error(t, result.(error))
+	assert.Equal(t, uint64(1), verifier.Mismatches())
+}
+
+func TestVerifySignatureRequestHeader(t *testing.T) {
+	signingCtx := newSigningTestContext()
+
+	verifier, err := NewSignatureVerifierWithHeader(signingSecretPath, signingSecretName, SignatureModeHeader, "X-Payload-Signature")
+	require.NoError(t, err)
+
+	continuePipeline, result := verifier.VerifySignature(signingCtx, signingPayload)
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "X-Payload-Signature")
+
+	// As stored by the HTTP trigger
+	signingCtx.AddValue(interfaces.HTTPHEADERPREFIX+"X-Payload-Signature", expectedHMAC(signingPayload))
+	continuePipeline, result = verifier.VerifySignature(signingCtx, signingPayload)
+	require.True(t, continuePipeline, "unexpected result: %v", result)
+	assert.Equal(t, []byte(signingPayload), result)
+}
+
+func TestVerifySignatureMismatchMetric(t *testing.T) {
+	mockSP := &mocks.SecretProvider{}
+	mockSP.On("GetSecret", signingSecretPath, signingSecretName).Return(map[string]string{signingSecretName: signingKey}, nil)
+	manager := telemetry.NewMetricsManager()
+
+	metricsDic := di.NewContainer(di.ServiceConstructorMap{
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return lc
+		},
+		bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+			return mockSP
+		},
+		container.MetricsManagerName: func(get di.Get) interface{} {
+			return manager
+		},
+	})
+
+	verifier, err := NewSignatureVerifier(signingSecretPath, signingSecretName, SignatureModeHeader)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		metricsCtx := appfunction.NewContext("123", metricsDic, "")
+		metricsCtx.SetPipelineId("signed")
+		metricsCtx.SetPipelineFunction(1, verifier.VerifySignature)
+		metricsCtx.AddValue(SignatureContextKey, expectedHMAC("other payload"))
+
+		continuePipeline, _ := verifier.VerifySignature(metricsCtx, signingPayload)
+		require.False(t, continuePipeline)
+	}
+
+	var snapshots []telemetry.MetricSnapshot
+	for _, snapshot := range manager.Snapshot() {
+		if snapshot.Name == SignatureMismatchesMetricName {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, "signed", snapshots[0].Tags[PipelineTag])
+	assert.Equal(t, "1", snapshots[0].Tags[PositionTag])
+	assert.Contains(t, snapshots[0].Tags[FunctionTag], "VerifySignature")
+	assert.Equal(t, int64(2), snapshots[0].Values["count"])
+	assert.Equal(t, uint64(2), verifier.Mismatches())
+}
+
+func TestVerifySignatureJWS(t *testing.T) {
+	signingCtx := newSigningTestContext()
+
+	verifier, err := NewSignatureVerifier(signingSecretPath, signingSecretName, SignatureModeJWS)
+	require.NoError(t, err)
+
+	tests := []struct {
+		Name             string
+		JWS              string
+		ExpectValid      bool
+		ExpectedMismatch uint64
+	}{
+		{"Valid", newTestJWS(`{"alg":"HS256"}`, signingPayload, signingKey), true, 0},
+		{"Wrong key", newTestJWS(`{"alg":"HS256"}`, signingPayload, "wrong-key"), false, 1},
+		{"Alg none", newTestJWS(`{"alg":"none"}`, signingPayload, signingKey), false, 1},
+		{"Not compact", "abc.def", false, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			continuePipeline, result := verifier.VerifySignature(signingCtx, test.JWS)
+			assert.Equal(t, test.ExpectValid, continuePipeline)
+			if test.ExpectValid {
+				assert.Equal(t, []byte(signingPayload), result)
+			} else {
+				assert.Error(t, result.(error))
+			}
+			assert.Equal(t, test.ExpectedMismatch, verifier.Mismatches())
+		})
+	}
+}
+
+func TestVerifySignatureNoData(t *testing.T) {
+	verifier, err := NewSignatureVerifier(signingSecretPath, signingSecretName, SignatureModeHeader)
+	require.NoError(t, err)
+
+	continuePipeline, result := verifier.VerifySignature(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.EqualError(t, result.(error), "No Data Received")
+}
@@ -0,0 +1,74 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"errors"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+)
+
+// Splitter splits Events so the remainder of the pipeline is executed separately for each part
+type Splitter struct {
+}
+
+// NewSplitter creates, initializes and returns a new instance of Splitter
+func NewSplitter() Splitter {
+	return Splitter{}
+}
+
+// SplitEventByReading splits an Event with multiple readings into one Event per reading. Each new Event retains
+// the profile, device and source names, origin and tags of the original Event. The remainder of the pipeline is
+// executed once for each of the new Events.
+// It will return an error and stop the pipeline if a non-edgex event is received or if no data is received.
+func (s Splitter) SplitEventByReading(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, errors.New("No Event Received")
+	}
+
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return false, errors.New("type received is not an Event")
+	}
+
+	if len(event.Readings) == 0 {
+		ctx.LoggingClient().Debugf("Event for device '%s' has no readings to split", event.DeviceName)
+		return false, nil
+	}
+
+	ctx.LoggingClient().Debugf("Splitting Event for device '%s' into %d Events", event.DeviceName, len(event.Readings))
+
+	result := make(interfaces.PipelineFanOut, len(event.Readings))
+	for index, reading := range event.Readings {
+		splitEvent := dtos.NewEvent(event.ProfileName, event.DeviceName, event.SourceName)
+		splitEvent.Origin = event.Origin
+		splitEvent.Readings = []dtos.BaseReading{reading}
+
+		if len(event.Tags) > 0 {
+			splitEvent.Tags = make(map[string]string, len(event.Tags))
+			for tag, value := range event.Tags {
+				splitEvent.Tags[tag] = value
+			}
+		}
+
+		result[index] = splitEvent
+	}
+
+	return true, result
+}
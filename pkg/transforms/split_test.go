@@ -0,0 +1,75 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitEventByReading(t *testing.T) {
+	event := newTestEvent(
+		t,
+		deviceName1,
+		map[string]string{"site": "plant-7"},
+		testReading{resourceName: "temperature", valueType: common.ValueTypeInt64, value: int64(72)},
+		testReading{resourceName: "humidity", valueType: common.ValueTypeInt64, value: int64(40)},
+		testReading{resourceName: "pressure", valueType: common.ValueTypeInt64, value: int64(1013)})
+
+	continuePipeline, result := NewSplitter().SplitEventByReading(ctx, event)
+	require.True(t, continuePipeline)
+
+	fanOut, ok := result.(interfaces.PipelineFanOut)
+	require.True(t, ok)
+	require.Len(t, fanOut, 3)
+
+	for index, item := range fanOut {
+		splitEvent, ok := item.(dtos.Event)
+		require.True(t, ok)
+		assert.NotEqual(t, event.Id, splitEvent.Id)
+		assert.Equal(t, event.DeviceName, splitEvent.DeviceName)
+		assert.Equal(t, event.ProfileName, splitEvent.ProfileName)
+		assert.Equal(t, event.SourceName, splitEvent.SourceName)
+		assert.Equal(t, event.Origin, splitEvent.Origin)
+		assert.Equal(t, event.Tags, splitEvent.Tags)
+		require.Len(t, splitEvent.Readings, 1)
+		assert.Equal(t, event.Readings[index], splitEvent.Readings[0])
+	}
+}
+
+func TestSplitEventByReadingNoReadings(t *testing.T) {
+	continuePipeline, result := NewSplitter().SplitEventByReading(ctx, dtos.NewEvent("profile", deviceName1, "source"))
+	assert.False(t, continuePipeline)
+	assert.Nil(t, result)
+}
+
+func TestSplitEventByReadingNoData(t *testing.T) {
+	continuePipeline, result := NewSplitter().SplitEventByReading(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.EqualError(t, result.(error), "No Event Received")
+
+	continuePipeline, result = NewSplitter().SplitEventByReading(ctx, "not an event")
+	assert.False(t, continuePipeline)
+	assert.EqualError(t, result.(error), "type received is not an Event")
+}
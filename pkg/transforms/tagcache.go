@@ -0,0 +1,102 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// maxTagCacheEntries is the number of keys a tagCache holds before evicting the least recently used
+const maxTagCacheEntries = 1000
+
+type tagCacheEntry struct {
+	key     string
+	tags    map[string]string
+	expires time.Time
+}
+
+// tagCache caches the tags retrieved for a key, such as a device name, for a TTL. It is bounded to maxEntries keys,
+// the least recently used are evicted first, so Events with many distinct keys can't grow it without limit.
+type tagCache struct {
+	ttl        time.Duration
+	maxEntries int
+	mutex      sync.Mutex
+	entries    map[string]*list.Element
+	order      *list.List
+	now        func() time.Time
+}
+
+// newTagCache returns a tagCache holding up to maxEntries keys for the TTL. A TTL of zero disables caching.
+func newTagCache(ttl time.Duration, maxEntries int) *tagCache {
+	return &tagCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+		now:        time.Now,
+	}
+}
+
+// get returns the tags cached for the key, if not expired. The tags returned must not be modified.
+func (cache *tagCache) get(key string) (map[string]string, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	element, found := cache.entries[key]
+	if !found {
+		return nil, false
+	}
+
+	entry := element.Value.(*tagCacheEntry)
+	if !cache.now().Before(entry.expires) {
+		cache.order.Remove(element)
+		delete(cache.entries, key)
+		return nil, false
+	}
+
+	cache.order.MoveToFront(element)
+	return entry.tags, true
+}
+
+// add caches the tags for the key, evicting the least recently used key when full
+func (cache *tagCache) add(key string, tags map[string]string) {
+	if cache.ttl <= 0 {
+		return
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	expires := cache.now().Add(cache.ttl)
+	if element, found := cache.entries[key]; found {
+		entry := element.Value.(*tagCacheEntry)
+		entry.tags = tags
+		entry.expires = expires
+		cache.order.MoveToFront(element)
+		return
+	}
+
+	cache.entries[key] = cache.order.PushFront(&tagCacheEntry{key: key, tags: tags, expires: expires})
+
+	for cache.order.Len() > cache.maxEntries {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*tagCacheEntry).key)
+	}
+}
@@ -0,0 +1,60 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagCache(t *testing.T) {
+	cache := newTagCache(time.Minute, 2)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	cache.add("one", map[string]string{"tag": "1"})
+	cache.add("two", map[string]string{"tag": "2"})
+
+	tags, found := cache.get("one")
+	assert.True(t, found)
+	assert.Equal(t, map[string]string{"tag": "1"}, tags)
+
+	// 'two' is the least recently used
+	cache.add("three", map[string]string{"tag": "3"})
+	_, found = cache.get("two")
+	assert.False(t, found, "least recently used key not evicted")
+	_, found = cache.get("one")
+	assert.True(t, found)
+	_, found = cache.get("three")
+	assert.True(t, found)
+	assert.Len(t, cache.entries, 2)
+
+	now = now.Add(2 * time.Minute)
+	_, found = cache.get("one")
+	assert.False(t, found, "expired key returned")
+	assert.Len(t, cache.entries, 1)
+}
+
+func TestTagCacheDisabled(t *testing.T) {
+	cache := newTagCache(0, maxTagCacheEntries)
+	cache.add("one", map[string]string{"tag": "1"})
+
+	_, found := cache.get("one")
+	assert.False(t, found)
+}
@@ -28,6 +28,10 @@ import (
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/http"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	commonConstants "github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+
+	"github.com/stretchr/testify/require"
 )
 
 var lc logger.LoggingClient
@@ -56,3 +60,29 @@ func TestMain(m *testing.M) {
 
 	os.Exit(m.Run())
 }
+
+// testReading is a simple reading added to the Events created by newTestEvent
+type testReading struct {
+	resourceName string
+	valueType    string
+	value        interface{}
+}
+
+// floatReading returns a test reading with the float value specified
+func floatReading(resourceName string, value float64) testReading {
+	return testReading{resourceName: resourceName, valueType: commonConstants.ValueTypeFloat64, value: value}
+}
+
+// newTestEvent returns an Event from the device, with the tags and a simple reading per test reading specified.
+// The test fails if a reading can't be added, i.e. its value doesn't match the value type.
+func newTestEvent(t *testing.T, deviceName string, tags map[string]string, readings ...testReading) dtos.Event {
+	t.Helper()
+
+	event := dtos.NewEvent("profile", deviceName, "source")
+	event.Tags = tags
+	for _, reading := range readings {
+		require.NoError(t, event.AddSimpleReading(reading.resourceName, reading.valueType, reading.value))
+	}
+
+	return event
+}
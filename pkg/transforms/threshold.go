@@ -0,0 +1,178 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+)
+
+const (
+	// AlertStateAlarm is the alert state of a resource that has crossed the alarm threshold
+	AlertStateAlarm = "alarm"
+	// AlertStateNormal is the alert state of a resource that has returned past the clear threshold
+	AlertStateNormal = "normal"
+	// AlertStateTagPrefix is prefixed to the resource name to form the tag key holding the resource's new alert state
+	AlertStateTagPrefix = "AlertState:"
+)
+
+// ThresholdAlert tracks the alert state of readings per device and resource and emits an alert Event only
+// when the state transitions. Hysteresis is provided by separate alarm and clear thresholds and debouncing
+// by requiring a number of consecutive readings past a threshold before the state transitions.
+// If AlarmThreshold >= ClearThreshold the alarm is raised on high values, otherwise it is raised on low values.
+type ThresholdAlert struct {
+	resourceNames  []string
+	alarmThreshold float64
+	clearThreshold float64
+	debounceCount  int
+	mutex          sync.Mutex
+	states         map[string]*thresholdState
+}
+
+type thresholdState struct {
+	alarmed     bool
+	consecutive int
+}
+
+// NewThresholdAlert creates, initializes and returns a new instance of ThresholdAlert.
+// An empty resourceNames list results in all numeric readings being checked.
+func NewThresholdAlert(resourceNames []string, alarmThreshold float64, clearThreshold float64, debounceCount int) (*ThresholdAlert, error) {
+	if debounceCount < 1 {
+		return nil, fmt.Errorf("debounce count must be 1 or greater, got %d", debounceCount)
+	}
+
+	return &ThresholdAlert{
+		resourceNames:  resourceNames,
+		alarmThreshold: alarmThreshold,
+		clearThreshold: clearThreshold,
+		debounceCount:  debounceCount,
+		states:         make(map[string]*thresholdState),
+	}, nil
+}
+
+// CheckThresholds checks the readings in the Event received against the thresholds. If the alert state of any
+// reading transitions, the pipeline continues with an Event containing the transitioned readings and a tag per
+// reading, keyed by AlertStateTagPrefix + resource name, with the new alert state. Otherwise the pipeline stops.
+// It will return an error and stop the pipeline if a non-edgex event is received or if no data is received.
+func (alert *ThresholdAlert) CheckThresholds(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, errors.New("No Event Received")
+	}
+
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return false, errors.New("type received is not an Event")
+	}
+
+	lc := ctx.LoggingClient()
+	lc.Debugf("Checking thresholds for device '%s'", event.DeviceName)
+
+	alertEvent := dtos.NewEvent(event.ProfileName, event.DeviceName, event.SourceName)
+	alertEvent.Tags = make(map[string]string)
+
+	alert.mutex.Lock()
+	defer alert.mutex.Unlock()
+
+	for _, reading := range event.Readings {
+		if !alert.isResourceOfInterest(reading.ResourceName) {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(reading.Value, 64)
+		if err != nil {
+			continue
+		}
+
+		key := event.DeviceName + "/" + reading.ResourceName
+		state, exists := alert.states[key]
+		if !exists {
+			state = &thresholdState{}
+			alert.states[key] = state
+		}
+
+		if !alert.update(state, value) {
+			continue
+		}
+
+		newState := AlertStateNormal
+		if state.alarmed {
+			newState = AlertStateAlarm
+		}
+
+		lc.Debugf("Alert state for '%s' transitioned to '%s' with value %s", key, newState, reading.Value)
+
+		alertEvent.Readings = append(alertEvent.Readings, reading)
+		alertEvent.Tags[AlertStateTagPrefix+reading.ResourceName] = newState
+	}
+
+	if len(alertEvent.Readings) == 0 {
+		return false, nil
+	}
+
+	return true, alertEvent
+}
+
+// update applies the value to the state and returns true if the state transitioned
+func (alert *ThresholdAlert) update(state *thresholdState, value float64) bool {
+	var pastThreshold bool
+
+	raiseOnHigh := alert.alarmThreshold >= alert.clearThreshold
+	switch {
+	case !state.alarmed && raiseOnHigh:
+		pastThreshold = value >= alert.alarmThreshold
+	case !state.alarmed && !raiseOnHigh:
+		pastThreshold = value <= alert.alarmThreshold
+	case state.alarmed && raiseOnHigh:
+		pastThreshold = value <= alert.clearThreshold
+	default:
+		pastThreshold = value >= alert.clearThreshold
+	}
+
+	if !pastThreshold {
+		state.consecutive = 0
+		return false
+	}
+
+	state.consecutive++
+	if state.consecutive < alert.debounceCount {
+		return false
+	}
+
+	state.alarmed = !state.alarmed
+	state.consecutive = 0
+	return true
+}
+
+func (alert *ThresholdAlert) isResourceOfInterest(resourceName string) bool {
+	if len(alert.resourceNames) == 0 {
+		return true
+	}
+
+	for _, name := range alert.resourceNames {
+		if name == resourceName {
+			return true
+		}
+	}
+
+	return false
+}
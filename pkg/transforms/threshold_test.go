@@ -0,0 +1,129 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewThresholdAlertBadDebounce(t *testing.T) {
+	_, err := NewThresholdAlert(nil, 10, 5, 0)
+	assert.Error(t, err)
+}
+
+func TestCheckThresholdsHysteresis(t *testing.T) {
+	alert, err := NewThresholdAlert([]string{"temperature"}, 30, 25, 1)
+	require.NoError(t, err)
+
+	tests := []struct {
+		Value         float64
+		ExpectedState string
+	}{
+		{20, ""},
+		{30, AlertStateAlarm},
+		{35, ""},
+		{27, ""}, // Within hysteresis band so still alarmed
+		{31, ""},
+		{25, AlertStateNormal},
+		{29, ""},
+		{40, AlertStateAlarm},
+	}
+
+	for _, test := range tests {
+		event := newTestEvent(t, deviceName1, nil, floatReading("temperature", test.Value))
+		continuePipeline, result := alert.CheckThresholds(ctx, event)
+		if len(test.ExpectedState) == 0 {
+			assert.False(t, continuePipeline, "value %v should not transition", test.Value)
+			assert.Nil(t, result)
+			continue
+		}
+
+		require.True(t, continuePipeline, "value %v should transition", test.Value)
+		alertEvent := result.(dtos.Event)
+		require.Len(t, alertEvent.Readings, 1)
+		assert.Equal(t, test.Value, aggregateReadingValue(t, alertEvent, "temperature"))
+		assert.Equal(t, test.ExpectedState, alertEvent.Tags[AlertStateTagPrefix+"temperature"])
+	}
+}
+
+func TestCheckThresholdsRaiseOnLow(t *testing.T) {
+	alert, err := NewThresholdAlert(nil, 10, 15, 1)
+	require.NoError(t, err)
+
+	continuePipeline, _ := alert.CheckThresholds(ctx, newTestEvent(t, deviceName1, nil, floatReading("temperature", 12)))
+	assert.False(t, continuePipeline)
+
+	event := newTestEvent(t, deviceName1, nil, floatReading("temperature", 9))
+	continuePipeline, result := alert.CheckThresholds(ctx, event)
+	require.True(t, continuePipeline)
+	assert.Equal(t, AlertStateAlarm, result.(dtos.Event).Tags[AlertStateTagPrefix+"temperature"])
+
+	event = newTestEvent(t, deviceName1, nil, floatReading("temperature", 16))
+	continuePipeline, result = alert.CheckThresholds(ctx, event)
+	require.True(t, continuePipeline)
+	assert.Equal(t, AlertStateNormal, result.(dtos.Event).Tags[AlertStateTagPrefix+"temperature"])
+}
+
+func TestCheckThresholdsDebounce(t *testing.T) {
+	alert, err := NewThresholdAlert(nil, 30, 25, 3)
+	require.NoError(t, err)
+
+	values := []float64{31, 32, 20, 31, 32}
+	for _, value := range values {
+		event := newTestEvent(t, deviceName1, nil, floatReading("temperature", value))
+		continuePipeline, _ := alert.CheckThresholds(ctx, event)
+		assert.False(t, continuePipeline, "value %v should not transition", value)
+	}
+
+	// Other devices are tracked independently
+	continuePipeline, _ := alert.CheckThresholds(ctx, newTestEvent(t, deviceName2, nil, floatReading("temperature", 50)))
+	assert.False(t, continuePipeline)
+
+	event := newTestEvent(t, deviceName1, nil, floatReading("temperature", 33))
+	continuePipeline, result := alert.CheckThresholds(ctx, event)
+	require.True(t, continuePipeline)
+	assert.Equal(t, deviceName1, result.(dtos.Event).DeviceName)
+	assert.Equal(t, AlertStateAlarm, result.(dtos.Event).Tags[AlertStateTagPrefix+"temperature"])
+}
+
+func TestCheckThresholdsResourceNotOfInterest(t *testing.T) {
+	alert, err := NewThresholdAlert([]string{"humidity"}, 30, 25, 1)
+	require.NoError(t, err)
+
+	event := newTestEvent(t, deviceName1, nil, floatReading("temperature", 100))
+	continuePipeline, result := alert.CheckThresholds(ctx, event)
+	assert.False(t, continuePipeline)
+	assert.Nil(t, result)
+}
+
+func TestCheckThresholdsNoData(t *testing.T) {
+	alert, err := NewThresholdAlert(nil, 30, 25, 1)
+	require.NoError(t, err)
+
+	continuePipeline, result := alert.CheckThresholds(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.EqualError(t, result.(error), "No Event Received")
+
+	continuePipeline, result = alert.CheckThresholds(ctx, "not an event")
+	assert.False(t, continuePipeline)
+	assert.EqualError(t, result.(error), "type received is not an Event")
+}
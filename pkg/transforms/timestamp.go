@@ -0,0 +1,187 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v2/pkg/interfaces"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+)
+
+const (
+	// TimestampUnitAuto detects the epoch unit of each origin from its magnitude
+	TimestampUnitAuto = "auto"
+	// TimestampUnitSeconds is epoch seconds
+	TimestampUnitSeconds = "s"
+	// TimestampUnitMilliseconds is epoch milliseconds
+	TimestampUnitMilliseconds = "ms"
+	// TimestampUnitMicroseconds is epoch microseconds
+	TimestampUnitMicroseconds = "us"
+	// TimestampUnitNanoseconds is epoch nanoseconds, which is the unit EdgeX uses for origins
+	TimestampUnitNanoseconds = "ns"
+)
+
+var timestampUnitNanos = map[string]int64{
+	TimestampUnitSeconds:      int64(time.Second),
+	TimestampUnitMilliseconds: int64(time.Millisecond),
+	TimestampUnitMicroseconds: int64(time.Microsecond),
+	TimestampUnitNanoseconds:  1,
+}
+
+// TimestampNormalizerOptions contains all options available to the timestamp normalizer
+type TimestampNormalizerOptions struct {
+	// SourceUnit is the epoch unit origins are received in. Defaults to TimestampUnitAuto.
+	SourceUnit string `param:"sourceunit"`
+	// TargetUnit is the epoch unit origins are converted to. Defaults to TimestampUnitNanoseconds.
+	TargetUnit string `param:"targetunit"`
+	// Precision origins are truncated to, i.e. time.Millisecond. Zero leaves the precision unchanged.
+	Precision time.Duration `param:"precision"`
+	// TimeZone the origin is formatted in when TimestampTag is set, i.e. 'UTC' or 'America/Chicago'.
+	// Defaults to UTC.
+	TimeZone string `param:"timezone"`
+	// TimestampTag is the optional name of the Event tag the normalized origin is added to in RFC3339 format
+	TimestampTag string `param:"timestamptag"`
+}
+
+// TimestampNormalizer normalizes the origin timestamps of Events and their Readings, since devices frequently
+// report origins in inconsistent units and precisions which breaks downstream time-series ingestion.
+type TimestampNormalizer struct {
+	options  TimestampNormalizerOptions
+	location *time.Location
+}
+
+// NewTimestampNormalizer creates, initializes and returns a new instance of TimestampNormalizer configured with
+// provided options
+func NewTimestampNormalizer(options TimestampNormalizerOptions) (*TimestampNormalizer, error) {
+	options.SourceUnit = strings.ToLower(options.SourceUnit)
+	options.TargetUnit = strings.ToLower(options.TargetUnit)
+
+	if len(options.SourceUnit) == 0 {
+		options.SourceUnit = TimestampUnitAuto
+	}
+
+	if len(options.TargetUnit) == 0 {
+		options.TargetUnit = TimestampUnitNanoseconds
+	}
+
+	if _, ok := timestampUnitNanos[options.SourceUnit]; !ok && options.SourceUnit != TimestampUnitAuto {
+		return nil, fmt.Errorf("invalid source unit '%s'", options.SourceUnit)
+	}
+
+	if _, ok := timestampUnitNanos[options.TargetUnit]; !ok {
+		return nil, fmt.Errorf("invalid target unit '%s'", options.TargetUnit)
+	}
+
+	if options.Precision < 0 {
+		return nil, errors.New("precision can not be negative")
+	}
+
+	location := time.UTC
+	if len(options.TimeZone) > 0 {
+		var err error
+		location, err = time.LoadLocation(options.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone '%s': %s", options.TimeZone, err.Error())
+		}
+	}
+
+	return &TimestampNormalizer{
+		options:  options,
+		location: location,
+	}, nil
+}
+
+// NormalizeTimestamps converts the origins of the Event received and its Readings to the target unit and
+// precision and optionally adds the Event origin formatted in the configured time zone as a tag.
+// Origins of zero are left unchanged.
+// It will return an error and stop the pipeline if a non-edgex event is received or if no data is received.
+func (normalizer *TimestampNormalizer) NormalizeTimestamps(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, errors.New("No Event Received")
+	}
+
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return false, errors.New("type received is not an Event")
+	}
+
+	ctx.LoggingClient().Debugf("Normalizing timestamps of Event for device '%s'", event.DeviceName)
+
+	var origin time.Time
+	event.Origin, origin = normalizer.normalize(event.Origin)
+
+	// Don't modify the readings of the Event received since they may be shared with other functions
+	readings := make([]dtos.BaseReading, len(event.Readings))
+	for index, reading := range event.Readings {
+		reading.Origin, _ = normalizer.normalize(reading.Origin)
+		readings[index] = reading
+	}
+	event.Readings = readings
+
+	if len(normalizer.options.TimestampTag) > 0 && event.Origin != 0 {
+		tags := make(map[string]string, len(event.Tags)+1)
+		for tag, value := range event.Tags {
+			tags[tag] = value
+		}
+		tags[normalizer.options.TimestampTag] = origin.In(normalizer.location).Format(time.RFC3339Nano)
+		event.Tags = tags
+	}
+
+	return true, event
+}
+
+func (normalizer *TimestampNormalizer) normalize(origin int64) (int64, time.Time) {
+	if origin == 0 {
+		return 0, time.Time{}
+	}
+
+	sourceUnit := normalizer.options.SourceUnit
+	if sourceUnit == TimestampUnitAuto {
+		sourceUnit = detectTimestampUnit(origin)
+	}
+
+	timestamp := time.Unix(0, origin*timestampUnitNanos[sourceUnit])
+	if normalizer.options.Precision > 0 {
+		timestamp = timestamp.Truncate(normalizer.options.Precision)
+	}
+
+	return timestamp.UnixNano() / timestampUnitNanos[normalizer.options.TargetUnit], timestamp
+}
+
+// detectTimestampUnit determines the epoch unit of the origin from its magnitude. The ranges used are correct for
+// origins between 1973 and 5138, which covers any origin a device reports with a working clock.
+func detectTimestampUnit(origin int64) string {
+	if origin < 0 {
+		origin = -origin
+	}
+
+	switch {
+	case origin < 1e11:
+		return TimestampUnitSeconds
+	case origin < 1e14:
+		return TimestampUnitMilliseconds
+	case origin < 1e17:
+		return TimestampUnitMicroseconds
+	default:
+		return TimestampUnitNanoseconds
+	}
+}
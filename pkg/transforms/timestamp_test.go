@@ -0,0 +1,144 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"testing"
+	"time"
+	// Embedded zone database so the time zone test doesn't depend on the host
+	_ "time/tzdata"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 2021-06-01T12:34:56.789123456Z
+const testOriginNanos = int64(1622550896789123456)
+
+func TestNewTimestampNormalizer(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Options     TimestampNormalizerOptions
+		ExpectError bool
+	}{
+		{"Valid defaults", TimestampNormalizerOptions{}, false},
+		{"Valid all options", TimestampNormalizerOptions{SourceUnit: "ms", TargetUnit: "s", Precision: time.Second, TimeZone: "UTC", TimestampTag: "time"}, false},
+		{"Bad source unit", TimestampNormalizerOptions{SourceUnit: "minutes"}, true},
+		{"Bad target unit", TimestampNormalizerOptions{TargetUnit: "auto"}, true},
+		{"Negative precision", TimestampNormalizerOptions{Precision: -time.Second}, true},
+		{"Bad time zone", TimestampNormalizerOptions{TimeZone: "Nowhere/Bogus"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := NewTimestampNormalizer(test.Options)
+			assert.Equal(t, test.ExpectError, err != nil)
+		})
+	}
+}
+
+func TestNormalizeTimestampsAutoDetect(t *testing.T) {
+	normalizer, err := NewTimestampNormalizer(TimestampNormalizerOptions{Precision: time.Millisecond})
+	require.NoError(t, err)
+
+	tests := []struct {
+		Name     string
+		Origin   int64
+		Expected int64
+	}{
+		{"Seconds", 1622550896, 1622550896000000000},
+		{"Milliseconds", 1622550896789, 1622550896789000000},
+		{"Microseconds", 1622550896789123, 1622550896789000000},
+		{"Nanoseconds", testOriginNanos, 1622550896789000000},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			event := newTestEvent(
+				t,
+				deviceName1,
+				nil,
+				testReading{resourceName: "temperature", valueType: common.ValueTypeInt64, value: int64(72)})
+			event.Origin = test.Origin
+			event.Readings[0].Origin = test.Origin
+
+			continuePipeline, result := normalizer.NormalizeTimestamps(ctx, event)
+			require.True(t, continuePipeline)
+
+			normalized := result.(dtos.Event)
+			assert.Equal(t, test.Expected, normalized.Origin)
+			assert.Equal(t, normalized.Origin, normalized.Readings[0].Origin)
+
+			// Original Event not modified
+			assert.Equal(t, test.Origin, event.Readings[0].Origin)
+		})
+	}
+}
+
+func TestNormalizeTimestampsTargetUnitAndTag(t *testing.T) {
+	normalizer, err := NewTimestampNormalizer(TimestampNormalizerOptions{
+		SourceUnit:   TimestampUnitNanoseconds,
+		TargetUnit:   TimestampUnitMilliseconds,
+		TimeZone:     "Asia/Kolkata",
+		TimestampTag: "originTime",
+	})
+	require.NoError(t, err)
+
+	event := dtos.NewEvent("profile", deviceName1, "source")
+	event.Origin = testOriginNanos
+	event.Tags = map[string]string{"site": "plant-7"}
+
+	continuePipeline, result := normalizer.NormalizeTimestamps(ctx, event)
+	require.True(t, continuePipeline)
+
+	normalized := result.(dtos.Event)
+	assert.Equal(t, int64(1622550896789), normalized.Origin)
+	assert.Equal(t, "2021-06-01T18:04:56.789123456+05:30", normalized.Tags["originTime"])
+	assert.Equal(t, "plant-7", normalized.Tags["site"])
+	assert.Len(t, event.Tags, 1)
+}
+
+func TestNormalizeTimestampsZeroOrigin(t *testing.T) {
+	normalizer, err := NewTimestampNormalizer(TimestampNormalizerOptions{TimestampTag: "originTime"})
+	require.NoError(t, err)
+
+	event := dtos.NewEvent("profile", deviceName1, "source")
+	event.Origin = 0
+
+	continuePipeline, result := normalizer.NormalizeTimestamps(ctx, event)
+	require.True(t, continuePipeline)
+
+	normalized := result.(dtos.Event)
+	assert.Equal(t, int64(0), normalized.Origin)
+	assert.NotContains(t, normalized.Tags, "originTime")
+}
+
+func TestNormalizeTimestampsNoData(t *testing.T) {
+	normalizer, err := NewTimestampNormalizer(TimestampNormalizerOptions{})
+	require.NoError(t, err)
+
+	continuePipeline, result := normalizer.NormalizeTimestamps(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.EqualError(t, result.(error), "No Event Received")
+
+	continuePipeline, result = normalizer.NormalizeTimestamps(ctx, "not an event")
+	assert.False(t, continuePipeline)
+	assert.EqualError(t, result.(error), "type received is not an Event")
+}
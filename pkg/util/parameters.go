@@ -0,0 +1,141 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ParameterTag is the struct tag specifying the name of the configurable function parameter bound to a field.
+	// The name may be followed by ',required' if the parameter must be specified, i.e. `param:"url,required"`.
+	ParameterTag = "param"
+	// ParameterDefaultTag is the struct tag specifying the value used when the parameter isn't specified
+	ParameterDefaultTag = "default"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// BindParameters decodes configurable function parameters into the fields of the struct pointed to by target
+// which have a 'param' tag. Parameter names are matched case insensitively and values are trimmed before parsing.
+// Supported field types are string, bool, integers, floats, time.Duration and []string, which is parsed from a
+// comma separated list. A 'default' tag specifies the value used when the parameter isn't specified.
+// An error is returned if a required parameter isn't specified or a value can't be parsed to the field's type.
+func BindParameters(parameters map[string]string, target interface{}) error {
+	targetValue := reflect.ValueOf(target)
+	if targetValue.Kind() != reflect.Ptr || targetValue.IsNil() || targetValue.Elem().Kind() != reflect.Struct {
+		return errors.New("parameters target must be a pointer to a struct")
+	}
+
+	lowerParameters := make(map[string]string, len(parameters))
+	for name, value := range parameters {
+		lowerParameters[strings.ToLower(name)] = value
+	}
+
+	structValue := targetValue.Elem()
+	structType := structValue.Type()
+
+	for index := 0; index < structType.NumField(); index++ {
+		field := structType.Field(index)
+		tag, ok := field.Tag.Lookup(ParameterTag)
+		if !ok {
+			continue
+		}
+
+		options := strings.Split(tag, ",")
+		name := strings.TrimSpace(options[0])
+		required := len(options) > 1 && strings.TrimSpace(options[1]) == "required"
+
+		value, found := lowerParameters[strings.ToLower(name)]
+		if !found {
+			if required {
+				return fmt.Errorf("could not find '%s' parameter", name)
+			}
+
+			value, found = field.Tag.Lookup(ParameterDefaultTag)
+			if !found {
+				continue
+			}
+		}
+
+		if err := setParameterField(structValue.Field(index), strings.TrimSpace(value)); err != nil {
+			return fmt.Errorf("could not parse '%s' to a %s for '%s' parameter: %s", value, field.Type.String(), name, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func setParameterField(field reflect.Value, value string) error {
+	if field.Type() == durationType {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(duration))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(parsed)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(parsed)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(parsed)
+
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(parsed)
+
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported field type %s", field.Type().String())
+		}
+		field.Set(reflect.ValueOf(DeleteEmptyAndTrim(strings.FieldsFunc(value, SplitComma))))
+
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type().String())
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testParameters struct {
+	URL       string        `param:"url,required"`
+	Enabled   bool          `param:"enabled" default:"true"`
+	MaxSize   int64         `param:"maxSize"`
+	Retries   uint8         `param:"retries" default:"3"`
+	Threshold float64       `param:"threshold"`
+	Timeout   time.Duration `param:"timeout" default:"10s"`
+	Names     []string      `param:"names"`
+	Ignored   string
+}
+
+func TestBindParameters(t *testing.T) {
+	var parameters testParameters
+	err := BindParameters(map[string]string{
+		"URL":       " http://localhost ",
+		"maxsize":   "1024",
+		"threshold": "1.5",
+		"names":     "one, two,, three",
+		"ignored":   "value",
+	}, &parameters)
+	require.NoError(t, err)
+
+	expected := testParameters{
+		URL:       "http://localhost",
+		Enabled:   true,
+		MaxSize:   1024,
+		Retries:   3,
+		Threshold: 1.5,
+		Timeout:   10 * time.Second,
+		Names:     []string{"one", "two", "three"},
+	}
+	assert.Equal(t, expected, parameters)
+}
+
+func TestBindParametersErrors(t *testing.T) {
+	tests := []struct {
+		Name          string
+		Parameters    map[string]string
+		ExpectedError string
+	}{
+		{"Missing required", map[string]string{}, "could not find 'url' parameter"},
+		{"Bad bool", map[string]string{"url": "x", "enabled": "bogus"},
+			"could not parse 'bogus' to a bool for 'enabled' parameter"},
+		{"Out of range", map[string]string{"url": "x", "retries": "256"},
+			"could not parse '256' to a uint8 for 'retries' parameter"},
+		{"Bad duration", map[string]string{"url": "x", "timeout": "10"},
+			"could not parse '10' to a time.Duration for 'timeout' parameter"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var parameters testParameters
+			err := BindParameters(test.Parameters, &parameters)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.ExpectedError)
+		})
+	}
+}
+
+func TestBindParametersBadTarget(t *testing.T) {
+	assert.Error(t, BindParameters(map[string]string{}, testParameters{}))
+	assert.Error(t, BindParameters(map[string]string{}, (*testParameters)(nil)))
+
+	unsupported := struct {
+		Value map[string]string `param:"value"`
+	}{}
+	assert.Error(t, BindParameters(map[string]string{"value": "x"}, &unsupported))
+}
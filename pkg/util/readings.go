@@ -0,0 +1,75 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+)
+
+// BinaryReadingReader returns an io.Reader of the binary reading's value, so large values, i.e. camera images, can
+// be streamed to their destination rather than copied. Binary values of events received as CBOR reference the
+// received payload, so the value is only held in memory once.
+func BinaryReadingReader(reading dtos.BaseReading) (io.Reader, error) {
+	if reading.ValueType != common.ValueTypeBinary {
+		return nil, fmt.Errorf("reading %s is not a binary reading, it is %s", reading.ResourceName, reading.ValueType)
+	}
+
+	return bytes.NewReader(reading.BinaryValue), nil
+}
+
+// BinaryMediaType returns the media type of the first binary reading of the Event, or an empty string if the Event
+// has no binary readings
+func BinaryMediaType(event dtos.Event) string {
+	if index := binaryReadingIndex(event); index >= 0 {
+		return event.Readings[index].MediaType
+	}
+	return ""
+}
+
+// EncodesAsCBOR returns whether the data, received as the input content type, can be serialized as CBOR rather than
+// JSON by the exports which opt in to it. Events with binary readings received as CBOR can be, so the binary values
+// are exported as they were received rather than inflated by the base64 encoding JSON requires.
+func EncodesAsCBOR(data interface{}, inputContentType string) bool {
+	if !strings.EqualFold(inputContentType, common.ContentTypeCBOR) {
+		return false
+	}
+
+	switch event := data.(type) {
+	case dtos.Event:
+		return binaryReadingIndex(event) >= 0
+	case *dtos.Event:
+		return event != nil && binaryReadingIndex(*event) >= 0
+	default:
+		return false
+	}
+}
+
+// binaryReadingIndex returns the index of the first binary reading of the Event, or -1 if it has none
+func binaryReadingIndex(event dtos.Event) int {
+	for index, reading := range event.Readings {
+		if reading.ValueType == common.ValueTypeBinary {
+			return index
+		}
+	}
+	return -1
+}
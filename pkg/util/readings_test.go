@@ -0,0 +1,78 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package util
+
+import (
+	"io"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBinaryReadingReader(t *testing.T) {
+	image := []byte{0xff, 0xd8, 0xff, 0xe0}
+	event := dtos.NewEvent("camera", "device", "image")
+	event.AddBinaryReading("image", image, "image/jpeg")
+	require.NoError(t, event.AddSimpleReading("temperature", common.ValueTypeInt32, int32(20)))
+
+	reader, err := BinaryReadingReader(event.Readings[0])
+	require.NoError(t, err)
+	actual, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, image, actual)
+
+	_, err = BinaryReadingReader(event.Readings[1])
+	assert.Error(t, err)
+}
+
+func TestBinaryMediaType(t *testing.T) {
+	event := dtos.NewEvent("camera", "device", "image")
+	require.NoError(t, event.AddSimpleReading("temperature", common.ValueTypeInt32, int32(20)))
+	assert.Equal(t, "", BinaryMediaType(event))
+
+	event.AddBinaryReading("image", []byte{0xff, 0xd8}, "image/jpeg")
+	assert.Equal(t, "image/jpeg", BinaryMediaType(event))
+}
+
+func TestEncodesAsCBOR(t *testing.T) {
+	binaryEvent := dtos.NewEvent("camera", "device", "image")
+	binaryEvent.AddBinaryReading("image", []byte{0xff, 0xd8}, "image/jpeg")
+	simpleEvent := dtos.NewEvent("camera", "device", "temperature")
+	require.NoError(t, simpleEvent.AddSimpleReading("temperature", common.ValueTypeInt32, int32(20)))
+
+	tests := []struct {
+		Name             string
+		Data             interface{}
+		InputContentType string
+		Expected         bool
+	}{
+		{"Binary Event received as CBOR", binaryEvent, common.ContentTypeCBOR, true},
+		{"Binary Event pointer received as CBOR", &binaryEvent, common.ContentTypeCBOR, true},
+		{"Binary Event received as JSON", binaryEvent, common.ContentTypeJSON, false},
+		{"Simple Event received as CBOR", simpleEvent, common.ContentTypeCBOR, false},
+		{"Bytes received as CBOR", []byte{0xa1}, common.ContentTypeCBOR, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Expected, EncodesAsCBOR(test.Data, test.InputContentType))
+		})
+	}
+}
@@ -0,0 +1,193 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package util
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/google/uuid"
+)
+
+// Float encodings of the V1 float readings
+const (
+	V1FloatEncodingBase64    = "Base64"
+	V1FloatEncodingENotation = "eNotation"
+)
+
+// V1Event is the EdgeX V1 Event model, as sent and received by V1 core services and device services
+type V1Event struct {
+	ID       string            `json:"id,omitempty"`
+	Pushed   int64             `json:"pushed,omitempty"`
+	Device   string            `json:"device,omitempty"`
+	Created  int64             `json:"created,omitempty"`
+	Modified int64             `json:"modified,omitempty"`
+	Origin   int64             `json:"origin,omitempty"`
+	Readings []V1Reading       `json:"readings,omitempty"`
+	Tags     map[string]string `json:"tags,omitempty"`
+}
+
+// V1Reading is the EdgeX V1 Reading model
+type V1Reading struct {
+	Id            string `json:"id,omitempty"`
+	Pushed        int64  `json:"pushed,omitempty"`
+	Created       int64  `json:"created,omitempty"`
+	Origin        int64  `json:"origin,omitempty"`
+	Modified      int64  `json:"modified,omitempty"`
+	Device        string `json:"device,omitempty"`
+	Name          string `json:"name,omitempty"`
+	Value         string `json:"value,omitempty"`
+	ValueType     string `json:"valueType,omitempty"`
+	FloatEncoding string `json:"floatEncoding,omitempty"`
+	BinaryValue   []byte `json:"binaryValue,omitempty"`
+	MediaType     string `json:"mediaType,omitempty"`
+}
+
+// FromV1Event converts the V1 Event to an Event DTO. V1 Events have no device profile or source, so the profile name
+// is the one specified and the source name is the name of the first reading. Ids which aren't UUIDs, as V1 allowed,
+// are replaced by new UUIDs and Base64 encoded float values are converted to the E notation V2 requires.
+func FromV1Event(v1Event V1Event, profileName string) (dtos.Event, error) {
+	sourceName := ""
+	if len(v1Event.Readings) > 0 {
+		sourceName = v1Event.Readings[0].Name
+	}
+
+	event := dtos.NewEvent(profileName, v1Event.Device, sourceName)
+	if _, err := uuid.Parse(v1Event.ID); err == nil {
+		event.Id = v1Event.ID
+	}
+	event.Origin = v1Origin(v1Event.Origin, v1Event.Created, event.Origin)
+	event.Tags = v1Event.Tags
+
+	for _, v1Reading := range v1Event.Readings {
+		deviceName := v1Reading.Device
+		if len(deviceName) == 0 {
+			deviceName = v1Event.Device
+		}
+
+		reading := dtos.BaseReading{
+			Id:           v1Reading.Id,
+			Origin:       v1Origin(v1Reading.Origin, v1Reading.Created, event.Origin),
+			DeviceName:   deviceName,
+			ResourceName: v1Reading.Name,
+			ProfileName:  profileName,
+			ValueType:    v1Reading.ValueType,
+		}
+		if _, err := uuid.Parse(reading.Id); err != nil {
+			reading.Id = uuid.NewString()
+		}
+
+		if reading.ValueType == common.ValueTypeBinary {
+			reading.BinaryValue = v1Reading.BinaryValue
+			reading.MediaType = v1Reading.MediaType
+		} else {
+			value, err := v1ReadingValue(v1Reading)
+			if err != nil {
+				return dtos.Event{}, err
+			}
+			reading.Value = value
+		}
+
+		event.Readings = append(event.Readings, reading)
+	}
+
+	return event, nil
+}
+
+// ToV1Event converts the Event DTO to a V1 Event, for V1 consumers of the exported data. The device profile and
+// source names, which V1 has no place for, are dropped.
+func ToV1Event(event dtos.Event) V1Event {
+	v1Event := V1Event{
+		ID:     event.Id,
+		Device: event.DeviceName,
+		Origin: event.Origin,
+		Tags:   event.Tags,
+	}
+
+	for _, reading := range event.Readings {
+		v1Reading := V1Reading{
+			Id:        reading.Id,
+			Origin:    reading.Origin,
+			Device:    reading.DeviceName,
+			Name:      reading.ResourceName,
+			ValueType: reading.ValueType,
+		}
+
+		if reading.ValueType == common.ValueTypeBinary {
+			v1Reading.BinaryValue = reading.BinaryValue
+			v1Reading.MediaType = reading.MediaType
+		} else {
+			v1Reading.Value = reading.Value
+			if isFloatValueType(reading.ValueType) {
+				v1Reading.FloatEncoding = V1FloatEncodingENotation
+			}
+		}
+
+		v1Event.Readings = append(v1Event.Readings, v1Reading)
+	}
+
+	return v1Event
+}
+
+// v1Origin returns the origin, in nanoseconds, of the V1 Event or Reading. V1 allowed the origin to be zero, in which
+// case the created time, in milliseconds, is used, or the fallback if that is zero too.
+func v1Origin(origin int64, created int64, fallback int64) int64 {
+	switch {
+	case origin != 0:
+		return origin
+	case created != 0:
+		return created * 1000000
+	default:
+		return fallback
+	}
+}
+
+// v1ReadingValue returns the value of the V1 simple reading, decoding the big endian Base64 encoded float values
+func v1ReadingValue(v1Reading V1Reading) (string, error) {
+	if !isFloatValueType(v1Reading.ValueType) || v1Reading.FloatEncoding != V1FloatEncodingBase64 {
+		return v1Reading.Value, nil
+	}
+
+	encoded, err := base64.StdEncoding.DecodeString(v1Reading.Value)
+	if err != nil {
+		return "", fmt.Errorf("unable to decode Base64 float value of reading %s: %s", v1Reading.Name, err.Error())
+	}
+
+	switch v1Reading.ValueType {
+	case common.ValueTypeFloat32:
+		var value float32
+		if err := binary.Read(bytes.NewReader(encoded), binary.BigEndian, &value); err != nil {
+			return "", fmt.Errorf("unable to decode Base64 float value of reading %s: %s", v1Reading.Name, err.Error())
+		}
+		return strconv.FormatFloat(float64(value), 'e', -1, 32), nil
+	default:
+		var value float64
+		if err := binary.Read(bytes.NewReader(encoded), binary.BigEndian, &value); err != nil {
+			return "", fmt.Errorf("unable to decode Base64 float value of reading %s: %s", v1Reading.Name, err.Error())
+		}
+		return strconv.FormatFloat(value, 'e', -1, 64), nil
+	}
+}
+
+func isFloatValueType(valueType string) bool {
+	return valueType == common.ValueTypeFloat32 || valueType == common.ValueTypeFloat64
+}
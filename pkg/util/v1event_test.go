@@ -0,0 +1,97 @@
+//
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package util
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromV1Event(t *testing.T) {
+	v1Event := V1Event{
+		ID:      "not-a-uuid",
+		Device:  "Random-Float-Device",
+		Created: 1600000000000,
+		Tags:    map[string]string{"site": "east"},
+		Readings: []V1Reading{
+			{Name: "Float32", Value: "P8AAAA==", ValueType: common.ValueTypeFloat32, FloatEncoding: V1FloatEncodingBase64},
+			{Name: "Float64", Value: "QAIAAAAAAAA=", ValueType: common.ValueTypeFloat64, FloatEncoding: V1FloatEncodingBase64},
+			{Name: "Int32", Value: "42", ValueType: common.ValueTypeInt32, Origin: 1600000000000000001},
+			{Name: "Image", BinaryValue: []byte{1, 2, 3}, ValueType: common.ValueTypeBinary, MediaType: "image/jpeg"},
+		},
+	}
+
+	event, err := FromV1Event(v1Event, "Random-Float-Profile")
+	require.NoError(t, err)
+	require.NoError(t, common.Validate(event))
+
+	assert.Equal(t, common.ApiVersion, event.ApiVersion)
+	assert.NotEqual(t, v1Event.ID, event.Id)
+	assert.Equal(t, "Random-Float-Device", event.DeviceName)
+	assert.Equal(t, "Random-Float-Profile", event.ProfileName)
+	assert.Equal(t, "Float32", event.SourceName)
+	assert.Equal(t, int64(1600000000000000000), event.Origin)
+	assert.Equal(t, v1Event.Tags, event.Tags)
+
+	require.Len(t, event.Readings, 4)
+	assert.Equal(t, "1.5e+00", event.Readings[0].Value)
+	assert.Equal(t, "2.25e+00", event.Readings[1].Value)
+	assert.Equal(t, "42", event.Readings[2].Value)
+	assert.Equal(t, int64(1600000000000000001), event.Readings[2].Origin)
+	assert.Equal(t, []byte{1, 2, 3}, event.Readings[3].BinaryValue)
+	assert.Equal(t, "image/jpeg", event.Readings[3].MediaType)
+	for _, reading := range event.Readings {
+		assert.Equal(t, "Random-Float-Device", reading.DeviceName)
+		assert.Equal(t, "Random-Float-Profile", reading.ProfileName)
+	}
+}
+
+func TestFromV1EventInvalidFloat(t *testing.T) {
+	v1Event := V1Event{
+		Device:   "Random-Float-Device",
+		Readings: []V1Reading{{Name: "Float32", Value: "bogus!", ValueType: common.ValueTypeFloat32, FloatEncoding: V1FloatEncodingBase64}},
+	}
+
+	_, err := FromV1Event(v1Event, "Random-Float-Profile")
+	assert.Error(t, err)
+}
+
+func TestToV1Event(t *testing.T) {
+	event, err := FromV1Event(V1Event{
+		Device: "Random-Float-Device",
+		Origin: 1600000000000000000,
+		Readings: []V1Reading{
+			{Name: "Float64", Value: "2.25e+00", ValueType: common.ValueTypeFloat64, FloatEncoding: V1FloatEncodingENotation},
+			{Name: "Image", BinaryValue: []byte{1, 2, 3}, ValueType: common.ValueTypeBinary, MediaType: "image/jpeg"},
+		},
+	}, "Random-Float-Profile")
+	require.NoError(t, err)
+
+	v1Event := ToV1Event(event)
+	assert.Equal(t, event.Id, v1Event.ID)
+	assert.Equal(t, "Random-Float-Device", v1Event.Device)
+	assert.Equal(t, event.Origin, v1Event.Origin)
+	require.Len(t, v1Event.Readings, 2)
+	assert.Equal(t, "Float64", v1Event.Readings[0].Name)
+	assert.Equal(t, "2.25e+00", v1Event.Readings[0].Value)
+	assert.Equal(t, V1FloatEncodingENotation, v1Event.Readings[0].FloatEncoding)
+	assert.Equal(t, []byte{1, 2, 3}, v1Event.Readings[1].BinaryValue)
+	assert.Equal(t, "image/jpeg", v1Event.Readings[1].MediaType)
+}